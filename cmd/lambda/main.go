@@ -7,69 +7,35 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/awslabs/aws-lambda-go-api-proxy/chi"
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 
 	"fis-playground/internal/handlers"
-	"fis-playground/internal/repository"
+	"fis-playground/internal/logging"
+	"fis-playground/internal/server"
 )
 
 var chiLambda *chiadapter.ChiLambda
 
 // init initializes the Chi router and Lambda adapter
 func init() {
+	logging.Init()
 	log.Println("Initializing Chi router...")
+
+	// Every instance must sign pagination tokens with the same secret
+	if err := handlers.RequirePageTokenSecret(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 	
 	// Create context for initialization
 	ctx := context.Background()
 	
 	// Initialize repository dependencies
-	clientManager, err := repository.NewClientManager(ctx)
+	repo, err := server.NewRepository(ctx)
 	if err != nil {
-		log.Fatalf("Failed to create client manager: %v", err)
+		log.Fatalf("Failed to create repository: %v", err)
 	}
 
-	repo := repository.NewDynamoDBRepositoryFromManager(clientManager)
-	itemHandler := handlers.NewItemHandler(repo)
-
-	// Create Chi router
-	r := chi.NewRouter()
-
-	// Add middleware
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.RequestID)
-
-	// Add CORS middleware
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: false,
-		MaxAge:           300,
-	}))
-
-	// Health check endpoints
-	r.Get("/health", itemHandler.HealthCheck)       // Simple API health check
-	r.Get("/health/db", itemHandler.HealthCheckDB)  // DynamoDB connectivity check
-	r.Get("/", itemHandler.HealthCheck)             // Root path health check
-
-	// API routes
-	r.Route("/items", func(r chi.Router) {
-		r.Get("/", itemHandler.ListItems)
-		r.Post("/", itemHandler.CreateItem)
-		
-		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", itemHandler.GetItem)
-			r.Put("/", itemHandler.UpdateItem)
-			r.Delete("/", itemHandler.DeleteItem)
-		})
-	})
-
 	// Initialize the Chi Lambda adapter
-	chiLambda = chiadapter.New(r)
+	chiLambda = chiadapter.New(server.NewRouter(repo))
 }
 
 // Handler is the main Lambda handler function
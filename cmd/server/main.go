@@ -0,0 +1,67 @@
+// Command server runs the API as a standalone HTTP server, for local
+// development and containers, with the same routes as the Lambda function.
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"fis-playground/internal/events"
+	"fis-playground/internal/logging"
+	"fis-playground/internal/server"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take to finish
+// after SIGTERM
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	logging.Init()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	repo, err := server.NewRepository(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create repository: %v", err)
+	}
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	broker := events.NewBroker()
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           server.NewServerRouter(repo, broker),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// End event streams on shutdown, or they would hold it up until the timeout
+	srv.RegisterOnShutdown(broker.Close)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Listening on %s", srv.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		log.Fatalf("Server failed: %v", err)
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Graceful shutdown failed: %v", err)
+	}
+	log.Println("Server stopped")
+}
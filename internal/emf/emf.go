@@ -0,0 +1,163 @@
+// Package emf records business metrics, such as items created, as
+// CloudWatch embedded metric format (EMF) log lines. CloudWatch Logs turns
+// each line into metric data points, so a Lambda function publishes metrics
+// just by logging them, without an agent, a scrape endpoint or API calls.
+package emf
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults used when EMF_NAMESPACE or EMF_SERVICE_NAME are not set
+const (
+	DefaultNamespace   = "FISPlayground"
+	DefaultServiceName = "fis-playground"
+)
+
+// ServiceDimension is the dimension every metric carries, naming the
+// service that emitted it
+const ServiceDimension = "Service"
+
+// Business metric names
+const (
+	ItemsCreated     = "ItemsCreated"
+	ItemsDeleted     = "ItemsDeleted"
+	ValidationErrors = "ValidationErrors"
+	// Errors counts error responses by their ErrorCode dimension
+	Errors = "Errors"
+)
+
+// ErrorCodeDimension is the dimension Errors is counted by
+const ErrorCodeDimension = "ErrorCode"
+
+// Emitter records business metrics. Tests can supply their own to capture
+// what was recorded.
+type Emitter interface {
+	// Count adds value to the named count metric. The dimensions are added
+	// to the service dimension every metric has.
+	Count(name string, value float64, dimensions map[string]string)
+}
+
+// Enabled reports whether EMF_ENABLED turns metrics on
+func Enabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("EMF_ENABLED"))
+	return enabled
+}
+
+// NewEmitterFromEnv creates an emitter logging to stdout, where Lambda
+// sends it on to CloudWatch Logs, under EMF_NAMESPACE and with
+// EMF_SERVICE_NAME as the service dimension. It returns nil when
+// EMF_ENABLED is not set.
+func NewEmitterFromEnv() Emitter {
+	if !Enabled() {
+		return nil
+	}
+	namespace := os.Getenv("EMF_NAMESPACE")
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	service := os.Getenv("EMF_SERVICE_NAME")
+	if service == "" {
+		service = DefaultServiceName
+	}
+	return NewLogger(os.Stdout, namespace, service)
+}
+
+// Logger is an Emitter writing each metric as one EMF log line
+type Logger struct {
+	namespace string
+	service   string
+	now       func() time.Time
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewLogger creates an emitter writing metrics in namespace to out
+func NewLogger(out io.Writer, namespace, service string) *Logger {
+	return &Logger{namespace: namespace, service: service, now: time.Now, out: out}
+}
+
+// Count writes the metric as an EMF document: the _aws metadata says which
+// top-level fields are the metric and its dimensions
+func (l *Logger) Count(name string, value float64, dimensions map[string]string) {
+	fields := map[string]interface{}{
+		ServiceDimension: l.service,
+		name:             value,
+	}
+	keys := []string{ServiceDimension}
+	for _, key := range slices.Sorted(maps.Keys(dimensions)) {
+		fields[key] = dimensions[key]
+		keys = append(keys, key)
+	}
+	fields["_aws"] = metadata{
+		Timestamp: l.now().UnixMilli(),
+		CloudWatchMetrics: []directive{{
+			Namespace:  l.namespace,
+			Dimensions: [][]string{keys},
+			Metrics:    []metricDefinition{{Name: name, Unit: "Count"}},
+		}},
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		log.Printf("Failed to encode metric %s: %v", name, err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.out.Write(append(data, '\n')); err != nil {
+		log.Printf("Failed to write metric %s: %v", name, err)
+	}
+}
+
+// metadata is the _aws field of an EMF document
+type metadata struct {
+	Timestamp         int64       `json:"Timestamp"`
+	CloudWatchMetrics []directive `json:"CloudWatchMetrics"`
+}
+
+// directive tells CloudWatch which fields of the document are metrics and
+// which are their dimensions
+type directive struct {
+	Namespace  string             `json:"Namespace"`
+	Dimensions [][]string         `json:"Dimensions"`
+	Metrics    []metricDefinition `json:"Metrics"`
+}
+
+type metricDefinition struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// discard is the Emitter used when none is configured
+type discard struct{}
+
+func (discard) Count(string, float64, map[string]string) {}
+
+// emitterKey is the context key for the request's emitter
+type emitterKey struct{}
+
+// WithEmitter returns a context carrying the emitter
+func WithEmitter(ctx context.Context, emitter Emitter) context.Context {
+	return context.WithValue(ctx, emitterKey{}, emitter)
+}
+
+// FromContext returns the emitter in ctx, or one that records nothing when
+// there is none, so callers never need to check
+func FromContext(ctx context.Context) Emitter {
+	if emitter, ok := ctx.Value(emitterKey{}).(Emitter); ok && emitter != nil {
+		return emitter
+	}
+	return discard{}
+}
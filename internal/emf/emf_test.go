@@ -0,0 +1,89 @@
+package emf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_WritesEMFDocument(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, "Items", "items-api")
+	logger.now = func() time.Time { return time.UnixMilli(1700000000123) }
+
+	logger.Count(Errors, 1, map[string]string{ErrorCodeDimension: "ITEM_NOT_FOUND"})
+
+	line := out.String()
+	if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+		t.Fatalf("Expected one log line, got %q", line)
+	}
+	var doc struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []struct{ Name, Unit string }
+			}
+		} `json:"_aws"`
+		Service   string
+		ErrorCode string
+		Errors    float64
+	}
+	if err := json.Unmarshal([]byte(line), &doc); err != nil {
+		t.Fatalf("Failed to decode %q: %v", line, err)
+	}
+	if doc.AWS.Timestamp != 1700000000123 {
+		t.Errorf("Expected the timestamp in milliseconds, got %d", doc.AWS.Timestamp)
+	}
+	if len(doc.AWS.CloudWatchMetrics) != 1 {
+		t.Fatalf("Expected one metric directive, got %+v", doc.AWS.CloudWatchMetrics)
+	}
+	directive := doc.AWS.CloudWatchMetrics[0]
+	if directive.Namespace != "Items" {
+		t.Errorf("Expected namespace Items, got %q", directive.Namespace)
+	}
+	if !reflect.DeepEqual(directive.Dimensions, [][]string{{ServiceDimension, ErrorCodeDimension}}) {
+		t.Errorf("Expected the service and error code dimensions, got %v", directive.Dimensions)
+	}
+	if len(directive.Metrics) != 1 || directive.Metrics[0].Name != Errors || directive.Metrics[0].Unit != "Count" {
+		t.Errorf("Expected an Errors count, got %+v", directive.Metrics)
+	}
+	if doc.Service != "items-api" || doc.ErrorCode != "ITEM_NOT_FOUND" || doc.Errors != 1 {
+		t.Errorf("Expected the dimension and metric values, got %+v", doc)
+	}
+}
+
+func TestFromContext_DiscardsWithoutEmitter(t *testing.T) {
+	// Must not panic
+	FromContext(context.Background()).Count(ItemsCreated, 1, nil)
+
+	var out bytes.Buffer
+	ctx := WithEmitter(context.Background(), NewLogger(&out, DefaultNamespace, DefaultServiceName))
+	FromContext(ctx).Count(ItemsCreated, 1, nil)
+	if !strings.Contains(out.String(), `"ItemsCreated":1`) {
+		t.Errorf("Expected the metric from the context's emitter, got %q", out.String())
+	}
+}
+
+func TestNewEmitterFromEnv(t *testing.T) {
+	t.Setenv("EMF_ENABLED", "")
+	if emitter := NewEmitterFromEnv(); emitter != nil {
+		t.Errorf("Expected no emitter when disabled, got %T", emitter)
+	}
+
+	t.Setenv("EMF_ENABLED", "true")
+	t.Setenv("EMF_NAMESPACE", "Custom")
+	t.Setenv("EMF_SERVICE_NAME", "")
+	logger, ok := NewEmitterFromEnv().(*Logger)
+	if !ok {
+		t.Fatal("Expected a logger when enabled")
+	}
+	if logger.namespace != "Custom" || logger.service != DefaultServiceName {
+		t.Errorf("Expected namespace Custom and the default service, got %q and %q", logger.namespace, logger.service)
+	}
+}
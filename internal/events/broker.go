@@ -0,0 +1,99 @@
+// Package events fans out item change events to subscribers in the same
+// process, backing the item event stream of the standalone server. Events
+// don't cross processes, so each Lambda instance or server replica only
+// sees its own writes.
+package events
+
+import (
+	"sync"
+
+	"fis-playground/internal/models"
+)
+
+// Item change event types
+const (
+	ItemCreated = "created"
+	ItemUpdated = "updated"
+	ItemDeleted = "deleted"
+	// ItemUpserted carries an item's current state, re-sent on request for
+	// consumers that missed its earlier events
+	ItemUpserted = "upsert"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind by
+// before further events to it are dropped
+const subscriberBuffer = 64
+
+// Event describes a change to an item
+type Event struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	// Item is the item after the change; it is nil for deletes
+	Item *models.Item `json:"item,omitempty"`
+	// OwnerID is the changed item's owner, which decides the subscribers
+	// scoped to their own items that see the event. It isn't sent, and
+	// deletes only carry it while items are scoped to their owner.
+	OwnerID string `json:"-"`
+}
+
+// Broker delivers published events to every current subscriber. Publishing
+// never blocks: a subscriber whose buffer is full misses the event rather
+// than stalling the write that published it.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// NewBroker creates a broker with no subscribers
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel receiving events published from now on and a
+// function that unsubscribes it. The channel is closed on unsubscribe or
+// when the broker is closed.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish sends the event to every subscriber with room for it
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Close closes every subscriber's channel, ending their streams, and makes
+// later subscriptions end immediately. It is meant for server shutdown.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestBroker_DeliversToSubscribers(t *testing.T) {
+	broker := NewBroker()
+	first, unsubscribeFirst := broker.Subscribe()
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := broker.Subscribe()
+
+	broker.Publish(Event{Type: ItemCreated, ID: "item-1"})
+	for _, ch := range []<-chan Event{first, second} {
+		if event := <-ch; event.Type != ItemCreated || event.ID != "item-1" {
+			t.Errorf("Expected the created event, got %+v", event)
+		}
+	}
+
+	unsubscribeSecond()
+	if _, ok := <-second; ok {
+		t.Error("Expected unsubscribing to close the channel")
+	}
+	broker.Publish(Event{Type: ItemDeleted, ID: "item-1"})
+	if event := <-first; event.Type != ItemDeleted {
+		t.Errorf("Expected the deleted event, got %+v", event)
+	}
+}
+
+func TestBroker_DropsEventsForSlowSubscribers(t *testing.T) {
+	broker := NewBroker()
+	ch, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	for range subscriberBuffer + 10 {
+		broker.Publish(Event{Type: ItemUpdated, ID: "item-1"})
+	}
+	if len(ch) != subscriberBuffer {
+		t.Errorf("Expected %d buffered events, got %d", subscriberBuffer, len(ch))
+	}
+}
+
+func TestBroker_CloseEndsSubscriptions(t *testing.T) {
+	broker := NewBroker()
+	ch, unsubscribe := broker.Subscribe()
+
+	broker.Close()
+	if _, ok := <-ch; ok {
+		t.Error("Expected closing the broker to close the channel")
+	}
+	unsubscribe() // safe after close
+
+	late, _ := broker.Subscribe()
+	if _, ok := <-late; ok {
+		t.Error("Expected subscriptions after close to end immediately")
+	}
+}
@@ -0,0 +1,491 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fis-playground/internal/handlers"
+	"fis-playground/internal/models"
+	"fis-playground/internal/querylimit"
+	"fis-playground/internal/repository"
+)
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// Response is a GraphQL response
+type Response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []*Error               `json:"errors,omitempty"`
+}
+
+// Error is a GraphQL error. Extensions carry the same code and type as the
+// REST API's error responses.
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// newError converts an APIError into a GraphQL error at the given path
+func newError(apiErr *handlers.APIError, path ...interface{}) *Error {
+	message := apiErr.Message
+	if apiErr.Details != "" {
+		message = fmt.Sprintf("%s: %s", apiErr.Message, apiErr.Details)
+	}
+	return &Error{
+		Message: message,
+		Path:    path,
+		Extensions: map[string]interface{}{
+			"code": string(apiErr.Code),
+			"type": string(apiErr.Type),
+		},
+	}
+}
+
+// requestError creates an error for a malformed request or argument
+func requestError(message string, path ...interface{}) *Error {
+	return newError(handlers.NewValidationError(handlers.CodeInvalidRequest, message), path...)
+}
+
+// Executor resolves GraphQL operations against the items an ItemHandler
+// serves. Mutations go through the handler's item operations, so they are
+// checked, recorded and published exactly like the REST API's writes.
+type Executor struct {
+	items      *handlers.ItemHandler
+	repo       repository.ItemRepository
+	itemFields map[string]bool
+	pageTokens *handlers.PageTokenCodec
+	limits     querylimit.Limits
+}
+
+// NewExecutor creates a new executor backed by the given item handler
+func NewExecutor(items *handlers.ItemHandler) *Executor {
+	return &Executor{
+		items:      items,
+		repo:       items.Repository(),
+		itemFields: itemFieldSet(),
+		pageTokens: handlers.NewHandlerConfig().PageTokenCodec(),
+		limits:     querylimit.LimitsFromEnv(),
+	}
+}
+
+// Execute parses and executes a request. Root fields are resolved in order,
+// which also satisfies the serial execution required for mutations.
+// Documents longer, nested deeper or selecting more fields than the
+// executor's limits are rejected while they are parsed, before any field
+// is resolved.
+func (e *Executor) Execute(ctx context.Context, req *Request) *Response {
+	doc, err := ParseWithLimits(req.Query, e.limits)
+	if errors.Is(err, querylimit.ErrTooComplex) {
+		return &Response{Errors: []*Error{requestError(err.Error())}}
+	}
+	if err != nil {
+		return &Response{Errors: []*Error{requestError(fmt.Sprintf("Syntax error: %v", err))}}
+	}
+
+	op, gqlErr := selectOperation(doc, req.OperationName)
+	if gqlErr != nil {
+		return &Response{Errors: []*Error{gqlErr}}
+	}
+
+	resp := &Response{Data: map[string]interface{}{}}
+	for _, field := range op.SelectionSet {
+		args, err := resolveArguments(field.Arguments, req.Variables)
+		if err != nil {
+			resp.Data[field.ResponseKey()] = nil
+			resp.Errors = append(resp.Errors, requestError(err.Error(), field.ResponseKey()))
+			continue
+		}
+
+		value, gqlErr := e.resolveRootField(ctx, op.Type, field, args)
+		if gqlErr != nil {
+			gqlErr.Path = append([]interface{}{field.ResponseKey()}, gqlErr.Path...)
+			resp.Errors = append(resp.Errors, gqlErr)
+			value = nil
+		}
+		resp.Data[field.ResponseKey()] = value
+	}
+
+	return resp
+}
+
+// selectOperation picks the operation to run from a document
+func selectOperation(doc *Document, name string) (*Operation, *Error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, requestError("operationName is required for documents with multiple operations")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, requestError(fmt.Sprintf("Unknown operation %q", name))
+}
+
+// resolveArguments substitutes variables into argument values
+func resolveArguments(args map[string]Value, variables map[string]interface{}) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		v, err := resolveValue(value, variables)
+		if err != nil {
+			return nil, err
+		}
+		resolved[name] = v
+	}
+	return resolved, nil
+}
+
+func resolveValue(value Value, variables map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case Variable:
+		val, ok := variables[string(v)]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", v)
+		}
+		return val, nil
+	case []Value:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := resolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	case map[string]Value:
+		obj := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			resolved, err := resolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = resolved
+		}
+		return obj, nil
+	default:
+		return v, nil
+	}
+}
+
+func (e *Executor) resolveRootField(ctx context.Context, opType string, field *Field, args map[string]interface{}) (interface{}, *Error) {
+	if field.Name == "__typename" {
+		if opType == "mutation" {
+			return "Mutation", nil
+		}
+		return "Query", nil
+	}
+
+	// Validate selections up front so a bad query never causes side effects
+	if gqlErr := e.validateSelection(field); gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	switch opType + "." + field.Name {
+	case "query.item":
+		return e.resolveItem(ctx, field, args)
+	case "query.items":
+		return e.resolveItems(ctx, field, args)
+	case "mutation.createItem":
+		return e.resolveCreateItem(ctx, field, args)
+	case "mutation.updateItem":
+		return e.resolveUpdateItem(ctx, field, args)
+	case "mutation.deleteItem":
+		return e.resolveDeleteItem(ctx, args)
+	default:
+		typeName := "Query"
+		if opType == "mutation" {
+			typeName = "Mutation"
+		}
+		return nil, requestError(fmt.Sprintf("Cannot query field %q on type %q", field.Name, typeName))
+	}
+}
+
+func (e *Executor) resolveItem(ctx context.Context, field *Field, args map[string]interface{}) (interface{}, *Error) {
+	id, gqlErr := stringArgument(args, "id", true)
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	item, err := e.repo.GetItem(ctx, id, nil)
+	if err != nil {
+		if repository.IsNotFoundError(err) {
+			return nil, nil // Nullable field: a missing item is not an error
+		}
+		return nil, newError(handlers.MapRepositoryError(err))
+	}
+	if !item.IsVisibleAt(time.Now()) || item.IsDeleted() {
+		return nil, nil
+	}
+	if apiErr := e.items.AuthorizeRead(ctx, item); apiErr != nil {
+		return nil, newError(apiErr)
+	}
+
+	return e.selectItem(item, field.SelectionSet)
+}
+
+func (e *Executor) resolveItems(ctx context.Context, field *Field, args map[string]interface{}) (interface{}, *Error) {
+	options := &repository.ListItemsOptions{Limit: 50}
+	e.items.ScopeList(ctx, options)
+
+	if limit, ok := args["limit"]; ok && limit != nil {
+		n, isInt := toInt(limit)
+		if !isInt || n <= 0 || n > 100 {
+			return nil, newError(handlers.NewValidationError(handlers.CodeInvalidValue, "Invalid limit value", "Limit must be between 1 and 100"))
+		}
+		options.Limit = int32(n)
+	}
+
+	cursor, gqlErr := stringArgument(args, "cursor", false)
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+	if cursor != "" {
+		key, err := e.pageTokens.Decode(cursor)
+		if err != nil {
+			return nil, newError(handlers.NewPageTokenError(err))
+		}
+		options.LastEvaluatedKey = key
+	}
+
+	// The status filter is applied by the repository, before the limit, so
+	// pages are filled with matching items
+	if filter, ok := args["filter"].(map[string]interface{}); ok {
+		if status, ok := filter["status"]; ok && status != nil {
+			s, isString := status.(string)
+			if !isString || !models.IsValidStatus(s) {
+				return nil, newError(handlers.NewValidationError(handlers.CodeInvalidValue, "Invalid status filter", models.ErrInvalidStatus.Error()))
+			}
+			options.StatusFilter = s
+		}
+	}
+
+	result, err := e.repo.ListItems(ctx, options)
+	if err != nil {
+		return nil, newError(handlers.MapRepositoryError(err))
+	}
+	result.Items = models.VisibleItems(result.Items, time.Now())
+
+	items := make([]interface{}, 0, len(result.Items))
+	for i := range result.Items {
+		selected, gqlErr := e.selectItem(&result.Items[i], selectionOf(field, "items"))
+		if gqlErr != nil {
+			return nil, gqlErr
+		}
+		items = append(items, selected)
+	}
+
+	var nextCursor interface{}
+	if result.HasMore {
+		token, err := e.pageTokens.Encode(result.LastEvaluatedKey)
+		if err != nil {
+			return nil, newError(handlers.MapRepositoryError(err))
+		}
+		nextCursor = token
+	}
+
+	connection := map[string]interface{}{}
+	for _, sel := range field.SelectionSet {
+		switch sel.Name {
+		case "items":
+			connection[sel.ResponseKey()] = items
+		case "count":
+			connection[sel.ResponseKey()] = len(items)
+		case "hasMore":
+			connection[sel.ResponseKey()] = result.HasMore
+		case "nextCursor":
+			connection[sel.ResponseKey()] = nextCursor
+		case "__typename":
+			connection[sel.ResponseKey()] = "ItemConnection"
+		}
+	}
+	return connection, nil
+}
+
+func (e *Executor) resolveCreateItem(ctx context.Context, field *Field, args map[string]interface{}) (interface{}, *Error) {
+	var input models.CreateItemRequest
+	if gqlErr := decodeInput(args, "input", &input); gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	item, apiErr := e.items.Create(ctx, &input)
+	if apiErr != nil {
+		return nil, newError(apiErr)
+	}
+
+	return e.selectItem(item, field.SelectionSet)
+}
+
+func (e *Executor) resolveUpdateItem(ctx context.Context, field *Field, args map[string]interface{}) (interface{}, *Error) {
+	id, gqlErr := stringArgument(args, "id", true)
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	var input models.UpdateItemRequest
+	if gqlErr := decodeInput(args, "input", &input); gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	// The input's generation is the only precondition; there is no If-Match
+	item, apiErr := e.items.Update(ctx, id, &input, "")
+	if apiErr != nil {
+		return nil, newError(apiErr)
+	}
+
+	return e.selectItem(item, field.SelectionSet)
+}
+
+func (e *Executor) resolveDeleteItem(ctx context.Context, args map[string]interface{}) (interface{}, *Error) {
+	id, gqlErr := stringArgument(args, "id", true)
+	if gqlErr != nil {
+		return nil, gqlErr
+	}
+
+	// An expected generation is passed on as the If-Match it stands for
+	var ifMatch string
+	if generation, ok := args["generation"]; ok && generation != nil {
+		n, isInt := toInt(generation)
+		if !isInt || n < 0 {
+			return nil, requestError("Argument \"generation\" must be a non-negative integer")
+		}
+		ifMatch = `"` + strconv.FormatInt(n, 10) + `"`
+	}
+
+	if apiErr := e.items.Delete(ctx, id, "", ifMatch); apiErr != nil {
+		return nil, newError(apiErr)
+	}
+
+	return true, nil
+}
+
+// validateSelection checks a root field's selection set against the schema
+func (e *Executor) validateSelection(field *Field) *Error {
+	switch field.Name {
+	case "item", "createItem", "updateItem":
+		return e.validateItemSelection(field.SelectionSet)
+	case "deleteItem":
+		if len(field.SelectionSet) > 0 {
+			return requestError("Field \"deleteItem\" of type \"Boolean\" must not have a selection")
+		}
+	case "items":
+		if len(field.SelectionSet) == 0 {
+			return requestError("Field of type \"ItemConnection\" must have a selection of subfields")
+		}
+		for _, sel := range field.SelectionSet {
+			switch sel.Name {
+			case "items":
+				if gqlErr := e.validateItemSelection(sel.SelectionSet); gqlErr != nil {
+					return gqlErr
+				}
+			case "count", "hasMore", "nextCursor", "__typename":
+			default:
+				return requestError(fmt.Sprintf("Cannot query field %q on type \"ItemConnection\"", sel.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// validateItemSelection checks that only Item fields are selected
+func (e *Executor) validateItemSelection(selections []*Field) *Error {
+	if len(selections) == 0 {
+		return requestError("Field of type \"Item\" must have a selection of subfields")
+	}
+	for _, sel := range selections {
+		if sel.Name != "__typename" && !e.itemFields[sel.Name] {
+			return requestError(fmt.Sprintf("Cannot query field %q on type \"Item\"", sel.Name))
+		}
+	}
+	return nil
+}
+
+// selectItem projects an item onto the requested fields using its JSON form.
+// Selections must already have been checked by validateSelection.
+func (e *Executor) selectItem(item *models.Item, selections []*Field) (interface{}, *Error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, newError(handlers.MapRepositoryError(err))
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, newError(handlers.MapRepositoryError(err))
+	}
+
+	selected := make(map[string]interface{}, len(selections))
+	for _, sel := range selections {
+		if sel.Name == "__typename" {
+			selected[sel.ResponseKey()] = "Item"
+			continue
+		}
+		selected[sel.ResponseKey()] = values[sel.Name]
+	}
+	return selected, nil
+}
+
+// selectionOf returns the sub-selection of the named child field
+func selectionOf(field *Field, name string) []*Field {
+	for _, sel := range field.SelectionSet {
+		if sel.Name == name {
+			return sel.SelectionSet
+		}
+	}
+	return nil
+}
+
+// stringArgument reads a string argument, optionally requiring it
+func stringArgument(args map[string]interface{}, name string, required bool) (string, *Error) {
+	value, ok := args[name]
+	if !ok || value == nil {
+		if required {
+			return "", newError(handlers.NewValidationError(handlers.CodeMissingField, fmt.Sprintf("Argument %q is required", name)))
+		}
+		return "", nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", requestError(fmt.Sprintf("Argument %q must be a string", name))
+	}
+	return s, nil
+}
+
+// decodeInput converts an input object argument into a request struct
+func decodeInput(args map[string]interface{}, name string, target interface{}) *Error {
+	value, ok := args[name]
+	if !ok || value == nil {
+		return newError(handlers.NewValidationError(handlers.CodeMissingField, fmt.Sprintf("Argument %q is required", name)))
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return requestError(fmt.Sprintf("Argument %q is invalid: %v", name, err))
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return requestError(fmt.Sprintf("Argument %q is invalid: %v", name, err))
+	}
+	return nil
+}
+
+// toInt converts a literal or JSON variable number to an int
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,432 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"fis-playground/internal/events"
+	"fis-playground/internal/handlers"
+	"fis-playground/internal/models"
+	"fis-playground/internal/querylimit"
+	"fis-playground/internal/repository"
+)
+
+// postGraphQL sends a GraphQL request through the HTTP handler
+func postGraphQL(t *testing.T, handler *Handler, req Request) *Response {
+	t.Helper()
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/graphql", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ServeGraphQL(w, httpReq)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return &resp
+}
+
+func TestGraphQL_CreateItemMutation(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewHandler(handlers.NewItemHandler(repo))
+
+	resp := postGraphQL(t, handler, Request{
+		Query: `mutation Create($input: CreateItemInput!) {
+			created: createItem(input: $input) { id name status }
+		}`,
+		Variables: map[string]interface{}{
+			"input": map[string]interface{}{"name": "GraphQL Item", "description": "Created via GraphQL"},
+		},
+	})
+
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", resp.Errors[0])
+	}
+
+	created := resp.Data["created"].(map[string]interface{})
+	if created["name"] != "GraphQL Item" || created["status"] != "active" {
+		t.Errorf("Unexpected created item: %v", created)
+	}
+	if _, ok := created["description"]; ok {
+		t.Error("Expected unselected field 'description' to be absent")
+	}
+
+	stored, err := repo.GetItem(context.Background(), created["id"].(string), nil)
+	if err != nil {
+		t.Fatalf("Expected item to be stored: %v", err)
+	}
+	if stored.Description != "Created via GraphQL" {
+		t.Errorf("Expected stored description, got %q", stored.Description)
+	}
+}
+
+func TestGraphQL_CreateItemChargesCreateBudget(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	executor := NewExecutor(handlers.NewItemHandler(repo))
+	ctx := handlers.WithCreateBudget(context.Background(), func(n int) time.Duration { return time.Minute })
+
+	resp := executor.Execute(ctx, &Request{Query: `mutation { createItem(input: {name: "Item", description: "Description"}) { id } }`})
+
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(handlers.CodeRateLimitExceeded) {
+		t.Fatalf("Expected a rate limit error, got %+v", resp.Errors)
+	}
+	result, err := repo.ListItems(context.Background(), nil)
+	if err != nil || len(result.Items) != 0 {
+		t.Errorf("Expected nothing to be created, got %v (%v)", result, err)
+	}
+}
+
+func TestGraphQL_ItemQueries(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for _, name := range []string{"First", "Second", "Third"} {
+		item := models.NewItem(name, "Description")
+		item.ID = strings.ToLower(name)
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewHandler(handlers.NewItemHandler(repo))
+
+	resp := postGraphQL(t, handler, Request{
+		Query: `{
+			item(id: "second") { id name }
+			page: items(limit: 2) { count hasMore nextCursor items { id } }
+		}`,
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", resp.Errors[0])
+	}
+
+	item := resp.Data["item"].(map[string]interface{})
+	if item["name"] != "Second" {
+		t.Errorf("Expected item 'Second', got %v", item["name"])
+	}
+
+	page := resp.Data["page"].(map[string]interface{})
+	if page["count"].(float64) != 2 || page["hasMore"] != true {
+		t.Fatalf("Unexpected first page: %v", page)
+	}
+
+	resp = postGraphQL(t, handler, Request{
+		Query:     `query Next($cursor: String) { items(cursor: $cursor) { hasMore items { id } } }`,
+		Variables: map[string]interface{}{"cursor": page["nextCursor"]},
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", resp.Errors[0])
+	}
+	next := resp.Data["items"].(map[string]interface{})
+	items := next["items"].([]interface{})
+	if len(items) != 1 || next["hasMore"] != false {
+		t.Errorf("Expected a final page with 1 item, got %v", next)
+	}
+}
+
+func TestGraphQL_RepositoryErrorExtensions(t *testing.T) {
+	handler := NewHandler(handlers.NewItemHandler(repository.NewMemoryRepository()))
+
+	resp := postGraphQL(t, handler, Request{
+		Query: `mutation { deleteItem(id: "missing") }`,
+	})
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(resp.Errors))
+	}
+	gqlErr := resp.Errors[0]
+	if gqlErr.Extensions["code"] != "NOT_FOUND" || gqlErr.Extensions["type"] != "not_found" {
+		t.Errorf("Expected NOT_FOUND extensions, got %v", gqlErr.Extensions)
+	}
+	if len(gqlErr.Path) != 1 || gqlErr.Path[0] != "deleteItem" {
+		t.Errorf("Expected path [deleteItem], got %v", gqlErr.Path)
+	}
+	if resp.Data["deleteItem"] != nil {
+		t.Errorf("Expected null data for failed field, got %v", resp.Data["deleteItem"])
+	}
+}
+
+func TestGraphQL_ValidationErrorExtensions(t *testing.T) {
+	handler := NewHandler(handlers.NewItemHandler(repository.NewMemoryRepository()))
+
+	resp := postGraphQL(t, handler, Request{
+		Query: `mutation { createItem(input: {name: "", description: "x"}) { id } }`,
+	})
+
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "MISSING_FIELD" {
+		t.Fatalf("Expected a MISSING_FIELD error, got %+v", resp.Errors)
+	}
+}
+
+func TestGraphQL_UnknownField(t *testing.T) {
+	handler := NewHandler(handlers.NewItemHandler(repository.NewMemoryRepository()))
+
+	resp := postGraphQL(t, handler, Request{Query: `{ items { items { secret } } }`})
+
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "INVALID_REQUEST" {
+		t.Fatalf("Expected an INVALID_REQUEST error, got %+v", resp.Errors)
+	}
+}
+
+func TestSDL_GeneratedFromModels(t *testing.T) {
+	sdl := SDL()
+
+	for _, want := range []string{"type Item {", "  created_at: String", "input CreateItemInput {", "type Mutation {"} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("Expected SDL to contain %q", want)
+		}
+	}
+}
+
+func TestGraphQL_RejectsTooComplexQueries(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewHandler(handlers.NewItemHandler(repo))
+	handler.executor.limits = querylimit.Limits{MaxDepth: 3, MaxComplexity: 10, MaxLength: querylimit.DefaultMaxLength}
+
+	var aliases strings.Builder
+	for i := range 11 {
+		fmt.Fprintf(&aliases, "a%d: __typename ", i)
+	}
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "Too deep", query: `{ items { items { id { name } } } }`},
+		{name: "Too many fields", query: "{ " + aliases.String() + "}"},
+		{name: "Deeply nested argument", query: `{ item(id: ` + strings.Repeat("[", 10000) + `) { id } }`},
+		{name: "Too long", query: `{ items { count } }` + strings.Repeat(" ", querylimit.DefaultMaxLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postGraphQL(t, handler, Request{Query: tt.query})
+
+			if len(resp.Errors) != 1 {
+				t.Fatalf("Expected one error, got %+v", resp.Errors)
+			}
+			if code := resp.Errors[0].Extensions["code"]; code != string(handlers.CodeInvalidRequest) {
+				t.Errorf("Expected code %s, got %v", handlers.CodeInvalidRequest, code)
+			}
+			if len(resp.Data) != 0 {
+				t.Errorf("Expected nothing to be resolved, got %v", resp.Data)
+			}
+		})
+	}
+}
+
+func TestGraphQL_AllowsQueriesWithinLimits(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	if err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description")); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewHandler(handlers.NewItemHandler(repo))
+	handler.executor.limits = querylimit.Limits{MaxDepth: 3, MaxComplexity: 10}
+
+	resp := postGraphQL(t, handler, Request{Query: `{ items { items { id name status } } }`})
+
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", resp.Errors[0])
+	}
+	if resp.Data["items"] == nil {
+		t.Errorf("Expected items to be resolved, got %v", resp.Data)
+	}
+}
+
+func TestGraphQL_BodyTooLarge(t *testing.T) {
+	handler := NewHandler(handlers.NewItemHandler(repository.NewMemoryRepository()))
+	handler.maxBodyBytes = 1024
+
+	body, _ := json.Marshal(Request{Query: "{ items { count } }" + strings.Repeat(" ", 2048)})
+	req := httptest.NewRequest("POST", "/graphql", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeGraphQL(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+func TestGraphQL_CreateItemRecordsCreator(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	items := handlers.NewItemHandler(repo)
+	broker := events.NewBroker()
+	items.SetEventBroker(broker)
+	subscription, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+	executor := NewExecutor(items)
+	ctx := handlers.WithUser(handlers.WithPrincipal(context.Background(), "alice"), "alice")
+
+	resp := executor.Execute(ctx, &Request{Query: `mutation { createItem(input: {name: "Item", description: "Description"}) { id } }`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", resp.Errors[0])
+	}
+	id := resp.Data["createItem"].(map[string]interface{})["id"].(string)
+
+	stored, err := repo.GetItem(context.Background(), id, nil)
+	if err != nil {
+		t.Fatalf("Expected item to be stored: %v", err)
+	}
+	if stored.CreatedBy != "alice" || stored.OwnerID != "alice" {
+		t.Errorf("Expected alice to be recorded as creator and owner, got %q and %q", stored.CreatedBy, stored.OwnerID)
+	}
+	select {
+	case event := <-subscription:
+		if event.Type != events.ItemCreated || event.ID != id {
+			t.Errorf("Expected a create event for %s, got %s %s", id, event.Type, event.ID)
+		}
+	default:
+		t.Error("Expected the create to publish an event")
+	}
+}
+
+func TestGraphQL_CreateItemRejectsReservedIDs(t *testing.T) {
+	t.Setenv("RESERVED_ID_PREFIX", "sys-")
+	repo := repository.NewMemoryRepository()
+	executor := NewExecutor(handlers.NewItemHandler(repo))
+
+	for _, id := range []string{"_meta#counter", "sys-item"} {
+		resp := executor.Execute(context.Background(), &Request{
+			Query:     `mutation Create($input: CreateItemInput!) { createItem(input: $input) { id } }`,
+			Variables: map[string]interface{}{"input": map[string]interface{}{"id": id, "name": "Item", "description": "Description"}},
+		})
+		if len(resp.Errors) != 1 {
+			t.Errorf("Expected creating %q to fail, got %+v", id, resp.Data)
+		}
+	}
+	result, err := repo.ListItems(context.Background(), nil)
+	if err != nil || len(result.Items) != 0 {
+		t.Errorf("Expected nothing to be created, got %v (%v)", result, err)
+	}
+}
+
+func TestGraphQL_WritesRequireOwner(t *testing.T) {
+	t.Setenv("ENFORCE_OWNERSHIP", "true")
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Item", "Description")
+	item.CreatedBy = "alice"
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	executor := NewExecutor(handlers.NewItemHandler(repo))
+	bob := handlers.WithPrincipal(context.Background(), "bob")
+
+	for _, query := range []string{
+		fmt.Sprintf(`mutation { updateItem(id: %q, input: {name: "Renamed", description: "Description"}) { id } }`, item.ID),
+		fmt.Sprintf(`mutation { deleteItem(id: %q) }`, item.ID),
+	} {
+		resp := executor.Execute(bob, &Request{Query: query})
+		if len(resp.Errors) != 1 {
+			t.Errorf("Expected %s by another principal to fail, got %+v", query, resp.Data)
+		}
+	}
+	stored, err := repo.GetItem(context.Background(), item.ID, nil)
+	if err != nil || stored.Name != "Item" {
+		t.Errorf("Expected the item to be unchanged, got %+v (%v)", stored, err)
+	}
+}
+
+func TestGraphQL_DeleteItemChecksGeneration(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	executor := NewExecutor(handlers.NewItemHandler(repo))
+	query := `mutation Delete($id: ID!, $generation: Int) { deleteItem(id: $id, generation: $generation) }`
+
+	resp := executor.Execute(context.Background(), &Request{
+		Query:     query,
+		Variables: map[string]interface{}{"id": item.ID, "generation": item.Generation + 1},
+	})
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(handlers.CodePreconditionFailed) {
+		t.Fatalf("Expected a stale generation to fail the precondition, got %+v", resp.Errors)
+	}
+
+	resp = executor.Execute(context.Background(), &Request{
+		Query:     query,
+		Variables: map[string]interface{}{"id": item.ID, "generation": item.Generation},
+	})
+	if len(resp.Errors) > 0 || resp.Data["deleteItem"] != true {
+		t.Fatalf("Expected the current generation to delete the item, got %+v", resp.Errors)
+	}
+}
+
+func TestGraphQL_StatusFilterAppliesBeforeLimit(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for _, id := range []string{"a", "b", "c"} {
+		item := models.NewItem("Item "+id, "Description")
+		item.ID = id
+		if id == "c" {
+			item.Status = "inactive"
+		}
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	executor := NewExecutor(handlers.NewItemHandler(repo))
+
+	resp := executor.Execute(context.Background(), &Request{Query: `{ items(limit: 1, filter: {status: "inactive"}) { items { id } } }`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", resp.Errors[0])
+	}
+	page := resp.Data["items"].(map[string]interface{})["items"].([]interface{})
+	if len(page) != 1 || page[0].(map[string]interface{})["id"] != "c" {
+		t.Errorf("Expected the inactive item, got %v", page)
+	}
+
+	resp = executor.Execute(context.Background(), &Request{Query: `{ items(filter: {status: "unknown"}) { count } }`})
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(handlers.CodeInvalidValue) {
+		t.Errorf("Expected an invalid status to be rejected, got %+v", resp.Errors)
+	}
+}
+
+func TestGraphQL_ScopedToOwner(t *testing.T) {
+	t.Setenv("JWT_JWKS_URL", "https://auth.example.com/jwks.json")
+	repo := repository.NewMemoryRepository()
+	for id, owner := range map[string]string{"alice-1": "alice", "bob-1": "bob"} {
+		item := models.NewItem("Item", "Description")
+		item.ID = id
+		item.OwnerID = owner
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	executor := NewExecutor(handlers.NewItemHandler(repo))
+	bob := handlers.WithPrincipal(handlers.WithUser(context.Background(), "bob"), "bob")
+
+	for _, query := range []string{
+		`{ item(id: "alice-1") { id } }`,
+		`mutation { updateItem(id: "alice-1", input: {name: "Renamed", description: "Description"}) { id } }`,
+		`mutation { deleteItem(id: "alice-1") }`,
+	} {
+		resp := executor.Execute(bob, &Request{Query: query})
+		if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != string(handlers.CodeForbidden) {
+			t.Errorf("Expected %s on another user's item to be forbidden, got %+v", query, resp.Errors)
+		}
+	}
+	stored, err := repo.GetItem(context.Background(), "alice-1", nil)
+	if err != nil || stored.Name != "Item" {
+		t.Errorf("Expected alice's item to be unchanged, got %+v (%v)", stored, err)
+	}
+
+	resp := executor.Execute(bob, &Request{Query: `{ items { items { id } } }`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", resp.Errors[0])
+	}
+	items := resp.Data["items"].(map[string]interface{})["items"].([]interface{})
+	if len(items) != 1 || items[0].(map[string]interface{})["id"] != "bob-1" {
+		t.Errorf("Expected only bob's item to be listed, got %v", items)
+	}
+}
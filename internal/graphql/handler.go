@@ -0,0 +1,57 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"fis-playground/internal/handlers"
+	"fis-playground/internal/logging"
+)
+
+// Handler serves the GraphQL endpoint over HTTP
+type Handler struct {
+	executor     *Executor
+	maxBodyBytes int64
+}
+
+// NewHandler creates a new GraphQL HTTP handler serving the items of the
+// given item handler
+func NewHandler(items *handlers.ItemHandler) *Handler {
+	return &Handler{
+		executor:     NewExecutor(items),
+		maxBodyBytes: handlers.NewHandlerConfig().MaxBodyBytes,
+	}
+}
+
+// ServeGraphQL handles POST /graphql requests. Per GraphQL over HTTP
+// conventions, execution errors are reported in the body with status 200.
+// Bodies are capped like the REST API's, at MAX_BODY_BYTES.
+func (h *Handler) ServeGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes)).Decode(&req); err != nil {
+		handlers.WriteJSONParseErrorResponse(w, r, err)
+		return
+	}
+
+	if req.Query == "" {
+		handlers.WriteMissingParameterErrorResponse(w, r, "query")
+		return
+	}
+
+	resp := h.executor.Execute(r.Context(), &req)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to encode GraphQL response", "error", err)
+	}
+}
+
+// ServeSchema handles GET /graphql/schema requests, returning the SDL
+func (h *Handler) ServeSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(SDL())); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to write GraphQL schema", "error", err)
+	}
+}
@@ -0,0 +1,431 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fis-playground/internal/querylimit"
+)
+
+// Document is a parsed GraphQL request document
+type Document struct {
+	Operations []*Operation
+}
+
+// Operation is a single query or mutation in a document
+type Operation struct {
+	Type         string // "query" or "mutation"
+	Name         string
+	SelectionSet []*Field
+}
+
+// Field is a selected field with its arguments and sub-selections
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    map[string]Value
+	SelectionSet []*Field
+}
+
+// ResponseKey returns the key under which the field appears in the result
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Value is an argument value: a literal, list, object or variable reference
+type Value interface{}
+
+// Variable is a reference to a request variable, e.g. $id
+type Variable string
+
+// tokenKind identifies the lexical class of a token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token is a single lexical token
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// lex splits a GraphQL document into tokens. Commas are insignificant in
+// GraphQL and are skipped along with whitespace and comments.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.ContainsRune("!$():=@[]{}|", rune(c)):
+			tokens = append(tokens, token{kind: tokenPunct, value: string(c), pos: i})
+			i++
+		case c == '.':
+			if !strings.HasPrefix(src[i:], "...") {
+				return nil, fmt.Errorf("unexpected character '.' at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenPunct, value: "...", pos: i})
+			i += 3
+		case c == '_' || isLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || isLetter(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenName, value: src[start:i], pos: start})
+		case c == '-' || isDigit(c):
+			start := i
+			kind := tokenInt
+			i++
+			for i < len(src) && (isDigit(src[i]) || strings.ContainsRune(".eE+-", rune(src[i]))) {
+				if !isDigit(src[i]) {
+					kind = tokenFloat
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: kind, value: src[start:i], pos: start})
+		case c == '"':
+			start := i
+			i++
+			for i < len(src) && src[i] != '"' {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			value, err := strconv.Unquote(src[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d: %v", start, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parser is a recursive-descent parser over the token stream. Selection
+// sets and list and object values are nested levels for its limits.
+type parser struct {
+	tokens []token
+	pos    int
+	limits *querylimit.Counter
+}
+
+// Parse parses a GraphQL document. Fragments and directives are not supported.
+func Parse(src string) (*Document, error) {
+	return ParseWithLimits(src, querylimit.Limits{})
+}
+
+// ParseWithLimits parses a GraphQL document, giving up with an error
+// wrapping querylimit.ErrTooComplex as soon as it is longer, nests deeper
+// or has more fields than limits allow
+func ParseWithLimits(src string, limits querylimit.Limits) (*Document, error) {
+	if err := limits.CheckLength(src); err != nil {
+		return nil, err
+	}
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, limits: querylimit.NewCounter(limits)}
+	doc := &Document{}
+	for p.peek().kind != tokenEOF {
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		doc.Operations = append(doc.Operations, op)
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("document contains no operations")
+	}
+	return doc, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(punct string) error {
+	t := p.next()
+	if t.kind != tokenPunct || t.value != punct {
+		return fmt.Errorf("expected %q at position %d, got %q", punct, t.pos, t.value)
+	}
+	return nil
+}
+
+func (p *parser) peekPunct(punct string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.value == punct
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: "query"}
+
+	// Shorthand query: "{ ... }"
+	if !p.peekPunct("{") {
+		t := p.next()
+		if t.kind != tokenName || (t.value != "query" && t.value != "mutation") {
+			if t.value == "fragment" || t.value == "subscription" {
+				return nil, fmt.Errorf("%s operations are not supported", t.value)
+			}
+			return nil, fmt.Errorf("expected operation at position %d, got %q", t.pos, t.value)
+		}
+		op.Type = t.value
+		if p.peek().kind == tokenName {
+			op.Name = p.next().value
+		}
+		if p.peekPunct("(") {
+			if err := p.skipVariableDefinitions(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.SelectionSet = selections
+	return op, nil
+}
+
+// skipVariableDefinitions consumes "($a: Type!, $b: [T] = default)". Variable
+// values are taken from the request as-is, so only syntax is checked here.
+func (p *parser) skipVariableDefinitions() error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	depth := 1
+	for depth > 0 {
+		t := p.next()
+		switch {
+		case t.kind == tokenEOF:
+			return fmt.Errorf("unterminated variable definitions")
+		case t.kind == tokenPunct && t.value == "(":
+			depth++
+		case t.kind == tokenPunct && t.value == ")":
+			depth--
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseSelectionSet() ([]*Field, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	if err := p.limits.Enter(); err != nil {
+		return nil, err
+	}
+	defer p.limits.Leave()
+
+	var fields []*Field
+	for !p.peekPunct("}") {
+		if p.peekPunct("...") {
+			return nil, fmt.Errorf("fragments are not supported")
+		}
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	p.next()
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("selection set cannot be empty")
+	}
+	return fields, nil
+}
+
+func (p *parser) parseField() (*Field, error) {
+	t := p.next()
+	if t.kind != tokenName {
+		return nil, fmt.Errorf("expected field name at position %d, got %q", t.pos, t.value)
+	}
+
+	if err := p.limits.Node(); err != nil {
+		return nil, err
+	}
+
+	field := &Field{Name: t.value}
+	if p.peekPunct(":") {
+		p.next()
+		nameTok := p.next()
+		if nameTok.kind != tokenName {
+			return nil, fmt.Errorf("expected field name after alias at position %d", nameTok.pos)
+		}
+		field.Alias = field.Name
+		field.Name = nameTok.value
+	}
+
+	if p.peekPunct("(") {
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		field.Arguments = args
+	}
+
+	if p.peekPunct("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	if p.peekPunct("{") {
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		field.SelectionSet = selections
+	}
+
+	return field, nil
+}
+
+func (p *parser) parseArguments() (map[string]Value, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	args := map[string]Value{}
+	for !p.peekPunct(")") {
+		name := p.next()
+		if name.kind != tokenName {
+			return nil, fmt.Errorf("expected argument name at position %d, got %q", name.pos, name.value)
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		args[name.value] = value
+	}
+	p.next()
+
+	return args, nil
+}
+
+func (p *parser) parseValue() (Value, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return t.value, nil
+	case tokenInt:
+		n, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q at position %d", t.value, t.pos)
+		}
+		return n, nil
+	case tokenFloat:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %q at position %d", t.value, t.pos)
+		}
+		return f, nil
+	case tokenName:
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			return t.value, nil // enum value
+		}
+	case tokenPunct:
+		switch t.value {
+		case "$":
+			name := p.next()
+			if name.kind != tokenName {
+				return nil, fmt.Errorf("expected variable name at position %d", name.pos)
+			}
+			return Variable(name.value), nil
+		case "[":
+			if err := p.limits.Enter(); err != nil {
+				return nil, err
+			}
+			defer p.limits.Leave()
+			list := []Value{}
+			for !p.peekPunct("]") {
+				if p.peek().kind == tokenEOF {
+					return nil, fmt.Errorf("unterminated list")
+				}
+				v, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			p.next()
+			return list, nil
+		case "{":
+			if err := p.limits.Enter(); err != nil {
+				return nil, err
+			}
+			defer p.limits.Leave()
+			obj := map[string]Value{}
+			for !p.peekPunct("}") {
+				name := p.next()
+				if name.kind != tokenName {
+					return nil, fmt.Errorf("expected object field name at position %d", name.pos)
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				v, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				obj[name.value] = v
+			}
+			p.next()
+			return obj, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.value, t.pos)
+}
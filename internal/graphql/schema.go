@@ -0,0 +1,104 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"fis-playground/internal/models"
+)
+
+// schemaField describes a field of a generated object or input type
+type schemaField struct {
+	Name string
+	Type string
+}
+
+// modelFields derives GraphQL fields from a model struct's JSON tags, so the
+// schema always matches what the REST API serializes
+func modelFields(t reflect.Type) []schemaField {
+	var fields []schemaField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, schemaField{Name: name, Type: graphQLType(f.Type)})
+	}
+	return fields
+}
+
+// graphQLType maps a Go type to the corresponding GraphQL type name
+func graphQLType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "String"
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return graphQLType(t.Elem())
+	case reflect.String:
+		return "String"
+	case reflect.Bool:
+		return "Boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Int"
+	case reflect.Float32, reflect.Float64:
+		return "Float"
+	case reflect.Slice, reflect.Array:
+		return "[" + graphQLType(t.Elem()) + "]"
+	default:
+		return "JSON"
+	}
+}
+
+// itemFieldSet returns the set of selectable fields on the Item type
+func itemFieldSet() map[string]bool {
+	set := map[string]bool{}
+	for _, f := range modelFields(reflect.TypeOf(models.Item{})) {
+		set[f.Name] = true
+	}
+	return set
+}
+
+// writeType renders a type or input definition in SDL
+func writeType(b *strings.Builder, kind, name string, fields []schemaField) {
+	fmt.Fprintf(b, "%s %s {\n", kind, name)
+	for _, f := range fields {
+		fmt.Fprintf(b, "  %s: %s\n", f.Name, f.Type)
+	}
+	b.WriteString("}\n\n")
+}
+
+// SDL returns the schema in GraphQL Schema Definition Language
+func SDL() string {
+	var b strings.Builder
+	b.WriteString("scalar JSON\n\n")
+	writeType(&b, "type", "Item", modelFields(reflect.TypeOf(models.Item{})))
+	writeType(&b, "type", "ItemConnection", []schemaField{
+		{Name: "items", Type: "[Item!]!"},
+		{Name: "count", Type: "Int!"},
+		{Name: "hasMore", Type: "Boolean!"},
+		{Name: "nextCursor", Type: "String"},
+	})
+	writeType(&b, "input", "ItemFilter", []schemaField{
+		{Name: "status", Type: "String"},
+	})
+	writeType(&b, "input", "CreateItemInput", modelFields(reflect.TypeOf(models.CreateItemRequest{})))
+	writeType(&b, "input", "UpdateItemInput", modelFields(reflect.TypeOf(models.UpdateItemRequest{})))
+	writeType(&b, "type", "Query", []schemaField{
+		{Name: "item(id: ID!)", Type: "Item"},
+		{Name: "items(filter: ItemFilter, limit: Int, cursor: String)", Type: "ItemConnection!"},
+	})
+	writeType(&b, "type", "Mutation", []schemaField{
+		{Name: "createItem(input: CreateItemInput!)", Type: "Item!"},
+		{Name: "updateItem(id: ID!, input: UpdateItemInput!)", Type: "Item!"},
+		{Name: "deleteItem(id: ID!, generation: Int)", Type: "Boolean!"},
+	})
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"fis-playground/internal/logging"
+	"fis-playground/internal/models"
+)
+
+// createJobRetention is how long a finished job's outcome stays queryable
+const createJobRetention = time.Hour
+
+// createJobs queues item creates accepted with 202 and records their
+// outcome. Jobs live in memory, so their status is only known to the
+// process that accepted them.
+type createJobs struct {
+	queue chan createWork
+
+	mu   sync.Mutex
+	jobs map[string]*models.CreateJob
+}
+
+// createWork is a queued create and the context it runs in
+type createWork struct {
+	ctx   context.Context
+	jobID string
+	item  *models.Item
+	// release gives up the create's Idempotency-Key when it fails
+	release func()
+}
+
+// newCreateJobs starts workers that run queued creates through h
+func newCreateJobs(h *ItemHandler, workers, queueSize int) *createJobs {
+	jobs := &createJobs{
+		queue: make(chan createWork, queueSize),
+		jobs:  map[string]*models.CreateJob{},
+	}
+	for range workers {
+		go func() {
+			for work := range jobs.queue {
+				h.runCreateJob(work)
+			}
+		}()
+	}
+	return jobs
+}
+
+// enqueue records a pending job for item and queues it, reporting false
+// when the queue is full
+func (j *createJobs) enqueue(ctx context.Context, item *models.Item, release func()) (*models.CreateJob, bool) {
+	now := time.Now()
+	job := &models.CreateJob{
+		ID:        uuid.New().String(),
+		Status:    models.JobPending,
+		ItemID:    item.ID,
+		CreatedAt: now,
+		OwnerID:   item.OwnerID,
+		CreatedBy: item.CreatedBy,
+	}
+	job.StatusURL = "/jobs/" + job.ID
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.prune(now)
+
+	select {
+	case j.queue <- createWork{ctx: ctx, jobID: job.ID, item: item, release: release}:
+	default:
+		return nil, false
+	}
+	j.jobs[job.ID] = job
+	snapshot := *job
+	return &snapshot, true
+}
+
+// finish records a job's outcome
+func (j *createJobs) finish(id string, apiErr *APIError) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job, ok := j.jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	job.CompletedAt = &now
+	job.Status = models.JobSucceeded
+	if apiErr != nil {
+		job.Status = models.JobFailed
+		job.Error = errorInfo(apiErr)
+	}
+}
+
+// get returns a copy of the job, if it is known
+func (j *createJobs) get(id string) (*models.CreateJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job, ok := j.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *job
+	return &snapshot, true
+}
+
+// prune forgets jobs that finished more than createJobRetention ago. The
+// caller holds j.mu.
+func (j *createJobs) prune(now time.Time) {
+	for id, job := range j.jobs {
+		if job.CompletedAt != nil && now.Sub(*job.CompletedAt) > createJobRetention {
+			delete(j.jobs, id)
+		}
+	}
+}
+
+// runCreateJob saves a queued item and publishes it, as a synchronous
+// create would
+func (h *ItemHandler) runCreateJob(work createWork) {
+	err := h.saveCreated(work.ctx, work.item)
+	if err != nil {
+		work.release()
+		apiErr := MapRepositoryError(err)
+		logging.FromContext(work.ctx).Warn("Async create failed", "job_id", work.jobID, "item_id", work.item.ID, "code", apiErr.Code, "cause", err)
+		h.createJobs.finish(work.jobID, apiErr)
+		return
+	}
+	h.createJobs.finish(work.jobID, nil)
+}
+
+// acceptCreate queues a validated item for an asynchronous create and
+// responds 202 with the job, whose status URL is also the Location.
+// release is called when the create fails or can't be queued.
+func (h *ItemHandler) acceptCreate(w http.ResponseWriter, r *http.Request, item *models.Item, release func()) {
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+
+	// The create outlives the request, but keeps its logger and request ID
+	job, ok := h.createJobs.enqueue(context.WithoutCancel(r.Context()), item, release)
+	if !ok {
+		release()
+		apiErr := NewSystemError(CodeServiceUnavailable, "Create queue is full", nil)
+		apiErr.Details = "Too many creates are waiting to be processed; retry after the Retry-After delay"
+		apiErr.RetryAfter = DefaultRetryAfter
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	w.Header().Set("Location", job.StatusURL)
+	writeJSONResponse(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Data:    job,
+	})
+}
+
+// GetCreateJob handles GET /jobs/{id} requests, reporting the status of a
+// create accepted asynchronously. Another caller's job is not found.
+func (h *ItemHandler) GetCreateJob(w http.ResponseWriter, r *http.Request) {
+	jobID := chi.URLParam(r, "id")
+	if jobID == "" {
+		WriteMissingParameterErrorResponse(w, r, "Job ID")
+		return
+	}
+
+	var job *models.CreateJob
+	ok := false
+	if h.createJobs != nil {
+		job, ok = h.createJobs.get(jobID)
+	}
+	if !ok || !canReadJob(r.Context(), job) {
+		WriteErrorResponse(w, r, NewNotFoundError("Job", jobID))
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    job,
+	})
+}
+
+// canReadJob reports whether the caller may see a job. A job accepted for
+// a user is only shown to that user, and one accepted for a principal
+// only to that principal; jobs accepted anonymously are open to everyone,
+// and admins see every job.
+func canReadJob(ctx context.Context, job *models.CreateJob) bool {
+	if IsAdmin(ctx) {
+		return true
+	}
+	if job.OwnerID != "" {
+		user, _ := UserFromContext(ctx)
+		return user == job.OwnerID
+	}
+	return job.CreatedBy == "" || PrincipalFromContext(ctx) == job.CreatedBy
+}
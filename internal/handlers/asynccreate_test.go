@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// createAsync posts body to CreateItem, expecting the create to be accepted
+func createAsync(t *testing.T, handler *ItemHandler, body string) *models.CreateJob {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, httptest.NewRequest("POST", "/items", strings.NewReader(body)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	var response struct {
+		Data models.CreateJob `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if location := w.Header().Get("Location"); location != response.Data.StatusURL || location != "/jobs/"+response.Data.ID {
+		t.Errorf("Expected Location to be the job's status URL, got %q and %q", location, response.Data.StatusURL)
+	}
+	return &response.Data
+}
+
+// waitForJob polls GET /jobs/{id} until the job finishes
+func waitForJob(t *testing.T, handler *ItemHandler, id string) *models.CreateJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		w := itemRequest(handler.GetCreateJob, "GET", "/jobs/"+id, id)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected the job to be trackable, got %d: %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.CreateJob `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Data.Status != models.JobPending {
+			return &response.Data
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected job %s to finish, still %s", id, response.Data.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCreateItem_Async(t *testing.T) {
+	t.Setenv("ASYNC_CREATES", "true")
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	job := createAsync(t, handler, `{"name":"Item","description":"Description"}`)
+	if job.ID == "" || job.ItemID == "" {
+		t.Fatalf("Expected the job and item IDs up front, got %+v", job)
+	}
+	if job.Status != models.JobPending {
+		t.Errorf("Expected a pending job, got %q", job.Status)
+	}
+
+	finished := waitForJob(t, handler, job.ID)
+	if finished.Status != models.JobSucceeded || finished.CompletedAt == nil || finished.Error != nil {
+		t.Fatalf("Expected the job to succeed, got %+v", finished)
+	}
+	item, err := repo.GetItem(context.Background(), job.ItemID, nil)
+	if err != nil || item.Name != "Item" {
+		t.Errorf("Expected the item to be saved, got %+v (%v)", item, err)
+	}
+
+	// Repository failures surface on the job rather than the 202
+	failed := waitForJob(t, handler, createAsync(t, handler, `{"id":"`+job.ItemID+`","name":"Again","description":"Description"}`).ID)
+	if failed.Status != models.JobFailed || failed.Error == nil || failed.Error.Code != string(CodeAlreadyExists) {
+		t.Errorf("Expected the duplicate create to fail with ALREADY_EXISTS, got %+v", failed)
+	}
+
+	// Invalid requests are still rejected before they are queued
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, httptest.NewRequest("POST", "/items", strings.NewReader(`{"description":"No name"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an invalid create to be rejected with 400, got %d", w.Code)
+	}
+}
+
+func TestCreateItem_AsyncQueueFull(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+	handler.createJobs = newCreateJobs(handler, 0, 0)
+
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a full queue to return 503, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header")
+	}
+}
+
+func TestGetCreateJob_Unknown(t *testing.T) {
+	t.Setenv("ASYNC_CREATES", "true")
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	if w := itemRequest(handler.GetCreateJob, "GET", "/jobs/missing", "missing"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected an unknown job to be not found, got %d", w.Code)
+	}
+}
+
+func TestCreateItem_SyncByDefault(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`)))
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected creates to be synchronous by default, got %d", w.Code)
+	}
+}
+
+func TestGetCreateJob_OtherCallersJob(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+	// Without workers the job stays queued
+	handler.createJobs = newCreateJobs(handler, 0, 1)
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`))
+	req = req.WithContext(WithPrincipal(WithUser(req.Context(), "alice"), "alice"))
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	var response struct {
+		Data models.CreateJob `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	jobID := response.Data.ID
+
+	getJob := func(ctx context.Context) int {
+		req := httptest.NewRequest("GET", "/jobs/"+jobID, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", jobID)
+		req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.GetCreateJob(w, req)
+		return w.Code
+	}
+
+	if code := getJob(WithPrincipal(WithUser(context.Background(), "alice"), "alice")); code != http.StatusOK {
+		t.Errorf("Expected alice to see her job, got %d", code)
+	}
+	if code := getJob(WithPrincipal(WithUser(context.Background(), "bob"), "bob")); code != http.StatusNotFound {
+		t.Errorf("Expected another user's job to be not found, got %d", code)
+	}
+	if code := getJob(context.Background()); code != http.StatusNotFound {
+		t.Errorf("Expected an anonymous caller not to see the job, got %d", code)
+	}
+	if code := getJob(WithAdmin(context.Background())); code != http.StatusOK {
+		t.Errorf("Expected an admin to see the job, got %d", code)
+	}
+}
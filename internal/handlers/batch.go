@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"fis-playground/internal/emf"
+	"fis-playground/internal/events"
+	"fis-playground/internal/models"
+)
+
+// batchCreateResult reports the outcome for one element of a batch create
+type batchCreateResult struct {
+	Index  int               `json:"index"`
+	Status string            `json:"status"`
+	Item   interface{}       `json:"item,omitempty"`
+	Error  *models.ErrorInfo `json:"error,omitempty"`
+}
+
+// Batch create result statuses
+const (
+	batchStatusCreated = "created"
+	batchStatusFailed  = "failed"
+)
+
+// BatchCreateItems handles POST /items/batch requests. Every element is
+// validated on its own and only the valid ones are written, so the
+// response carries a result per element, in request order. It is 201 when
+// every item was created and 207 when any failed.
+func (h *ItemHandler) BatchCreateItems(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var batchReq models.BatchCreateRequest
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&batchReq); err != nil {
+		WriteJSONParseErrorResponse(w, r, err)
+		return
+	}
+
+	// Validate request
+	if err := batchReq.Validate(); err != nil {
+		WriteValidationErrorResponse(w, r, err)
+		return
+	}
+	if maxItems := h.config.BatchMaxItems; maxItems > 0 && len(batchReq.Items) > maxItems {
+		WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Too many items in batch",
+			fmt.Sprintf("A batch may contain at most %d items, got %d", maxItems, len(batchReq.Items))))
+		return
+	}
+
+	results := make([]batchCreateResult, len(batchReq.Items))
+	var items []*models.Item
+	var indexes []int
+	for i := range batchReq.Items {
+		results[i].Index = i
+		if err := models.ValidateBatchItem(&batchReq.Items[i]); err != nil {
+			results[i].Status = batchStatusFailed
+			results[i].Error = errorInfo(MapValidationError(err))
+			continue
+		}
+		item := h.newItem(r.Context(), &batchReq.Items[i])
+		items = append(items, item)
+		indexes = append(indexes, i)
+	}
+
+	// Every item about to be created is charged to the caller's budget
+	if apiErr := ChargeCreates(r.Context(), len(items)); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	if len(items) > 0 {
+		errs, err := h.repo.BatchCreateItems(r.Context(), items)
+		if err != nil {
+			WriteRepositoryErrorResponse(w, r, err)
+			return
+		}
+		for j, item := range items {
+			result := &results[indexes[j]]
+			if errs[j] != nil {
+				result.Status = batchStatusFailed
+				result.Error = errorInfo(MapRepositoryError(errs[j]))
+				continue
+			}
+			result.Status = batchStatusCreated
+			result.Item = h.itemView(r, item)
+			h.publish(events.ItemCreated, item.ID, item)
+		}
+	}
+
+	created := 0
+	for _, result := range results {
+		if result.Status == batchStatusCreated {
+			created++
+		}
+	}
+	if created > 0 {
+		emf.FromContext(r.Context()).Count(emf.ItemsCreated, float64(created), nil)
+	}
+	status := http.StatusCreated
+	if created < len(results) {
+		status = http.StatusMultiStatus
+	}
+
+	writeJSONResponse(w, status, models.APIResponse{
+		Success: created > 0,
+		Data: batchCreateResponse{
+			Created: created,
+			Failed:  len(results) - created,
+			Results: results,
+		},
+	})
+}
+
+// BatchGetItems handles POST /items/batch-get requests, returning the
+// items found for the requested IDs and the IDs that were not found.
+// Repeated IDs are looked up once, and items outside their visibility
+// window or, when items are scoped to their owner, owned by another user
+// are reported as not found.
+func (h *ItemHandler) BatchGetItems(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var getReq models.BatchGetRequest
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&getReq); err != nil {
+		WriteJSONParseErrorResponse(w, r, err)
+		return
+	}
+
+	// Validate request
+	if err := getReq.Validate(); err != nil {
+		WriteValidationErrorResponse(w, r, err)
+		return
+	}
+	ids := getReq.UniqueIDs()
+	if maxItems := h.config.BatchMaxItems; maxItems > 0 && len(ids) > maxItems {
+		WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Too many IDs in batch",
+			fmt.Sprintf("A batch may contain at most %d IDs, got %d", maxItems, len(ids))))
+		return
+	}
+
+	items, err := h.repo.BatchGetItems(r.Context(), ids)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+	items = models.ExcludeDeleted(models.VisibleItems(items, time.Now()))
+	items = slices.DeleteFunc(items, func(item models.Item) bool {
+		return h.authorizeItemOwner(r.Context(), &item) != nil
+	})
+
+	found := make(map[string]bool, len(items))
+	for _, item := range items {
+		found[item.ID] = true
+	}
+	missing := []string{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	// Return success response
+	writeJSONResponse(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: batchGetResponse{
+			Items:   h.itemViews(r, items),
+			Missing: missing,
+		},
+	})
+}
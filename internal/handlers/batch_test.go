@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+func batchCreate(handler *ItemHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/items/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.BatchCreateItems(w, req)
+	return w
+}
+
+func TestBatchCreateItems_MixedValidAndInvalid(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	w := batchCreate(handler, `{"items":[
+		{"name":"First","description":"Valid item"},
+		{"name":"","description":"Missing name"},
+		{"id":"chosen-id","name":"Third","description":"Client ID"},
+		{"name":"Fourth","description":"Valid item"}
+	]}`)
+
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusMultiStatus, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data struct {
+			Created int `json:"created"`
+			Failed  int `json:"failed"`
+			Results []struct {
+				Index  int    `json:"index"`
+				Status string `json:"status"`
+				Item   *struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"item"`
+				Error *struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			} `json:"results"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Data.Created != 2 || response.Data.Failed != 2 || len(response.Data.Results) != 4 {
+		t.Fatalf("Expected 2 created and 2 failed of 4 results, got %+v", response.Data)
+	}
+	expected := []struct {
+		status string
+		code   string
+	}{
+		{batchStatusCreated, ""},
+		{batchStatusFailed, string(CodeMissingField)},
+		{batchStatusFailed, string(CodeInvalidValue)},
+		{batchStatusCreated, ""},
+	}
+	for i, result := range response.Data.Results {
+		if result.Index != i || result.Status != expected[i].status {
+			t.Errorf("Result %d: expected index %d status %s, got %+v", i, i, expected[i].status, result)
+			continue
+		}
+		if expected[i].code != "" {
+			if result.Error == nil || result.Error.Code != expected[i].code || result.Item != nil {
+				t.Errorf("Result %d: expected error %s without an item, got %+v", i, expected[i].code, result)
+			}
+			continue
+		}
+		if result.Error != nil || result.Item == nil || result.Item.ID == "" {
+			t.Errorf("Result %d: expected a created item, got %+v", i, result)
+			continue
+		}
+		if _, err := repo.GetItem(context.Background(), result.Item.ID, nil); err != nil {
+			t.Errorf("Result %d: expected item %s to be stored, got %v", i, result.Item.ID, err)
+		}
+	}
+
+	if _, err := repo.GetItem(context.Background(), "chosen-id", nil); err == nil {
+		t.Error("Expected the item with a client ID not to be created")
+	}
+}
+
+func TestBatchCreateItems_AllCreated(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	w := batchCreate(handler, `{"items":[{"name":"A","description":"One"},{"name":"B","description":"Two"}]}`)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+}
+
+func TestBatchCreateItems_ChargesEachValidItem(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	var charged []int
+	budget := func(n int) time.Duration {
+		charged = append(charged, n)
+		if n > 2 {
+			return time.Second
+		}
+		return 0
+	}
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/items/batch", strings.NewReader(body))
+		req = req.WithContext(WithCreateBudget(req.Context(), budget))
+		w := httptest.NewRecorder()
+		handler.BatchCreateItems(w, req)
+		return w
+	}
+
+	// The invalid element is never created, so it isn't charged
+	if w := send(`{"items":[{"name":"A","description":"One"},{"name":"","description":"Invalid"},{"name":"B","description":"Two"}]}`); w.Code != http.StatusMultiStatus {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusMultiStatus, w.Code, w.Body.String())
+	}
+	if w := send(`{"items":[{"name":"C","description":"One"},{"name":"D","description":"Two"},{"name":"E","description":"Three"}]}`); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected a batch over the budget to be refused, got %d", w.Code)
+	}
+	if !slices.Equal(charged, []int{2, 3}) {
+		t.Errorf("Expected charges of 2 and 3 creates, got %v", charged)
+	}
+}
+
+func TestBatchCreateItems_RejectsBatch(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	handler.config.BatchMaxItems = 2
+
+	oversized := make([]string, 3)
+	for i := range oversized {
+		oversized[i] = fmt.Sprintf(`{"name":"Item %d","description":"Description"}`, i)
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode ErrorCode
+	}{
+		{"Too many items", `{"items":[` + strings.Join(oversized, ",") + `]}`, CodeInvalidValue},
+		{"No items", `{"items":[]}`, CodeMissingField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := batchCreate(handler, tt.body)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			var response struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error.Code != string(tt.expectedCode) {
+				t.Errorf("Expected error code %s, got %s", tt.expectedCode, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestBodyTooLarge_BatchAndImport(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	handler.config.MaxBodyBytes = 1024
+
+	item := `{"name":"Item","description":"` + strings.Repeat("x", 2048) + `"}`
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		handle http.HandlerFunc
+	}{
+		{"Batch create", "/items/batch", `{"items":[` + item + `]}`, handler.BatchCreateItems},
+		{"Batch get", "/items/batch-get", `{"ids":["` + strings.Repeat("x", 2048) + `"]}`, handler.BatchGetItems},
+		{"Import", "/admin/import", item, handler.ImportItem},
+		{"Bulk tag", "/items/bulk-tag", `{"add_tags":["` + strings.Repeat("x", 2048) + `"]}`, handler.BulkTagItems},
+		{"Bulk delete", "/items/bulk-delete-by-filter", `{"filter":{"name_contains":"` + strings.Repeat("x", 2048) + `"}}`, handler.BulkDeleteByFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			tt.handle(w, req)
+
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+			}
+			var response models.APIResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error.Code != string(CodePayloadTooLarge) {
+				t.Errorf("Expected error code %s, got %s", CodePayloadTooLarge, response.Error.Code)
+			}
+		})
+	}
+}
+
+func batchGet(handler *ItemHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/items/batch-get", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.BatchGetItems(w, req)
+	return w
+}
+
+func TestBatchGetItems_FoundAndMissing(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	var ids []string
+	for _, name := range []string{"First", "Second"} {
+		item := models.NewItem(name, "Description")
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+	handler := NewItemHandler(repo)
+
+	body := fmt.Sprintf(`{"ids":[%q,"missing-1",%q,%q,"missing-1"]}`, ids[1], ids[0], ids[1])
+	w := batchGet(handler, body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data struct {
+			Items []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+			Missing []string `json:"missing"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Data.Items) != 2 || response.Data.Items[0].ID != ids[1] || response.Data.Items[1].ID != ids[0] {
+		t.Errorf("Expected items [%s %s] once each in request order, got %+v", ids[1], ids[0], response.Data.Items)
+	}
+	if len(response.Data.Missing) != 1 || response.Data.Missing[0] != "missing-1" {
+		t.Errorf("Expected missing [missing-1], got %v", response.Data.Missing)
+	}
+}
+
+func TestBatchGetItems_RejectsEmptyIDs(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	for _, body := range []string{`{"ids":[]}`, `{"ids":["a",""]}`} {
+		w := batchGet(handler, body)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d for %s, got %d", http.StatusBadRequest, body, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), string(CodeMissingField)) {
+			t.Errorf("Expected %s for %s, got %s", CodeMissingField, body, w.Body.String())
+		}
+	}
+}
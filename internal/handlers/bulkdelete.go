@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"fis-playground/internal/emf"
+	"fis-playground/internal/models"
+)
+
+// BulkDeleteConfirmTTL is how long a bulk delete confirm token stays valid
+const BulkDeleteConfirmTTL = 5 * time.Minute
+
+// Confirm token errors
+var (
+	ErrInvalidConfirmToken = errors.New("invalid confirm token")
+	ErrExpiredConfirmToken = errors.New("confirm token has expired")
+)
+
+// bulkDeleteConfirmation is the signed content of a confirm token: the
+// previewed filter and match count
+type bulkDeleteConfirmation struct {
+	Filter    models.ItemFilter `json:"f"`
+	Count     int               `json:"n"`
+	ExpiresAt int64             `json:"exp"`
+}
+
+// confirmTokenContext separates confirm token signatures from page token
+// signatures made with the same secret
+const confirmTokenContext = "bulk-delete-confirm:"
+
+// encodeConfirmToken signs a confirmation into an opaque token
+func (h *ItemHandler) encodeConfirmToken(confirmation bulkDeleteConfirmation) (string, error) {
+	data, err := json.Marshal(confirmation)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal confirmation: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + h.signConfirmToken(encoded), nil
+}
+
+// decodeConfirmToken verifies and parses a token produced by encodeConfirmToken
+func (h *ItemHandler) decodeConfirmToken(token string, now time.Time) (*bulkDeleteConfirmation, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(h.signConfirmToken(encoded))) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidConfirmToken)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfirmToken, err)
+	}
+	var confirmation bulkDeleteConfirmation
+	if err := json.Unmarshal(data, &confirmation); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfirmToken, err)
+	}
+	if now.Unix() > confirmation.ExpiresAt {
+		return nil, ErrExpiredConfirmToken
+	}
+	return &confirmation, nil
+}
+
+// signConfirmToken computes the URL-safe HMAC-SHA256 signature of the encoded confirmation
+func (h *ItemHandler) signConfirmToken(encoded string) string {
+	mac := hmac.New(sha256.New, h.config.PageTokenSecret)
+	mac.Write([]byte(confirmTokenContext + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// BulkDeleteByFilter handles POST /items/bulk-delete-by-filter requests.
+// Without a confirm_token it only counts the matching items and returns a
+// token confirming that preview; sending the token back with the same
+// filter performs the deletion. If the number of matches changed since the
+// preview, the deletion is refused so it can be previewed again.
+func (h *ItemHandler) BulkDeleteByFilter(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var deleteReq models.BulkDeleteRequest
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&deleteReq); err != nil {
+		WriteJSONParseErrorResponse(w, r, err)
+		return
+	}
+
+	// Validate request
+	if err := deleteReq.Validate(); err != nil {
+		WriteValidationErrorResponse(w, r, err)
+		return
+	}
+
+	var confirmation *bulkDeleteConfirmation
+	if deleteReq.ConfirmToken != "" {
+		var err error
+		confirmation, err = h.decodeConfirmToken(deleteReq.ConfirmToken, time.Now())
+		if errors.Is(err, ErrExpiredConfirmToken) {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Confirm token has expired", "Preview the deletion again for a new token"))
+			return
+		}
+		if err != nil {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidFormat, "Invalid confirm token", "The confirm_token is malformed or has been modified"))
+			return
+		}
+		if confirmation.Filter != deleteReq.Filter {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Confirm token does not match the filter", "The token confirms a different filter"))
+			return
+		}
+	}
+
+	matched, err := h.repo.DeleteItemsByFilter(r.Context(), deleteReq.Filter, true)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	// Preview: report what would be deleted with a token confirming it
+	if confirmation == nil {
+		expiresAt := time.Now().Add(BulkDeleteConfirmTTL)
+		token, err := h.encodeConfirmToken(bulkDeleteConfirmation{
+			Filter:    deleteReq.Filter,
+			Count:     matched,
+			ExpiresAt: expiresAt.Unix(),
+		})
+		if err != nil {
+			WriteInternalErrorResponse(w, r, err)
+			return
+		}
+
+		writeJSONResponse(w, http.StatusOK, models.APIResponse{
+			Success: true,
+			Data: bulkDeletePreviewResponse{
+				WouldDelete:  matched,
+				ConfirmToken: token,
+				ExpiresAt:    expiresAt.UTC().Format(time.RFC3339),
+			},
+		})
+		return
+	}
+
+	if matched != confirmation.Count {
+		WriteErrorResponse(w, r, &APIError{
+			Type:       ErrorTypeConflict,
+			Code:       CodePreconditionFailed,
+			Message:    "Matching items changed since the preview",
+			Details:    fmt.Sprintf("The preview matched %d items but %d match now; preview the deletion again", confirmation.Count, matched),
+			StatusCode: http.StatusConflict,
+		})
+		return
+	}
+
+	deleted, err := h.repo.DeleteItemsByFilter(r.Context(), deleteReq.Filter, false)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+	if deleted > 0 {
+		emf.FromContext(r.Context()).Count(emf.ItemsDeleted, float64(deleted), nil)
+	}
+
+	// Return success response
+	writeJSONResponse(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: bulkDeleteResponse{
+			Deleted: deleted,
+		},
+	})
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+func bulkDelete(handler *ItemHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/items/bulk-delete-by-filter", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.BulkDeleteByFilter(w, req)
+	return w
+}
+
+// seedStatuses creates one item per status and returns the repository
+func seedStatuses(t *testing.T, statuses ...string) *repository.MemoryRepository {
+	repo := repository.NewMemoryRepository()
+	for _, status := range statuses {
+		item := models.NewItem("Item", "Description")
+		item.Status = status
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	return repo
+}
+
+// previewBulkDelete previews deleting inactive items and returns the confirm token
+func previewBulkDelete(t *testing.T, handler *ItemHandler, expectedCount int) string {
+	w := bulkDelete(handler, `{"filter":{"status":"inactive"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected preview status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data struct {
+			WouldDelete  int    `json:"would_delete"`
+			ConfirmToken string `json:"confirm_token"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.WouldDelete != expectedCount || response.Data.ConfirmToken == "" {
+		t.Fatalf("Expected a preview of %d items with a token, got %+v", expectedCount, response.Data)
+	}
+	return response.Data.ConfirmToken
+}
+
+func countItems(t *testing.T, repo repository.ItemRepository) int {
+	result, err := repo.ListItems(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	return len(result.Items)
+}
+
+func TestBulkDeleteByFilter_PreviewThenConfirm(t *testing.T) {
+	repo := seedStatuses(t, "active", "inactive", "inactive", "pending")
+	handler := NewItemHandler(repo)
+
+	token := previewBulkDelete(t, handler, 2)
+	if count := countItems(t, repo); count != 4 {
+		t.Fatalf("Expected the preview to delete nothing, %d items remain", count)
+	}
+
+	w := bulkDelete(handler, `{"filter":{"status":"inactive"},"confirm_token":"`+token+`"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected confirm status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"deleted":2`) {
+		t.Errorf("Expected 2 items deleted, got %s", w.Body.String())
+	}
+
+	result, _ := repo.ListItems(context.Background(), nil)
+	if len(result.Items) != 2 {
+		t.Fatalf("Expected 2 items to remain, got %d", len(result.Items))
+	}
+	for _, item := range result.Items {
+		if item.Status == "inactive" {
+			t.Errorf("Expected inactive items to be deleted, found %s", item.ID)
+		}
+	}
+}
+
+func TestBulkDeleteByFilter_RejectsBadTokens(t *testing.T) {
+	repo := seedStatuses(t, "active", "inactive")
+	handler := NewItemHandler(repo)
+	token := previewBulkDelete(t, handler, 1)
+
+	expired, err := handler.encodeConfirmToken(bulkDeleteConfirmation{
+		Filter:    models.ItemFilter{Status: "inactive"},
+		Count:     1,
+		ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode token: %v", err)
+	}
+	pageToken, _ := handler.pageTokens.Encode(map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: "item-1"},
+	})
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode ErrorCode
+	}{
+		{"Tampered token", `{"filter":{"status":"inactive"},"confirm_token":"x` + token + `"}`, CodeInvalidFormat},
+		{"Page token", `{"filter":{"status":"inactive"},"confirm_token":"` + pageToken + `"}`, CodeInvalidFormat},
+		{"Expired token", `{"filter":{"status":"inactive"},"confirm_token":"` + expired + `"}`, CodeInvalidValue},
+		{"Different filter", `{"filter":{"status":"active"},"confirm_token":"` + token + `"}`, CodeInvalidValue},
+		{"Missing filter", `{"confirm_token":"` + token + `"}`, CodeMissingField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := bulkDelete(handler, tt.body)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+			var response models.APIResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error.Code != string(tt.expectedCode) {
+				t.Errorf("Expected error code '%s', got '%s'", tt.expectedCode, response.Error.Code)
+			}
+		})
+	}
+
+	if count := countItems(t, repo); count != 2 {
+		t.Errorf("Expected no items deleted, %d remain", count)
+	}
+}
+
+func TestBulkDeleteByFilter_MatchesChangedSincePreview(t *testing.T) {
+	repo := seedStatuses(t, "inactive")
+	handler := NewItemHandler(repo)
+	token := previewBulkDelete(t, handler, 1)
+
+	item := models.NewItem("Late", "Description")
+	item.Status = "inactive"
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+
+	w := bulkDelete(handler, `{"filter":{"status":"inactive"},"confirm_token":"`+token+`"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+	if count := countItems(t, repo); count != 2 {
+		t.Errorf("Expected no items deleted, %d remain", count)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"fis-playground/internal/models"
+)
+
+// ErrorCatalogEntry describes one error code clients may receive
+type ErrorCatalogEntry struct {
+	Code        ErrorCode `json:"code"`
+	Type        ErrorType `json:"type"`
+	Status      int       `json:"status"`
+	Description string    `json:"description"`
+}
+
+// ErrorCatalog lists every ErrorCode with its type and default HTTP status.
+// A test checks it against the constants in errors.go, so a new code can't
+// be added without describing it here.
+var ErrorCatalog = []ErrorCatalogEntry{
+	// Validation errors
+	{CodeInvalidRequest, ErrorTypeValidation, http.StatusBadRequest, "The request is invalid; details explain why"},
+	{CodeMissingField, ErrorTypeValidation, http.StatusBadRequest, "A required field or parameter is missing or empty"},
+	{CodeInvalidFormat, ErrorTypeValidation, http.StatusBadRequest, "A value, such as the JSON body, a timestamp or a pagination token, is malformed"},
+	{CodeValueTooLong, ErrorTypeValidation, http.StatusBadRequest, "A field exceeds its maximum length"},
+	{CodeInvalidValue, ErrorTypeValidation, http.StatusBadRequest, "A value is well-formed but not allowed, such as an unknown status"},
+	{CodeHeadersTooLarge, ErrorTypeValidation, http.StatusRequestHeaderFieldsTooLarge, "The request headers exceed the configured size or count limits"},
+	{CodePayloadTooLarge, ErrorTypeValidation, http.StatusRequestEntityTooLarge, "The request body exceeds the configured size limit"},
+	{CodeMethodNotAllowed, ErrorTypeValidation, http.StatusMethodNotAllowed, "The route does not support the request's method; the Allow header lists the methods it does"},
+
+	// Resource errors
+	{CodeNotFound, ErrorTypeNotFound, http.StatusNotFound, "The item, or the route, does not exist"},
+	{CodeAlreadyExists, ErrorTypeConflict, http.StatusConflict, "An item with this ID, or with this name when NAME_UNIQUENESS is set, already exists"},
+	{CodeLimitReached, ErrorTypeConflict, http.StatusConflict, "The table holds the maximum number of items"},
+	{CodeStaleGeneration, ErrorTypeConflict, http.StatusConflict, "The item changed since the given generation was read; re-read it and retry"},
+	{CodeVersionConflict, ErrorTypeConflict, http.StatusConflict, "The item changed since the given version was read; re-read it and retry"},
+	{CodePreconditionFailed, ErrorTypeConflict, http.StatusConflict, "The item does not meet a condition the request requires, such as a status; 412 when an If-Match ETag is stale"},
+	{CodeLeaseHeld, ErrorTypeConflict, http.StatusConflict, "Another principal holds an unexpired lease on the item; details name the holder and when the lease ends"},
+	{CodeIdempotencyReused, ErrorTypeConflict, http.StatusConflict, "The Idempotency-Key was used for a create with a different body, or that create hasn't finished yet"},
+
+	// Database errors
+	{CodeDatabaseError, ErrorTypeDatabase, http.StatusInternalServerError, "A database operation failed"},
+	{CodeConnectionError, ErrorTypeDatabase, http.StatusServiceUnavailable, "The database could not be reached"},
+	{CodeOperationFailed, ErrorTypeDatabase, http.StatusInternalServerError, "A database operation could not be completed"},
+	{CodeThroughputExceeded, ErrorTypeDatabase, http.StatusTooManyRequests, "The database is throttling requests; retry after a brief delay"},
+
+	// System errors
+	{CodeInternalError, ErrorTypeSystem, http.StatusInternalServerError, "An unexpected error occurred"},
+	{CodeServiceUnavailable, ErrorTypeSystem, http.StatusServiceUnavailable, "The service is temporarily unavailable"},
+	{CodeTimeout, ErrorTypeSystem, http.StatusRequestTimeout, "The request took too long to complete"},
+
+	// Authentication errors
+	{CodeUnauthorized, ErrorTypeAuth, http.StatusUnauthorized, "Credentials, such as the admin key, are missing or wrong"},
+	{CodeForbidden, ErrorTypeAuth, http.StatusForbidden, "The request is not allowed, even with valid credentials"},
+
+	// Rate limiting errors
+	{CodeRateLimitExceeded, ErrorTypeRate, http.StatusTooManyRequests, "Too many requests; retry later"},
+}
+
+// ListErrorCodes handles GET /errors requests, returning the error catalog
+func (h *ItemHandler) ListErrorCodes(w http.ResponseWriter, r *http.Request) {
+	response := models.APIResponse{
+		Success: true,
+		Data:    ErrorCatalog,
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// declaredErrorCodes parses errors.go for the values of its ErrorCode constants
+func declaredErrorCodes(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "errors.go", nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse errors.go: %v", err)
+	}
+
+	var codes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		if ident, ok := spec.Type.(*ast.Ident); !ok || ident.Name != "ErrorCode" {
+			return true
+		}
+		for _, value := range spec.Values {
+			if lit, ok := value.(*ast.BasicLit); ok {
+				code, _ := strconv.Unquote(lit.Value)
+				codes = append(codes, code)
+			}
+		}
+		return true
+	})
+	return codes
+}
+
+func TestErrorCatalog_CoversEveryCode(t *testing.T) {
+	catalogued := map[ErrorCode]int{}
+	for _, entry := range ErrorCatalog {
+		catalogued[entry.Code]++
+		if entry.Description == "" || entry.Type == "" || entry.Status == 0 {
+			t.Errorf("Expected a complete entry for %s, got %+v", entry.Code, entry)
+		}
+	}
+
+	codes := declaredErrorCodes(t)
+	if len(codes) == 0 {
+		t.Fatal("Expected to find ErrorCode constants in errors.go")
+	}
+	for _, code := range codes {
+		if catalogued[ErrorCode(code)] != 1 {
+			t.Errorf("Expected %s to be catalogued once, found %d entries", code, catalogued[ErrorCode(code)])
+		}
+	}
+	if len(ErrorCatalog) != len(codes) {
+		t.Errorf("Expected %d catalog entries, got %d", len(codes), len(ErrorCatalog))
+	}
+}
+
+func TestListErrorCodes(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	req := httptest.NewRequest("GET", "/errors", nil)
+	w := httptest.NewRecorder()
+	handler.ListErrorCodes(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Data []ErrorCatalogEntry `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	byCode := map[ErrorCode]ErrorCatalogEntry{}
+	for _, entry := range response.Data {
+		byCode[entry.Code] = entry
+	}
+	expected := map[ErrorCode]struct {
+		errorType ErrorType
+		status    int
+	}{
+		CodeNotFound:           {ErrorTypeNotFound, http.StatusNotFound},
+		CodeMissingField:       {ErrorTypeValidation, http.StatusBadRequest},
+		CodeAlreadyExists:      {ErrorTypeConflict, http.StatusConflict},
+		CodeThroughputExceeded: {ErrorTypeDatabase, http.StatusTooManyRequests},
+	}
+	for code, want := range expected {
+		got, ok := byCode[code]
+		if !ok {
+			t.Errorf("Expected %s in the catalog", code)
+			continue
+		}
+		if got.Type != want.errorType || got.Status != want.status {
+			t.Errorf("Expected %s to be %s/%d, got %s/%d", code, want.errorType, want.status, got.Type, got.Status)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"fis-playground/internal/models"
+)
+
+// HandlerConfig holds configuration for the HTTP handlers
+type HandlerConfig struct {
+	// StatusLabels maps a language tag ("" for the default) to the
+	// human-readable label for each status value
+	StatusLabels map[string]map[string]string
+
+	// PageTokenSecret signs pagination tokens; PageTokenTTL bounds their age
+	PageTokenSecret []byte
+	PageTokenTTL    time.Duration
+
+	// DeleteRequireStatus, when set, only allows deleting items with this
+	// status, enforcing a lifecycle such as deactivate-then-delete
+	DeleteRequireStatus string
+
+	// DeprecatedFields maps deprecated response fields to their sunset date.
+	// Requesting one via ?fields= adds Deprecation and Sunset headers and a
+	// warning to the response.
+	DeprecatedFields map[string]time.Time
+
+	// ReservedIDPrefix, when set, stops clients from creating items whose
+	// ID starts with it (case-insensitively), keeping the namespace for
+	// system items created through the admin import
+	ReservedIDPrefix string
+
+	// MaxBodyBytes is the largest JSON body any request may send; longer
+	// bodies are rejected with 413 before they are parsed
+	MaxBodyBytes int64
+
+	// BatchMaxItems is the most items one batch create may contain
+	BatchMaxItems int
+
+	// EnforceOwnership restricts updates and deletes to the principal that
+	// created the item; admins can still write any item
+	EnforceOwnership bool
+
+	// ScopeToOwner keeps users to their own items: reads and writes of an
+	// item owned by another user are rejected, and listings only include
+	// the caller's items. Items get owners from verified bearer tokens, so
+	// it is on whenever JWT_JWKS_URL is set. Admins are never scoped.
+	ScopeToOwner bool
+
+	// DefaultListLimit is the page size used when a listing omits limit.
+	// Such responses carry a warning when more items remain, so clients
+	// that never pass a limit notice the listing is paginated.
+	DefaultListLimit int
+
+	// ListDescriptionMax, when positive, truncates descriptions in list
+	// responses to this many characters; single-item reads are unaffected
+	ListDescriptionMax int
+
+	// DefaultRules fill in fields of created items based on their other
+	// fields, applied in order after validation
+	DefaultRules []models.DefaultRule
+
+	// HealthCheckTables are further tables, such as per-tenant tables,
+	// that /health/db probes besides the main table. HealthCheckSample,
+	// when positive, probes only that many of them, picked at random on
+	// each check. Each probe is bounded by HealthCheckTimeout.
+	HealthCheckTables  []string
+	HealthCheckSample  int
+	HealthCheckTimeout time.Duration
+
+	// AsyncCreates accepts creates with 202 and saves them in the
+	// background, returning a job to poll instead of the item.
+	// AsyncCreateWorkers creates run at once, and at most
+	// AsyncCreateQueueSize wait before creates are turned away with 503.
+	AsyncCreates         bool
+	AsyncCreateWorkers   int
+	AsyncCreateQueueSize int
+
+	// IdempotencyTTL is how long a create's Idempotency-Key is remembered;
+	// repeating the key within it returns the item the create made
+	IdempotencyTTL time.Duration
+}
+
+// Default configuration values
+const (
+	DefaultPageTokenTTL   = 15 * time.Minute
+	DefaultMaxBodyBytes   = 64 << 10
+	DefaultBatchMaxItems  = 100
+	DefaultListLimit      = 50
+	DefaultHealthTimeout  = 2 * time.Second
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	DefaultAsyncCreateWorkers   = 4
+	DefaultAsyncCreateQueueSize = 100
+)
+
+// defaultStatusLabels are used when STATUS_LABELS is not set
+var defaultStatusLabels = map[string]string{
+	"active":   "Active",
+	"inactive": "Inactive",
+	"pending":  "Pending",
+}
+
+// NewHandlerConfig creates a new handler configuration from environment variables
+func NewHandlerConfig() *HandlerConfig {
+	cfg := &HandlerConfig{
+		StatusLabels: map[string]map[string]string{
+			"": defaultStatusLabels,
+		},
+		PageTokenSecret:     []byte(os.Getenv("PAGE_TOKEN_SECRET")),
+		PageTokenTTL:        envDuration("PAGE_TOKEN_TTL", DefaultPageTokenTTL),
+		DeleteRequireStatus: os.Getenv("DELETE_REQUIRE_STATUS"),
+		DeprecatedFields:    parseDeprecatedFields(os.Getenv("DEPRECATED_FIELDS")),
+		ReservedIDPrefix:    os.Getenv("RESERVED_ID_PREFIX"),
+		ScopeToOwner:        os.Getenv("JWT_JWKS_URL") != "",
+		MaxBodyBytes:        int64(envInt("MAX_BODY_BYTES", DefaultMaxBodyBytes)),
+		BatchMaxItems:       envInt("BATCH_MAX_ITEMS", DefaultBatchMaxItems),
+		DefaultListLimit:    min(envInt("DEFAULT_LIST_LIMIT", DefaultListLimit), 100),
+		ListDescriptionMax:  envInt("LIST_DESCRIPTION_MAX", 0),
+		DefaultRules:        parseDefaultRules(os.Getenv("DEFAULT_RULES")),
+		HealthCheckTables:   splitList(os.Getenv("HEALTH_CHECK_TABLES")),
+		HealthCheckSample:   envInt("HEALTH_CHECK_SAMPLE", 0),
+		HealthCheckTimeout:  envDuration("HEALTH_CHECK_TIMEOUT", DefaultHealthTimeout),
+		IdempotencyTTL:      envDuration("IDEMPOTENCY_TTL", DefaultIdempotencyTTL),
+
+		AsyncCreateWorkers:   envInt("ASYNC_CREATE_WORKERS", DefaultAsyncCreateWorkers),
+		AsyncCreateQueueSize: envInt("ASYNC_CREATE_QUEUE_SIZE", DefaultAsyncCreateQueueSize),
+	}
+	cfg.EnforceOwnership, _ = strconv.ParseBool(os.Getenv("ENFORCE_OWNERSHIP"))
+	cfg.AsyncCreates, _ = strconv.ParseBool(os.Getenv("ASYNC_CREATES"))
+
+	if len(cfg.PageTokenSecret) == 0 {
+		cfg.PageTokenSecret = processPageTokenSecret()
+	}
+
+	// STATUS_LABELS overrides the default labels, STATUS_LABELS_<LANG> adds a localization
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if key != "STATUS_LABELS" && !strings.HasPrefix(key, "STATUS_LABELS_") {
+			continue
+		}
+		lang := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(key, "STATUS_LABELS"), "_"))
+		labels := parseKeyValueList(value)
+		if len(labels) == 0 {
+			log.Printf("Ignoring %s: expected comma-separated status=label pairs", key)
+			continue
+		}
+		cfg.StatusLabels[lang] = labels
+	}
+
+	return cfg
+}
+
+// PageTokenCodec returns the pagination token codec for this configuration
+func (c *HandlerConfig) PageTokenCodec() *PageTokenCodec {
+	return NewPageTokenCodec(c.PageTokenSecret, c.PageTokenTTL)
+}
+
+// envDuration reads a duration environment variable, falling back to the
+// default when unset or invalid
+func envDuration(name string, def time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Printf("Ignoring invalid %s %q, using default %s", name, value, def)
+		return def
+	}
+	return d
+}
+
+// envInt reads a positive integer environment variable, falling back to
+// the default when unset or invalid
+func envInt(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("Ignoring invalid %s %q, using default %d", name, value, def)
+		return def
+	}
+	return n
+}
+
+// parseDefaultRules parses DEFAULT_RULES, a JSON list of default rules,
+// ignoring it entirely when any rule is invalid
+func parseDefaultRules(value string) []models.DefaultRule {
+	if value == "" {
+		return nil
+	}
+	rules, err := models.ParseDefaultRules([]byte(value))
+	if err != nil {
+		log.Printf("Ignoring invalid DEFAULT_RULES: %v", err)
+		return nil
+	}
+	return rules
+}
+
+// splitList parses a comma-separated list, skipping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseKeyValueList parses "a=A,b=B" into a map, skipping malformed entries
+func parseKeyValueList(value string) map[string]string {
+	result := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+)
+
+type createBudgetKey struct{}
+
+// CreateBudget charges n creates to the caller's budget. It returns zero
+// when they are allowed, or how long until they would be without charging
+// anything.
+type CreateBudget func(n int) time.Duration
+
+// WithCreateBudget returns a context whose creates are charged to budget
+func WithCreateBudget(ctx context.Context, budget CreateBudget) context.Context {
+	return context.WithValue(ctx, createBudgetKey{}, budget)
+}
+
+// ChargeCreates charges n creates to the budget in ctx, returning a 429
+// error once it is spent. Creates in a context without a budget are not
+// limited.
+func ChargeCreates(ctx context.Context, n int) *APIError {
+	budget, ok := ctx.Value(createBudgetKey{}).(CreateBudget)
+	if !ok || n <= 0 {
+		return nil
+	}
+	wait := budget(n)
+	if wait <= 0 {
+		return nil
+	}
+	return &APIError{
+		Type:       ErrorTypeRate,
+		Code:       CodeRateLimitExceeded,
+		Message:    "Create rate limit exceeded",
+		Details:    "Too many items created by this caller; retry after the Retry-After delay",
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: int(math.Ceil(wait.Seconds())),
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/emf"
+	"fis-playground/internal/repository"
+)
+
+// metricRecorder captures the metrics handlers emit
+type metricRecorder struct {
+	counts []recordedMetric
+}
+
+type recordedMetric struct {
+	name       string
+	value      float64
+	dimensions map[string]string
+}
+
+func (m *metricRecorder) Count(name string, value float64, dimensions map[string]string) {
+	m.counts = append(m.counts, recordedMetric{name: name, value: value, dimensions: dimensions})
+}
+
+// total sums the metric's values across the given dimension value, or all
+// of them when dimension is empty
+func (m *metricRecorder) total(name, dimension, value string) float64 {
+	var total float64
+	for _, metric := range m.counts {
+		if metric.name == name && (dimension == "" || metric.dimensions[dimension] == value) {
+			total += metric.value
+		}
+	}
+	return total
+}
+
+func TestCreateItem_EmitsItemsCreated(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+	metrics := &metricRecorder{}
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`))
+	req = req.WithContext(emf.WithEmitter(context.Background(), metrics))
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if got := metrics.total(emf.ItemsCreated, "", ""); got != 1 {
+		t.Errorf("Expected ItemsCreated 1, got %v in %+v", got, metrics.counts)
+	}
+	if got := metrics.total(emf.Errors, "", ""); got != 0 {
+		t.Errorf("Expected no errors, got %v", got)
+	}
+}
+
+func TestCreateItem_EmitsValidationErrors(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+	metrics := &metricRecorder{}
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"description":"Description"}`))
+	req = req.WithContext(emf.WithEmitter(context.Background(), metrics))
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if got := metrics.total(emf.ValidationErrors, "", ""); got != 1 {
+		t.Errorf("Expected ValidationErrors 1, got %v", got)
+	}
+	if got := metrics.total(emf.Errors, emf.ErrorCodeDimension, string(CodeMissingField)); got != 1 {
+		t.Errorf("Expected one %s error, got %+v", CodeMissingField, metrics.counts)
+	}
+	if got := metrics.total(emf.ItemsCreated, "", ""); got != 0 {
+		t.Errorf("Expected no ItemsCreated, got %v", got)
+	}
+}
+
+func TestDeleteItem_EmitsItemsDeleted(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	metrics := &metricRecorder{}
+
+	req := httptest.NewRequest("DELETE", "/items/test-id", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "test-id")
+	ctx := context.WithValue(emf.WithEmitter(context.Background(), metrics), chi.RouteCtxKey, rctx)
+	req = req.WithContext(ctx)
+	w := httptest.NewRecorder()
+	handler.DeleteItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got := metrics.total(emf.ItemsDeleted, "", ""); got != 1 {
+		t.Errorf("Expected ItemsDeleted 1, got %v", got)
+	}
+}
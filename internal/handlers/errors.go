@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"fis-playground/internal/emf"
+	"fis-playground/internal/logging"
 	"fis-playground/internal/models"
 	"fis-playground/internal/repository"
 )
@@ -33,10 +38,19 @@ const (
 	CodeInvalidFormat      ErrorCode = "INVALID_FORMAT"
 	CodeValueTooLong       ErrorCode = "VALUE_TOO_LONG"
 	CodeInvalidValue       ErrorCode = "INVALID_VALUE"
+	CodeHeadersTooLarge    ErrorCode = "HEADERS_TOO_LARGE"
+	CodePayloadTooLarge    ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeMethodNotAllowed   ErrorCode = "METHOD_NOT_ALLOWED"
 
 	// Resource errors
 	CodeNotFound           ErrorCode = "NOT_FOUND"
 	CodeAlreadyExists      ErrorCode = "ALREADY_EXISTS"
+	CodeLimitReached       ErrorCode = "LIMIT_REACHED"
+	CodeStaleGeneration    ErrorCode = "STALE_GENERATION"
+	CodeVersionConflict    ErrorCode = "VERSION_CONFLICT"
+	CodePreconditionFailed ErrorCode = "PRECONDITION_FAILED"
+	CodeLeaseHeld          ErrorCode = "LEASE_HELD"
+	CodeIdempotencyReused  ErrorCode = "IDEMPOTENCY_REUSED"
 
 	// Database errors
 	CodeDatabaseError      ErrorCode = "DATABASE_ERROR"
@@ -65,8 +79,18 @@ type APIError struct {
 	Details    string
 	StatusCode int
 	Cause      error
+
+	// RetryAfter is how many seconds a client should wait before retrying.
+	// Throughput and rate-limit errors always send it, with zero meaning
+	// DefaultRetryAfter; other errors only send it when it is set.
+	RetryAfter int
 }
 
+// DefaultRetryAfter is the Retry-After, in seconds, sent with throughput and
+// rate-limit errors that don't set their own, configured via
+// RETRY_AFTER_SECONDS (default 1)
+var DefaultRetryAfter = envInt("RETRY_AFTER_SECONDS", 1)
+
 // Error implements the error interface
 func (e *APIError) Error() string {
 	if e.Cause != nil {
@@ -119,12 +143,18 @@ func NewDatabaseError(code ErrorCode, message string, cause error) *APIError {
 		statusCode = http.StatusTooManyRequests
 	}
 	
+	retryAfter := 0
+	if code == CodeThroughputExceeded {
+		retryAfter = DefaultRetryAfter
+	}
+
 	return &APIError{
 		Type:       ErrorTypeDatabase,
 		Code:       code,
 		Message:    message,
 		StatusCode: statusCode,
 		Cause:      cause,
+		RetryAfter: retryAfter,
 	}
 }
 
@@ -171,6 +201,60 @@ func MapRepositoryError(err error) *APIError {
 			StatusCode: http.StatusConflict,
 			Cause:      err,
 		}
+	case repository.IsLimitReachedError(err):
+		return &APIError{
+			Type:       ErrorTypeConflict,
+			Code:       CodeLimitReached,
+			Message:    "Item limit reached",
+			Details:    err.Error(),
+			StatusCode: http.StatusConflict,
+			Cause:      err,
+		}
+	case repository.IsStaleGenerationError(err):
+		return &APIError{
+			Type:       ErrorTypeConflict,
+			Code:       CodeStaleGeneration,
+			Message:    "Item generation is stale",
+			Details:    err.Error(),
+			StatusCode: http.StatusConflict,
+			Cause:      err,
+		}
+	case repository.IsVersionConflictError(err):
+		return &APIError{
+			Type:       ErrorTypeConflict,
+			Code:       CodeVersionConflict,
+			Message:    "Item version conflict",
+			Details:    err.Error(),
+			StatusCode: http.StatusConflict,
+			Cause:      err,
+		}
+	case repository.IsNotOwnerError(err):
+		return &APIError{
+			Type:       ErrorTypeAuth,
+			Code:       CodeForbidden,
+			Message:    "Item belongs to another principal",
+			Details:    "Only the item's creator can change it",
+			StatusCode: http.StatusForbidden,
+			Cause:      err,
+		}
+	case repository.IsLeaseHeldError(err):
+		return &APIError{
+			Type:       ErrorTypeConflict,
+			Code:       CodeLeaseHeld,
+			Message:    "Item is leased to another holder",
+			Details:    err.Error(),
+			StatusCode: http.StatusConflict,
+			Cause:      err,
+		}
+	case repository.IsPreconditionFailedError(err):
+		return &APIError{
+			Type:       ErrorTypeConflict,
+			Code:       CodePreconditionFailed,
+			Message:    "Item does not meet the required condition",
+			Details:    err.Error(),
+			StatusCode: http.StatusConflict,
+			Cause:      err,
+		}
 	case repository.IsValidationError(err):
 		return &APIError{
 			Type:       ErrorTypeValidation,
@@ -199,6 +283,7 @@ func MapRepositoryError(err error) *APIError {
 				Details:    "Please retry your request after a brief delay",
 				StatusCode: http.StatusTooManyRequests,
 				Cause:      err,
+				RetryAfter: DefaultRetryAfter,
 			}
 		}
 		return &APIError{
@@ -232,6 +317,13 @@ func MapValidationError(err error) *APIError {
 
 	// Map specific validation errors to appropriate codes
 	switch {
+	case errors.Is(err, models.ErrCreatedAtInFuture), errors.Is(err, models.ErrBatchItemID), errors.Is(err, models.ErrInvalidVisibilityWindow),
+		errors.Is(err, models.ErrInvalidTag), errors.Is(err, models.ErrDuplicateTag), errors.Is(err, models.ErrInvalidTTL), errors.Is(err, models.ErrInvalidExpiresAt),
+		errors.Is(err, models.ErrInvalidMetadataKey), errors.Is(err, models.ErrInvalidMetadataMode):
+		code = CodeInvalidValue
+	case errors.Is(err, models.ErrTooManyTags), errors.Is(err, models.ErrTagTooLong),
+		errors.Is(err, models.ErrTooManyMetadataKeys), errors.Is(err, models.ErrMetadataValueTooLong):
+		code = CodeValueTooLong
 	case containsError(message, "empty", "required"):
 		code = CodeMissingField
 	case containsError(message, "too long", "exceed"):
@@ -253,33 +345,102 @@ func MapValidationError(err error) *APIError {
 
 // WriteErrorResponse writes a standardized error response
 func WriteErrorResponse(w http.ResponseWriter, r *http.Request, apiErr *APIError) {
-	// Log the error for debugging
+	// Log the error for debugging, with the request ID when the request
+	// logger middleware ran
+	attrs := []any{
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", apiErr.StatusCode,
+		"code", string(apiErr.Code),
+		"message", apiErr.Message,
+	}
 	if apiErr.Cause != nil {
-		log.Printf("API Error [%s %s]: %s - %s (caused by: %v)", 
-			r.Method, r.URL.Path, apiErr.Code, apiErr.Message, apiErr.Cause)
+		attrs = append(attrs, "cause", apiErr.Cause.Error())
+	}
+	logger := logging.FromContext(r.Context())
+	if apiErr.StatusCode >= http.StatusInternalServerError {
+		logger.Error("API error", attrs...)
 	} else {
-		log.Printf("API Error [%s %s]: %s - %s", 
-			r.Method, r.URL.Path, apiErr.Code, apiErr.Message)
+		logger.Warn("API error", attrs...)
 	}
 
-	// Create error info
-	errorInfo := &models.ErrorInfo{
-		Code:    string(apiErr.Code),
-		Message: apiErr.Message,
-		Type:    string(apiErr.Type),
-		Details: apiErr.Details,
+	emitter := emf.FromContext(r.Context())
+	emitter.Count(emf.Errors, 1, map[string]string{emf.ErrorCodeDimension: string(apiErr.Code)})
+	if apiErr.Type == ErrorTypeValidation {
+		emitter.Count(emf.ValidationErrors, 1, nil)
+	}
+
+	// Tell throttled clients when to come back
+	if apiErr.Code == CodeThroughputExceeded || apiErr.Code == CodeRateLimitExceeded || apiErr.RetryAfter > 0 {
+		retryAfter := apiErr.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = DefaultRetryAfter
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+
+	// Both formats carry the same error; the client picks one via Accept
+	w.Header().Add("Vary", "Accept")
+	if acceptsProblemJSON(r.Header.Get("Accept")) {
+		writeJSONResponseAs(w, ProblemContentType, apiErr.StatusCode, problemDetails(apiErr))
+		return
 	}
 
 	// Create response
 	response := models.APIResponse{
 		Success: false,
-		Error:   errorInfo,
+		Error:   errorInfo(apiErr),
 	}
 
 	// Write response
 	writeJSONResponse(w, apiErr.StatusCode, response)
 }
 
+// ProblemContentType is the media type of RFC 7807 error responses
+const ProblemContentType = "application/problem+json"
+
+// errorInfo converts an API error into its response form
+func errorInfo(apiErr *APIError) *models.ErrorInfo {
+	return &models.ErrorInfo{
+		Code:    string(apiErr.Code),
+		Message: apiErr.Message,
+		Type:    string(apiErr.Type),
+		Details: apiErr.Details,
+	}
+}
+
+// problemDetails converts an API error into RFC 7807 problem details. The
+// type URI points at the code's entry in the GET /errors catalog.
+func problemDetails(apiErr *APIError) *models.ProblemDetails {
+	return &models.ProblemDetails{
+		Type:   "/errors#" + string(apiErr.Code),
+		Title:  apiErr.Message,
+		Status: apiErr.StatusCode,
+		Detail: apiErr.Details,
+		Code:   string(apiErr.Code),
+	}
+}
+
+// acceptsProblemJSON checks whether an Accept header lists
+// application/problem+json without excluding it with q=0
+func acceptsProblemJSON(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(mediaType), ProblemContentType) {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
 // WriteValidationErrorResponse writes a validation error response
 func WriteValidationErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	apiErr := MapValidationError(err)
@@ -292,8 +453,22 @@ func WriteRepositoryErrorResponse(w http.ResponseWriter, r *http.Request, err er
 	WriteErrorResponse(w, r, apiErr)
 }
 
-// WriteJSONParseErrorResponse writes a JSON parsing error response
+// WriteJSONParseErrorResponse writes a JSON parsing error response, or 413
+// when the body was cut off for exceeding its size limit
 func WriteJSONParseErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		WriteErrorResponse(w, r, &APIError{
+			Type:       ErrorTypeValidation,
+			Code:       CodePayloadTooLarge,
+			Message:    "Request body too large",
+			Details:    fmt.Sprintf("Request body exceeds the maximum of %d bytes", tooLarge.Limit),
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Cause:      err,
+		})
+		return
+	}
+
 	apiErr := &APIError{
 		Type:       ErrorTypeValidation,
 		Code:       CodeInvalidFormat,
@@ -403,11 +578,16 @@ func findSubstring(s, substr string) int {
 
 // writeJSONResponse writes a JSON response to the HTTP response writer
 func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	writeJSONResponseAs(w, "application/json", statusCode, data)
+}
+
+// writeJSONResponseAs writes a JSON response with a JSON-based content type
+func writeJSONResponseAs(w http.ResponseWriter, contentType string, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(statusCode)
 
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Failed to encode JSON response: %v", err)
+		slog.Error("Failed to encode JSON response", "error", err)
 		// Fallback to plain text error
 		http.Error(w, `{"success": false, "error": {"code": "INTERNAL_ERROR", "message": "Failed to encode response", "type": "system"}}`, http.StatusInternalServerError)
 	}
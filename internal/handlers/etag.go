@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// itemETag is the strong ETag of one representation of an item: the
+// generation, which If-Match checks, and a hash of the representation sent.
+// Views and leases change the item without bumping the generation, and
+// ?fields= projections and status labels change what is sent, so the hash
+// tells those representations apart.
+func itemETag(item *models.Item, representation interface{}) string {
+	body, _ := json.Marshal(representation)
+	hash := fnv.New64a()
+	hash.Write(body)
+	return fmt.Sprintf(`"%d-%016x"`, item.Generation, hash.Sum64())
+}
+
+// ifMatchGeneration parses an If-Match header into the generation a write
+// requires. It returns nil when the header is absent or "*", which only
+// requires the item to exist, as every write already does. Only the
+// generation is compared, so a view or lease since the ETag was read, or a
+// read of another representation, doesn't fail the write; a bare
+// generation is accepted too. ETags this API can't have issued, including
+// weak ones that never match under If-Match's strong comparison, fail the
+// precondition outright.
+func ifMatchGeneration(header string) (*int64, *APIError) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tags := strings.Split(header, ",")
+	if len(tags) > 1 {
+		return nil, NewValidationError(CodeInvalidValue, "Invalid If-Match header", "If-Match must name a single ETag")
+	}
+	tag := strings.TrimSpace(tags[0])
+	value, ok := strings.CutPrefix(tag, `"`)
+	if ok {
+		value, ok = strings.CutSuffix(value, `"`)
+	}
+	value, _, _ = strings.Cut(value, "-")
+	generation, err := strconv.ParseInt(value, 10, 64)
+	if !ok || err != nil || generation < 0 {
+		return nil, newIfMatchError(tag)
+	}
+	return &generation, nil
+}
+
+// ifNoneMatch reports whether the If-None-Match header matches etag, so a
+// GET can answer 304 Not Modified. Unlike If-Match it uses the weak
+// comparison, so W/"3-…" matches "3-…", and it may list several ETags.
+func ifNoneMatch(r *http.Request, etag string) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// newIfMatchError reports an If-Match header that doesn't match the item
+func newIfMatchError(tag string) *APIError {
+	return &APIError{
+		Type:       ErrorTypeConflict,
+		Code:       CodePreconditionFailed,
+		Message:    "Item has changed",
+		Details:    fmt.Sprintf("The item no longer matches If-Match %s; re-read it and retry", tag),
+		StatusCode: http.StatusPreconditionFailed,
+	}
+}
+
+// conditionalError maps a repository error from a write that may have been
+// conditioned on the If-Match header ifMatch, reporting a stale generation
+// as a failed precondition when the condition came from the header
+func conditionalError(err error, generation *int64, ifMatch string) *APIError {
+	if generation != nil && repository.IsStaleGenerationError(err) {
+		apiErr := newIfMatchError(ifMatch)
+		apiErr.Cause = err
+		return apiErr
+	}
+	return MapRepositoryError(err)
+}
+
+// applyIfMatch combines the If-Match generation with one sent in the body,
+// which must agree when both are given
+func applyIfMatch(generation **int64, ifMatch *int64) *APIError {
+	if ifMatch == nil {
+		return nil
+	}
+	if *generation != nil && **generation != *ifMatch {
+		return NewValidationError(CodeInvalidValue, "Conflicting generation", "The body's generation and the If-Match header disagree")
+	}
+	*generation = ifMatch
+	return nil
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// conditionalRequest calls handle for the item with an optional If-Match
+func conditionalRequest(handle http.HandlerFunc, method, id, ifMatch, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/items/"+id, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	handle(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Error == nil {
+		t.Fatalf("Expected an error response, got %s", w.Body.String())
+	}
+	return response.Error.Code
+}
+
+func TestETag_IfMatchOnUpdate(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+
+	fetched := conditionalRequest(handler.GetItem, "GET", item.ID, "", "")
+	etag := fetched.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `"1-`) {
+		t.Fatalf(`Expected an ETag of generation 1, got %q`, etag)
+	}
+
+	updated := conditionalRequest(handler.UpdateItem, "PUT", item.ID, etag, `{"name":"Renamed"}`)
+	if updated.Code != http.StatusOK {
+		t.Fatalf("Expected update with a matching ETag to succeed, got %d: %s", updated.Code, updated.Body.String())
+	}
+	if got := updated.Header().Get("ETag"); !strings.HasPrefix(got, `"2-`) {
+		t.Errorf(`Expected an ETag of generation 2, got %q`, got)
+	}
+
+	// The first ETag is now stale, for updates, patches and deletes alike
+	for name, handle := range map[string]http.HandlerFunc{
+		"PUT":    handler.UpdateItem,
+		"PATCH":  handler.PatchItem,
+		"DELETE": handler.DeleteItem,
+	} {
+		w := conditionalRequest(handle, name, item.ID, etag, `{"name":"Lost update"}`)
+		if w.Code != http.StatusPreconditionFailed {
+			t.Fatalf("%s: expected status %d, got %d: %s", name, http.StatusPreconditionFailed, w.Code, w.Body.String())
+		}
+		if code := errorCode(t, w); code != string(CodePreconditionFailed) {
+			t.Errorf("%s: expected code %s, got %s", name, CodePreconditionFailed, code)
+		}
+	}
+
+	stored, err := repo.GetItem(context.Background(), item.ID, nil)
+	if err != nil {
+		t.Fatalf("Expected the item to survive the stale delete, got %v", err)
+	}
+	if stored.Name != "Renamed" {
+		t.Errorf("Expected stale writes to be rejected, got name %q", stored.Name)
+	}
+}
+
+func TestETag_IfMatchOnDelete(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+
+	w := conditionalRequest(handler.DeleteItem, "DELETE", item.ID, itemETag(item, item), "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected delete with a matching ETag to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := repo.GetItem(context.Background(), item.ID, nil); err == nil {
+		t.Error("Expected the item to be deleted")
+	}
+}
+
+func TestETag_InvalidIfMatch(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		body           string
+		expectedStatus int
+		expectedCode   ErrorCode
+	}{
+		{"weak ETags never match", `W/"1"`, `{"name":"Renamed"}`, http.StatusPreconditionFailed, CodePreconditionFailed},
+		{"foreign ETag", `"abc"`, `{"name":"Renamed"}`, http.StatusPreconditionFailed, CodePreconditionFailed},
+		{"several ETags", `"1", "2"`, `{"name":"Renamed"}`, http.StatusBadRequest, CodeInvalidValue},
+		{"disagrees with the body", `"1"`, `{"name":"Renamed","generation":2}`, http.StatusBadRequest, CodeInvalidValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := conditionalRequest(handler.UpdateItem, "PUT", item.ID, tt.ifMatch, tt.body)
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if code := errorCode(t, w); code != string(tt.expectedCode) {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, code)
+			}
+		})
+	}
+
+	w := conditionalRequest(handler.UpdateItem, "PUT", item.ID, "*", `{"name":"Renamed"}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected If-Match * to match any existing item, got %d", w.Code)
+	}
+}
+
+func TestETag_IfNoneMatchOnGet(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/items/"+item.ID, nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", item.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.GetItem(w, req)
+		return w
+	}
+
+	etag := get("").Header().Get("ETag")
+	for _, header := range []string{etag, "W/" + etag, `"0", ` + etag, "*"} {
+		w := get(header)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Expected If-None-Match %s to return 304, got %d", header, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected no body with 304, got %q", w.Body.String())
+		}
+		if got := w.Header().Get("ETag"); got != etag {
+			t.Errorf("Expected ETag %s with 304, got %q", etag, got)
+		}
+	}
+
+	// The ETag GET compares against is the one updates check
+	updated := conditionalRequest(handler.UpdateItem, "PUT", item.ID, `"1"`, `{"name":"Renamed"}`)
+	if updated.Code != http.StatusOK {
+		t.Fatalf("Expected update to succeed, got %d: %s", updated.Code, updated.Body.String())
+	}
+	w := get(etag)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected a non-matching If-None-Match to return 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("ETag"); got != updated.Header().Get("ETag") {
+		t.Errorf("Expected GET's ETag %q to match the update's %q", got, updated.Header().Get("ETag"))
+	}
+	var response struct {
+		Data models.Item `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Data.Name != "Renamed" {
+		t.Errorf("Expected the current item in the body, got %s (%v)", w.Body.String(), err)
+	}
+}
+
+func TestETag_ChangesWithViewsLeasesAndProjections(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	etagOf := func(target string) string {
+		req := httptest.NewRequest("GET", target, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", item.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.GetItem(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return w.Header().Get("ETag")
+	}
+
+	seen := map[string]string{"initial": etagOf("/items/" + item.ID)}
+	if _, err := repo.IncrementViewCount(context.Background(), item.ID); err != nil {
+		t.Fatalf("Failed to count view: %v", err)
+	}
+	seen["after a view"] = etagOf("/items/" + item.ID)
+	if _, err := repo.GetForUpdate(context.Background(), item.ID, "alice", 60); err != nil {
+		t.Fatalf("Failed to lease item: %v", err)
+	}
+	seen["after a lease"] = etagOf("/items/" + item.ID)
+	seen["projected"] = etagOf("/items/" + item.ID + "?fields=name")
+
+	byETag := map[string]string{}
+	for state, etag := range seen {
+		if other, ok := byETag[etag]; ok {
+			t.Errorf("Expected %s and %s to have different ETags, both got %s", state, other, etag)
+		}
+		byETag[etag] = state
+	}
+
+	// Writes only check the generation, so views and leases don't fail them
+	w := conditionalRequest(handler.UpdateItem, "PUT", item.ID, seen["initial"], `{"name":"Renamed"}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected an ETag of the same generation to match, got %d: %s", w.Code, w.Body.String())
+	}
+}
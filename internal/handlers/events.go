@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/events"
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// eventKeepAlive is how often an idle event stream sends a comment, so
+// proxies don't close it for inactivity
+const eventKeepAlive = 15 * time.Second
+
+// SetEventBroker makes the handler publish item changes to broker, and
+// StreamEvents serve them. Without a broker nothing is published.
+func (h *ItemHandler) SetEventBroker(broker *events.Broker) {
+	h.events = broker
+}
+
+// publish reports an item change to event stream subscribers, if any
+func (h *ItemHandler) publish(eventType, id string, item *models.Item) {
+	if h.events == nil {
+		return
+	}
+	event := events.Event{Type: eventType, ID: id, Item: item}
+	if item != nil {
+		event.OwnerID = item.OwnerID
+	}
+	h.events.Publish(event)
+}
+
+// publishDeleted reports the delete of an item with the given owner to
+// event stream subscribers, if any
+func (h *ItemHandler) publishDeleted(id, owner string) {
+	if h.events == nil {
+		return
+	}
+	h.events.Publish(events.Event{Type: events.ItemDeleted, ID: id, OwnerID: owner})
+}
+
+// ReplayItemEvents handles POST /items/{id}/replay-events requests,
+// publishing the item's current state as an upsert event for consumers that
+// missed its earlier events. Like reads, soft-deleted items are not found.
+func (h *ItemHandler) ReplayItemEvents(w http.ResponseWriter, r *http.Request) {
+	itemID := chi.URLParam(r, "id")
+	if itemID == "" {
+		WriteMissingParameterErrorResponse(w, r, "Item ID")
+		return
+	}
+	if h.events == nil {
+		WriteInternalErrorResponse(w, r, fmt.Errorf("event stream is not configured"))
+		return
+	}
+
+	item, err := h.repo.GetItem(r.Context(), itemID, &repository.GetItemOptions{Consistent: true})
+	if err == nil && item.IsDeleted() {
+		err = repository.ErrItemNotFound
+	}
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	event := events.Event{Type: events.ItemUpserted, ID: item.ID, Item: item}
+	h.events.Publish(event)
+
+	response := models.APIResponse{
+		Success: true,
+		Data:    event,
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// StreamEvents handles GET /items/events requests, streaming item changes
+// as Server-Sent Events until the client disconnects. Each event is named
+// after its type and carries the event as JSON data. Items outside their
+// visibility window are left out, as they are from reads, and when items
+// are scoped to their owner subscribers only see changes to their items.
+//
+// Streams need a long-lived connection, so this is only routed by the
+// standalone server; the Lambda function can't hold one open.
+func (h *ItemHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	if h.events == nil {
+		WriteInternalErrorResponse(w, r, fmt.Errorf("event stream is not configured"))
+		return
+	}
+
+	scope := h.ownerScope(r.Context())
+	subscription, unsubscribe := h.events.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher := http.NewResponseController(w)
+	if err := flusher.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-subscription:
+			if !ok {
+				return
+			}
+			if event.Item != nil && !event.Item.IsVisibleAt(time.Now()) {
+				continue
+			}
+			if !scope.IncludesOwner(event.OwnerID) {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		if err := flusher.Flush(); err != nil {
+			return
+		}
+	}
+}
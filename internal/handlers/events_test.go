@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"fis-playground/internal/events"
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+func TestReplayItemEvents_PublishesCurrentState(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	broker := events.NewBroker()
+	handler.SetEventBroker(broker)
+
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	updated, err := repo.UpdateItem(context.Background(), item.ID, &models.UpdateItemRequest{Name: "Renamed"})
+	if err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+
+	subscription, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	w := itemRequest(handler.ReplayItemEvents, "POST", "/items/"+item.ID+"/replay-events", item.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	select {
+	case event := <-subscription:
+		if event.Type != events.ItemUpserted || event.ID != item.ID {
+			t.Errorf("Expected an upsert event for %s, got %s %s", item.ID, event.Type, event.ID)
+		}
+		if event.Item == nil || event.Item.Name != "Renamed" || event.Item.Generation != updated.Generation {
+			t.Errorf("Expected the event to carry the current item, got %+v", event.Item)
+		}
+	default:
+		t.Fatal("Expected the replay to publish an event")
+	}
+}
+
+func TestReplayItemEvents_NotFound(t *testing.T) {
+	t.Setenv("SOFT_DELETE", "true")
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	broker := events.NewBroker()
+	handler.SetEventBroker(broker)
+
+	deleted := models.NewItem("Deleted", "Description")
+	if err := repo.CreateItem(context.Background(), deleted); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	if err := repo.DeleteItem(context.Background(), deleted.ID, nil); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+
+	subscription, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	for _, id := range []string{"missing", deleted.ID} {
+		w := itemRequest(handler.ReplayItemEvents, "POST", "/items/"+id+"/replay-events", id)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %s, got %d: %s", id, w.Code, w.Body.String())
+		}
+	}
+	if len(subscription) != 0 {
+		t.Errorf("Expected no events for missing items, got %d", len(subscription))
+	}
+}
+
+func TestStreamEvents_ScopedToOwner(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+	handler.config.ScopeToOwner = true
+	handler.SetEventBroker(events.NewBroker())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.StreamEvents(w, r.WithContext(WithUser(r.Context(), "bob")))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", srv.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect to the event stream: %v", err)
+	}
+	defer stream.Body.Close()
+
+	// Events are delivered in order, so alice's would arrive first
+	alices := models.NewItem("Alice's", "Description")
+	alices.OwnerID = "alice"
+	bobs := models.NewItem("Bob's", "Description")
+	bobs.OwnerID = "bob"
+	handler.publish(events.ItemUpdated, alices.ID, alices)
+	handler.publishDeleted(alices.ID, "alice")
+	handler.publish(events.ItemUpdated, bobs.ID, bobs)
+
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event events.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		if event.ID != bobs.ID {
+			t.Errorf("Expected only bob's item's events, got %s of %s", event.Type, event.ID)
+		}
+		return
+	}
+	t.Fatalf("Expected an event, got %v", scanner.Err())
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"fis-playground/internal/models"
+)
+
+// ExplainItem handles POST /items/explain requests. It runs a create
+// payload through the same validation, normalization and default rules as
+// POST /items and reports each step's outcome without saving anything, so
+// client developers can see why a payload is rejected or changed.
+func (h *ItemHandler) ExplainItem(w http.ResponseWriter, r *http.Request) {
+	var createReq models.CreateItemRequest
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&createReq); err != nil {
+		WriteJSONParseErrorResponse(w, r, err)
+		return
+	}
+
+	report := explainResponse{Valid: true, Rules: []explainRule{}}
+	addRule := func(rule string, apiErr *APIError) {
+		result := explainRule{Rule: rule, Passed: apiErr == nil}
+		if apiErr != nil {
+			result.Error = errorInfo(apiErr)
+			report.Valid = false
+		}
+		report.Rules = append(report.Rules, result)
+	}
+	for _, result := range createReq.Explain() {
+		addRule(result.Rule, MapValidationError(result.Err))
+	}
+	addRule("reserved_id", h.checkReservedID(createReq.ID))
+	report.Normalized = &createReq
+
+	// Only a valid payload would be created, so only it gets an item
+	if report.Valid {
+		// As newItem does, but keeping the item from before the default
+		// rules to tell which fields they filled in
+		item := createReq.NewItem()
+		item.CreatedBy = PrincipalFromContext(r.Context())
+		item.OwnerID, _ = UserFromContext(r.Context())
+		undefaulted := *item
+		models.ApplyDefaultRules(item, h.config.DefaultRules)
+
+		report.Item = h.itemView(r, item)
+		for _, diff := range models.DiffItems(&undefaulted, item) {
+			report.Defaults = append(report.Defaults, explainDefault{Field: diff.Field, Value: diff.B})
+		}
+	}
+
+	response := models.APIResponse{
+		Success: true,
+		Data:    report,
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
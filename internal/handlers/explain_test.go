@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// explainReport is the decoded data of a POST /items/explain response
+type explainReport struct {
+	Valid bool `json:"valid"`
+	Rules []struct {
+		Rule   string            `json:"rule"`
+		Passed bool              `json:"passed"`
+		Error  *models.ErrorInfo `json:"error"`
+	} `json:"rules"`
+	Normalized models.CreateItemRequest `json:"normalized"`
+	Item       *models.Item             `json:"item"`
+	Defaults   []explainDefault         `json:"defaults"`
+}
+
+// explain posts body to the explain endpoint and decodes the report
+func explain(t *testing.T, handler *ItemHandler, body string) explainReport {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/items/explain", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ExplainItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response struct {
+		Data explainReport `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response.Data
+}
+
+func TestExplainItem_Valid(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	rules, err := models.ParseDefaultRules([]byte(`[{"when": {"category": "inbox"}, "set": {"status": "pending"}}]`))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+	handler.config.DefaultRules = rules
+
+	report := explain(t, handler, `{"name":"Item","description":"Description","category":"inbox","tags":["  Red ","blue"]}`)
+
+	if !report.Valid {
+		t.Errorf("Expected the payload to be valid, got %+v", report.Rules)
+	}
+	for _, rule := range report.Rules {
+		if !rule.Passed {
+			t.Errorf("Expected rule %s to pass, got %+v", rule.Rule, rule.Error)
+		}
+	}
+	if want := []string{"blue", "red"}; !slices.Equal(report.Normalized.Tags, want) {
+		t.Errorf("Expected normalized tags %v, got %v", want, report.Normalized.Tags)
+	}
+	if report.Item == nil || report.Item.Status != "pending" || !slices.Equal(report.Item.Tags, []string{"blue", "red"}) {
+		t.Errorf("Expected the item to be created pending with normalized tags, got %+v", report.Item)
+	}
+	if len(report.Defaults) != 1 || report.Defaults[0].Field != "status" || report.Defaults[0].Value != "pending" {
+		t.Errorf("Expected the default rule to set status, got %+v", report.Defaults)
+	}
+
+	result, err := repo.ListItems(context.Background(), nil)
+	if err != nil || len(result.Items) != 0 {
+		t.Fatalf("Expected nothing to be saved, got %+v (%v)", result, err)
+	}
+}
+
+func TestExplainItem_RuleOutcomes(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+	handler.config.ReservedIDPrefix = "sys-"
+
+	report := explain(t, handler, `{"id":"sys-1","name":" ","description":"Description","category":"`+strings.Repeat("x", 51)+`","tags":["a","A"]}`)
+
+	if report.Valid {
+		t.Error("Expected the payload to be invalid")
+	}
+	if report.Item != nil {
+		t.Errorf("Expected no item for an invalid payload, got %+v", report.Item)
+	}
+
+	failed := map[string]string{
+		"name_required":   string(CodeMissingField),
+		"category_length": string(CodeValueTooLong),
+		"tags":            string(CodeInvalidValue),
+		"reserved_id":     string(CodeForbidden),
+	}
+	if len(report.Rules) != 11 {
+		t.Errorf("Expected every rule to be reported, got %d", len(report.Rules))
+	}
+	for _, rule := range report.Rules {
+		code, shouldFail := failed[rule.Rule]
+		switch {
+		case shouldFail && rule.Passed:
+			t.Errorf("Expected rule %s to fail", rule.Rule)
+		case shouldFail && (rule.Error == nil || rule.Error.Code != code):
+			t.Errorf("Expected rule %s to fail with %s, got %+v", rule.Rule, code, rule.Error)
+		case !shouldFail && !rule.Passed:
+			t.Errorf("Expected rule %s to pass, got %+v", rule.Rule, rule.Error)
+		}
+	}
+}
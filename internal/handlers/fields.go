@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"fis-playground/internal/models"
+)
+
+// itemFieldNames are the response fields a client may select with ?fields=
+var itemFieldNames = func() map[string]bool {
+	names := map[string]bool{"status_label": true}
+	t := reflect.TypeOf(models.Item{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// requestedFields parses the comma-separated fields query parameter. A nil
+// result means all fields were requested.
+func requestedFields(r *http.Request) ([]string, *APIError) {
+	param := r.URL.Query().Get("fields")
+	if param == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !itemFieldNames[field] {
+			return nil, NewValidationError(CodeInvalidValue, "Invalid fields parameter", fmt.Sprintf("Unknown field '%s'", field))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// selectFields reduces a response item to the requested fields. The ID is
+// always included so items stay addressable. Its keys are written sorted.
+func selectFields(view interface{}, fields []string) (sortedObject, error) {
+	data, err := json.Marshal(view)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	selected := sortedObject{"id": all["id"]}
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			selected[field] = value
+		}
+	}
+	// A shortened description keeps its flag
+	if truncated, ok := all["description_truncated"]; ok && selected["description"] != nil {
+		selected["description_truncated"] = truncated
+	}
+	return selected, nil
+}
+
+// deprecationWarnings sets the Deprecation and Sunset headers when any of
+// the requested fields is deprecated, and returns a warning per such field.
+// Sunset carries the earliest sunset date among them.
+func (h *ItemHandler) deprecationWarnings(w http.ResponseWriter, fields []string) []string {
+	var warnings []string
+	var sunset time.Time
+	for _, field := range fields {
+		date, ok := h.config.DeprecatedFields[field]
+		if !ok {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("Field '%s' is deprecated and will be removed after %s", field, date.Format("2006-01-02")))
+		if sunset.IsZero() || date.Before(sunset) {
+			sunset = date
+		}
+	}
+
+	if len(warnings) > 0 {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+	return warnings
+}
+
+// parseDeprecatedFields parses "field=2025-12-31,..." into sunset dates,
+// skipping malformed entries
+func parseDeprecatedFields(value string) map[string]time.Time {
+	fields := map[string]time.Time{}
+	for name, sunset := range parseKeyValueList(value) {
+		date, err := time.Parse("2006-01-02", sunset)
+		if err != nil {
+			log.Printf("Ignoring deprecated field %s: sunset %q is not a YYYY-MM-DD date", name, sunset)
+			continue
+		}
+		fields[name] = date
+	}
+	return fields
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// readOptionsRepository records the options handlers read items with
+type readOptionsRepository struct {
+	MockRepository
+	getOptions  *repository.GetItemOptions
+	listOptions *repository.ListItemsOptions
+}
+
+func (r *readOptionsRepository) GetItem(ctx context.Context, id string, options *repository.GetItemOptions) (*models.Item, error) {
+	r.getOptions = options
+	return r.MockRepository.GetItem(ctx, id, options)
+}
+
+func (r *readOptionsRepository) ListItems(ctx context.Context, options *repository.ListItemsOptions) (*repository.ListItemsResult, error) {
+	r.listOptions = options
+	return r.MockRepository.ListItems(ctx, options)
+}
+
+func getItemWithFields(handler *ItemHandler, fields string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/items/test-id?fields="+fields, nil)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "test-id")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	handler.GetItem(w, req)
+	return w
+}
+
+func TestGetItem_Fields(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	w := getItemWithFields(handler, "name,status")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data) != 3 || response.Data["id"] != "test-id" || response.Data["name"] != "Test Item" || response.Data["status"] != "active" {
+		t.Errorf("Expected only id, name and status, got %v", response.Data)
+	}
+	if w.Header().Get("Deprecation") != "" {
+		t.Errorf("Expected no Deprecation header, got %q", w.Header().Get("Deprecation"))
+	}
+}
+
+func TestGetItem_FieldsProjectRepositoryRead(t *testing.T) {
+	repo := &readOptionsRepository{}
+	handler := NewItemHandler(repo)
+
+	w := getItemWithFields(handler, "name")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if strings.Join(repo.getOptions.Fields, ",") != "name" {
+		t.Errorf("Expected the repository to read only name, got %v", repo.getOptions.Fields)
+	}
+	for _, omitted := range []string{`"description"`, `"status"`, `"created_at"`} {
+		if strings.Contains(w.Body.String(), omitted) {
+			t.Errorf("Expected %s to be omitted, got %s", omitted, w.Body.String())
+		}
+	}
+}
+
+func TestListItems_Fields(t *testing.T) {
+	repo := &readOptionsRepository{}
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("GET", "/items?fields=name,status", nil)
+	w := httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if strings.Join(repo.listOptions.ProjectionFields, ",") != "name,status" {
+		t.Errorf("Expected the repository to read only name and status, got %v", repo.listOptions.ProjectionFields)
+	}
+	var response struct {
+		Data struct {
+			Items []map[string]interface{} `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data.Items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(response.Data.Items))
+	}
+	item := response.Data.Items[0]
+	if len(item) != 3 || item["id"] != "1" || item["name"] != "Item 1" || item["status"] != "active" {
+		t.Errorf("Expected only id, name and status, got %v", item)
+	}
+}
+
+func TestListItems_UnknownField(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	req := httptest.NewRequest("GET", "/items?fields=name,colour", nil)
+	w := httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), string(CodeInvalidValue)) {
+		t.Errorf("Expected %d %s, got %d: %s", http.StatusBadRequest, CodeInvalidValue, w.Code, w.Body.String())
+	}
+}
+
+func TestGetItem_UnknownField(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	w := getItemWithFields(handler, "name,colour")
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGetItem_DeprecatedField(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	handler.config.DeprecatedFields = map[string]time.Time{
+		"description": time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC),
+		"generation":  time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	w := getItemWithFields(handler, "name,description,generation")
+
+	if w.Header().Get("Deprecation") != "true" {
+		t.Errorf("Expected Deprecation header 'true', got %q", w.Header().Get("Deprecation"))
+	}
+	if sunset := w.Header().Get("Sunset"); sunset != "Thu, 31 Dec 2026 00:00:00 GMT" {
+		t.Errorf("Expected the earliest sunset date, got %q", sunset)
+	}
+
+	var response struct {
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Warnings) != 2 || !strings.Contains(response.Warnings[0], "description") {
+		t.Errorf("Expected a warning per deprecated field, got %v", response.Warnings)
+	}
+}
+
+func TestListItems_DeprecatedField(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	handler.config.DeprecatedFields = map[string]time.Time{
+		"description": time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	for fields, deprecated := range map[string]bool{"name": false, "name,description": true} {
+		req := httptest.NewRequest("GET", "/items?fields="+fields, nil)
+		w := httptest.NewRecorder()
+
+		handler.ListItems(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if got := w.Header().Get("Sunset") != ""; got != deprecated {
+			t.Errorf("Expected Sunset header present=%v for fields=%s, got %q", deprecated, fields, w.Header().Get("Sunset"))
+		}
+	}
+}
+
+func TestParseDeprecatedFields(t *testing.T) {
+	fields := parseDeprecatedFields("description=2026-12-31, status=soon")
+
+	if len(fields) != 1 || !fields["description"].Equal(time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected only the well-formed entry, got %v", fields)
+	}
+}
@@ -1,36 +1,59 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"fis-playground/internal/emf"
+	"fis-playground/internal/events"
+	"fis-playground/internal/logging"
 	"fis-playground/internal/models"
 	"fis-playground/internal/repository"
 )
 
 // ItemHandler handles HTTP requests for item operations
 type ItemHandler struct {
-	repo repository.ItemRepository
+	repo       repository.ItemRepository
+	config     *HandlerConfig
+	pageTokens *PageTokenCodec
+	events     *events.Broker // nil publishes no events
+	createJobs *createJobs    // nil creates synchronously
+	// probeClient creates the clients GET /admin/probe probes tables with
+	probeClient repository.ProbeClientFunc
 }
 
 // NewItemHandler creates a new item handler instance
 func NewItemHandler(repo repository.ItemRepository) *ItemHandler {
-	return &ItemHandler{
-		repo: repo,
+	config := NewHandlerConfig()
+	h := &ItemHandler{
+		repo:        repo,
+		config:      config,
+		pageTokens:  config.PageTokenCodec(),
+		probeClient: repository.NewProbeClient,
 	}
+	if config.AsyncCreates {
+		h.createJobs = newCreateJobs(h, config.AsyncCreateWorkers, config.AsyncCreateQueueSize)
+	}
+	return h
 }
 
 // HealthCheck handles GET /health requests - simple API health check
 func (h *ItemHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	response := models.APIResponse{
 		Success: true,
-		Data: map[string]interface{}{
-			"status":  "healthy",
-			"service": "FIS Playground API",
+		Data: healthResponse{
+			Status:  "healthy",
+			Service: "FIS Playground API",
 		},
 	}
 
@@ -43,8 +66,8 @@ func (h *ItemHandler) HealthCheckDB(w http.ResponseWriter, r *http.Request) {
 	dynamoDBMessage := "Connected"
 
 	// Try to verify DynamoDB connection
-	if dynamoRepo, ok := h.repo.(*repository.DynamoDBRepository); ok {
-		if err := dynamoRepo.HealthCheck(r.Context()); err != nil {
+	if checker, ok := h.repo.(interface{ HealthCheck(context.Context) error }); ok {
+		if err := checker.HealthCheck(r.Context()); err != nil {
 			dynamoDBStatus = "unhealthy"
 			dynamoDBMessage = err.Error()
 		}
@@ -53,6 +76,19 @@ func (h *ItemHandler) HealthCheckDB(w http.ResponseWriter, r *http.Request) {
 		dynamoDBMessage = "Repository type not recognized"
 	}
 
+	// Probe the further configured tables; any unhealthy one fails the check
+	tables := h.checkTables(r.Context())
+	unhealthy := 0
+	for _, table := range tables {
+		if !table.Healthy {
+			unhealthy++
+		}
+	}
+	if unhealthy > 0 && dynamoDBStatus == "healthy" {
+		dynamoDBStatus = "unhealthy"
+		dynamoDBMessage = fmt.Sprintf("%d of %d tables unhealthy", unhealthy, len(tables))
+	}
+
 	success := dynamoDBStatus == "healthy"
 	statusCode := http.StatusOK
 	if !success {
@@ -61,59 +97,188 @@ func (h *ItemHandler) HealthCheckDB(w http.ResponseWriter, r *http.Request) {
 
 	response := models.APIResponse{
 		Success: success,
-		Data: map[string]interface{}{
-			"status":    dynamoDBStatus,
-			"service":   "DynamoDB",
-			"message":   dynamoDBMessage,
-			"tableName": h.getTableName(),
+		Data: dbHealthResponse{
+			Status:    dynamoDBStatus,
+			Service:   "DynamoDB",
+			Message:   dynamoDBMessage,
+			TableName: h.getTableName(),
+			Tables:    tables,
 		},
 	}
 
 	writeJSONResponse(w, statusCode, response)
 }
 
+// checkTables probes the configured health check tables, or a random
+// sample of them, returning nil when there are none or the repository
+// can't probe tables
+func (h *ItemHandler) checkTables(ctx context.Context) []repository.TableHealth {
+	checker, ok := h.repo.(repository.TableChecker)
+	if !ok || len(h.config.HealthCheckTables) == 0 {
+		return nil
+	}
+
+	tables := h.config.HealthCheckTables
+	if sample := h.config.HealthCheckSample; sample > 0 && sample < len(tables) {
+		tables = slices.Clone(tables)
+		rand.Shuffle(len(tables), func(i, j int) { tables[i], tables[j] = tables[j], tables[i] })
+		tables = tables[:sample]
+	}
+	return checker.CheckTables(ctx, tables, h.config.HealthCheckTimeout)
+}
+
 // getTableName returns the DynamoDB table name if available
 func (h *ItemHandler) getTableName() string {
-	if dynamoRepo, ok := h.repo.(*repository.DynamoDBRepository); ok {
-		return dynamoRepo.GetTableName()
+	if named, ok := h.repo.(interface{ GetTableName() string }); ok {
+		return named.GetTableName()
 	}
 	return "unknown"
 }
 
-// CreateItem handles POST /items requests
+// limitBody caps how much of the request body can be read, so an oversized
+// body fails to decode with 413 instead of being read into memory
+func (h *ItemHandler) limitBody(w http.ResponseWriter, r *http.Request) io.Reader {
+	return http.MaxBytesReader(w, r.Body, h.config.MaxBodyBytes)
+}
+
+// CreateItem handles POST /items requests. With async creates configured,
+// a valid item is queued instead of saved and the response is 202 with a
+// job to poll at GET /jobs/{id}. A create repeating the Idempotency-Key of
+// an earlier one returns the item it created with 200.
 func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var createReq models.CreateItemRequest
-	if err := json.NewDecoder(r.Body).Decode(&createReq); err != nil {
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&createReq); err != nil {
 		WriteJSONParseErrorResponse(w, r, err)
 		return
 	}
 
-	// Validate request
-	if err := createReq.Validate(); err != nil {
+	// Admins may skip validation to repair items
+	r, apiErr := skipValidation(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	// Validate the request and charge it to the caller's create budget
+	item, apiErr := h.prepareCreate(r.Context(), &createReq)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	release, ok := h.claimIdempotencyKey(w, r, &createReq, item)
+	if !ok {
+		return
+	}
+
+	if h.createJobs != nil {
+		h.acceptCreate(w, r, item, release)
+		return
+	}
+
+	// Save to repository
+	if err := h.saveCreated(r.Context(), item); err != nil {
+		release()
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data:    h.itemView(r, item),
+	}
+
+	writeJSONResponse(w, http.StatusCreated, response)
+}
+
+// checkReservedID rejects a client-supplied ID in the namespace kept for
+// system items
+func (h *ItemHandler) checkReservedID(id string) *APIError {
+	prefix := h.config.ReservedIDPrefix
+	if prefix == "" || !strings.HasPrefix(strings.ToLower(id), strings.ToLower(prefix)) {
+		return nil
+	}
+	return &APIError{
+		Type:       ErrorTypeAuth,
+		Code:       CodeForbidden,
+		Message:    "Reserved item ID",
+		Details:    fmt.Sprintf("IDs starting with %q are reserved", prefix),
+		StatusCode: http.StatusForbidden,
+	}
+}
+
+// newItem creates the item a validated create request describes, recording
+// who created it and applying the default rules
+func (h *ItemHandler) newItem(ctx context.Context, createReq *models.CreateItemRequest) *models.Item {
+	item := createReq.NewItem()
+	item.CreatedBy = PrincipalFromContext(ctx)
+	item.OwnerID, _ = UserFromContext(ctx)
+	models.ApplyDefaultRules(item, h.config.DefaultRules)
+	return item
+}
+
+// ImportItem handles POST /admin/import requests. Unlike CreateItem it
+// accepts an original created_at, so it is only routed behind the admin guard.
+func (h *ItemHandler) ImportItem(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var importReq models.ImportItemRequest
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&importReq); err != nil {
+		WriteJSONParseErrorResponse(w, r, err)
+		return
+	}
+
+	// Admins may skip validation to repair items
+	r, apiErr := skipValidation(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	// Validate request. The ID is checked even when validation is skipped.
+	if err := importReq.ValidateID(); err != nil {
 		WriteValidationErrorResponse(w, r, err)
 		return
 	}
+	if !repository.SkipsValidation(r.Context()) {
+		if err := importReq.Validate(); err != nil {
+			WriteValidationErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	// Client-supplied IDs may not use the reserved namespace
+	if apiErr := h.checkReservedID(importReq.ID); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
 
-	// Create new item
-	item := models.NewItem(createReq.Name, createReq.Description)
+	// Create new item, preserving the original creation time
+	item := importReq.NewItem()
 
 	// Save to repository
 	if err := h.repo.CreateItem(r.Context(), item); err != nil {
 		WriteRepositoryErrorResponse(w, r, err)
 		return
 	}
+	h.publish(events.ItemCreated, item.ID, item)
+	emf.FromContext(r.Context()).Count(emf.ItemsCreated, 1, nil)
 
 	// Return success response
 	response := models.APIResponse{
 		Success: true,
-		Data:    item,
+		Data:    h.itemView(r, item),
 	}
 
 	writeJSONResponse(w, http.StatusCreated, response)
 }
 
-// GetItem handles GET /items/{id} requests
+// GetItem handles GET /items/{id} requests. With ?download=true the
+// response is marked as a JSON attachment named after the item, and with
+// ?consistent=true the item is read with a strongly consistent read. Items
+// outside their visibility window are not found unless an admin previews
+// them with ?preview=true.
 func (h *ItemHandler) GetItem(w http.ResponseWriter, r *http.Request) {
 	itemID := chi.URLParam(r, "id")
 	if itemID == "" {
@@ -121,8 +286,115 @@ func (h *ItemHandler) GetItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Retrieve item from repository
-	item, err := h.repo.GetItem(r.Context(), itemID)
+	fields, apiErr := requestedFields(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	preview, apiErr := previewRequested(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	includeDeleted, apiErr := includeDeletedRequested(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	download := false
+	if param := r.URL.Query().Get("download"); param != "" {
+		var err error
+		if download, err = strconv.ParseBool(param); err != nil {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid download parameter", "download must be true or false"))
+			return
+		}
+	}
+
+	options := &repository.GetItemOptions{Fields: fields}
+	if param := r.URL.Query().Get("consistent"); param != "" {
+		var err error
+		if options.Consistent, err = strconv.ParseBool(param); err != nil {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid consistent parameter", "consistent must be true or false"))
+			return
+		}
+	}
+
+	// Retrieve item from repository, reading only the requested fields
+	item, err := h.repo.GetItem(r.Context(), itemID, options)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	// Hidden and soft-deleted items look exactly like missing ones
+	if (!preview && !item.IsVisibleAt(time.Now())) || (!includeDeleted && item.IsDeleted()) {
+		WriteRepositoryErrorResponse(w, r, repository.ErrItemNotFound)
+		return
+	}
+	if apiErr := h.authorizeItemOwner(r.Context(), item); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	// Have browsers save the response as a file instead of rendering it
+	if download {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, attachmentName(item.ID)))
+	}
+
+	// Return success response, tagged with the representation sent
+	response := models.APIResponse{
+		Success: true,
+		Data:    h.itemView(r, item),
+	}
+	if fields != nil {
+		if response.Data, err = selectFields(response.Data, fields); err != nil {
+			WriteInternalErrorResponse(w, r, err)
+			return
+		}
+	}
+	etag := itemETag(item, response.Data)
+	w.Header().Set("ETag", etag)
+	if ifNoneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if fields != nil {
+		response.Warnings = h.deprecationWarnings(w, fields)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// attachmentName makes an ID safe to use as a quoted filename, replacing
+// quotes, backslashes, path separators and control characters
+func attachmentName(id string) string {
+	return strings.Map(func(c rune) rune {
+		if c < 0x20 || c == 0x7f || strings.ContainsRune(`"\/`, c) {
+			return '_'
+		}
+		return c
+	}, id)
+}
+
+// CompactItems handles POST /admin/compact requests, permanently removing
+// items soft-deleted before the given cutoff
+func (h *ItemHandler) CompactItems(w http.ResponseWriter, r *http.Request) {
+	beforeParam := r.URL.Query().Get("before")
+	if beforeParam == "" {
+		WriteMissingParameterErrorResponse(w, r, "before")
+		return
+	}
+
+	before, err := time.Parse(time.RFC3339, beforeParam)
+	if err != nil {
+		WriteErrorResponse(w, r, NewValidationError(CodeInvalidFormat, "Invalid before parameter", "before must be an RFC3339 timestamp"))
+		return
+	}
+
+	purged, err := h.repo.CompactDeletedItems(r.Context(), before)
 	if err != nil {
 		WriteRepositoryErrorResponse(w, r, err)
 		return
@@ -131,23 +403,241 @@ func (h *ItemHandler) GetItem(w http.ResponseWriter, r *http.Request) {
 	// Return success response
 	response := models.APIResponse{
 		Success: true,
-		Data:    item,
+		Data: compactResponse{
+			Before: before.UTC().Format(time.RFC3339),
+			Purged: purged,
+		},
 	}
 
 	writeJSONResponse(w, http.StatusOK, response)
 }
 
-// ListItems handles GET /items requests
+// BulkTagItems handles POST /items/bulk-tag requests, adding and removing
+// tags on one batch of the items matching a filter. While has_more is true
+// the caller repeats the request with next_cursor as cursor.
+func (h *ItemHandler) BulkTagItems(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var bulkReq models.BulkTagRequest
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&bulkReq); err != nil {
+		WriteJSONParseErrorResponse(w, r, err)
+		return
+	}
+
+	// Validate request
+	if err := bulkReq.Validate(); err != nil {
+		WriteValidationErrorResponse(w, r, err)
+		return
+	}
+
+	options := &repository.BulkTagOptions{
+		Filter:     bulkReq.Filter,
+		AddTags:    bulkReq.AddTags,
+		RemoveTags: bulkReq.RemoveTags,
+		DryRun:     bulkReq.DryRun,
+	}
+	if bulkReq.Cursor != "" {
+		key, err := h.pageTokens.Decode(bulkReq.Cursor)
+		if err != nil {
+			WriteErrorResponse(w, r, NewPageTokenError(err))
+			return
+		}
+		options.LastEvaluatedKey = key
+	}
+
+	result, err := h.repo.BulkTagItems(r.Context(), options)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	var nextCursor string
+	if result.HasMore {
+		nextCursor, err = h.pageTokens.Encode(result.LastEvaluatedKey)
+		if err != nil {
+			WriteInternalErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	matchedIDs := result.MatchedIDs
+	if matchedIDs == nil {
+		matchedIDs = []string{}
+	}
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data: bulkTagResponse{
+			DryRun:     bulkReq.DryRun,
+			Matched:    len(matchedIDs),
+			MatchedIDs: matchedIDs,
+			Updated:    result.Updated,
+			HasMore:    result.HasMore,
+			NextCursor: nextCursor,
+		},
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// GetRawItem handles GET /admin/items/{id}/raw requests, returning the item's
+// stored attributes in DynamoDB JSON format
+func (h *ItemHandler) GetRawItem(w http.ResponseWriter, r *http.Request) {
+	// Extract ID from URL path
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		WriteMissingParameterErrorResponse(w, r, "id")
+		return
+	}
+
+	raw, err := h.repo.GetRawItem(r.Context(), id)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data:    repository.RawItemJSON(raw),
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// FacetItems handles GET /items/facets?by={field} requests, returning item
+// counts per distinct value of the field. Facets scan the whole table.
+func (h *ItemHandler) FacetItems(w http.ResponseWriter, r *http.Request) {
+	field := r.URL.Query().Get("by")
+	if field == "" {
+		WriteMissingParameterErrorResponse(w, r, "by")
+		return
+	}
+	if !models.IsFacetField(field) {
+		apiErr := NewValidationError(CodeInvalidValue, "Invalid by parameter", "by must be one of: "+strings.Join(models.FacetFields, ", "))
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	facet, err := h.repo.FacetItems(r.Context(), field, h.ownerScope(r.Context()))
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data:    facet,
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// AutocompleteItems handles GET /items/autocomplete?q={prefix}&limit={n}
+// requests, returning the ID and name of items whose name starts with q.
+// Limits above the maximum are capped rather than rejected.
+func (h *ItemHandler) AutocompleteItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		WriteMissingParameterErrorResponse(w, r, "q")
+		return
+	}
+	if err := models.ValidateAutocompleteQuery(query); err != nil {
+		apiErr := NewValidationError(CodeInvalidValue, "Invalid q parameter",
+			fmt.Sprintf("q must be at least %d characters", models.MinAutocompleteQueryLength))
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	limit := models.DefaultAutocompleteLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, apiErr := parseLimit(limitStr)
+		if apiErr != nil {
+			WriteErrorResponse(w, r, apiErr)
+			return
+		}
+		if parsed <= 0 {
+			apiErr := NewValidationError(CodeInvalidFormat, "Invalid limit parameter", "Limit must be a positive integer")
+			WriteErrorResponse(w, r, apiErr)
+			return
+		}
+		limit = min(parsed, models.MaxAutocompleteLimit)
+	}
+
+	items, err := h.repo.AutocompleteItems(r.Context(), strings.TrimSpace(query), limit, h.ownerScope(r.Context()))
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	response := models.APIResponse{
+		Success: true,
+		Data:    models.AutocompleteResult{Items: models.Suggestions(models.VisibleItems(items, time.Now()))},
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// DiffItems handles GET /items/diff?a={id}&b={id} requests
+func (h *ItemHandler) DiffItems(w http.ResponseWriter, r *http.Request) {
+	idA := r.URL.Query().Get("a")
+	if idA == "" {
+		WriteMissingParameterErrorResponse(w, r, "a")
+		return
+	}
+	idB := r.URL.Query().Get("b")
+	if idB == "" {
+		WriteMissingParameterErrorResponse(w, r, "b")
+		return
+	}
+
+	// Retrieve both items from repository
+	itemA, err := h.repo.GetItem(r.Context(), idA, nil)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+	itemB, err := h.repo.GetItem(r.Context(), idB, nil)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+	for _, item := range []*models.Item{itemA, itemB} {
+		if apiErr := h.authorizeItemOwner(r.Context(), item); apiErr != nil {
+			WriteErrorResponse(w, r, apiErr)
+			return
+		}
+	}
+
+	differences := models.DiffItems(itemA, itemB)
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data: diffResponse{
+			A:           idA,
+			B:           idB,
+			Identical:   len(differences) == 0,
+			Differences: differences,
+		},
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// ListItems handles GET /items and GET /items/by-date/{date} requests
 func (h *ItemHandler) ListItems(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters for pagination
 	options := &repository.ListItemsOptions{
-		Limit: 50, // Default limit
+		Limit: int32(h.config.DefaultListLimit),
 	}
+	h.scopeListToOwner(r.Context(), options)
 
 	// Parse limit parameter
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err != nil {
-			apiErr := NewValidationError(CodeInvalidFormat, "Invalid limit parameter", "Limit must be a valid integer")
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr != "" {
+		if limit, apiErr := parseLimit(limitStr); apiErr != nil {
 			WriteErrorResponse(w, r, apiErr)
 			return
 		} else if limit <= 0 || limit > 100 {
@@ -159,11 +649,116 @@ func (h *ItemHandler) ListItems(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse pagination token (simplified - in real implementation would decode properly)
-	if token := r.URL.Query().Get("next_token"); token != "" {
-		// For now, we'll skip complex token parsing
-		// In a real implementation, this would decode the pagination token
-		log.Printf("Pagination token provided: %s", token)
+	// Parse status filter
+	if status := r.URL.Query().Get("status"); status != "" {
+		if !models.IsValidStatus(status) {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid status parameter", models.ErrInvalidStatus.Error()))
+			return
+		}
+		options.StatusFilter = status
+	}
+
+	// GET /items/by-date/{date} lists the items created on that day
+	if date := chi.URLParam(r, "date"); date != "" {
+		if _, err := time.Parse(repository.CreatedDateFormat, date); err != nil {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidFormat, "Invalid date", "Must be a calendar date such as 2024-01-15"))
+			return
+		}
+		options.CreatedDate = date
+	}
+
+	// Parse created_at bounds
+	for param, bound := range map[string]*time.Time{
+		"created_after":  &options.CreatedAfter,
+		"created_before": &options.CreatedBefore,
+	} {
+		if value := r.URL.Query().Get(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				WriteErrorResponse(w, r, NewValidationError(CodeInvalidFormat, "Invalid "+param+" parameter", "Must be an RFC3339 timestamp"))
+				return
+			}
+			*bound = parsed
+		}
+	}
+	if !options.CreatedAfter.IsZero() && !options.CreatedBefore.IsZero() && options.CreatedAfter.After(options.CreatedBefore) {
+		WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid created_at range", "created_after must not be later than created_before"))
+		return
+	}
+
+	// Parse tag filter, given by repeating tag or as a comma-separated tags
+	// list; tag_match picks whether items need every tag or any of them
+	tags, tagged := r.URL.Query()["tag"]
+	if list, ok := r.URL.Query()["tags"]; ok {
+		tagged = true
+		for _, value := range list {
+			tags = append(tags, strings.Split(value, ",")...)
+		}
+	}
+	if tagged {
+		tags = models.NormalizeTags(tags)
+		if err := models.ValidateTags(tags); err != nil {
+			WriteValidationErrorResponse(w, r, err)
+			return
+		}
+		options.TagFilter = tags
+	}
+	switch match := r.URL.Query().Get("tag_match"); match {
+	case "", "all":
+	case "any":
+		options.TagMatchAny = true
+	default:
+		WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid tag_match parameter", "tag_match must be all or any"))
+		return
+	}
+
+	// Parse sort order
+	if field := r.URL.Query().Get("sort"); field != "" {
+		if !models.IsValidSortField(field) {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid sort parameter", models.ErrInvalidSortField.Error()))
+			return
+		}
+		options.SortField = field
+	}
+	switch order := r.URL.Query().Get("order"); order {
+	case "", models.SortAscending:
+	case models.SortDescending:
+		options.Descending = true
+	default:
+		WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid order parameter", models.ErrInvalidSortOrder.Error()))
+		return
+	}
+
+	fields, apiErr := requestedFields(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	preview, apiErr := previewRequested(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	if options.IncludeDeleted, apiErr = includeDeletedRequested(r); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+	options.ProjectionFields = fields
+
+	// Parse pagination token; cursor is accepted as an alias
+	token := r.URL.Query().Get("next_token")
+	if token == "" {
+		token = r.URL.Query().Get("cursor")
+	}
+	if token != "" {
+		key, err := h.pageTokens.Decode(token)
+		if err != nil {
+			WriteErrorResponse(w, r, NewPageTokenError(err))
+			return
+		}
+		options.LastEvaluatedKey = key
 	}
 
 	// Retrieve items from repository
@@ -172,29 +767,70 @@ func (h *ItemHandler) ListItems(w http.ResponseWriter, r *http.Request) {
 		WriteRepositoryErrorResponse(w, r, err)
 		return
 	}
-
-	// Prepare response data
-	responseData := map[string]interface{}{
-		"items":    result.Items,
-		"has_more": result.HasMore,
-		"count":    len(result.Items),
+	if !preview {
+		result.Items = models.VisibleItems(result.Items, time.Now())
 	}
 
-	// Add next token if there are more items (simplified)
+	// Encode next token if there are more items
+	var nextToken string
 	if result.HasMore {
-		responseData["next_token"] = "pagination_token_placeholder"
+		nextToken, err = h.pageTokens.Encode(result.LastEvaluatedKey)
+		if err != nil {
+			WriteInternalErrorResponse(w, r, err)
+			return
+		}
 	}
 
 	// Return success response
+	views := h.itemViews(r, result.Items)
+	truncateDescriptions(views, h.config.ListDescriptionMax)
+	version := h.collectionVersion(r)
+	list := models.NewListResponse(views, result.HasMore, nextToken)
+	list.CollectionVersion = version
 	response := models.APIResponse{
 		Success: true,
-		Data:    responseData,
+		Data:    list,
+	}
+	if fields != nil {
+		selected := make([]sortedObject, len(views))
+		for i := range views {
+			if selected[i], err = selectFields(views[i], fields); err != nil {
+				WriteInternalErrorResponse(w, r, err)
+				return
+			}
+		}
+		list := models.NewListResponse(selected, result.HasMore, nextToken)
+		list.CollectionVersion = version
+		response.Data = list
+		response.Warnings = h.deprecationWarnings(w, fields)
+	}
+	if limitStr == "" && result.HasMore {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("Results are limited to the default page size of %d; pass limit or follow next_token to retrieve more items", options.Limit))
 	}
 
 	writeJSONResponse(w, http.StatusOK, response)
 }
 
-// UpdateItem handles PUT /items/{id} requests
+// collectionVersion returns the repository's collection version, or "" when
+// it doesn't track one. The version is optional, so failing to read it is
+// logged rather than failing the listing.
+func (h *ItemHandler) collectionVersion(r *http.Request) string {
+	versioner, ok := h.repo.(repository.CollectionVersioner)
+	if !ok {
+		return ""
+	}
+	version, err := versioner.CollectionVersion(r.Context())
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("Failed to read collection version", "error", err)
+		return ""
+	}
+	return version
+}
+
+// UpdateItem handles PUT /items/{id} requests. With If-Match, items
+// changed since that ETag was read are rejected with 412. The
+// metadata_mode query parameter chooses whether metadata is merged or
+// replaced.
 func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	itemID := chi.URLParam(r, "id")
 	if itemID == "" {
@@ -204,34 +840,108 @@ func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var updateReq models.UpdateItemRequest
-	if err := json.NewDecoder(r.Body).Decode(&updateReq); err != nil {
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&updateReq); err != nil {
 		WriteJSONParseErrorResponse(w, r, err)
 		return
 	}
+	updateReq.MetadataMode = r.URL.Query().Get("metadata_mode")
 
-	// Validate request
-	if err := updateReq.Validate(); err != nil {
-		WriteValidationErrorResponse(w, r, err)
+	// Admins may skip validation to repair items
+	r, apiErr := skipValidation(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
 		return
 	}
 
 	// Update item in repository
-	item, err := h.repo.UpdateItem(r.Context(), itemID, &updateReq)
+	item, apiErr := h.Update(r.Context(), itemID, &updateReq, r.Header.Get("If-Match"))
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data:    h.itemView(r, item),
+	}
+	w.Header().Set("ETag", itemETag(item, response.Data))
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// PatchItem handles PATCH /items/{id} requests. Unlike PUT, omitted fields
+// are left unchanged and optional fields sent as "" are cleared.
+func (h *ItemHandler) PatchItem(w http.ResponseWriter, r *http.Request) {
+	itemID := chi.URLParam(r, "id")
+	if itemID == "" {
+		WriteMissingParameterErrorResponse(w, r, "Item ID")
+		return
+	}
+
+	// Parse request body
+	var patchReq models.PatchItemRequest
+	if err := json.NewDecoder(h.limitBody(w, r)).Decode(&patchReq); err != nil {
+		WriteJSONParseErrorResponse(w, r, err)
+		return
+	}
+
+	// Admins may skip validation to repair items
+	r, apiErr := skipValidation(r)
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	// Validate request
+	if !repository.SkipsValidation(r.Context()) {
+		if err := patchReq.Validate(); err != nil {
+			WriteValidationErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	owner, apiErr := h.requiredOwner(r.Context())
+	if apiErr == nil {
+		apiErr = h.authorizeItemWrite(r.Context(), itemID)
+	}
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+	patchReq.RequireOwner = owner
+
+	ifMatch, apiErr := ifMatchGeneration(r.Header.Get("If-Match"))
+	if apiErr == nil {
+		apiErr = applyIfMatch(&patchReq.Generation, ifMatch)
+	}
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	// Patch item in repository
+	item, err := h.repo.PatchItem(r.Context(), itemID, &patchReq)
 	if err != nil {
-		WriteRepositoryErrorResponse(w, r, err)
+		WriteErrorResponse(w, r, conditionalError(err, ifMatch, r.Header.Get("If-Match")))
 		return
 	}
+	h.publish(events.ItemUpdated, item.ID, item)
 
 	// Return success response
 	response := models.APIResponse{
 		Success: true,
-		Data:    item,
+		Data:    h.itemView(r, item),
 	}
+	w.Header().Set("ETag", itemETag(item, response.Data))
 
 	writeJSONResponse(w, http.StatusOK, response)
 }
 
-// DeleteItem handles DELETE /items/{id} requests
+// DeleteItem handles DELETE /items/{id} requests. With ?require_status=
+// (or DELETE_REQUIRE_STATUS configured) only items with that status are
+// deleted; others are rejected with 409. With If-Match, items changed since
+// that ETag was read are rejected with 412.
 func (h *ItemHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
 	itemID := chi.URLParam(r, "id")
 	if itemID == "" {
@@ -240,21 +950,51 @@ func (h *ItemHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete item from repository
-	if err := h.repo.DeleteItem(r.Context(), itemID); err != nil {
-		WriteRepositoryErrorResponse(w, r, err)
+	if apiErr := h.Delete(r.Context(), itemID, r.URL.Query().Get("require_status"), r.Header.Get("If-Match")); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
 		return
 	}
 
 	// Return success response
 	response := models.APIResponse{
 		Success: true,
-		Data: map[string]interface{}{
-			"message": "Item deleted successfully",
-			"id":      itemID,
+		Data: deleteResponse{
+			Message: "Item deleted successfully",
+			ID:      itemID,
 		},
 	}
 
 	writeJSONResponse(w, http.StatusOK, response)
 }
 
+// RecordView handles POST /items/{id}/view requests, counting a view of
+// the item and returning the new view count
+func (h *ItemHandler) RecordView(w http.ResponseWriter, r *http.Request) {
+	itemID := chi.URLParam(r, "id")
+	if itemID == "" {
+		WriteMissingParameterErrorResponse(w, r, "Item ID")
+		return
+	}
+
+	if apiErr := h.authorizeItemWrite(r.Context(), itemID); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	count, err := h.repo.IncrementViewCount(r.Context(), itemID)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	// Return success response
+	writeJSONResponse(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: viewCountResponse{
+			ID:        itemID,
+			ViewCount: count,
+		},
+	})
+}
+
 // Helper functions for response creation
@@ -5,11 +5,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/go-chi/chi/v5"
 
 	"fis-playground/internal/models"
@@ -29,7 +35,17 @@ func (m *MockRepository) CreateItem(ctx context.Context, item *models.Item) erro
 	return nil
 }
 
-func (m *MockRepository) GetItem(ctx context.Context, id string) (*models.Item, error) {
+func (m *MockRepository) BatchCreateItems(ctx context.Context, items []*models.Item) ([]error, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	for i, item := range items {
+		item.ID = fmt.Sprintf("test-id-%d", i)
+	}
+	return make([]error, len(items)), nil
+}
+
+func (m *MockRepository) GetItem(ctx context.Context, id string, options *repository.GetItemOptions) (*models.Item, error) {
 	if m.ShouldReturnError != nil {
 		return nil, m.ShouldReturnError
 	}
@@ -41,6 +57,17 @@ func (m *MockRepository) GetItem(ctx context.Context, id string) (*models.Item,
 	}, nil
 }
 
+func (m *MockRepository) BatchGetItems(ctx context.Context, ids []string) ([]models.Item, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	items := make([]models.Item, len(ids))
+	for i, id := range ids {
+		items[i] = models.Item{ID: id, Name: "Test Item", Description: "Test Description", Status: "active"}
+	}
+	return items, nil
+}
+
 func (m *MockRepository) ListItems(ctx context.Context, options *repository.ListItemsOptions) (*repository.ListItemsResult, error) {
 	if m.ShouldReturnError != nil {
 		return nil, m.ShouldReturnError
@@ -70,10 +97,90 @@ func (m *MockRepository) UpdateItem(ctx context.Context, id string, updates *mod
 	}, nil
 }
 
-func (m *MockRepository) DeleteItem(ctx context.Context, id string) error {
+func (m *MockRepository) PatchItem(ctx context.Context, id string, patch *models.PatchItemRequest) (*models.Item, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	item := &models.Item{ID: id, Name: "Test Item", Description: "Test Description", Status: "active"}
+	item.ApplyPatch(patch)
+	return item, nil
+}
+
+func (m *MockRepository) DeleteItem(ctx context.Context, id string, options *repository.DeleteItemOptions) error {
+	return m.ShouldReturnError
+}
+
+func (m *MockRepository) RestoreItem(ctx context.Context, id string, requireOwner string) (*models.Item, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	return &models.Item{ID: id, Name: "Test Item", Description: "Test Description", Status: "active"}, nil
+}
+
+func (m *MockRepository) GetForUpdate(ctx context.Context, id string, holder string, leaseSeconds int) (*models.Item, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	expires := models.LeaseExpiry(time.Now(), leaseSeconds)
+	return &models.Item{ID: id, Name: "Test Item", Description: "Test Description", Status: "active", LeaseHolder: holder, LeaseExpiresAt: &expires}, nil
+}
+
+func (m *MockRepository) PurgeItem(ctx context.Context, id string) error {
 	return m.ShouldReturnError
 }
 
+func (m *MockRepository) IncrementViewCount(ctx context.Context, id string) (int64, error) {
+	if m.ShouldReturnError != nil {
+		return 0, m.ShouldReturnError
+	}
+	return 1, nil
+}
+
+func (m *MockRepository) GetRawItem(ctx context.Context, id string) (map[string]types.AttributeValue, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	return map[string]types.AttributeValue{
+		"id":   &types.AttributeValueMemberS{Value: id},
+		"name": &types.AttributeValueMemberS{Value: "Test Item"},
+	}, nil
+}
+
+func (m *MockRepository) FacetItems(ctx context.Context, field string, scope *repository.OwnerScope) (*models.FacetResult, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	return models.NewFacetResult(field), nil
+}
+
+func (m *MockRepository) AutocompleteItems(ctx context.Context, prefix string, limit int, scope *repository.OwnerScope) ([]models.Item, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) BulkTagItems(ctx context.Context, options *repository.BulkTagOptions) (*repository.BulkTagResult, error) {
+	if m.ShouldReturnError != nil {
+		return nil, m.ShouldReturnError
+	}
+	return &repository.BulkTagResult{}, nil
+}
+
+func (m *MockRepository) DeleteItemsByFilter(ctx context.Context, filter models.ItemFilter, dryRun bool) (int, error) {
+	if m.ShouldReturnError != nil {
+		return 0, m.ShouldReturnError
+	}
+	return 0, nil
+}
+
+func (m *MockRepository) CompactDeletedItems(ctx context.Context, before time.Time) (int, error) {
+	if m.ShouldReturnError != nil {
+		return 0, m.ShouldReturnError
+	}
+	return 0, nil
+}
+
 func TestHealthCheck(t *testing.T) {
 	handler := NewItemHandler(&MockRepository{})
 
@@ -196,6 +303,193 @@ func TestCreateItem_ValidationError(t *testing.T) {
 	}
 }
 
+func TestCreateAndUpdateItem_Tags(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description","tags":[" Sale ","featured"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var created struct {
+		Data models.Item `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !reflect.DeepEqual(created.Data.Tags, []string{"featured", "sale"}) {
+		t.Errorf("Expected trimmed, sorted tags [featured sale], got %v", created.Data.Tags)
+	}
+
+	update := func(body string) {
+		req := httptest.NewRequest("PUT", "/items/"+created.Data.ID, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", created.Data.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.UpdateItem(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusOK, body, w.Code, w.Body.String())
+		}
+	}
+	for _, step := range []struct {
+		body     string
+		expected []string
+	}{
+		{body: `{"tags":["clearance"]}`, expected: []string{"clearance"}},
+		{body: `{"name":"Renamed"}`, expected: []string{"clearance"}},
+		{body: `{"tags":[]}`, expected: nil},
+	} {
+		update(step.body)
+		stored, err := repo.GetItem(context.Background(), created.Data.ID, nil)
+		if err != nil {
+			t.Fatalf("Failed to get item: %v", err)
+		}
+		if !reflect.DeepEqual(stored.Tags, step.expected) {
+			t.Errorf("Expected tags %v after %s, got %v", step.expected, step.body, stored.Tags)
+		}
+	}
+}
+
+func TestCreateItem_DefaultRules(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+	rules, err := models.ParseDefaultRules([]byte(`[{"when": {"category": "inbox"}, "set": {"status": "pending"}}]`))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+	handler.config.DefaultRules = rules
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus string
+	}{
+		{name: "Rule fires", body: `{"name":"Item","description":"Description","category":"inbox"}`, expectedStatus: "pending"},
+		{name: "Rule doesn't fire", body: `{"name":"Item","description":"Description","category":"tools"}`, expectedStatus: "active"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/items", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.CreateItem(w, req)
+
+			var response struct {
+				Data models.Item `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Data.Status != tt.expectedStatus {
+				t.Errorf("Expected status %q, got %q", tt.expectedStatus, response.Data.Status)
+			}
+		})
+	}
+}
+
+func TestCreateItem_TrimsWhitespace(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"  Foo  ","description":"  Described  "}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data models.Item `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	stored, err := repo.GetItem(context.Background(), response.Data.ID, nil)
+	if err != nil {
+		t.Fatalf("Failed to read item: %v", err)
+	}
+	if stored.Name != "Foo" || stored.Description != "Described" {
+		t.Errorf("Expected name %q and description %q, got %q and %q", "Foo", "Described", stored.Name, stored.Description)
+	}
+
+	update := httptest.NewRequest("PUT", "/items/"+stored.ID, strings.NewReader(`{"name":"  Foo   Bar  "}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", stored.ID)
+	update = update.WithContext(context.WithValue(update.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+	handler.UpdateItem(w, update)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if stored, err = repo.GetItem(context.Background(), stored.ID, nil); err != nil || stored.Name != "Foo Bar" {
+		t.Errorf("Expected the updated name %q, got %v (%v)", "Foo Bar", stored, err)
+	}
+}
+
+func TestCreateItem_NameUniqueByCategory(t *testing.T) {
+	t.Setenv("NAME_UNIQUENESS", "category")
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{`{"name":"Hammer","description":"Description","category":"tools"}`, http.StatusCreated},
+		{`{"name":"Hammer","description":"Description","category":"garden"}`, http.StatusCreated},
+		{`{"name":"Hammer","description":"Description","category":"tools"}`, http.StatusConflict},
+	}
+	for i, tt := range tests {
+		req := httptest.NewRequest("POST", "/items", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.CreateItem(w, req)
+		if w.Code != tt.want {
+			t.Fatalf("Create %d: expected status %d, got %d: %s", i+1, tt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestCreateItem_InvalidTags(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	tooMany := make([]string, models.MaxTagsPerItem+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%q", fmt.Sprintf("tag-%d", i))
+	}
+	tests := map[string]ErrorCode{
+		`["sale","SALE "]`:                     CodeInvalidValue,
+		`[""]`:                                 CodeInvalidValue,
+		`["has space"]`:                        CodeInvalidValue,
+		`["` + strings.Repeat("a", 51) + `"]`:  CodeValueTooLong,
+		"[" + strings.Join(tooMany, ",") + "]": CodeValueTooLong,
+	}
+	for tags, code := range tests {
+		body := `{"name":"Item","description":"Description","tags":` + tags + `}`
+		req := httptest.NewRequest("POST", "/items", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.CreateItem(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for tags %s, got %d", http.StatusBadRequest, tags, w.Code)
+		}
+		var response models.APIResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error == nil || response.Error.Code != string(code) {
+			t.Errorf("Expected error code %s for tags %s, got %+v", code, tags, response.Error)
+		}
+	}
+}
+
 func TestCreateItem_InvalidJSON(t *testing.T) {
 	handler := NewItemHandler(&MockRepository{})
 
@@ -227,6 +521,60 @@ func TestCreateItem_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestCreateItem_BodyTooLarge(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	handler.config.MaxBodyBytes = 1024
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode string
+		expectedHTTP int
+	}{
+		{name: "Oversized", body: `{"name":"Item","description":"` + strings.Repeat("x", 2048) + `"}`, expectedCode: string(CodePayloadTooLarge), expectedHTTP: http.StatusRequestEntityTooLarge},
+		{name: "Oversized malformed", body: `{"name":"` + strings.Repeat("x", 2048), expectedCode: string(CodePayloadTooLarge), expectedHTTP: http.StatusRequestEntityTooLarge},
+		{name: "Small malformed", body: `{"name":"Item",`, expectedCode: string(CodeInvalidFormat), expectedHTTP: http.StatusBadRequest},
+		{name: "Malformed at the limit", body: `{"name":"` + strings.Repeat("x", 1014), expectedCode: string(CodeInvalidFormat), expectedHTTP: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/items", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.CreateItem(w, req)
+
+			if w.Code != tt.expectedHTTP {
+				t.Fatalf("Expected status %d, got %d", tt.expectedHTTP, w.Code)
+			}
+			var response models.APIResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error.Code != tt.expectedCode {
+				t.Errorf("Expected error code %s, got %s", tt.expectedCode, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestUpdateItem_BodyTooLarge(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	handler.config.MaxBodyBytes = 1024
+
+	req := httptest.NewRequest("PUT", "/items/test-id", strings.NewReader(`{"name":"`+strings.Repeat("x", 2048)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "test-id")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	handler.UpdateItem(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
 func TestCreateItem_RepositoryError(t *testing.T) {
 	mockRepo := &MockRepository{
 		ShouldReturnError: repository.ErrItemAlreadyExists,
@@ -263,6 +611,112 @@ func TestCreateItem_RepositoryError(t *testing.T) {
 	}
 }
 
+func TestCreateItem_ThroughputErrorSetsRetryAfter(t *testing.T) {
+	mockRepo := &MockRepository{
+		ShouldReturnError: fmt.Errorf("%w: throughput exceeded", repository.ErrOperationFailed),
+	}
+	handler := NewItemHandler(mockRepo)
+
+	body, _ := json.Marshal(models.CreateItemRequest{Name: "Test Item", Description: "Test Description"})
+	req := httptest.NewRequest("POST", "/items", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateItem(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != strconv.Itoa(DefaultRetryAfter) {
+		t.Errorf("Expected Retry-After %d, got %q", DefaultRetryAfter, got)
+	}
+}
+
+func TestWriteErrorResponse_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiErr   *APIError
+		expected string
+	}{
+		{"throughput error with its own delay", &APIError{Code: CodeThroughputExceeded, StatusCode: http.StatusTooManyRequests, RetryAfter: 7}, "7"},
+		{"rate limit error uses the default", &APIError{Code: CodeRateLimitExceeded, StatusCode: http.StatusTooManyRequests}, strconv.Itoa(DefaultRetryAfter)},
+		{"other errors have none", NewNotFoundError("Item", "item-1"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			WriteErrorResponse(w, httptest.NewRequest("GET", "/items", nil), tt.apiErr)
+
+			if got := w.Header().Get("Retry-After"); got != tt.expected {
+				t.Errorf("Expected Retry-After %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetItem_ErrorFormatFollowsAccept(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	getMissing := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/items/missing", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "missing")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.GetItem(w, req)
+		return w
+	}
+
+	t.Run("problem+json", func(t *testing.T) {
+		w := getMissing("application/json;q=0.5, application/problem+json")
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+			t.Errorf("Expected Content-Type %s, got %q", ProblemContentType, ct)
+		}
+		var problem models.ProblemDetails
+		if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+			t.Fatalf("Failed to decode problem: %v", err)
+		}
+		expected := models.ProblemDetails{
+			Type:   "/errors#NOT_FOUND",
+			Title:  "Resource not found",
+			Status: http.StatusNotFound,
+			Detail: "item not found",
+			Code:   string(CodeNotFound),
+		}
+		if problem != expected {
+			t.Errorf("Expected %+v, got %+v", expected, problem)
+		}
+	})
+
+	for _, accept := range []string{"", "application/json", "application/problem+json;q=0"} {
+		t.Run("APIResponse for Accept "+accept, func(t *testing.T) {
+			w := getMissing(accept)
+
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected Content-Type application/json, got %q", ct)
+			}
+			var response models.APIResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Success || response.Error == nil || response.Error.Code != string(CodeNotFound) || response.Error.Message != "Resource not found" {
+				t.Errorf("Expected a NOT_FOUND APIResponse, got %+v", response)
+			}
+		})
+	}
+}
+
 func TestGetItem_MissingID(t *testing.T) {
 	handler := NewItemHandler(&MockRepository{})
 
@@ -328,6 +782,45 @@ func TestGetItem_NotFound(t *testing.T) {
 	}
 }
 
+func TestListItems_LimitFormats(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         string
+		expectedLimit int32
+		expectedCode  ErrorCode
+		expectedText  string
+	}{
+		{name: "Surrounding whitespace", limit: " 50 ", expectedLimit: 50},
+		{name: "Leading plus", limit: "+50", expectedLimit: 50},
+		{name: "Decimal", limit: "50.0", expectedCode: CodeInvalidFormat, expectedText: "whole number"},
+		{name: "Not a number", limit: "abc", expectedCode: CodeInvalidFormat, expectedText: "valid integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &readOptionsRepository{}
+			handler := NewItemHandler(repo)
+
+			req := httptest.NewRequest("GET", "/items?limit="+url.QueryEscape(tt.limit), nil)
+			w := httptest.NewRecorder()
+			handler.ListItems(w, req)
+
+			if tt.expectedCode == "" {
+				if w.Code != http.StatusOK {
+					t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+				}
+				if repo.listOptions.Limit != tt.expectedLimit {
+					t.Errorf("Expected limit %d, got %d", tt.expectedLimit, repo.listOptions.Limit)
+				}
+				return
+			}
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), string(tt.expectedCode)) || !strings.Contains(w.Body.String(), tt.expectedText) {
+				t.Errorf("Expected %d %s mentioning %q, got %d: %s", http.StatusBadRequest, tt.expectedCode, tt.expectedText, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestListItems_InvalidLimit(t *testing.T) {
 	handler := NewItemHandler(&MockRepository{})
 
@@ -452,6 +945,86 @@ func TestDeleteItem_NotFound(t *testing.T) {
 	}
 }
 
+func TestDeleteItem_RequireStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         string
+		expectedStatus int
+	}{
+		{"Inactive item is deleted", "inactive", http.StatusOK},
+		{"Active item is rejected", "active", http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			item := models.NewItem("Item", "Description")
+			item.Status = tt.status
+			if err := repo.CreateItem(context.Background(), item); err != nil {
+				t.Fatalf("Failed to seed item: %v", err)
+			}
+			handler := NewItemHandler(repo)
+
+			req := httptest.NewRequest("DELETE", "/items/"+item.ID+"?require_status=inactive", nil)
+			w := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", item.ID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			handler.DeleteItem(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			_, err := repo.GetItem(context.Background(), item.ID, nil)
+			if tt.expectedStatus == http.StatusOK {
+				if !errors.Is(err, repository.ErrItemNotFound) {
+					t.Errorf("Expected item to be deleted, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected item to remain, got %v", err)
+			}
+			var response models.APIResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error == nil || response.Error.Code != string(CodePreconditionFailed) {
+				t.Errorf("Expected error code %s, got %+v", CodePreconditionFailed, response.Error)
+			}
+		})
+	}
+}
+
+func TestDeleteItem_ConfiguredRequireStatus(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+	handler.config.DeleteRequireStatus = "inactive"
+
+	for query, expectedStatus := range map[string]int{"": http.StatusConflict, "?require_status=active": http.StatusBadRequest} {
+		req := httptest.NewRequest("DELETE", "/items/"+item.ID+query, nil)
+		w := httptest.NewRecorder()
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", item.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.DeleteItem(w, req)
+
+		if w.Code != expectedStatus {
+			t.Errorf("Expected status %d for %q, got %d", expectedStatus, query, w.Code)
+		}
+	}
+}
+
 // Test error mapping functions
 
 func TestMapRepositoryError(t *testing.T) {
@@ -476,6 +1049,20 @@ func TestMapRepositoryError(t *testing.T) {
 			expectedCode:   CodeAlreadyExists,
 			expectedStatus: http.StatusConflict,
 		},
+		{
+			name:           "Limit reached error",
+			inputError:     repository.ErrLimitReached,
+			expectedType:   ErrorTypeConflict,
+			expectedCode:   CodeLimitReached,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Stale generation error",
+			inputError:     repository.ErrStaleGeneration,
+			expectedType:   ErrorTypeConflict,
+			expectedCode:   CodeStaleGeneration,
+			expectedStatus: http.StatusConflict,
+		},
 		{
 			name:           "Validation error",
 			inputError:     repository.ErrInvalidInput,
@@ -547,14 +1134,39 @@ func TestMapValidationError(t *testing.T) {
 			expectedCode: CodeInvalidValue,
 		},
 		{
-			name:         "Generic validation error",
-			inputError:   errors.New("some validation error"),
-			expectedCode: CodeInvalidRequest,
+			name:         "Too many tags",
+			inputError:   models.ErrTooManyTags,
+			expectedCode: CodeValueTooLong,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
+		{
+			name:         "Tag too long",
+			inputError:   models.ErrTagTooLong,
+			expectedCode: CodeValueTooLong,
+		},
+		{
+			name:         "Duplicate tag",
+			inputError:   models.ErrDuplicateTag,
+			expectedCode: CodeInvalidValue,
+		},
+		{
+			name:         "Invalid tag",
+			inputError:   models.ErrInvalidTag,
+			expectedCode: CodeInvalidValue,
+		},
+		{
+			name:         "Invalid TTL",
+			inputError:   models.ErrInvalidTTL,
+			expectedCode: CodeInvalidValue,
+		},
+		{
+			name:         "Generic validation error",
+			inputError:   errors.New("some validation error"),
+			expectedCode: CodeInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
 			apiErr := MapValidationError(tt.inputError)
 
 			if apiErr.Type != ErrorTypeValidation {
@@ -570,4 +1182,1433 @@ func TestMapValidationError(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestImportItem_Backdated(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	body := `{"name": "Legacy Item", "description": "Imported", "created_at": "2020-01-02T03:04:05Z"}`
+	req := httptest.NewRequest("POST", "/admin/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	before := time.Now()
+	handler.ImportItem(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var response struct {
+		Data models.Item `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	expected := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if !response.Data.CreatedAt.Equal(expected) {
+		t.Errorf("Expected created_at %v, got %v", expected, response.Data.CreatedAt)
+	}
+	if response.Data.UpdatedAt.Before(before.Add(-time.Second)) {
+		t.Errorf("Expected updated_at to be the import time, got %v", response.Data.UpdatedAt)
+	}
+}
+
+func TestImportItem_FutureCreatedAt(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	body := `{"name": "Item", "description": "Imported", "created_at": "` + future + `"}`
+	req := httptest.NewRequest("POST", "/admin/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ImportItem(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Error.Code != "INVALID_VALUE" {
+		t.Errorf("Expected error code 'INVALID_VALUE', got '%s'", response.Error.Code)
+	}
+}
+
+func TestDiffItems(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	itemA := models.NewItem("Widget", "Same description")
+	itemA.ID = "a"
+	itemB := models.NewItem("Widget v2", "Same description")
+	itemB.ID = "b"
+	itemB.Status = "inactive"
+	itemB.CreatedAt = itemA.CreatedAt
+	itemB.UpdatedAt = itemA.UpdatedAt
+	for _, item := range []*models.Item{itemA, itemB} {
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("GET", "/items/diff?a=a&b=b", nil)
+	w := httptest.NewRecorder()
+
+	handler.DiffItems(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Data struct {
+			Identical   bool               `json:"identical"`
+			Differences []models.FieldDiff `json:"differences"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Data.Identical {
+		t.Error("Expected items not to be identical")
+	}
+
+	fields := map[string]models.FieldDiff{}
+	for _, diff := range response.Data.Differences {
+		fields[diff.Field] = diff
+	}
+	if len(fields) != 2 {
+		t.Errorf("Expected 2 differing fields, got %v", response.Data.Differences)
+	}
+	if fields["name"].A != "Widget" || fields["name"].B != "Widget v2" {
+		t.Errorf("Unexpected name diff: %+v", fields["name"])
+	}
+	if fields["status"].A != "active" || fields["status"].B != "inactive" {
+		t.Errorf("Unexpected status diff: %+v", fields["status"])
+	}
+}
+
+func TestDiffItems_MissingItem(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Widget", "Description")
+	item.ID = "a"
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("GET", "/items/diff?a=a&b=missing", nil)
+	w := httptest.NewRecorder()
+
+	handler.DiffItems(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestCompactItems(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := map[string]*time.Time{
+		"Old":  timePtr(cutoff.Add(-48 * time.Hour)),
+		"New":  timePtr(cutoff.Add(time.Hour)),
+		"Live": nil,
+	}
+	ids := map[string]string{}
+	for name, deletedAt := range seed {
+		item := models.NewItem(name, "Description")
+		item.DeletedAt = deletedAt
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+		ids[name] = item.ID
+	}
+
+	handler := NewItemHandler(repo)
+	req := httptest.NewRequest("POST", "/admin/compact?before="+cutoff.Format(time.RFC3339), nil)
+	w := httptest.NewRecorder()
+
+	handler.CompactItems(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Data struct {
+			Purged int `json:"purged"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Purged != 1 {
+		t.Errorf("Expected 1 item purged, got %d", response.Data.Purged)
+	}
+
+	if _, err := repo.GetItem(context.Background(), ids["Old"], nil); !repository.IsNotFoundError(err) {
+		t.Errorf("Expected item deleted before the cutoff to be purged, got %v", err)
+	}
+	for _, name := range []string{"New", "Live"} {
+		if _, err := repo.GetItem(context.Background(), ids[name], nil); err != nil {
+			t.Errorf("Expected item %q to be kept, got %v", name, err)
+		}
+	}
+}
+
+func TestCompactItems_InvalidBefore(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	tests := []struct {
+		name         string
+		target       string
+		expectedCode string
+	}{
+		{name: "Missing before", target: "/admin/compact", expectedCode: "MISSING_FIELD"},
+		{name: "Malformed before", target: "/admin/compact?before=yesterday", expectedCode: "INVALID_FORMAT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.target, nil)
+			w := httptest.NewRecorder()
+
+			handler.CompactItems(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+
+			var response models.APIResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error.Code != tt.expectedCode {
+				t.Errorf("Expected error code '%s', got '%s'", tt.expectedCode, response.Error.Code)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestListItems_TypedResponse(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListItems(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	list, err := models.ParseListResponse(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if list.Count != 1 || len(list.Items) != 1 {
+		t.Fatalf("Expected 1 item, got count=%d items=%d", list.Count, len(list.Items))
+	}
+	if list.Items[0].Name != "Item 1" {
+		t.Errorf("Expected item name 'Item 1', got '%s'", list.Items[0].Name)
+	}
+	if list.HasMore || list.NextToken != "" {
+		t.Errorf("Expected a single page, got has_more=%v next_token=%q", list.HasMore, list.NextToken)
+	}
+}
+
+func TestListItems_CollectionVersion(t *testing.T) {
+	t.Setenv("COLLECTION_VERSION", "true")
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+
+	listVersion := func() string {
+		w := httptest.NewRecorder()
+		handler.ListItems(w, httptest.NewRequest("GET", "/items", nil))
+		list, err := models.ParseListResponse(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return list.CollectionVersion
+	}
+
+	before := listVersion()
+	if before == "" {
+		t.Fatal("Expected a collection_version")
+	}
+	if again := listVersion(); again != before {
+		t.Errorf("Expected the version to stay %s without changes, got %s", before, again)
+	}
+	if _, err := repo.UpdateItem(context.Background(), item.ID, &models.UpdateItemRequest{Description: "Changed"}); err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+	if after := listVersion(); after == before {
+		t.Errorf("Expected the update to change the version %s", before)
+	}
+}
+
+func TestListItems_ByDate(t *testing.T) {
+	t.Setenv("CREATED_DATE_TIMEZONE", "America/New_York")
+	repo := repository.NewMemoryRepository()
+	// New York is UTC-5, so its 15th runs from 05:00 UTC on the 15th to
+	// 05:00 UTC on the 16th
+	for name, createdAt := range map[string]time.Time{
+		"Evening of the 14th": time.Date(2024, 1, 15, 4, 59, 0, 0, time.UTC),
+		"Morning of the 15th": time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC),
+		"Evening of the 15th": time.Date(2024, 1, 16, 4, 59, 0, 0, time.UTC),
+		"Morning of the 16th": time.Date(2024, 1, 16, 5, 0, 0, 0, time.UTC),
+	} {
+		item := models.NewItem(name, "Description")
+		item.CreatedAt = createdAt
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	listDate := func(date string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/items/by-date/"+date, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("date", date)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)))
+		return w
+	}
+
+	w := listDate("2024-01-15")
+	list, err := models.ParseListResponse(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.Name)
+	}
+	if want := []string{"Morning of the 15th", "Evening of the 15th"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+
+	for _, date := range []string{"2024-1-15", "2024-02-30", "yesterday"} {
+		if w := listDate(date); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %q, got %d", date, w.Code)
+		}
+	}
+}
+
+func TestGetRawItem(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Raw Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("GET", "/admin/items/"+item.ID+"/raw", nil)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", item.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	handler.GetRawItem(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Data map[string]map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if got := response.Data["name"]["S"]; got != "Raw Item" {
+		t.Errorf("Expected name stored as S 'Raw Item', got %v", response.Data["name"])
+	}
+	if got := response.Data["generation"]["N"]; got != "1" {
+		t.Errorf("Expected generation stored as N '1', got %v", response.Data["generation"])
+	}
+}
+
+func TestFacetItems(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	seed := []struct {
+		status   string
+		category string
+	}{
+		{"active", "tools"},
+		{"active", "toys"},
+		{"inactive", "tools"},
+		{"pending", ""},
+	}
+	for _, s := range seed {
+		item := models.NewItem("Item", "Description")
+		item.Status = s.status
+		item.Category = s.category
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	tests := []struct {
+		by             string
+		expectedCounts map[string]int
+		expectedMissed int
+	}{
+		{"status", map[string]int{"active": 2, "inactive": 1, "pending": 1}, 0},
+		{"category", map[string]int{"tools": 2, "toys": 1}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/items/facets?by="+tt.by, nil)
+			w := httptest.NewRecorder()
+
+			handler.FacetItems(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var response struct {
+				Data models.FacetResult `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if len(response.Data.Counts) != len(tt.expectedCounts) {
+				t.Errorf("Expected counts %v, got %v", tt.expectedCounts, response.Data.Counts)
+			}
+			for value, count := range tt.expectedCounts {
+				if response.Data.Counts[value] != count {
+					t.Errorf("Expected %s=%d, got %d", value, count, response.Data.Counts[value])
+				}
+			}
+			if response.Data.Missing != tt.expectedMissed {
+				t.Errorf("Expected %d items missing %s, got %d", tt.expectedMissed, tt.by, response.Data.Missing)
+			}
+		})
+	}
+}
+
+func TestFacetItems_InvalidField(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	for query, code := range map[string]string{"": "MISSING_FIELD", "?by=description": "INVALID_VALUE"} {
+		req := httptest.NewRequest("GET", "/items/facets"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler.FacetItems(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, query, w.Code)
+		}
+		var response models.APIResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error == nil || response.Error.Code != code {
+			t.Errorf("Expected error code %s for %q, got %+v", code, query, response.Error)
+		}
+	}
+}
+
+func TestAutocompleteItems(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for _, name := range []string{"Fork", "foam roller", "Football", "Spoon"} {
+		if err := repo.CreateItem(context.Background(), models.NewItem(name, "Description")); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"q=fo", []string{"foam roller", "Football", "Fork"}},
+		{"q=FOR", []string{"Fork"}},
+		{"q=fo&limit=2", []string{"foam roller", "Football"}},
+		{"q=sp&limit=500", []string{"Spoon"}},
+		{"q=knife", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.AutocompleteItems(w, httptest.NewRequest("GET", "/items/autocomplete?"+tt.query, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			var response struct {
+				Data struct {
+					Items []map[string]interface{} `json:"items"`
+				} `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			names := []string{}
+			for _, item := range response.Data.Items {
+				if len(item) != 2 || item["id"] == "" {
+					t.Errorf("Expected only id and name, got %v", item)
+				}
+				names = append(names, item["name"].(string))
+			}
+			if !reflect.DeepEqual(names, tt.expectedNames) {
+				t.Errorf("Expected %v, got %v", tt.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestAutocompleteItems_InvalidQuery(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode ErrorCode
+	}{
+		{"missing q", "", CodeMissingField},
+		{"too short", "q=f", CodeInvalidValue},
+		{"too short after trimming", "q=+f+", CodeInvalidValue},
+		{"invalid limit", "q=fo&limit=none", CodeInvalidFormat},
+		{"zero limit", "q=fo&limit=0", CodeInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.AutocompleteItems(w, httptest.NewRequest("GET", "/items/autocomplete?"+tt.query, nil))
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			var response models.APIResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error.Code != string(tt.expectedCode) {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestListItems_StatusFilter(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for _, status := range []string{"active", "inactive", "active", "pending"} {
+		item := models.NewItem("Item", "Description")
+		item.Status = status
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("GET", "/items?status=active", nil)
+	w := httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	list, err := models.ParseListResponse(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if list.Count != 2 {
+		t.Errorf("Expected 2 active items, got %d", list.Count)
+	}
+	for _, item := range list.Items {
+		if item.Status != "active" {
+			t.Errorf("Expected only active items, got status %s", item.Status)
+		}
+	}
+}
+
+func TestListItems_DefaultLimitWarning(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for i := 0; i < 3; i++ {
+		if err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description")); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+	handler.config.DefaultListLimit = 2
+
+	tests := []struct {
+		query       string
+		expectWarn  bool
+		expectCount int
+	}{
+		{query: "", expectWarn: true, expectCount: 2},
+		{query: "?limit=2", expectWarn: false, expectCount: 2},
+		{query: "?limit=5", expectWarn: false, expectCount: 3},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/items"+tt.query, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		var response models.APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		warned := false
+		for _, warning := range response.Warnings {
+			warned = warned || strings.Contains(warning, "default page size of 2")
+		}
+		if warned != tt.expectWarn {
+			t.Errorf("Expected warning=%v for %q, got warnings %v", tt.expectWarn, tt.query, response.Warnings)
+		}
+
+		list, err := models.ParseListResponse(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if list.Count != tt.expectCount {
+			t.Errorf("Expected %d items for %q, got %d", tt.expectCount, tt.query, list.Count)
+		}
+	}
+
+	// The last page of a defaulted listing has nothing more to warn about
+	handler.config.DefaultListLimit = 5
+	req := httptest.NewRequest("GET", "/items", nil)
+	w := httptest.NewRecorder()
+	handler.ListItems(w, req)
+	var response models.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Warnings) != 0 {
+		t.Errorf("Expected no warnings without more items, got %v", response.Warnings)
+	}
+}
+
+func TestListItems_TagFilter(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for _, tags := range [][]string{{"urgent"}, {"urgent", "sale"}, {"sale"}, nil} {
+		item := models.NewItem("Item", "Description")
+		item.Tags = tags
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{query: "tag=urgent", expected: 2},
+		{query: "tag=URGENT", expected: 2},
+		{query: "tag=urgent&tag=sale", expected: 1},
+		{query: "tag=urgent&tag=urgent", expected: 2},
+		{query: "tags=urgent,sale", expected: 1},
+		{query: "tags=urgent,sale&tag_match=all", expected: 1},
+		{query: "tags=urgent,sale&tag_match=any", expected: 3},
+		{query: "tags=urgent&tag=sale&tag_match=any", expected: 3},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/items?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		list, err := models.ParseListResponse(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Failed to parse response for %q: %v", tt.query, err)
+		}
+		if list.Count != tt.expected {
+			t.Errorf("Expected %d items for %q, got %d", tt.expected, tt.query, list.Count)
+		}
+	}
+
+	for query, code := range map[string]ErrorCode{
+		"tag=":                           CodeInvalidValue,
+		"tag=has%20space":                CodeInvalidValue,
+		"tag=" + strings.Repeat("a", 51): CodeValueTooLong,
+		"tags=urgent,":                   CodeInvalidValue,
+		"tags=urgent,sale&tag_match=one": CodeInvalidValue,
+	} {
+		req := httptest.NewRequest("GET", "/items?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		var response models.APIResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if w.Code != http.StatusBadRequest || response.Error == nil || response.Error.Code != string(code) {
+			t.Errorf("Expected 400 %s for %q, got %d %+v", code, query, w.Code, response.Error)
+		}
+	}
+}
+
+func TestListItems_InvalidStatusFilter(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	req := httptest.NewRequest("GET", "/items?status=archived", nil)
+	w := httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error.Code != string(CodeInvalidValue) {
+		t.Errorf("Expected error code '%s', got '%s'", CodeInvalidValue, response.Error.Code)
+	}
+}
+
+func TestListItems_CreatedRange(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, age := range []time.Duration{10 * 24 * time.Hour, 3 * 24 * time.Hour, time.Hour} {
+		item := models.NewItem("Item", "Description")
+		item.CreatedAt = now.Add(-age)
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{query: "created_after=" + now.Add(-7*24*time.Hour).Format(time.RFC3339), expected: 2},
+		{query: "created_before=" + now.Add(-2*time.Hour).Format(time.RFC3339), expected: 2},
+		{query: "created_after=" + now.Add(-7*24*time.Hour).Format(time.RFC3339) + "&created_before=" + now.Add(-2*time.Hour).Format(time.RFC3339), expected: 1},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/items?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		list, err := models.ParseListResponse(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Failed to parse response for %q: %v", tt.query, err)
+		}
+		if list.Count != tt.expected {
+			t.Errorf("Expected %d items for %q, got %d", tt.expected, tt.query, list.Count)
+		}
+	}
+}
+
+func TestListItems_InvalidCreatedRange(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	tests := map[string]ErrorCode{
+		"created_after=yesterday":   CodeInvalidFormat,
+		"created_before=2024-01-15": CodeInvalidFormat,
+		"created_after=2024-02-01T00:00:00Z&created_before=2024-01-01T00:00:00Z": CodeInvalidValue,
+	}
+	for query, code := range tests {
+		req := httptest.NewRequest("GET", "/items?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, query, w.Code)
+		}
+		var response models.APIResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error == nil || response.Error.Code != string(code) {
+			t.Errorf("Expected error code %s for %q, got %+v", code, query, response.Error)
+		}
+	}
+}
+
+func patchItem(handler *ItemHandler, id, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("PATCH", "/items/"+id, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	handler.PatchItem(w, req)
+	return w
+}
+
+func TestPatchItem_OmitVersusClear(t *testing.T) {
+	tests := []struct {
+		name                string
+		body                string
+		expectedName        string
+		expectedDescription string
+		expectedCategory    string
+	}{
+		{
+			name:                "Omitted fields are unchanged",
+			body:                `{"name":"Renamed"}`,
+			expectedName:        "Renamed",
+			expectedDescription: "Original description",
+			expectedCategory:    "tools",
+		},
+		{
+			name:                "Empty description is cleared",
+			body:                `{"description":""}`,
+			expectedName:        "Original",
+			expectedDescription: "",
+			expectedCategory:    "tools",
+		},
+		{
+			name:                "Empty category is cleared alongside a change",
+			body:                `{"category":"","description":"New description"}`,
+			expectedName:        "Original",
+			expectedDescription: "New description",
+			expectedCategory:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			item := models.NewItem("Original", "Original description")
+			item.Category = "tools"
+			if err := repo.CreateItem(context.Background(), item); err != nil {
+				t.Fatalf("Failed to seed item: %v", err)
+			}
+			handler := NewItemHandler(repo)
+
+			w := patchItem(handler, item.ID, tt.body)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			stored, err := repo.GetItem(context.Background(), item.ID, nil)
+			if err != nil {
+				t.Fatalf("Failed to get item: %v", err)
+			}
+			if stored.Name != tt.expectedName || stored.Description != tt.expectedDescription || stored.Category != tt.expectedCategory {
+				t.Errorf("Expected name=%q description=%q category=%q, got name=%q description=%q category=%q",
+					tt.expectedName, tt.expectedDescription, tt.expectedCategory, stored.Name, stored.Description, stored.Category)
+			}
+			if stored.Generation != 2 {
+				t.Errorf("Expected generation 2, got %d", stored.Generation)
+			}
+		})
+	}
+}
+
+func TestPatchItem_NullRemovesOptionalAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		tags     []string
+		metadata map[string]string
+		expires  bool
+	}{
+		{name: "Omitted fields are unchanged", body: `{"name":"Renamed"}`, tags: []string{"sale"}, metadata: map[string]string{"color": "red"}, expires: true},
+		{name: "Null tags are removed", body: `{"tags":null}`, metadata: map[string]string{"color": "red"}, expires: true},
+		{name: "Null metadata and expiry are removed", body: `{"metadata":null,"expires_at":null}`, tags: []string{"sale"}},
+		{name: "Values replace", body: `{"tags":["New"],"metadata":{"size":"L"}}`, tags: []string{"new"}, metadata: map[string]string{"size": "L"}, expires: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			item := models.NewItem("Original", "Original description")
+			item.Tags = []string{"sale"}
+			item.Metadata = map[string]string{"color": "red"}
+			expires := time.Now().Add(time.Hour).Truncate(time.Second)
+			item.ExpiresAt = &expires
+			if err := repo.CreateItem(context.Background(), item); err != nil {
+				t.Fatalf("Failed to seed item: %v", err)
+			}
+			handler := NewItemHandler(repo)
+
+			w := patchItem(handler, item.ID, tt.body)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			stored, err := repo.GetItem(context.Background(), item.ID, nil)
+			if err != nil {
+				t.Fatalf("Failed to get item: %v", err)
+			}
+			if !reflect.DeepEqual(stored.Tags, tt.tags) {
+				t.Errorf("Expected tags %v, got %v", tt.tags, stored.Tags)
+			}
+			if !reflect.DeepEqual(stored.Metadata, tt.metadata) {
+				t.Errorf("Expected metadata %v, got %v", tt.metadata, stored.Metadata)
+			}
+			if (stored.ExpiresAt != nil) != tt.expires {
+				t.Errorf("Expected expiry set to be %t, got %v", tt.expires, stored.ExpiresAt)
+			}
+			if tt.tags == nil && strings.Contains(w.Body.String(), `"tags"`) {
+				t.Errorf("Expected removed tags to be absent from the response, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPatchItem_InvalidExpiresAt(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	w := patchItem(handler, "test-id", `{"expires_at":"2000-01-01T00:00:00Z"}`)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), string(CodeInvalidValue)) {
+		t.Errorf("Expected %s, got %s", CodeInvalidValue, w.Body.String())
+	}
+}
+
+func TestPatchItem_RequiredFieldsCannotBeCleared(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	for _, body := range []string{`{"name":""}`, `{"status":""}`} {
+		w := patchItem(handler, "test-id", body)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, body, w.Code)
+		}
+	}
+}
+
+func bulkTag(handler *ItemHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/items/bulk-tag", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.BulkTagItems(w, req)
+	return w
+}
+
+func TestBulkTagItems_AddAndRemoveAcrossFilteredSet(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	var active, inactive []string
+	for i, status := range []string{"active", "inactive", "active", "active", "inactive"} {
+		item := models.NewItem(fmt.Sprintf("Item %d", i), "Description")
+		item.Status = status
+		item.Tags = []string{"legacy"}
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+		if status == "active" {
+			active = append(active, item.ID)
+		} else {
+			inactive = append(inactive, item.ID)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	w := bulkTag(handler, `{"filter":{"status":"active"},"add_tags":["Sale","featured"],"remove_tags":["legacy"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data struct {
+			Matched int  `json:"matched"`
+			Updated int  `json:"updated"`
+			HasMore bool `json:"has_more"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Matched != 3 || response.Data.Updated != 3 || response.Data.HasMore {
+		t.Errorf("Expected 3 matched and updated with no more, got %+v", response.Data)
+	}
+
+	for _, id := range active {
+		item, _ := repo.GetItem(context.Background(), id, nil)
+		if !reflect.DeepEqual(item.Tags, []string{"featured", "sale"}) {
+			t.Errorf("Expected active item tags [featured sale], got %v", item.Tags)
+		}
+	}
+	for _, id := range inactive {
+		item, _ := repo.GetItem(context.Background(), id, nil)
+		if !reflect.DeepEqual(item.Tags, []string{"legacy"}) {
+			t.Errorf("Expected inactive item tags unchanged, got %v", item.Tags)
+		}
+	}
+
+	// Removing the last tags leaves none
+	w = bulkTag(handler, `{"filter":{"status":"active"},"remove_tags":["sale","featured"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	for _, id := range active {
+		item, _ := repo.GetItem(context.Background(), id, nil)
+		if len(item.Tags) != 0 {
+			t.Errorf("Expected no tags after removal, got %v", item.Tags)
+		}
+	}
+}
+
+func TestBulkTagItems_DryRunChangesNothing(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+
+	w := bulkTag(handler, `{"add_tags":["sale"],"dry_run":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"matched_ids":["`+item.ID+`"]`) || !strings.Contains(w.Body.String(), `"updated":0`) {
+		t.Errorf("Expected the item reported as matched but not updated, got %s", w.Body.String())
+	}
+
+	stored, _ := repo.GetItem(context.Background(), item.ID, nil)
+	if len(stored.Tags) != 0 || stored.Generation != item.Generation {
+		t.Errorf("Expected dry run to leave the item unchanged, got %+v", stored)
+	}
+}
+
+func TestBulkTagItems_InvalidRequests(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	for _, body := range []string{
+		`{"filter":{"status":"active"}}`,
+		`{"add_tags":["has space"]}`,
+		`{"add_tags":["sale"],"remove_tags":["SALE"]}`,
+		`{"filter":{"status":"archived"},"add_tags":["sale"]}`,
+	} {
+		w := bulkTag(handler, body)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, body, w.Code)
+		}
+	}
+
+	w := bulkTag(handler, `{"add_tags":["sale"],"cursor":"not-a-token"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a malformed cursor, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestUpdateItem_RacingUpdatesSecondConflicts(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Original", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+
+	// Both clients read the item at generation 1 before writing
+	put := func(name string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"name":%q,"generation":%d}`, name, item.Generation)
+		req := httptest.NewRequest("PUT", "/items/"+item.ID, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", item.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.UpdateItem(w, req)
+		return w
+	}
+
+	if w := put("First"); w.Code != http.StatusOK {
+		t.Fatalf("Expected first update status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w := put("Second")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected second update status %d, got %d", http.StatusConflict, w.Code)
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error.Code != string(CodeStaleGeneration) {
+		t.Errorf("Expected error code '%s', got '%s'", CodeStaleGeneration, response.Error.Code)
+	}
+
+	stored, _ := repo.GetItem(context.Background(), item.ID, nil)
+	if stored.Name != "First" || stored.Generation != 2 {
+		t.Errorf("Expected the first update to be kept at generation 2, got %q at %d", stored.Name, stored.Generation)
+	}
+}
+
+func TestUpdateItem_MetadataMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		body     string
+		expected map[string]string
+	}{
+		{
+			name:     "Merge keeps untouched keys",
+			query:    "?metadata_mode=merge",
+			body:     `{"metadata":{"color":"blue","size":null}}`,
+			expected: map[string]string{"color": "blue", "origin": "uk"},
+		},
+		{
+			name:     "Replace overwrites the map",
+			query:    "?metadata_mode=replace",
+			body:     `{"metadata":{"color":"blue"}}`,
+			expected: map[string]string{"color": "blue"},
+		},
+		{
+			name:     "Replace is the default",
+			body:     `{"metadata":{"weight":"2kg"}}`,
+			expected: map[string]string{"weight": "2kg"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			item := models.NewItem("Original", "Description")
+			item.Metadata = map[string]string{"color": "red", "size": "large", "origin": "uk"}
+			if err := repo.CreateItem(context.Background(), item); err != nil {
+				t.Fatalf("Failed to seed item: %v", err)
+			}
+			handler := NewItemHandler(repo)
+
+			req := httptest.NewRequest("PUT", "/items/"+item.ID+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", item.ID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+			handler.UpdateItem(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			stored, _ := repo.GetItem(context.Background(), item.ID, nil)
+			if !reflect.DeepEqual(stored.Metadata, tt.expected) {
+				t.Errorf("Expected metadata %v, got %v", tt.expected, stored.Metadata)
+			}
+		})
+	}
+}
+
+func TestUpdateItem_InvalidMetadataMode(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	req := httptest.NewRequest("PUT", "/items/test-id?metadata_mode=append", strings.NewReader(`{"metadata":{"a":"b"}}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "test-id")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	handler.UpdateItem(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error.Code != string(CodeInvalidValue) {
+		t.Errorf("Expected error code '%s', got '%s'", CodeInvalidValue, response.Error.Code)
+	}
+}
+
+func TestCreateItem_ReservedIDPrefix(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+		expectedCode   ErrorCode
+	}{
+		{name: "Reserved prefix", id: "sys-config", expectedStatus: http.StatusForbidden, expectedCode: CodeForbidden},
+		{name: "Reserved prefix in another case", id: "SYS-config", expectedStatus: http.StatusForbidden, expectedCode: CodeForbidden},
+		{name: "Allowed ID", id: "system-widget", expectedStatus: http.StatusCreated},
+		{name: "Generated ID", id: "", expectedStatus: http.StatusCreated},
+		{name: "Invalid ID", id: "bad#id", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			handler := NewItemHandler(repo)
+			handler.config.ReservedIDPrefix = "sys-"
+
+			body := fmt.Sprintf(`{"id":%q,"name":"Item","description":"Description"}`, tt.id)
+			req := httptest.NewRequest("POST", "/items", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.CreateItem(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			var response struct {
+				Data  models.Item       `json:"data"`
+				Error *models.ErrorInfo `json:"error"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if tt.expectedCode != "" && response.Error.Code != string(tt.expectedCode) {
+				t.Errorf("Expected error code '%s', got '%s'", tt.expectedCode, response.Error.Code)
+			}
+			if tt.expectedStatus == http.StatusCreated && tt.id != "" && response.Data.ID != tt.id {
+				t.Errorf("Expected item to be created with ID %q, got %q", tt.id, response.Data.ID)
+			}
+		})
+	}
+}
+
+func TestGetItem_Download(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+
+	tests := []struct {
+		name                string
+		query               string
+		expectedStatus      int
+		expectedDisposition string
+	}{
+		{"Download requested", "?download=true", http.StatusOK, `attachment; filename="` + item.ID + `.json"`},
+		{"Download declined", "?download=false", http.StatusOK, ""},
+		{"No download parameter", "", http.StatusOK, ""},
+		{"Invalid download parameter", "?download=maybe", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/items/"+item.ID+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", item.ID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			handler.GetItem(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tt.expectedDisposition {
+				t.Errorf("Expected Content-Disposition %q, got %q", tt.expectedDisposition, got)
+			}
+		})
+	}
+}
+
+func TestGetItem_Consistent(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedStatus     int
+		expectedConsistent bool
+	}{
+		{"Consistent requested", "?consistent=true", http.StatusOK, true},
+		{"Consistent declined", "?consistent=false", http.StatusOK, false},
+		{"Default", "", http.StatusOK, false},
+		{"Invalid consistent parameter", "?consistent=always", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &readOptionsRepository{}
+			handler := NewItemHandler(repo)
+			req := httptest.NewRequest("GET", "/items/test-id"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "test-id")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			handler.GetItem(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if got := repo.getOptions != nil && repo.getOptions.Consistent; got != tt.expectedConsistent {
+				t.Errorf("Expected a consistent read %v, got %v", tt.expectedConsistent, got)
+			}
+		})
+	}
+}
+
+func TestAttachmentName_EscapesUnsafeCharacters(t *testing.T) {
+	if got := attachmentName(`a"b\c/d` + "\n"); got != "a_b_c_d_" {
+		t.Errorf("Expected unsafe characters replaced, got %q", got)
+	}
+}
+
+func TestRecordView(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Viewed", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+
+	recordView := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/items/"+id+"/view", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.RecordView(w, req)
+		return w
+	}
+
+	for expected := 1; expected <= 2; expected++ {
+		w := recordView(item.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data struct {
+				ViewCount int `json:"view_count"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Data.ViewCount != expected {
+			t.Errorf("Expected view count %d, got %d", expected, response.Data.ViewCount)
+		}
+	}
+
+	if w := recordView("missing"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for a missing item, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestListItems_SortOrder(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	base := time.Now().UTC().Truncate(time.Second)
+	for i, name := range []string{"banana", "cherry", "Apple"} {
+		item := models.NewItem(name, "Description")
+		item.CreatedAt = base.Add(time.Duration(i) * time.Second)
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "", expected: "banana,cherry,Apple"},
+		{query: "sort=created_at&order=desc", expected: "Apple,cherry,banana"},
+		{query: "order=desc", expected: "Apple,cherry,banana"},
+		{query: "sort=name", expected: "Apple,banana,cherry"},
+		{query: "sort=name&order=desc", expected: "cherry,banana,Apple"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/items?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		list, err := models.ParseListResponse(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Failed to parse response for %q: %v", tt.query, err)
+		}
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.expected {
+			t.Errorf("Expected %s for %q, got %s", tt.expected, tt.query, got)
+		}
+	}
+}
+
+func TestListItems_InvalidSortOrder(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	for _, query := range []string{"sort=status", "sort=created_at&order=up", "order=DESC"} {
+		req := httptest.NewRequest("GET", "/items?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, query, w.Code)
+		}
+		var response models.APIResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error == nil || response.Error.Code != string(CodeInvalidValue) {
+			t.Errorf("Expected error code %s for %q, got %+v", CodeInvalidValue, query, response.Error)
+		}
+	}
+}
+
+func TestUpdateItem_RacingVersionedUpdatesSecondConflicts(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Original", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+
+	// Both clients read the item at version 1 before writing
+	put := func(name string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"name":%q,"version":%d}`, name, item.Version)
+		req := httptest.NewRequest("PUT", "/items/"+item.ID, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", item.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.UpdateItem(w, req)
+		return w
+	}
+
+	if w := put("First"); w.Code != http.StatusOK {
+		t.Fatalf("Expected first update status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w := put("Second")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected second update status %d, got %d", http.StatusConflict, w.Code)
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error.Code != string(CodeVersionConflict) {
+		t.Errorf("Expected error code '%s', got '%s'", CodeVersionConflict, response.Error.Code)
+	}
+
+	stored, _ := repo.GetItem(context.Background(), item.ID, nil)
+	if stored.Name != "First" || stored.Version != 2 {
+		t.Errorf("Expected the first update to be kept at version 2, got %q at %d", stored.Name, stored.Version)
+	}
+}
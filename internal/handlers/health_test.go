@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fis-playground/internal/repository"
+)
+
+// tableCheckingRepository reports the configured tables as healthy or not
+type tableCheckingRepository struct {
+	MockRepository
+	healthy map[string]bool
+	probed  []string
+}
+
+func (m *tableCheckingRepository) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func (m *tableCheckingRepository) CheckTables(ctx context.Context, tables []string, timeout time.Duration) []repository.TableHealth {
+	m.probed = tables
+	results := make([]repository.TableHealth, len(tables))
+	for i, table := range tables {
+		results[i] = repository.TableHealth{Table: table, Healthy: m.healthy[table]}
+	}
+	return results
+}
+
+func TestHealthCheckDB_Tables(t *testing.T) {
+	tests := []struct {
+		name           string
+		healthy        map[string]bool
+		expectedStatus int
+	}{
+		{name: "All healthy", healthy: map[string]bool{"tenant-a": true, "tenant-b": true, "tenant-c": true}, expectedStatus: http.StatusOK},
+		{name: "One unhealthy", healthy: map[string]bool{"tenant-a": true, "tenant-b": false, "tenant-c": true}, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &tableCheckingRepository{healthy: tt.healthy}
+			handler := NewItemHandler(repo)
+			handler.config.HealthCheckTables = []string{"tenant-a", "tenant-b", "tenant-c"}
+
+			w := httptest.NewRecorder()
+			handler.HealthCheckDB(w, httptest.NewRequest("GET", "/health/db", nil))
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var response struct {
+				Data struct {
+					Tables []repository.TableHealth `json:"tables"`
+				} `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Data.Tables) != 3 {
+				t.Fatalf("Expected every table reported, got %+v", response.Data.Tables)
+			}
+			for _, table := range response.Data.Tables {
+				if table.Healthy != tt.healthy[table.Table] {
+					t.Errorf("Expected %s healthy=%v, got %v", table.Table, tt.healthy[table.Table], table.Healthy)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthCheckDB_TableSample(t *testing.T) {
+	repo := &tableCheckingRepository{healthy: map[string]bool{}}
+	handler := NewItemHandler(repo)
+	handler.config.HealthCheckTables = []string{"tenant-a", "tenant-b", "tenant-c", "tenant-d"}
+	handler.config.HealthCheckSample = 2
+
+	handler.HealthCheckDB(httptest.NewRecorder(), httptest.NewRequest("GET", "/health/db", nil))
+	if len(repo.probed) != 2 {
+		t.Errorf("Expected 2 sampled tables probed, got %v", repo.probed)
+	}
+}
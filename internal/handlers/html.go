@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// itemsPageTemplate renders GET /items.html. html/template escapes item
+// content for its context, so names and descriptions can't inject markup.
+var itemsPageTemplate = template.Must(template.New("items").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Items</title>
+</head>
+<body>
+<h1>Items</h1>
+<table>
+<thead><tr><th>ID</th><th>Name</th><th>Description</th><th>Status</th><th>Category</th><th>Created</th></tr></thead>
+<tbody>
+{{- range .Items}}
+<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.Description}}</td><td>{{.Status}}</td><td>{{.Category}}</td><td>{{.CreatedAt.UTC.Format "2006-01-02 15:04:05"}}</td></tr>
+{{- else}}
+<tr><td colspan="6">No items</td></tr>
+{{- end}}
+</tbody>
+</table>
+<nav>
+{{- if .PrevURL}} <a rel="prev" href="{{.PrevURL}}">Previous</a>{{end}}
+{{- if .NextURL}} <a rel="next" href="{{.NextURL}}">Next</a>{{end}}
+</nav>
+</body>
+</html>
+`))
+
+// itemsPage is the data itemsPageTemplate renders
+type itemsPage struct {
+	Items   []models.Item
+	PrevURL string
+	NextURL string
+}
+
+// ListItemsHTML handles GET /items.html requests, rendering a page of
+// items as an HTML table for browsing the playground by hand. Page tokens
+// only lead forward, so each link carries the tokens of the pages before
+// it as repeated prev parameters, and the previous link pops the last one.
+func (h *ItemHandler) ListItemsHTML(w http.ResponseWriter, r *http.Request) {
+	options := &repository.ListItemsOptions{
+		Limit: int32(h.config.DefaultListLimit),
+	}
+	h.scopeListToOwner(r.Context(), options)
+
+	query := r.URL.Query()
+	if value := query.Get("limit"); value != "" {
+		limit, apiErr := parseLimit(value)
+		if apiErr == nil && (limit <= 0 || limit > 100) {
+			apiErr = NewValidationError(CodeInvalidValue, "Invalid limit value", "Limit must be between 1 and 100")
+		}
+		if apiErr != nil {
+			WriteErrorResponse(w, r, apiErr)
+			return
+		}
+		options.Limit = int32(limit)
+	}
+
+	token := query.Get("next_token")
+	if token != "" {
+		key, err := h.pageTokens.Decode(token)
+		if err != nil {
+			WriteErrorResponse(w, r, NewPageTokenError(err))
+			return
+		}
+		options.LastEvaluatedKey = key
+	}
+
+	result, err := h.repo.ListItems(r.Context(), options)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	page := itemsPage{Items: models.VisibleItems(result.Items, time.Now())}
+	previous := query["prev"]
+	if token != "" {
+		if len(previous) == 0 {
+			page.PrevURL = itemsPageURL(query, "", nil)
+		} else {
+			page.PrevURL = itemsPageURL(query, previous[len(previous)-1], previous[:len(previous)-1])
+		}
+	}
+	if result.HasMore {
+		nextToken, err := h.pageTokens.Encode(result.LastEvaluatedKey)
+		if err != nil {
+			WriteInternalErrorResponse(w, r, err)
+			return
+		}
+		// The first page has no token, so the page after it leads back
+		// to it with no prev parameters
+		history := previous
+		if token != "" {
+			history = append(history[:len(history):len(history)], token)
+		}
+		page.NextURL = itemsPageURL(query, nextToken, history)
+	}
+
+	var body bytes.Buffer
+	if err := itemsPageTemplate.Execute(&body, page); err != nil {
+		WriteInternalErrorResponse(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// itemsPageURL links to the page of /items.html starting at token, keeping
+// the request's other parameters
+func itemsPageURL(query url.Values, token string, previous []string) string {
+	values := url.Values{}
+	for name, value := range query {
+		if name != "next_token" && name != "prev" {
+			values[name] = value
+		}
+	}
+	if token != "" {
+		values.Set("next_token", token)
+	}
+	if len(previous) > 0 {
+		values["prev"] = previous
+	}
+	link := url.URL{Path: "/items.html", RawQuery: values.Encode()}
+	return link.String()
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// getItemsHTML requests target from ListItemsHTML, failing unless it
+// renders
+func getItemsHTML(t *testing.T, handler *ItemHandler, target string) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	w := httptest.NewRecorder()
+	handler.ListItemsHTML(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected status 200, got %d: %s", target, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/html") {
+		t.Errorf("GET %s: expected an HTML content type, got %q", target, contentType)
+	}
+	return w.Body.String()
+}
+
+// pageLink returns the unescaped href of the link with rel, or ""
+func pageLink(body, rel string) string {
+	match := regexp.MustCompile(`rel="` + rel + `" href="([^"]*)"`).FindStringSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return html.UnescapeString(match[1])
+}
+
+// tableRows returns the body of the rendered table
+func tableRows(body string) string {
+	_, rows, _ := strings.Cut(body, "<tbody>")
+	rows, _, _ = strings.Cut(rows, "</tbody>")
+	return rows
+}
+
+func TestListItemsHTML_EscapesItemContent(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem(`<script>alert("name")</script>`, `<img src=x onerror=alert(1)>`)
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+
+	body := getItemsHTML(t, NewItemHandler(repo), "/items.html")
+
+	if strings.Contains(body, "<script>") || strings.Contains(body, "<img") {
+		t.Errorf("Expected item content to be escaped, got:\n%s", body)
+	}
+	if !strings.Contains(body, "&lt;script&gt;alert(&#34;name&#34;)&lt;/script&gt;") {
+		t.Errorf("Expected the escaped name in the table, got:\n%s", body)
+	}
+}
+
+func TestListItemsHTML_PaginationLinks(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for _, name := range []string{"First", "Second", "Third", "Fourth", "Fifth"} {
+		if err := repo.CreateItem(context.Background(), models.NewItem(name, "Paged in HTML")); err != nil {
+			t.Fatalf("Failed to create item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	first := getItemsHTML(t, handler, "/items.html?limit=2")
+	if pageLink(first, "prev") != "" {
+		t.Error("Expected no previous link on the first page")
+	}
+	next := pageLink(first, "next")
+	if !strings.Contains(next, "next_token=") || !strings.Contains(next, "limit=2") {
+		t.Fatalf("Expected a next link carrying the token and limit, got %q", next)
+	}
+
+	second := getItemsHTML(t, handler, next)
+	if prev := pageLink(second, "prev"); prev != "/items.html?limit=2" {
+		t.Errorf("Expected the second page to link back to the first, got %q", prev)
+	}
+	third := getItemsHTML(t, handler, pageLink(second, "next"))
+	if pageLink(third, "next") != "" {
+		t.Error("Expected no next link on the last page")
+	}
+
+	// Tokens record when they were issued, so compare the rows rather
+	// than the links
+	back := getItemsHTML(t, handler, pageLink(third, "prev"))
+	if tableRows(back) != tableRows(second) {
+		t.Errorf("Expected the previous link from the third page to render the second page, got:\n%s", back)
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"fis-playground/internal/logging"
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// IdempotencyKeyHeader lets clients retry a create without creating the
+// item twice
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted
+const maxIdempotencyKeyLength = 255
+
+// claimIdempotencyKey claims the request's Idempotency-Key for item, so a
+// retry of the create finds the item rather than creating another. It
+// returns a release to call when the create fails, or false when the
+// request was already answered: with the original item when the key was
+// used before with the same body, and with an error otherwise. Creates
+// without the header, or against a repository that can't store keys, are
+// left alone.
+func (h *ItemHandler) claimIdempotencyKey(w http.ResponseWriter, r *http.Request, createReq *models.CreateItemRequest, item *models.Item) (release func(), ok bool) {
+	release = func() {}
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		return release, true
+	}
+	store, ok := h.repo.(repository.IdempotencyStore)
+	if !ok {
+		return release, true
+	}
+	if apiErr := validateIdempotencyKey(key); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return nil, false
+	}
+
+	body, err := json.Marshal(createReq)
+	if err != nil {
+		WriteErrorResponse(w, r, NewSystemError(CodeInternalError, "Failed to hash request", err))
+		return nil, false
+	}
+	hash := sha256.Sum256(body)
+	requestHash := hex.EncodeToString(hash[:])
+
+	// The item needs its ID now so the key can name it
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+
+	// Keys are per principal, so clients can't replay each other's creates
+	ctx := r.Context()
+	scoped := url.QueryEscape(PrincipalFromContext(ctx)) + "#" + url.QueryEscape(key)
+	existing, err := store.ClaimIdempotencyKey(ctx, scoped, requestHash, item.ID, h.config.IdempotencyTTL)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return nil, false
+	}
+	if existing == nil {
+		// An async create fails after the request has finished
+		releaseCtx := context.WithoutCancel(ctx)
+		return func() {
+			if err := store.ReleaseIdempotencyKey(releaseCtx, scoped, item.ID); err != nil {
+				logging.FromContext(releaseCtx).Warn("Failed to release idempotency key", "item_id", item.ID, "error", err)
+			}
+		}, true
+	}
+
+	if existing.RequestHash != requestHash {
+		WriteErrorResponse(w, r, &APIError{
+			Type:       ErrorTypeConflict,
+			Code:       CodeIdempotencyReused,
+			Message:    "Idempotency key reused",
+			Details:    "The Idempotency-Key was already used for a create with a different body",
+			StatusCode: http.StatusConflict,
+		})
+		return nil, false
+	}
+
+	original, err := h.repo.GetItem(ctx, existing.ItemID, &repository.GetItemOptions{Consistent: true})
+	if repository.IsNotFoundError(err) {
+		WriteErrorResponse(w, r, &APIError{
+			Type:       ErrorTypeConflict,
+			Code:       CodeIdempotencyReused,
+			Message:    "Original request not finished",
+			Details:    "The create first sent with this Idempotency-Key hasn't finished, or its item has since been deleted",
+			StatusCode: http.StatusConflict,
+		})
+		return nil, false
+	}
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return nil, false
+	}
+
+	w.Header().Set("Idempotent-Replayed", "true")
+	writeJSONResponse(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    h.itemView(r, original),
+	})
+	return nil, false
+}
+
+// validateIdempotencyKey checks the key is at most maxIdempotencyKeyLength
+// printable ASCII characters
+func validateIdempotencyKey(key string) *APIError {
+	valid := len(key) <= maxIdempotencyKeyLength
+	for i := 0; valid && i < len(key); i++ {
+		valid = key[i] >= ' ' && key[i] <= '~'
+	}
+	if valid {
+		return nil
+	}
+	return &APIError{
+		Type:       ErrorTypeValidation,
+		Code:       CodeInvalidValue,
+		Message:    "Invalid Idempotency-Key",
+		Details:    fmt.Sprintf("The Idempotency-Key must be at most %d printable ASCII characters", maxIdempotencyKeyLength),
+		StatusCode: http.StatusBadRequest,
+	}
+}
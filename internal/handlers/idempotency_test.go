@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// idempotentCreate sends a create with the given body and Idempotency-Key
+// as principal
+func idempotentCreate(handler *ItemHandler, body, key, principal string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, key)
+	if principal != "" {
+		req = req.WithContext(WithPrincipal(req.Context(), principal))
+	}
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+	return w
+}
+
+// createdItem decodes the item in a create response
+func createdItem(t *testing.T, w *httptest.ResponseRecorder) models.Item {
+	t.Helper()
+	var response struct {
+		Data models.Item `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response.Data
+}
+
+func TestCreateItem_IdempotencyKeyReplay(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	body := `{"name":"Item","description":"Description"}`
+
+	w := idempotentCreate(handler, body, "key-1", "alice")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	first := createdItem(t, w)
+
+	w = idempotentCreate(handler, body, "key-1", "alice")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the replay to return 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("Expected the replay to be marked Idempotent-Replayed")
+	}
+	if replayed := createdItem(t, w); replayed.ID != first.ID {
+		t.Errorf("Expected the replay to return item %s, got %s", first.ID, replayed.ID)
+	}
+
+	result, err := repo.ListItems(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if len(result.Items) != 1 {
+		t.Errorf("Expected one item to be created, got %d", len(result.Items))
+	}
+
+	// Another principal's key of the same name is unrelated
+	w = idempotentCreate(handler, body, "key-1", "bob")
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected another principal's create to succeed, got %d", w.Code)
+	}
+}
+
+func TestCreateItem_IdempotencyKeyConflictingBody(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	w := idempotentCreate(handler, `{"name":"Item","description":"Description"}`, "key-1", "alice")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = idempotentCreate(handler, `{"name":"Other","description":"Description"}`, "key-1", "alice")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error == nil || response.Error.Code != string(CodeIdempotencyReused) {
+		t.Errorf("Expected IDEMPOTENCY_REUSED, got %+v", response.Error)
+	}
+}
+
+func TestCreateItem_IdempotencyKeyReleasedOnFailure(t *testing.T) {
+	t.Setenv("NAME_UNIQUENESS", "global")
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	if w := idempotentCreate(handler, `{"name":"Taken","description":"Description"}`, "", ""); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	body := `{"name":"Taken","description":"Retried"}`
+	if w := idempotentCreate(handler, body, "key-1", "alice"); w.Code != http.StatusConflict {
+		t.Fatalf("Expected the duplicate name to conflict, got %d", w.Code)
+	}
+
+	// The failed create gave up its key, so the retry fails the same way
+	// rather than looking for an item that was never created
+	w := idempotentCreate(handler, body, "key-1", "alice")
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error == nil || response.Error.Code != string(CodeAlreadyExists) {
+		t.Errorf("Expected ALREADY_EXISTS, got %+v", response.Error)
+	}
+}
+
+func TestCreateItem_InvalidIdempotencyKey(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+	body := `{"name":"Item","description":"Description"}`
+
+	for _, key := range []string{strings.Repeat("k", maxIdempotencyKeyLength+1), "key\x01"} {
+		if w := idempotentCreate(handler, body, key, "alice"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for key %q, got %d", key, w.Code)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"fis-playground/internal/models"
+)
+
+// ItemView is the response representation of an item with optional computed fields
+type ItemView struct {
+	*models.Item
+	StatusLabel string `json:"status_label,omitempty"`
+	// DescriptionTruncated marks a description shortened for a listing
+	DescriptionTruncated bool `json:"description_truncated,omitempty"`
+}
+
+// wantsLabels checks if the client requested computed status labels
+func wantsLabels(r *http.Request) bool {
+	return r.URL.Query().Get("labels") == "true"
+}
+
+// statusLabels selects the label map for the request's Accept-Language,
+// falling back to the default labels when no localization matches
+func (h *ItemHandler) statusLabels(r *http.Request) map[string]string {
+	for _, lang := range acceptedLanguages(r.Header.Get("Accept-Language")) {
+		if labels, ok := h.config.StatusLabels[lang]; ok {
+			return labels
+		}
+		// Fall back from a regional tag (e.g. "de-at") to its primary language
+		if primary, _, found := strings.Cut(lang, "-"); found {
+			if labels, ok := h.config.StatusLabels[primary]; ok {
+				return labels
+			}
+		}
+	}
+	return h.config.StatusLabels[""]
+}
+
+// acceptedLanguages returns the language tags of an Accept-Language header in
+// preference order. Quality values are honored only to drop "q=0" entries;
+// clients list languages by preference in practice.
+func acceptedLanguages(header string) []string {
+	var langs []string
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" || strings.ReplaceAll(params, " ", "") == "q=0" {
+			continue
+		}
+		langs = append(langs, tag)
+	}
+	return langs
+}
+
+// itemView wraps an item for the response, adding the status label when requested
+func (h *ItemHandler) itemView(r *http.Request, item *models.Item) interface{} {
+	if !wantsLabels(r) {
+		return item
+	}
+	return &ItemView{
+		Item:        item,
+		StatusLabel: h.statusLabels(r)[item.Status],
+	}
+}
+
+// itemViews wraps a list of items for the response; labels are only filled
+// in when requested, so unlabeled views serialize exactly like the items
+func (h *ItemHandler) itemViews(r *http.Request, items []models.Item) []ItemView {
+	var labels map[string]string
+	if wantsLabels(r) {
+		labels = h.statusLabels(r)
+	}
+	views := make([]ItemView, len(items))
+	for i := range items {
+		views[i] = ItemView{
+			Item:        &items[i],
+			StatusLabel: labels[items[i].Status],
+		}
+	}
+	return views
+}
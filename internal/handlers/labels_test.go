@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestGetItem_StatusLabel(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		expectedLabel  string
+	}{
+		{
+			name:          "Labels not requested",
+			target:        "/items/test-id",
+			expectedLabel: "",
+		},
+		{
+			name:          "Default label",
+			target:        "/items/test-id?labels=true",
+			expectedLabel: defaultStatusLabels["active"],
+		},
+		{
+			name:           "Localized label",
+			target:         "/items/test-id?labels=true",
+			acceptLanguage: "de-AT, en;q=0.5",
+			expectedLabel:  "Aktiv",
+		},
+		{
+			name:           "Unknown language falls back to default",
+			target:         "/items/test-id?labels=true",
+			acceptLanguage: "fr",
+			expectedLabel:  defaultStatusLabels["active"],
+		},
+	}
+
+	handler.config.StatusLabels["de"] = map[string]string{"active": "Aktiv"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "test-id")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.GetItem(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var response struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			label, _ := response.Data["status_label"].(string)
+			if label != tt.expectedLabel {
+				t.Errorf("Expected status_label %q, got %q", tt.expectedLabel, label)
+			}
+			if response.Data["status"] != "active" {
+				t.Errorf("Expected status 'active' to be preserved, got %v", response.Data["status"])
+			}
+		})
+	}
+}
+
+func TestListItems_StatusLabel(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	req := httptest.NewRequest("GET", "/items?labels=true", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListItems(w, req)
+
+	var response struct {
+		Data struct {
+			Items []map[string]interface{} `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Data.Items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(response.Data.Items))
+	}
+	if label := response.Data.Items[0]["status_label"]; label != "Active" {
+		t.Errorf("Expected status_label 'Active', got %v", label)
+	}
+}
+
+func TestNewHandlerConfig_StatusLabelsEnv(t *testing.T) {
+	t.Setenv("STATUS_LABELS", "active=Live")
+	t.Setenv("STATUS_LABELS_DE", "active=Aktiv")
+	t.Setenv("STATUS_LABELSX", "active=Unrelated")
+
+	cfg := NewHandlerConfig()
+	if got := cfg.StatusLabels[""]["active"]; got != "Live" {
+		t.Errorf("Expected the default label Live, got %q", got)
+	}
+	if got := cfg.StatusLabels["de"]["active"]; got != "Aktiv" {
+		t.Errorf("Expected the German label Aktiv, got %q", got)
+	}
+	if _, ok := cfg.StatusLabels["x"]; ok {
+		t.Error("Expected STATUS_LABELSX not to be read as a localization")
+	}
+}
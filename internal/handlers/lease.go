@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+)
+
+// GetItemForUpdate handles POST /items/{id}/lease requests, returning the
+// item with a lease held by the requesting principal. The optional seconds
+// parameter sets the lease length. An item leased to someone else is
+// rejected with 409 naming the holder.
+func (h *ItemHandler) GetItemForUpdate(w http.ResponseWriter, r *http.Request) {
+	itemID := chi.URLParam(r, "id")
+	if itemID == "" {
+		WriteMissingParameterErrorResponse(w, r, "Item ID")
+		return
+	}
+
+	seconds := models.DefaultLeaseSeconds
+	if param := r.URL.Query().Get("seconds"); param != "" {
+		var err error
+		if seconds, err = strconv.Atoi(param); err != nil || models.ValidateLeaseSeconds(seconds) != nil {
+			WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid seconds parameter",
+				fmt.Sprintf("seconds must be between 1 and %d", models.MaxLeaseSeconds)))
+			return
+		}
+	}
+
+	holder := PrincipalFromContext(r.Context())
+	if holder == "" {
+		WriteErrorResponse(w, r, &APIError{
+			Type:       ErrorTypeAuth,
+			Code:       CodeForbidden,
+			Message:    "Principal required",
+			Details:    "A lease is held by a principal, and the request has no principal",
+			StatusCode: http.StatusForbidden,
+		})
+		return
+	}
+
+	if apiErr := h.authorizeItemWrite(r.Context(), itemID); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	item, err := h.repo.GetForUpdate(r.Context(), itemID, holder, seconds)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data:    h.itemView(r, item),
+	}
+	w.Header().Set("ETag", itemETag(item, response.Data))
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
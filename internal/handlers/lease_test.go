@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// leaseRequest asks for a lease on the item as principal
+func leaseRequest(handler *ItemHandler, target, id, principal string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", target, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	if principal != "" {
+		ctx = WithPrincipal(ctx, principal)
+	}
+	w := httptest.NewRecorder()
+	handler.GetItemForUpdate(w, req.WithContext(ctx))
+	return w
+}
+
+func TestGetItemForUpdate(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+
+	w := leaseRequest(handler, "/items/"+item.ID+"/lease?seconds=60", item.ID, "alice")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the lease to be granted, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data models.Item `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.ID != item.ID || response.Data.LeaseHolder != "alice" || response.Data.LeaseExpiresAt == nil {
+		t.Errorf("Expected the item leased to alice, got %+v", response.Data)
+	}
+	if w.Header().Get("ETag") == "" {
+		t.Error("Expected the leased item's ETag")
+	}
+
+	w = leaseRequest(handler, "/items/"+item.ID+"/lease", item.ID, "bob")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected a contended lease to conflict, got %d", w.Code)
+	}
+	var conflict models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&conflict); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if conflict.Error == nil || conflict.Error.Code != string(CodeLeaseHeld) || !strings.Contains(conflict.Error.Details, "alice") {
+		t.Errorf("Expected LEASE_HELD naming alice, got %+v", conflict.Error)
+	}
+}
+
+func TestGetItemForUpdate_InvalidRequests(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	tests := []struct {
+		name      string
+		target    string
+		principal string
+		status    int
+	}{
+		{"no principal", "/items/item-1/lease", "", http.StatusForbidden},
+		{"zero seconds", "/items/item-1/lease?seconds=0", "alice", http.StatusBadRequest},
+		{"too many seconds", "/items/item-1/lease?seconds=301", "alice", http.StatusBadRequest},
+		{"non-numeric seconds", "/items/item-1/lease?seconds=soon", "alice", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := leaseRequest(handler, tt.target, "item-1", tt.principal); w.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
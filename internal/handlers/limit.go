@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseLimit parses a limit query parameter. Surrounding whitespace and a
+// leading + are accepted, as some clients send them; decimals such as
+// "50.0" are not, even when whole, and are told apart from values that
+// aren't numbers at all. Either is an INVALID_FORMAT error.
+func parseLimit(value string) (int, *APIError) {
+	value = strings.TrimSpace(value)
+	limit, err := strconv.Atoi(value)
+	if err == nil {
+		return limit, nil
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return 0, NewValidationError(CodeInvalidFormat, "Invalid limit parameter", "Limit must be a whole number without a decimal point, e.g. 50 rather than 50.0")
+	}
+	return 0, NewValidationError(CodeInvalidFormat, "Invalid limit parameter", "Limit must be a valid integer")
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+
+	"fis-playground/internal/emf"
+	"fis-playground/internal/events"
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// Item operations shared by the REST handlers and the other APIs served
+// over the same items, such as GraphQL, so every API applies the same
+// validation, ownership and precondition checks, records the same
+// bookkeeping and publishes the same events.
+
+// Repository returns the repository the handler serves
+func (h *ItemHandler) Repository() repository.ItemRepository {
+	return h.repo
+}
+
+// AuthorizeRead rejects reading an item owned by another user when items
+// are scoped to their owner, as GET /items/{id} does
+func (h *ItemHandler) AuthorizeRead(ctx context.Context, item *models.Item) *APIError {
+	return h.authorizeItemOwner(ctx, item)
+}
+
+// ScopeList limits a listing to the caller's items when items are scoped
+// to their owner, as GET /items does
+func (h *ItemHandler) ScopeList(ctx context.Context, options *repository.ListItemsOptions) {
+	h.scopeListToOwner(ctx, options)
+}
+
+// Create validates and creates the item a create request describes, as
+// POST /items does. Idempotency keys and async creates are HTTP features
+// and don't apply.
+func (h *ItemHandler) Create(ctx context.Context, createReq *models.CreateItemRequest) (*models.Item, *APIError) {
+	item, apiErr := h.prepareCreate(ctx, createReq)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if err := h.saveCreated(ctx, item); err != nil {
+		return nil, MapRepositoryError(err)
+	}
+	return item, nil
+}
+
+// prepareCreate validates a create request and returns the item to save,
+// charged to the caller's create budget. The ID is checked even when
+// validation is skipped, and client-supplied IDs may not use the reserved
+// namespace.
+func (h *ItemHandler) prepareCreate(ctx context.Context, createReq *models.CreateItemRequest) (*models.Item, *APIError) {
+	if err := createReq.ValidateID(); err != nil {
+		return nil, MapValidationError(err)
+	}
+	if !repository.SkipsValidation(ctx) {
+		if err := createReq.Validate(); err != nil {
+			return nil, MapValidationError(err)
+		}
+	}
+	if apiErr := h.checkReservedID(createReq.ID); apiErr != nil {
+		return nil, apiErr
+	}
+	if apiErr := ChargeCreates(ctx, 1); apiErr != nil {
+		return nil, apiErr
+	}
+	return h.newItem(ctx, createReq), nil
+}
+
+// saveCreated saves a prepared item and reports its creation
+func (h *ItemHandler) saveCreated(ctx context.Context, item *models.Item) error {
+	if err := h.repo.CreateItem(ctx, item); err != nil {
+		return err
+	}
+	h.publish(events.ItemCreated, item.ID, item)
+	emf.FromContext(ctx).Count(emf.ItemsCreated, 1, nil)
+	return nil
+}
+
+// Update validates and applies an update to the item with the given ID, as
+// PUT /items/{id} does. ifMatch is the request's If-Match header, or ""
+// when it has none.
+func (h *ItemHandler) Update(ctx context.Context, id string, updateReq *models.UpdateItemRequest, ifMatch string) (*models.Item, *APIError) {
+	if !repository.SkipsValidation(ctx) {
+		if err := updateReq.Validate(); err != nil {
+			return nil, MapValidationError(err)
+		}
+	}
+
+	owner, apiErr := h.requiredOwner(ctx)
+	if apiErr == nil {
+		apiErr = h.authorizeItemWrite(ctx, id)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	updateReq.RequireOwner = owner
+
+	// If-Match is the header form of the body's generation
+	generation, apiErr := ifMatchGeneration(ifMatch)
+	if apiErr == nil {
+		apiErr = applyIfMatch(&updateReq.Generation, generation)
+	}
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	item, err := h.repo.UpdateItem(ctx, id, updateReq)
+	if err != nil {
+		return nil, conditionalError(err, generation, ifMatch)
+	}
+	h.publish(events.ItemUpdated, item.ID, item)
+	return item, nil
+}
+
+// Delete deletes the item with the given ID, as DELETE /items/{id} does.
+// requireStatus can only restate the configured DELETE_REQUIRE_STATUS, and
+// ifMatch is the request's If-Match header, or "" when it has none.
+func (h *ItemHandler) Delete(ctx context.Context, id, requireStatus, ifMatch string) *APIError {
+	// The configured rule always applies; the request can only restate it
+	if requireStatus != "" && !models.IsValidStatus(requireStatus) {
+		return NewValidationError(CodeInvalidValue, "Invalid require_status parameter", models.ErrInvalidStatus.Error())
+	}
+	if configured := h.config.DeleteRequireStatus; configured != "" {
+		if requireStatus != "" && requireStatus != configured {
+			return NewValidationError(CodeInvalidValue, "Invalid require_status parameter", "Deletion requires status "+configured)
+		}
+		requireStatus = configured
+	}
+
+	// The deleted item's owner decides which subscribers see the delete
+	owner, apiErr := h.requiredOwner(ctx)
+	var itemOwner string
+	if apiErr == nil {
+		itemOwner, apiErr = h.scopedItemOwner(ctx, id)
+	}
+	if apiErr != nil {
+		return apiErr
+	}
+
+	generation, apiErr := ifMatchGeneration(ifMatch)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	options := &repository.DeleteItemOptions{RequireStatus: requireStatus, RequireOwner: owner, Generation: generation}
+	if err := h.repo.DeleteItem(ctx, id, options); err != nil {
+		return conditionalError(err, generation, ifMatch)
+	}
+	h.publishDeleted(id, itemOwner)
+	emf.FromContext(ctx).Count(emf.ItemsDeleted, 1, nil)
+	return nil
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Pagination token errors
+var (
+	ErrInvalidPageToken = errors.New("invalid pagination token")
+	ErrExpiredPageToken = errors.New("pagination token has expired")
+)
+
+// pageKeyAttribute is the JSON form of a single key attribute in a page token
+type pageKeyAttribute struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+// pageTokenPayload is the signed content of a page token
+type pageTokenPayload struct {
+	Key      map[string]pageKeyAttribute `json:"k"`
+	IssuedAt int64                       `json:"iat"`
+}
+
+// PageTokenCodec encodes a LastEvaluatedKey into an opaque, URL-safe token
+// and back. Tokens are HMAC-signed so the embedded issue time can't be
+// tampered with, and are rejected once older than the TTL.
+type PageTokenCodec struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewPageTokenCodec creates a codec; a zero TTL disables expiry
+func NewPageTokenCodec(secret []byte, ttl time.Duration) *PageTokenCodec {
+	return &PageTokenCodec{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+var (
+	processSecretOnce sync.Once
+	processSecret     []byte
+)
+
+// MinPageTokenSecretLength is the shortest PAGE_TOKEN_SECRET accepted by
+// RequirePageTokenSecret
+const MinPageTokenSecretLength = 32
+
+// RequirePageTokenSecret checks that PAGE_TOKEN_SECRET is configured, and
+// long enough to sign tokens. Deployments with several processes, such as
+// Lambda, call it at startup: without a shared secret each process signs
+// with its own random one, so a token issued by one instance is rejected by
+// every other and by the same instance after a cold start.
+func RequirePageTokenSecret() error {
+	secret := os.Getenv("PAGE_TOKEN_SECRET")
+	if secret == "" {
+		return errors.New("PAGE_TOKEN_SECRET must be set so every instance accepts the same pagination tokens")
+	}
+	if len(secret) < MinPageTokenSecretLength {
+		return fmt.Errorf("PAGE_TOKEN_SECRET must be at least %d bytes", MinPageTokenSecretLength)
+	}
+	return nil
+}
+
+// processPageTokenSecret returns a random secret shared by all codecs in this
+// process, used when PAGE_TOKEN_SECRET is not configured, as in a single
+// local server. Tokens signed with it do not survive a restart.
+func processPageTokenSecret() []byte {
+	processSecretOnce.Do(func() {
+		processSecret = make([]byte, 32)
+		if _, err := rand.Read(processSecret); err != nil {
+			log.Fatalf("Failed to generate pagination token secret: %v", err)
+		}
+	})
+	return processSecret
+}
+
+// Encode serializes a LastEvaluatedKey into a signed token
+func (c *PageTokenCodec) Encode(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	payload := pageTokenPayload{
+		Key:      make(map[string]pageKeyAttribute, len(key)),
+		IssuedAt: c.now().Unix(),
+	}
+	for name, av := range key {
+		switch v := av.(type) {
+		case *types.AttributeValueMemberS:
+			payload.Key[name] = pageKeyAttribute{S: &v.Value}
+		case *types.AttributeValueMemberN:
+			payload.Key[name] = pageKeyAttribute{N: &v.Value}
+		case *types.AttributeValueMemberB:
+			payload.Key[name] = pageKeyAttribute{B: v.Value}
+		default:
+			return "", fmt.Errorf("unsupported key attribute type %T for %q", av, name)
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal pagination key: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + c.sign(encoded), nil
+}
+
+// Decode verifies and parses a token produced by Encode
+func (c *PageTokenCodec) Decode(token string) (map[string]types.AttributeValue, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(c.sign(encoded))) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidPageToken)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+
+	var payload pageTokenPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	if len(payload.Key) == 0 {
+		return nil, fmt.Errorf("%w: token contains no key", ErrInvalidPageToken)
+	}
+
+	if c.ttl > 0 {
+		issuedAt := time.Unix(payload.IssuedAt, 0)
+		if c.now().Sub(issuedAt) > c.ttl {
+			return nil, fmt.Errorf("%w: issued at %s", ErrExpiredPageToken, issuedAt.UTC().Format(time.RFC3339))
+		}
+	}
+
+	key := make(map[string]types.AttributeValue, len(payload.Key))
+	for name, attr := range payload.Key {
+		switch {
+		case attr.S != nil:
+			key[name] = &types.AttributeValueMemberS{Value: *attr.S}
+		case attr.N != nil:
+			key[name] = &types.AttributeValueMemberN{Value: *attr.N}
+		case attr.B != nil:
+			key[name] = &types.AttributeValueMemberB{Value: attr.B}
+		default:
+			return nil, fmt.Errorf("%w: attribute %q has no value", ErrInvalidPageToken, name)
+		}
+	}
+	return key, nil
+}
+
+// sign computes the URL-safe HMAC-SHA256 signature of the encoded payload
+func (c *PageTokenCodec) sign(encoded string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// NewPageTokenError converts a token decoding error into an API error
+func NewPageTokenError(err error) *APIError {
+	if errors.Is(err, ErrExpiredPageToken) {
+		return NewValidationError(CodeInvalidValue, "Pagination token has expired", "Restart pagination from the first page")
+	}
+	return NewValidationError(CodeInvalidFormat, "Invalid pagination token", "The next_token parameter is malformed or has been modified")
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+func testPageKey() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: "item-1"},
+	}
+}
+
+func TestPageTokenCodec_FreshToken(t *testing.T) {
+	codec := NewPageTokenCodec([]byte("secret"), time.Minute)
+
+	token, err := codec.Encode(testPageKey())
+	if err != nil {
+		t.Fatalf("Failed to encode token: %v", err)
+	}
+
+	key, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("Expected fresh token to decode, got %v", err)
+	}
+
+	id, ok := key["id"].(*types.AttributeValueMemberS)
+	if !ok || id.Value != "item-1" {
+		t.Errorf("Expected key id 'item-1', got %v", key["id"])
+	}
+}
+
+func TestPageTokenCodec_ExpiredToken(t *testing.T) {
+	codec := NewPageTokenCodec([]byte("secret"), time.Minute)
+	issued := time.Now()
+	codec.now = func() time.Time { return issued }
+
+	token, err := codec.Encode(testPageKey())
+	if err != nil {
+		t.Fatalf("Failed to encode token: %v", err)
+	}
+
+	codec.now = func() time.Time { return issued.Add(2 * time.Minute) }
+	if _, err := codec.Decode(token); !errors.Is(err, ErrExpiredPageToken) {
+		t.Fatalf("Expected ErrExpiredPageToken, got %v", err)
+	}
+}
+
+func TestPageTokenCodec_TamperedToken(t *testing.T) {
+	codec := NewPageTokenCodec([]byte("secret"), time.Minute)
+
+	token, err := codec.Encode(testPageKey())
+	if err != nil {
+		t.Fatalf("Failed to encode token: %v", err)
+	}
+
+	other := NewPageTokenCodec([]byte("other-secret"), time.Minute)
+	if _, err := other.Decode(token); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("Expected ErrInvalidPageToken for foreign signature, got %v", err)
+	}
+
+	if _, err := codec.Decode("bm90LWEtdG9rZW4"); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("Expected ErrInvalidPageToken for unsigned token, got %v", err)
+	}
+}
+
+func TestListItems_PageTokens(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for _, name := range []string{"First", "Second", "Third"} {
+		if err := repo.CreateItem(context.Background(), models.NewItem(name, "Description")); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+	issued := time.Now()
+	handler.pageTokens.now = func() time.Time { return issued }
+
+	req := httptest.NewRequest("GET", "/items?limit=2", nil)
+	w := httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	list, err := models.ParseListResponse(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if list.NextToken == "" {
+		t.Fatal("Expected a next_token on the first page")
+	}
+	target := "/items?limit=2&next_token=" + url.QueryEscape(list.NextToken)
+
+	// A fresh token continues the listing
+	req = httptest.NewRequest("GET", target, nil)
+	w = httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d for fresh token, got %d", http.StatusOK, w.Code)
+	}
+
+	// The same token is rejected once it has expired
+	handler.pageTokens.now = func() time.Time { return issued.Add(handler.config.PageTokenTTL + time.Second) }
+	req = httptest.NewRequest("GET", target, nil)
+	w = httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d for expired token, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var errResponse models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&errResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if errResponse.Error.Code != string(CodeInvalidValue) {
+		t.Errorf("Expected error code '%s', got '%s'", CodeInvalidValue, errResponse.Error.Code)
+	}
+}
+
+func TestPageTokenCodec_AttributeTypesRoundTrip(t *testing.T) {
+	codec := NewPageTokenCodec([]byte("secret"), time.Minute)
+	key := map[string]types.AttributeValue{
+		"id":      &types.AttributeValueMemberS{Value: "item-1"},
+		"version": &types.AttributeValueMemberN{Value: "42"},
+		"hash":    &types.AttributeValueMemberB{Value: []byte{0x00, 0xff, 0x10}},
+	}
+
+	token, err := codec.Encode(key)
+	if err != nil {
+		t.Fatalf("Failed to encode token: %v", err)
+	}
+	if url.QueryEscape(token) != token {
+		t.Errorf("Expected a URL-safe token, got %q", token)
+	}
+
+	decoded, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("Expected token to decode, got %v", err)
+	}
+
+	if s, ok := decoded["id"].(*types.AttributeValueMemberS); !ok || s.Value != "item-1" {
+		t.Errorf("Expected S attribute 'item-1', got %#v", decoded["id"])
+	}
+	if n, ok := decoded["version"].(*types.AttributeValueMemberN); !ok || n.Value != "42" {
+		t.Errorf("Expected N attribute '42', got %#v", decoded["version"])
+	}
+	if b, ok := decoded["hash"].(*types.AttributeValueMemberB); !ok || string(b.Value) != "\x00\xff\x10" {
+		t.Errorf("Expected B attribute 00ff10, got %#v", decoded["hash"])
+	}
+}
+
+func TestListItems_WalkAllPages(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	const total = 7
+	for i := 0; i < total; i++ {
+		if err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description")); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+
+	seen := map[string]bool{}
+	pages := 0
+	target := "/items?limit=2"
+	for {
+		req := httptest.NewRequest("GET", target, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		list, err := models.ParseListResponse(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Failed to parse page %d: %v", pages+1, err)
+		}
+		pages++
+		for _, item := range list.Items {
+			if seen[item.ID] {
+				t.Errorf("Item %s returned twice", item.ID)
+			}
+			seen[item.ID] = true
+		}
+
+		if !list.HasMore {
+			break
+		}
+		if list.NextToken == "" {
+			t.Fatalf("Expected a next_token on page %d with has_more", pages)
+		}
+		if pages > total {
+			t.Fatal("Pagination did not terminate")
+		}
+		target = "/items?limit=2&next_token=" + url.QueryEscape(list.NextToken)
+	}
+
+	if len(seen) != total {
+		t.Errorf("Expected to page through %d items, got %d", total, len(seen))
+	}
+	if pages != 4 {
+		t.Errorf("Expected 4 pages of at most 2 items, got %d", pages)
+	}
+}
+
+func TestListItems_MalformedPageToken(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	for _, target := range []string{"/items?next_token=not-a-token", "/items?limit=5&cursor=test"} {
+		req := httptest.NewRequest("GET", target, nil)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d for %s, got %d", http.StatusBadRequest, target, w.Code)
+		}
+
+		var response models.APIResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error.Code != string(CodeInvalidFormat) {
+			t.Errorf("Expected error code '%s' for %s, got '%s'", CodeInvalidFormat, target, response.Error.Code)
+		}
+	}
+}
+
+func TestRequirePageTokenSecret(t *testing.T) {
+	for value, wantErr := range map[string]bool{
+		"":                      true,
+		"short":                 true,
+		strings.Repeat("s", 32): false,
+	} {
+		t.Setenv("PAGE_TOKEN_SECRET", value)
+		if err := RequirePageTokenSecret(); (err != nil) != wantErr {
+			t.Errorf("Expected error %t for a %d byte secret, got %v", wantErr, len(value), err)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// ownedItemRequest sends method to /items/owned as principal, optionally
+// with the admin key
+func ownedItemRequest(handler *ItemHandler, method, body, principal string, admin bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/items/owned", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "owned")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	if principal != "" {
+		ctx = WithPrincipal(ctx, principal)
+	}
+	if admin {
+		ctx = WithAdmin(ctx)
+	}
+	req = req.WithContext(ctx)
+
+	switch method {
+	case "GET":
+		handler.GetItem(w, req)
+	case "PUT":
+		handler.UpdateItem(w, req)
+	case "PATCH":
+		handler.PatchItem(w, req)
+	case "DELETE":
+		handler.DeleteItem(w, req)
+	}
+	return w
+}
+
+func TestCreateItem_RecordsPrincipal(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(WithPrincipal(req.Context(), "alice"))
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+
+	var response struct {
+		Data models.Item `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.CreatedBy != "alice" {
+		t.Errorf("Expected created_by 'alice', got %q", response.Data.CreatedBy)
+	}
+}
+
+func TestOwnershipEnforcement(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		principal      string
+		admin          bool
+		enforce        bool
+		expectedStatus int
+	}{
+		{name: "Owner updates", method: "PUT", body: `{"name":"Renamed"}`, principal: "alice", enforce: true, expectedStatus: http.StatusOK},
+		{name: "Owner patches", method: "PATCH", body: `{"name":"Renamed"}`, principal: "alice", enforce: true, expectedStatus: http.StatusOK},
+		{name: "Owner deletes", method: "DELETE", principal: "alice", enforce: true, expectedStatus: http.StatusOK},
+		{name: "Non-owner updates", method: "PUT", body: `{"name":"Renamed"}`, principal: "bob", enforce: true, expectedStatus: http.StatusForbidden},
+		{name: "Non-owner patches", method: "PATCH", body: `{"name":"Renamed"}`, principal: "bob", enforce: true, expectedStatus: http.StatusForbidden},
+		{name: "Non-owner deletes", method: "DELETE", principal: "bob", enforce: true, expectedStatus: http.StatusForbidden},
+		{name: "Anonymous updates", method: "PUT", body: `{"name":"Renamed"}`, enforce: true, expectedStatus: http.StatusForbidden},
+		{name: "Admin overrides update", method: "PUT", body: `{"name":"Renamed"}`, principal: "bob", admin: true, enforce: true, expectedStatus: http.StatusOK},
+		{name: "Admin overrides delete", method: "DELETE", admin: true, enforce: true, expectedStatus: http.StatusOK},
+		{name: "Not enforced", method: "PUT", body: `{"name":"Renamed"}`, principal: "bob", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			item := models.NewItem("Item", "Description")
+			item.ID = "owned"
+			item.CreatedBy = "alice"
+			if err := repo.CreateItem(context.Background(), item); err != nil {
+				t.Fatalf("Failed to seed item: %v", err)
+			}
+			handler := NewItemHandler(repo)
+			handler.config.EnforceOwnership = tt.enforce
+
+			w := ownedItemRequest(handler, tt.method, tt.body, tt.principal, tt.admin)
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusForbidden {
+				var response models.APIResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Error.Code != string(CodeForbidden) {
+					t.Errorf("Expected error code %s, got %s", CodeForbidden, response.Error.Code)
+				}
+
+				stored, err := repo.GetItem(context.Background(), "owned", nil)
+				if err != nil || stored.Name != "Item" {
+					t.Errorf("Expected the item to be unchanged, got %+v (%v)", stored, err)
+				}
+			}
+		})
+	}
+}
+
+// userItemRequest sends method to /items/owned as the verified user, or
+// anonymously when user is empty
+func userItemRequest(handler *ItemHandler, method, body, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/items/owned", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "owned")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	if user != "" {
+		ctx = WithPrincipal(WithUser(ctx, user), user)
+	}
+	req = req.WithContext(ctx)
+
+	switch method {
+	case "GET":
+		handler.GetItem(w, req)
+	case "PUT":
+		handler.UpdateItem(w, req)
+	case "DELETE":
+		handler.DeleteItem(w, req)
+	}
+	return w
+}
+
+func TestCreateItem_RecordsOwner(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(WithUser(req.Context(), "user-1"))
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+
+	var response struct {
+		Data models.Item `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.OwnerID != "user-1" {
+		t.Errorf("Expected owner_id 'user-1', got %q", response.Data.OwnerID)
+	}
+}
+
+func TestOwnerScoping(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		user           string
+		owner          string
+		scoped         bool
+		expectedStatus int
+	}{
+		{name: "Owner reads", method: "GET", user: "alice", owner: "alice", scoped: true, expectedStatus: http.StatusOK},
+		{name: "Owner updates", method: "PUT", body: `{"name":"Renamed"}`, user: "alice", owner: "alice", scoped: true, expectedStatus: http.StatusOK},
+		{name: "Owner deletes", method: "DELETE", user: "alice", owner: "alice", scoped: true, expectedStatus: http.StatusOK},
+		{name: "Other user reads", method: "GET", user: "bob", owner: "alice", scoped: true, expectedStatus: http.StatusForbidden},
+		{name: "Other user updates", method: "PUT", body: `{"name":"Renamed"}`, user: "bob", owner: "alice", scoped: true, expectedStatus: http.StatusForbidden},
+		{name: "Other user deletes", method: "DELETE", user: "bob", owner: "alice", scoped: true, expectedStatus: http.StatusForbidden},
+		{name: "Anonymous reads", method: "GET", owner: "alice", scoped: true, expectedStatus: http.StatusForbidden},
+		{name: "Unowned item is open", method: "PUT", body: `{"name":"Renamed"}`, user: "bob", scoped: true, expectedStatus: http.StatusOK},
+		{name: "Not scoped", method: "GET", user: "bob", owner: "alice", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			item := models.NewItem("Item", "Description")
+			item.ID = "owned"
+			item.OwnerID = tt.owner
+			if err := repo.CreateItem(context.Background(), item); err != nil {
+				t.Fatalf("Failed to seed item: %v", err)
+			}
+			handler := NewItemHandler(repo)
+			handler.config.ScopeToOwner = tt.scoped
+
+			w := userItemRequest(handler, tt.method, tt.body, tt.user)
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusForbidden {
+				var response models.APIResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Error.Code != string(CodeForbidden) {
+					t.Errorf("Expected error code %s, got %s", CodeForbidden, response.Error.Code)
+				}
+
+				stored, err := repo.GetItem(context.Background(), "owned", nil)
+				if err != nil || stored.Name != "Item" {
+					t.Errorf("Expected the item to be unchanged, got %+v (%v)", stored, err)
+				}
+			}
+		})
+	}
+
+	// Admins are never scoped
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Item", "Description")
+	item.ID = "owned"
+	item.OwnerID = "alice"
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+	handler.config.ScopeToOwner = true
+	if w := ownedItemRequest(handler, "GET", "", "bob", true); w.Code != http.StatusOK {
+		t.Errorf("Expected an admin to read another user's item, got %d", w.Code)
+	}
+}
+
+func TestListItems_ScopedToOwner(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	for id, owner := range map[string]string{"alice-1": "alice", "alice-2": "alice", "bob-1": "bob", "open": ""} {
+		item := models.NewItem(id, "Description")
+		item.ID = id
+		item.OwnerID = owner
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+	handler.config.ScopeToOwner = true
+
+	list := func(ctx context.Context) []string {
+		req := httptest.NewRequest("GET", "/items", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+		var response struct {
+			Data struct {
+				Items []models.Item `json:"items"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		var ids []string
+		for _, item := range response.Data.Items {
+			ids = append(ids, item.ID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	if ids := list(WithUser(context.Background(), "alice")); !slices.Equal(ids, []string{"alice-1", "alice-2"}) {
+		t.Errorf("Expected only alice's items to be listed, got %v", ids)
+	}
+	if ids := list(context.Background()); !slices.Equal(ids, []string{"open"}) {
+		t.Errorf("Expected anonymous callers to list only unowned items, got %v", ids)
+	}
+	if ids := list(WithAdmin(context.Background())); len(ids) != 4 {
+		t.Errorf("Expected admins to list every item, got %v", ids)
+	}
+}
+
+// scopedOwnerHandler returns a handler with items scoped to their owner,
+// serving alice's items "owned" and "deleted", soft-deleted, and bob's
+// item "mine"
+func scopedOwnerHandler(t *testing.T) (*ItemHandler, repository.ItemRepository) {
+	t.Helper()
+	t.Setenv("SOFT_DELETE", "true")
+	repo := repository.NewMemoryRepository()
+	for id, owner := range map[string]string{"owned": "alice", "deleted": "alice", "mine": "bob"} {
+		item := models.NewItem("Item "+id, "Description")
+		item.ID = id
+		item.OwnerID = owner
+		item.Category = owner
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	if err := repo.DeleteItem(context.Background(), "deleted", nil); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+	handler.config.ScopeToOwner = true
+	return handler, repo
+}
+
+// asUser returns the request made by user as both the verified user and
+// the principal
+func asUser(req *http.Request, user string) *http.Request {
+	return req.WithContext(WithPrincipal(WithUser(req.Context(), user), user))
+}
+
+func TestOwnerScoping_ItemRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		id     string
+		handle func(*ItemHandler) http.HandlerFunc
+	}{
+		{name: "Diff", method: "GET", target: "/items/diff?a=mine&b=owned", handle: func(h *ItemHandler) http.HandlerFunc { return h.DiffItems }},
+		{name: "View", method: "POST", target: "/items/owned/view", id: "owned", handle: func(h *ItemHandler) http.HandlerFunc { return h.RecordView }},
+		{name: "Lease", method: "POST", target: "/items/owned/lease", id: "owned", handle: func(h *ItemHandler) http.HandlerFunc { return h.GetItemForUpdate }},
+		{name: "Restore", method: "POST", target: "/items/deleted/restore", id: "deleted", handle: func(h *ItemHandler) http.HandlerFunc { return h.RestoreItem }},
+		{name: "Purge", method: "DELETE", target: "/items/deleted/purge", id: "deleted", handle: func(h *ItemHandler) http.HandlerFunc { return h.PurgeItem }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, repo := scopedOwnerHandler(t)
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = asUser(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), "bob")
+			w := httptest.NewRecorder()
+			tt.handle(handler)(w, req)
+
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+			}
+			if tt.id != "" {
+				stored, err := repo.GetItem(context.Background(), tt.id, nil)
+				if err != nil {
+					t.Fatalf("Expected the item to remain: %v", err)
+				}
+				if stored.ViewCount != 0 || stored.LeaseHolder != "" || (tt.id == "deleted") != stored.IsDeleted() {
+					t.Errorf("Expected the item to be unchanged, got %+v", stored)
+				}
+			}
+		})
+	}
+}
+
+func TestOwnerScoping_BatchGetItems(t *testing.T) {
+	handler, _ := scopedOwnerHandler(t)
+
+	req := asUser(httptest.NewRequest("POST", "/items/batch-get", strings.NewReader(`{"ids":["owned","mine"]}`)), "bob")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.BatchGetItems(w, req)
+
+	var response struct {
+		Data struct {
+			Items   []models.Item `json:"items"`
+			Missing []string      `json:"missing"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data.Items) != 1 || response.Data.Items[0].ID != "mine" {
+		t.Errorf("Expected only bob's item, got %+v", response.Data.Items)
+	}
+	if !slices.Equal(response.Data.Missing, []string{"owned"}) {
+		t.Errorf("Expected alice's item to be reported missing, got %v", response.Data.Missing)
+	}
+}
+
+func TestOwnerScoping_FacetItems(t *testing.T) {
+	handler, _ := scopedOwnerHandler(t)
+
+	req := asUser(httptest.NewRequest("GET", "/items/facets?by=category", nil), "bob")
+	w := httptest.NewRecorder()
+	handler.FacetItems(w, req)
+
+	var response struct {
+		Data models.FacetResult `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Counts["alice"] != 0 || response.Data.Counts["bob"] != 1 {
+		t.Errorf("Expected only bob's item to be counted, got %v", response.Data.Counts)
+	}
+}
+
+func TestOwnerScoping_AutocompleteItems(t *testing.T) {
+	handler, _ := scopedOwnerHandler(t)
+
+	req := asUser(httptest.NewRequest("GET", "/items/autocomplete?q=Item", nil), "bob")
+	w := httptest.NewRecorder()
+	handler.AutocompleteItems(w, req)
+
+	var response struct {
+		Data models.AutocompleteResult `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data.Items) != 1 || response.Data.Items[0].ID != "mine" {
+		t.Errorf("Expected only bob's item to be suggested, got %+v", response.Data.Items)
+	}
+}
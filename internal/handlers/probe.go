@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// ProbeTable handles GET /admin/probe?table=X&region=Y requests. It runs
+// DescribeTable against the table with a temporary client for the region,
+// so a migration target can be checked before any configuration points at
+// it. An unreachable table is reported, not returned as an error.
+func (h *ItemHandler) ProbeTable(w http.ResponseWriter, r *http.Request) {
+	table := r.URL.Query().Get("table")
+	if table == "" {
+		WriteMissingParameterErrorResponse(w, r, "table")
+		return
+	}
+	if !repository.IsValidTableName(table) {
+		WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid table parameter", "table must be 3 to 255 letters, digits, underscores, dots or hyphens"))
+		return
+	}
+	region := r.URL.Query().Get("region")
+	if region == "" {
+		WriteMissingParameterErrorResponse(w, r, "region")
+		return
+	}
+	if !repository.IsValidRegion(region) {
+		WriteErrorResponse(w, r, NewValidationError(CodeInvalidValue, "Invalid region parameter", "region must be an AWS region name, such as us-east-1"))
+		return
+	}
+
+	result := probeResponse{Table: table, Region: region}
+	client, err := h.probeClient(r.Context(), region)
+	if err != nil {
+		result.Message = "Failed to create client: " + err.Error()
+	} else {
+		health := repository.ProbeTable(r.Context(), client, table, h.config.HealthCheckTimeout)
+		result.Reachable = health.Healthy
+		result.Message = health.Message
+		result.LatencyMs = health.LatencyMs
+	}
+
+	response := models.APIResponse{
+		Success: true,
+		Data:    result,
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
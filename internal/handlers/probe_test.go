@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/repository"
+)
+
+// probeClient is a fake DynamoDB client that only answers DescribeTable
+type probeClient struct {
+	repository.DynamoDBAPI
+	tables map[string]types.TableStatus
+}
+
+func (c *probeClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	status, ok := c.tables[*params.TableName]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: params.TableName}
+	}
+	return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: status}}, nil
+}
+
+func probeTable(handler *ItemHandler, query string) (*httptest.ResponseRecorder, probeResponse) {
+	req := httptest.NewRequest("GET", "/admin/probe?"+query, nil)
+	w := httptest.NewRecorder()
+	handler.ProbeTable(w, req)
+
+	var response struct {
+		Data probeResponse `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response.Data
+}
+
+func TestProbeTable(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	var regions []string
+	handler.probeClient = func(ctx context.Context, region string) (repository.DynamoDBAPI, error) {
+		regions = append(regions, region)
+		return &probeClient{tables: map[string]types.TableStatus{
+			"items-v2":       types.TableStatusActive,
+			"items-creating": types.TableStatusCreating,
+		}}, nil
+	}
+
+	tests := []struct {
+		name              string
+		table             string
+		expectedReachable bool
+		expectedMessage   string
+	}{
+		{"Active table", "items-v2", true, "Connected"},
+		{"Table not yet active", "items-creating", false, "Table is CREATING"},
+		{"Missing table", "items-missing", false, "DynamoDB health check failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, result := probeTable(handler, "table="+tt.table+"&region=eu-west-1")
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if result.Table != tt.table || result.Region != "eu-west-1" {
+				t.Errorf("Expected the probed table and region, got %+v", result)
+			}
+			if result.Reachable != tt.expectedReachable || !strings.HasPrefix(result.Message, tt.expectedMessage) {
+				t.Errorf("Expected reachable=%v with %q, got %+v", tt.expectedReachable, tt.expectedMessage, result)
+			}
+		})
+	}
+	if len(regions) != len(tests) || regions[0] != "eu-west-1" {
+		t.Errorf("Expected a client for eu-west-1 per probe, got %v", regions)
+	}
+}
+
+func TestProbeTable_ClientFailure(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	handler.probeClient = func(ctx context.Context, region string) (repository.DynamoDBAPI, error) {
+		return nil, errors.New("no credentials")
+	}
+
+	w, result := probeTable(handler, "table=items-v2&region=eu-west-1")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if result.Reachable || !strings.Contains(result.Message, "no credentials") {
+		t.Errorf("Expected the client failure reported, got %+v", result)
+	}
+}
+
+func TestProbeTable_InvalidParameters(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+	handler.probeClient = func(ctx context.Context, region string) (repository.DynamoDBAPI, error) {
+		t.Fatal("Expected no client for invalid parameters")
+		return nil, nil
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode ErrorCode
+	}{
+		{"Missing table", "region=eu-west-1", CodeMissingField},
+		{"Missing region", "table=items-v2", CodeMissingField},
+		{"Invalid table", "table=items/v2&region=eu-west-1", CodeInvalidValue},
+		{"Invalid region", "table=items-v2&region=evil.example.com", CodeInvalidValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := probeTable(handler, tt.query)
+
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), string(tt.expectedCode)) {
+				t.Errorf("Expected %d %s, got %d: %s", http.StatusBadRequest, tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
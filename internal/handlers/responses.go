@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// The data payloads below are structs rather than maps so their fields
+// serialize in the documented order, which snapshot tests can rely on.
+
+// healthResponse is the data payload of GET /health
+type healthResponse struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+}
+
+// dbHealthResponse is the data payload of GET /health/db
+type dbHealthResponse struct {
+	Status    string                   `json:"status"`
+	Service   string                   `json:"service"`
+	Message   string                   `json:"message"`
+	TableName string                   `json:"tableName"`
+	Tables    []repository.TableHealth `json:"tables,omitempty"`
+}
+
+// deleteResponse is the data payload of a delete or purge of one item
+type deleteResponse struct {
+	Message string `json:"message"`
+	ID      string `json:"id"`
+}
+
+// viewCountResponse is the data payload of POST /items/{id}/view
+type viewCountResponse struct {
+	ID        string `json:"id"`
+	ViewCount int64  `json:"view_count"`
+}
+
+// compactResponse is the data payload of POST /admin/compact
+type compactResponse struct {
+	Before string `json:"before"`
+	Purged int    `json:"purged"`
+}
+
+// probeResponse is the data payload of GET /admin/probe
+type probeResponse struct {
+	Table     string `json:"table"`
+	Region    string `json:"region"`
+	Reachable bool   `json:"reachable"`
+	Message   string `json:"message"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// bulkTagResponse is the data payload of POST /items/bulk-tag
+type bulkTagResponse struct {
+	DryRun     bool     `json:"dry_run"`
+	Matched    int      `json:"matched"`
+	MatchedIDs []string `json:"matched_ids"`
+	Updated    int      `json:"updated"`
+	HasMore    bool     `json:"has_more"`
+	NextCursor string   `json:"next_cursor"`
+}
+
+// diffResponse is the data payload of GET /items/diff
+type diffResponse struct {
+	A           string             `json:"a"`
+	B           string             `json:"b"`
+	Identical   bool               `json:"identical"`
+	Differences []models.FieldDiff `json:"differences"`
+}
+
+// batchCreateResponse is the data payload of POST /items/batch
+type batchCreateResponse struct {
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+	Results []batchCreateResult `json:"results"`
+}
+
+// batchGetResponse is the data payload of POST /items/batch-get
+type batchGetResponse struct {
+	Items   []ItemView `json:"items"`
+	Missing []string   `json:"missing"`
+}
+
+// bulkDeletePreviewResponse is the data payload of a bulk delete preview
+type bulkDeletePreviewResponse struct {
+	WouldDelete  int    `json:"would_delete"`
+	ConfirmToken string `json:"confirm_token"`
+	ExpiresAt    string `json:"expires_at"`
+}
+
+// bulkDeleteResponse is the data payload of a confirmed bulk delete
+type bulkDeleteResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// explainResponse is the data payload of POST /items/explain. Normalized
+// is the payload as validation left it, and Item the item it would create.
+type explainResponse struct {
+	Valid      bool                      `json:"valid"`
+	Rules      []explainRule             `json:"rules"`
+	Normalized *models.CreateItemRequest `json:"normalized"`
+	Item       interface{}               `json:"item,omitempty"`
+	Defaults   []explainDefault          `json:"defaults,omitempty"`
+}
+
+// explainRule is the outcome of one validation rule
+type explainRule struct {
+	Rule   string            `json:"rule"`
+	Passed bool              `json:"passed"`
+	Error  *models.ErrorInfo `json:"error,omitempty"`
+}
+
+// explainDefault is a field a default rule filled in
+type explainDefault struct {
+	Field string      `json:"field"`
+	Value interface{} `json:"value"`
+}
+
+// sortedObject is a JSON object whose fields are only known at run time,
+// such as a ?fields= projection. It always writes its keys sorted, so the
+// same object serializes to the same bytes every time.
+type sortedObject map[string]json.RawMessage
+
+// MarshalJSON writes the object's fields in sorted key order
+func (o sortedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range slices.Sorted(maps.Keys(o)) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		value := o[key]
+		if value == nil {
+			value = json.RawMessage("null")
+		}
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
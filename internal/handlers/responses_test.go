@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"fis-playground/internal/models"
+)
+
+func TestResponses_StableKeyOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected string
+	}{
+		{
+			name:     "Delete",
+			data:     deleteResponse{Message: "Item deleted successfully", ID: "item-1"},
+			expected: `{"message":"Item deleted successfully","id":"item-1"}`,
+		},
+		{
+			name:     "Health",
+			data:     healthResponse{Status: "healthy", Service: "FIS Playground API"},
+			expected: `{"status":"healthy","service":"FIS Playground API"}`,
+		},
+		{
+			name:     "Projection",
+			data:     sortedObject{"status": json.RawMessage(`"active"`), "id": json.RawMessage(`"item-1"`), "name": json.RawMessage(`"Item"`), "category": nil},
+			expected: `{"category":null,"id":"item-1","name":"Item","status":"active"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				data, err := json.Marshal(tt.data)
+				if err != nil {
+					t.Fatalf("Failed to marshal: %v", err)
+				}
+				if string(data) != tt.expected {
+					t.Fatalf("Expected %s, got %s", tt.expected, data)
+				}
+			}
+		})
+	}
+}
+
+func TestGetItem_FieldsSerializeStably(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	first := getItemWithFields(handler, "status,name,description,category")
+	if first.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, first.Code)
+	}
+	body := first.Body.String()
+	if !strings.Contains(body, `"data":{"description":`) {
+		t.Errorf("Expected projected fields in sorted order, got %s", body)
+	}
+
+	for range 20 {
+		if w := getItemWithFields(handler, "status,name,description,category"); w.Body.String() != body {
+			t.Fatalf("Expected identical responses, got %s and %s", body, w.Body.String())
+		}
+	}
+}
+
+func TestBatchGetResponse_FieldOrder(t *testing.T) {
+	data, err := json.Marshal(models.APIResponse{
+		Success: true,
+		Data:    batchGetResponse{Items: []ItemView{}, Missing: []string{"b"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"data":{"items":[],"missing":["b"]}`) {
+		t.Errorf("Expected items before missing, got %s", data)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// routableMethods are the methods the API registers routes for, in the
+// order the Allow header lists them
+var routableMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// RouteNotFound handles requests for paths no route serves, with the same
+// JSON error shape as the API's other errors
+func RouteNotFound(w http.ResponseWriter, r *http.Request) {
+	WriteErrorResponse(w, r, &APIError{
+		Type:       ErrorTypeNotFound,
+		Code:       CodeNotFound,
+		Message:    "Route not found",
+		Details:    fmt.Sprintf("No route serves %s", r.URL.Path),
+		StatusCode: http.StatusNotFound,
+	})
+}
+
+// MethodNotAllowed returns the handler for requests to one of routes that
+// doesn't support their method, listing the methods it does in the Allow
+// header. Register it once every route is, as it reads them up front.
+func MethodNotAllowed(routes chi.Routes) http.HandlerFunc {
+	// Chi knows the allowed methods when it calls a custom 405 handler but
+	// doesn't pass them on, and matching a method against nested routers
+	// reports false positives, so they're looked up in a flat copy of routes
+	lookup := chi.NewRouter()
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	_ = chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if slices.Contains(routableMethods, method) {
+			lookup.Method(method, trimTrailingSlash(route), noop)
+		}
+		return nil
+	})
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := trimTrailingSlash(r.URL.Path)
+		var allowed []string
+		for _, method := range routableMethods {
+			if lookup.Match(chi.NewRouteContext(), method, path) {
+				allowed = append(allowed, method)
+			}
+		}
+
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		WriteErrorResponse(w, r, &APIError{
+			Type:       ErrorTypeValidation,
+			Code:       CodeMethodNotAllowed,
+			Message:    "Method not allowed",
+			Details:    fmt.Sprintf("%s does not support %s; allowed methods: %s", r.URL.Path, r.Method, strings.Join(allowed, ", ")),
+			StatusCode: http.StatusMethodNotAllowed,
+		})
+	}
+}
+
+// trimTrailingSlash drops a path's trailing slash, so /items/ and the
+// /items/ index route of a subrouter both match /items
+func trimTrailingSlash(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"fis-playground/internal/logging"
+	"fis-playground/internal/repository"
+)
+
+// SkipValidationHeader asks for a write to bypass the model validators
+const SkipValidationHeader = "X-Skip-Validation"
+
+// skipValidation parses X-Skip-Validation, with which admins' data-repair
+// tools can write items that fail validation, such as legacy rows. Only
+// admins may skip validation. Every granted bypass is logged as an audit
+// warning, and the returned request's repository writes skip the model
+// validators; storage checks such as key presence still apply.
+func skipValidation(r *http.Request) (*http.Request, *APIError) {
+	value := r.Header.Get(SkipValidationHeader)
+	if value == "" {
+		return r, nil
+	}
+	skip, err := strconv.ParseBool(value)
+	if err != nil {
+		return r, NewValidationError(CodeInvalidValue, "Invalid "+SkipValidationHeader+" header", SkipValidationHeader+" must be true or false")
+	}
+	if !skip {
+		return r, nil
+	}
+	if !IsAdmin(r.Context()) {
+		return r, &APIError{
+			Type:       ErrorTypeAuth,
+			Code:       CodeForbidden,
+			Message:    "Skipping validation requires the admin key",
+			Details:    "Only admins can write items that fail validation",
+			StatusCode: http.StatusForbidden,
+		}
+	}
+
+	logging.FromContext(r.Context()).Warn("AUDIT: validation skipped",
+		"audit", "skip_validation",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"principal", PrincipalFromContext(r.Context()))
+	return r.WithContext(repository.WithSkipValidation(r.Context())), nil
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/logging"
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// skipValidationRequest builds a request with X-Skip-Validation set, as an
+// admin when admin is true, logging to logs
+func skipValidationRequest(method, target, id, body string, admin bool, logs *bytes.Buffer) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(SkipValidationHeader, "true")
+	ctx := logging.WithLogger(req.Context(), slog.New(slog.NewJSONHandler(logs, nil)))
+	if admin {
+		ctx = WithAdmin(ctx)
+	}
+	if id != "" {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	}
+	return req.WithContext(ctx)
+}
+
+func TestSkipValidation_RequiresAdmin(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	var logs bytes.Buffer
+
+	body := `{"name":"` + strings.Repeat("x", 150) + `","description":"Legacy row"}`
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, skipValidationRequest("POST", "/items", "", body, false, &logs))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+	result, err := repo.ListItems(context.Background(), &repository.ListItemsOptions{Limit: 10})
+	if err != nil || len(result.Items) != 0 {
+		t.Errorf("Expected nothing to be created, got %v (%v)", result, err)
+	}
+	if strings.Contains(logs.String(), "AUDIT") {
+		t.Errorf("Expected no audit entry for a refused bypass, got %s", logs.String())
+	}
+}
+
+func TestSkipValidation_AdminCreatesInvalidItem(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	var logs bytes.Buffer
+
+	name := strings.Repeat("x", 150)
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, skipValidationRequest("POST", "/items", "", `{"name":"`+name+`","description":""}`, true, &logs))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	result, err := repo.ListItems(context.Background(), &repository.ListItemsOptions{Limit: 10})
+	if err != nil || len(result.Items) != 1 || result.Items[0].Name != name {
+		t.Fatalf("Expected the invalid item to be stored, got %v (%v)", result, err)
+	}
+	for _, want := range []string{`"level":"WARN"`, `"msg":"AUDIT: validation skipped"`, `"audit":"skip_validation"`, `"method":"POST"`, `"path":"/items"`} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("Expected the audit entry to contain %s, got %s", want, logs.String())
+		}
+	}
+}
+
+func TestSkipValidation_KeepsIDChecks(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	for _, id := range []string{"_meta#item_count", "has space"} {
+		body := `{"id":"` + id + `","name":"Legacy","description":"Legacy row"}`
+		for _, route := range []struct {
+			target string
+			handle http.HandlerFunc
+		}{
+			{"/items", handler.CreateItem},
+			{"/admin/import", handler.ImportItem},
+		} {
+			var logs bytes.Buffer
+			w := httptest.NewRecorder()
+			route.handle(w, skipValidationRequest("POST", route.target, "", body, true, &logs))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected %s with ID %q to be rejected with 400, got %d", route.target, id, w.Code)
+			}
+		}
+	}
+}
+
+func TestSkipValidation_AdminUpdatesToInvalidStatus(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+
+	var logs bytes.Buffer
+	w := httptest.NewRecorder()
+	handler.UpdateItem(w, skipValidationRequest("PUT", "/items/"+item.ID, item.ID, `{"status":"legacy"}`, false, &logs))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 without the admin key, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.UpdateItem(w, skipValidationRequest("PUT", "/items/"+item.ID, item.ID, `{"status":"legacy"}`, true, &logs))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	stored, err := repo.GetItem(context.Background(), item.ID, nil)
+	if err != nil || stored.Status != "legacy" {
+		t.Fatalf("Expected the invalid status to be stored, got %v (%v)", stored, err)
+	}
+	if !strings.Contains(logs.String(), `"path":"/items/`+item.ID+`"`) {
+		t.Errorf("Expected an audit entry for the update, got %s", logs.String())
+	}
+}
+
+func TestSkipValidation_InvalidHeader(t *testing.T) {
+	handler := NewItemHandler(repository.NewMemoryRepository())
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`))
+	req.Header.Set(SkipValidationHeader, "maybe")
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req.WithContext(WithAdmin(req.Context())))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/events"
+	"fis-playground/internal/models"
+)
+
+// includeDeletedRequested parses the include_deleted parameter, which
+// includes soft-deleted items in reads
+func includeDeletedRequested(r *http.Request) (bool, *APIError) {
+	param := r.URL.Query().Get("include_deleted")
+	if param == "" {
+		return false, nil
+	}
+	includeDeleted, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, NewValidationError(CodeInvalidValue, "Invalid include_deleted parameter", "include_deleted must be true or false")
+	}
+	return includeDeleted, nil
+}
+
+// RestoreItem handles POST /items/{id}/restore requests, undoing a soft
+// delete. Items that aren't deleted are rejected with 409.
+func (h *ItemHandler) RestoreItem(w http.ResponseWriter, r *http.Request) {
+	itemID := chi.URLParam(r, "id")
+	if itemID == "" {
+		WriteMissingParameterErrorResponse(w, r, "Item ID")
+		return
+	}
+
+	owner, apiErr := h.requiredOwner(r.Context())
+	if apiErr == nil {
+		apiErr = h.authorizeItemWrite(r.Context(), itemID)
+	}
+	if apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	item, err := h.repo.RestoreItem(r.Context(), itemID, owner)
+	if err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+	h.publish(events.ItemUpdated, item.ID, item)
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data:    h.itemView(r, item),
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// PurgeItem handles DELETE /items/{id}/purge requests, permanently removing
+// a soft-deleted item. Items that aren't deleted are rejected with 409.
+func (h *ItemHandler) PurgeItem(w http.ResponseWriter, r *http.Request) {
+	itemID := chi.URLParam(r, "id")
+	if itemID == "" {
+		WriteMissingParameterErrorResponse(w, r, "Item ID")
+		return
+	}
+
+	if apiErr := h.authorizeItemWrite(r.Context(), itemID); apiErr != nil {
+		WriteErrorResponse(w, r, apiErr)
+		return
+	}
+
+	if err := h.repo.PurgeItem(r.Context(), itemID); err != nil {
+		WriteRepositoryErrorResponse(w, r, err)
+		return
+	}
+
+	// Return success response
+	response := models.APIResponse{
+		Success: true,
+		Data: deleteResponse{
+			Message: "Item purged successfully",
+			ID:      itemID,
+		},
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
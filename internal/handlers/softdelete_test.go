@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// itemRequest sends a request for the item with the given ID to handle
+func itemRequest(handle http.HandlerFunc, method, target, id string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	handle(w, req)
+	return w
+}
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	t.Setenv("SOFT_DELETE", "true")
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	listedCount := func(target string) int {
+		w := httptest.NewRecorder()
+		handler.ListItems(w, httptest.NewRequest("GET", target, nil))
+		var response struct {
+			Data models.ListItemsResponse `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return len(response.Data.Items)
+	}
+
+	if w := itemRequest(handler.DeleteItem, "DELETE", "/items/"+item.ID, item.ID); w.Code != http.StatusOK {
+		t.Fatalf("Expected delete to succeed, got %d", w.Code)
+	}
+	if w := itemRequest(handler.GetItem, "GET", "/items/"+item.ID, item.ID); w.Code != http.StatusNotFound {
+		t.Errorf("Expected a deleted item to be not found, got %d", w.Code)
+	}
+	if n := listedCount("/items"); n != 0 {
+		t.Errorf("Expected the deleted item to be unlisted, got %d items", n)
+	}
+
+	w := itemRequest(handler.GetItem, "GET", "/items/"+item.ID+"?include_deleted=true", item.ID)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected include_deleted to read the deleted item, got %d", w.Code)
+	}
+	if n := listedCount("/items?include_deleted=true"); n != 1 {
+		t.Errorf("Expected include_deleted to list the deleted item, got %d items", n)
+	}
+
+	if w := itemRequest(handler.RestoreItem, "POST", "/items/"+item.ID+"/restore", item.ID); w.Code != http.StatusOK {
+		t.Fatalf("Expected restore to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := itemRequest(handler.GetItem, "GET", "/items/"+item.ID, item.ID); w.Code != http.StatusOK {
+		t.Errorf("Expected the restored item to be found, got %d", w.Code)
+	}
+	if n := listedCount("/items"); n != 1 {
+		t.Errorf("Expected the restored item to be listed, got %d items", n)
+	}
+	if w := itemRequest(handler.RestoreItem, "POST", "/items/"+item.ID+"/restore", item.ID); w.Code != http.StatusConflict {
+		t.Errorf("Expected restoring a live item to conflict, got %d", w.Code)
+	}
+}
+
+func TestGetItem_InvalidIncludeDeleted(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	w := itemRequest(handler.GetItem, "GET", "/items/1?include_deleted=maybe", "1")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestPurgeItem(t *testing.T) {
+	t.Setenv("SOFT_DELETE", "true")
+	repo := repository.NewMemoryRepository()
+	handler := NewItemHandler(repo)
+
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+
+	if w := itemRequest(handler.PurgeItem, "DELETE", "/items/"+item.ID+"/purge", item.ID); w.Code != http.StatusConflict {
+		t.Errorf("Expected purging a live item to conflict, got %d", w.Code)
+	}
+	if w := itemRequest(handler.GetItem, "GET", "/items/"+item.ID, item.ID); w.Code != http.StatusOK {
+		t.Fatalf("Expected the live item to survive a rejected purge, got %d", w.Code)
+	}
+
+	if w := itemRequest(handler.DeleteItem, "DELETE", "/items/"+item.ID, item.ID); w.Code != http.StatusOK {
+		t.Fatalf("Expected delete to succeed, got %d", w.Code)
+	}
+	if w := itemRequest(handler.PurgeItem, "DELETE", "/items/"+item.ID+"/purge", item.ID); w.Code != http.StatusOK {
+		t.Fatalf("Expected purging a deleted item to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := itemRequest(handler.GetItem, "GET", "/items/"+item.ID+"?include_deleted=true", item.ID); w.Code != http.StatusNotFound {
+		t.Errorf("Expected the purged item to be gone, got %d", w.Code)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import "unicode/utf8"
+
+// ellipsis marks where a truncated description was cut
+const ellipsis = "…"
+
+// truncateDescriptions shortens the views' descriptions to limit characters,
+// followed by an ellipsis, and flags them. The items are copied first, so
+// the repository's items are left whole. A limit of zero or less truncates
+// nothing.
+func truncateDescriptions(views []ItemView, limit int) {
+	if limit <= 0 {
+		return
+	}
+	for i := range views {
+		description, truncated := truncateRunes(views[i].Description, limit)
+		if !truncated {
+			continue
+		}
+		item := *views[i].Item
+		item.Description = description + ellipsis
+		views[i].Item = &item
+		views[i].DescriptionTruncated = true
+	}
+}
+
+// truncateRunes returns the first limit characters of s, never splitting a
+// multi-byte character, and whether anything was cut
+func truncateRunes(s string, limit int) (string, bool) {
+	if utf8.RuneCountInString(s) <= limit {
+		return s, false
+	}
+	count := 0
+	for i := range s {
+		if count == limit {
+			return s[:i], true
+		}
+		count++
+	}
+	return s, false
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		input     string
+		limit     int
+		expected  string
+		truncated bool
+	}{
+		{"short", 10, "short", false},
+		{"exact", 5, "exact", false},
+		{"truncated", 5, "trunc", true},
+		{"héllo wörld", 7, "héllo w", true},
+		{"日本語のテキスト", 3, "日本語", true},
+	}
+
+	for _, tt := range tests {
+		got, truncated := truncateRunes(tt.input, tt.limit)
+		if got != tt.expected || truncated != tt.truncated {
+			t.Errorf("truncateRunes(%q, %d) = %q, %v; expected %q, %v", tt.input, tt.limit, got, truncated, tt.expected, tt.truncated)
+		}
+	}
+}
+
+func TestListItems_TruncatesDescriptions(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	long := models.NewItem("Long", "Ünïcödé description that goes on")
+	short := models.NewItem("Short", "Brief")
+	for _, item := range []*models.Item{long, short} {
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	handler := NewItemHandler(repo)
+	handler.config.ListDescriptionMax = 7
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	w := httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	var list struct {
+		Data struct {
+			Items []struct {
+				ID                   string `json:"id"`
+				Description          string `json:"description"`
+				DescriptionTruncated bool   `json:"description_truncated"`
+			} `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	for _, item := range list.Data.Items {
+		switch item.ID {
+		case long.ID:
+			if item.Description != "Ünïcödé…" || !item.DescriptionTruncated {
+				t.Errorf("Expected a truncated, flagged description, got %q, %v", item.Description, item.DescriptionTruncated)
+			}
+		case short.ID:
+			if item.Description != "Brief" || item.DescriptionTruncated {
+				t.Errorf("Expected the short description untouched, got %q, %v", item.Description, item.DescriptionTruncated)
+			}
+		}
+	}
+	if strings.Count(w.Body.String(), "description_truncated") != 1 {
+		t.Errorf("Expected only the truncated item flagged, got %s", w.Body.String())
+	}
+
+	// GetItem still returns the full text
+	req = httptest.NewRequest("GET", "/items/"+long.ID, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", long.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+	handler.GetItem(w, req)
+
+	var get struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &get); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if get.Data["description"] != long.Description {
+		t.Errorf("Expected the full description, got %v", get.Data["description"])
+	}
+	if _, ok := get.Data["description_truncated"]; ok {
+		t.Errorf("Expected no truncation flag, got %v", get.Data)
+	}
+}
+
+func TestListItems_TruncatedDescriptionKeepsFlagWithFields(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Long", "A description longer than the limit")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	handler := NewItemHandler(repo)
+	handler.config.ListDescriptionMax = 5
+
+	req := httptest.NewRequest("GET", "/items?fields=description", nil)
+	w := httptest.NewRecorder()
+	handler.ListItems(w, req)
+
+	if !strings.Contains(w.Body.String(), `"description":"A des…","description_truncated":true`) {
+		t.Errorf("Expected the truncated description and its flag, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+type adminContextKey struct{}
+
+// WithAdmin marks a request context as carrying a valid admin key
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminContextKey{}, true)
+}
+
+// IsAdmin reports whether the request carried a valid admin key
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminContextKey{}).(bool)
+	return admin
+}
+
+// previewRequested parses the preview parameter, which includes items
+// outside their visibility window in reads. Only admins may preview.
+func previewRequested(r *http.Request) (bool, *APIError) {
+	param := r.URL.Query().Get("preview")
+	if param == "" {
+		return false, nil
+	}
+	preview, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, NewValidationError(CodeInvalidValue, "Invalid preview parameter", "preview must be true or false")
+	}
+	if preview && !IsAdmin(r.Context()) {
+		return false, &APIError{
+			Type:       ErrorTypeAuth,
+			Code:       CodeForbidden,
+			Message:    "Preview requires the admin key",
+			Details:    "Only admins can read items outside their visibility window",
+			StatusCode: http.StatusForbidden,
+		}
+	}
+	return preview, nil
+}
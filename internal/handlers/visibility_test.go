@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// seedVisibilityWindows stores a not-yet-visible, an expired and an
+// in-window item under those IDs
+func seedVisibilityWindows(t *testing.T) *repository.MemoryRepository {
+	now := time.Now()
+	hourAgo, inAnHour := now.Add(-time.Hour), now.Add(time.Hour)
+	windows := map[string][2]*time.Time{
+		"upcoming":  {&inAnHour, nil},
+		"expired":   {nil, &hourAgo},
+		"in-window": {&hourAgo, &inAnHour},
+	}
+
+	repo := repository.NewMemoryRepository()
+	for id, window := range windows {
+		item := models.NewItem("Item "+id, "Description")
+		item.ID = id
+		item.VisibleFrom, item.VisibleUntil = window[0], window[1]
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	return repo
+}
+
+func getVisibleItem(handler *ItemHandler, id, query string, admin bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/items/"+id+query, nil)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	if admin {
+		ctx = WithAdmin(ctx)
+	}
+
+	handler.GetItem(w, req.WithContext(ctx))
+	return w
+}
+
+func TestGetItem_VisibilityWindow(t *testing.T) {
+	handler := NewItemHandler(seedVisibilityWindows(t))
+
+	tests := []struct {
+		name           string
+		id             string
+		query          string
+		admin          bool
+		expectedStatus int
+	}{
+		{"Not yet visible", "upcoming", "", false, http.StatusNotFound},
+		{"Visibility expired", "expired", "", false, http.StatusNotFound},
+		{"In window", "in-window", "", false, http.StatusOK},
+		{"Admin preview of not yet visible", "upcoming", "?preview=true", true, http.StatusOK},
+		{"Admin preview of expired", "expired", "?preview=true", true, http.StatusOK},
+		{"Admin without preview", "upcoming", "", true, http.StatusNotFound},
+		{"Preview without admin key", "upcoming", "?preview=true", false, http.StatusForbidden},
+		{"Invalid preview value", "in-window", "?preview=maybe", false, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getVisibleItem(handler, tt.id, tt.query, tt.admin)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestListItems_HidesItemsOutsideVisibilityWindow(t *testing.T) {
+	handler := NewItemHandler(seedVisibilityWindows(t))
+
+	listIDs := func(query string, admin bool) []string {
+		req := httptest.NewRequest("GET", "/items"+query, nil)
+		if admin {
+			req = req.WithContext(WithAdmin(req.Context()))
+		}
+		w := httptest.NewRecorder()
+		handler.ListItems(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var response struct {
+			Data struct {
+				Items []struct {
+					ID string `json:"id"`
+				} `json:"items"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		var ids []string
+		for _, item := range response.Data.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	if ids := listIDs("", false); len(ids) != 1 || ids[0] != "in-window" {
+		t.Errorf("Expected only the in-window item, got %v", ids)
+	}
+	if ids := listIDs("?preview=true", true); len(ids) != 3 {
+		t.Errorf("Expected an admin preview to list all 3 items, got %v", ids)
+	}
+}
+
+func TestCreateItem_VisibilityWindowOrder(t *testing.T) {
+	handler := NewItemHandler(&MockRepository{})
+
+	body := `{"name":"Item","description":"Description","visible_from":"2025-02-01T00:00:00Z","visible_until":"2025-01-01T00:00:00Z"}`
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.CreateItem(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), string(CodeInvalidValue)) {
+		t.Errorf("Expected %s, got %s", CodeInvalidValue, w.Body.String())
+	}
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maskedKeys are attribute keys carrying user-supplied content. IDs, error
+// codes and other operational fields are never masked.
+var maskedKeys = map[string]bool{
+	"name":        true,
+	"description": true,
+	"query":       true,
+}
+
+// quotingKeys are attribute keys whose text may quote user content, such as
+// error messages naming an item. Only the quoted parts are masked, keeping
+// the rest readable. Messages are treated the same way, since the standard
+// library log package formats values into the message.
+var quotingKeys = map[string]bool{
+	slog.MessageKey: true,
+	"error":         true,
+	"cause":         true,
+	"panic":         true,
+}
+
+// Config holds configuration for the structured logger
+type Config struct {
+	// MaskUserContent replaces user content fields with a length indicator
+	MaskUserContent bool
+	Level           slog.Level
+}
+
+// NewConfig creates a new logging configuration from environment variables
+func NewConfig() *Config {
+	cfg := &Config{Level: slog.LevelInfo}
+
+	if mask, err := strconv.ParseBool(os.Getenv("LOG_MASK_USER_CONTENT")); err == nil {
+		cfg.MaskUserContent = mask
+	}
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := cfg.Level.UnmarshalText([]byte(level)); err != nil {
+			fmt.Fprintf(os.Stderr, "Ignoring invalid LOG_LEVEL %q: %v\n", level, err)
+		}
+	}
+
+	return cfg
+}
+
+// NewLogger creates a JSON structured logger writing to w. Masking is applied
+// in the handler itself so every log site inherits it.
+func NewLogger(w io.Writer, cfg *Config) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.Level}
+	if cfg.MaskUserContent {
+		opts.ReplaceAttr = maskUserContent
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+// Init installs the structured logger as the process-wide default, which also
+// routes the standard library log package through it
+func Init() {
+	slog.SetDefault(NewLogger(os.Stdout, NewConfig()))
+}
+
+// maskUserContent replaces the value of user content attributes, at any
+// nesting depth, with "***" followed by the original length, and masks the
+// quoted strings in messages and errors
+func maskUserContent(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	if maskedKeys[a.Key] {
+		return slog.String(a.Key, Mask(a.Value.String()))
+	}
+	if quotingKeys[a.Key] {
+		return slog.String(a.Key, MaskQuoted(a.Value.String()))
+	}
+	return a
+}
+
+// Mask returns the masked representation of a user content value
+func Mask(value string) string {
+	return fmt.Sprintf("***(%d)", len([]rune(value)))
+}
+
+// MaskQuoted masks every Go-quoted string in text, the form %q gives the
+// values errors and log lines in this module quote, including IDs
+func MaskQuoted(text string) string {
+	var masked strings.Builder
+	for {
+		start := strings.IndexByte(text, '"')
+		if start < 0 {
+			masked.WriteString(text)
+			return masked.String()
+		}
+		masked.WriteString(text[:start])
+		quoted, err := strconv.QuotedPrefix(text[start:])
+		if err != nil {
+			masked.WriteString(text[start:])
+			return masked.String()
+		}
+		value, _ := strconv.Unquote(quoted)
+		masked.WriteString(Mask(value))
+		text = text[start+len(quoted):]
+	}
+}
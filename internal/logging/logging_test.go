@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// itemLike is a LogValuer shaped like models.Item
+type itemLike struct {
+	ID   string
+	Name string
+}
+
+func (i itemLike) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", i.ID),
+		slog.String("name", i.Name),
+	)
+}
+
+func logEntry(t *testing.T, mask bool) map[string]interface{} {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Config{MaskUserContent: mask, Level: slog.LevelInfo})
+	logger.Info("item created",
+		"id", "item-1",
+		"code", "ALREADY_EXISTS",
+		"description", "secret text",
+		"item", itemLike{ID: "item-1", Name: "Secret"},
+	)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode log entry %q: %v", buf.String(), err)
+	}
+	return entry
+}
+
+func TestNewLogger_MasksUserContent(t *testing.T) {
+	entry := logEntry(t, true)
+
+	if entry["description"] != "***(11)" {
+		t.Errorf("Expected masked description, got %v", entry["description"])
+	}
+	item := entry["item"].(map[string]interface{})
+	if item["name"] != "***(6)" {
+		t.Errorf("Expected masked nested name, got %v", item["name"])
+	}
+
+	if entry["id"] != "item-1" || item["id"] != "item-1" {
+		t.Errorf("Expected IDs to be kept, got %v and %v", entry["id"], item["id"])
+	}
+	if entry["code"] != "ALREADY_EXISTS" {
+		t.Errorf("Expected error code to be kept, got %v", entry["code"])
+	}
+}
+
+func TestNewLogger_FullOutputWhenMaskingDisabled(t *testing.T) {
+	entry := logEntry(t, false)
+
+	if entry["description"] != "secret text" {
+		t.Errorf("Expected full description, got %v", entry["description"])
+	}
+	item := entry["item"].(map[string]interface{})
+	if item["name"] != "Secret" {
+		t.Errorf("Expected full nested name, got %v", item["name"])
+	}
+}
+
+func TestNewLogger_MasksQuotedUserContent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, &Config{MaskUserContent: true, Level: slog.LevelInfo})
+
+	// The log package formats values into the message, and errors quote
+	// the names they report
+	slog.NewLogLogger(logger.Handler(), slog.LevelInfo).Printf("Dual write: secondary create failed: %v", errors.New(`item already exists: an item named "Secret" already exists`))
+	logger.Warn("API error", "cause", `an item named "Secret \"plan\"" already exists`, "error", errors.New(`bad "Secret"`))
+
+	if strings.Contains(buf.String(), "Secret") {
+		t.Fatalf("Expected quoted user content to be masked, got %s", buf.String())
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var first, second map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Failed to decode log entry %q: %v", lines[0], err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("Failed to decode log entry %q: %v", lines[1], err)
+	}
+	if want := "Dual write: secondary create failed: item already exists: an item named ***(6) already exists"; first["msg"] != want {
+		t.Errorf("Expected message %q, got %v", want, first["msg"])
+	}
+	if want := "an item named ***(13) already exists"; second["cause"] != want {
+		t.Errorf("Expected cause %q, got %v", want, second["cause"])
+	}
+	if second["error"] != "bad ***(6)" {
+		t.Errorf("Expected masked error, got %v", second["error"])
+	}
+}
+
+func TestMaskQuoted(t *testing.T) {
+	for text, want := range map[string]string{
+		"no quotes":            "no quotes",
+		`named "a" and "bc"`:   "named ***(1) and ***(2)",
+		`escaped "say \"hi\""`: "escaped ***(8)",
+		`unterminated "quote`:  `unterminated "quote`,
+		`unicode "héllo" kept`: "unicode ***(5) kept",
+	} {
+		if got := MaskQuoted(text); got != want {
+			t.Errorf("MaskQuoted(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"fis-playground/internal/handlers"
+)
+
+// AdminKeyHeader is the request header carrying the admin API key
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminKeyFromEnv returns the admin API key configured via ADMIN_API_KEY
+func AdminKeyFromEnv() string {
+	return os.Getenv("ADMIN_API_KEY")
+}
+
+// RequireAdminKey rejects requests that do not carry the configured admin key.
+// When no key is configured, admin routes are disabled entirely.
+func RequireAdminKey(adminKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if adminKey == "" {
+				handlers.WriteErrorResponse(w, r, &handlers.APIError{
+					Type:       handlers.ErrorTypeAuth,
+					Code:       handlers.CodeForbidden,
+					Message:    "Admin endpoints are disabled",
+					Details:    "No admin key is configured for this deployment",
+					StatusCode: http.StatusForbidden,
+				})
+				return
+			}
+
+			provided := r.Header.Get(AdminKeyHeader)
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(adminKey)) != 1 {
+				handlers.WriteErrorResponse(w, r, &handlers.APIError{
+					Type:       handlers.ErrorTypeAuth,
+					Code:       handlers.CodeUnauthorized,
+					Message:    "Admin key required",
+					Details:    "A valid " + AdminKeyHeader + " header is required",
+					StatusCode: http.StatusUnauthorized,
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(handlers.WithAdmin(r.Context())))
+		})
+	}
+}
+
+// IdentifyAdmin marks requests carrying the configured admin key as admin
+// requests without rejecting others, for routes where admins see more
+func IdentifyAdmin(adminKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := r.Header.Get(AdminKeyHeader)
+			if adminKey != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(adminKey)) == 1 {
+				r = r.WithContext(handlers.WithAdmin(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fis-playground/internal/handlers"
+)
+
+func TestRequireAdminKey(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name           string
+		configuredKey  string
+		providedKey    string
+		expectedStatus int
+	}{
+		{name: "Valid key", configuredKey: "secret", providedKey: "secret", expectedStatus: http.StatusNoContent},
+		{name: "Missing key", configuredKey: "secret", providedKey: "", expectedStatus: http.StatusUnauthorized},
+		{name: "Wrong key", configuredKey: "secret", providedKey: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "Admin disabled", configuredKey: "", providedKey: "", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/admin/import", nil)
+			if tt.providedKey != "" {
+				req.Header.Set(AdminKeyHeader, tt.providedKey)
+			}
+			w := httptest.NewRecorder()
+
+			RequireAdminKey(tt.configuredKey)(ok).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestIdentifyAdmin(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuredKey string
+		providedKey   string
+		expectAdmin   bool
+	}{
+		{name: "Valid key", configuredKey: "secret", providedKey: "secret", expectAdmin: true},
+		{name: "Wrong key", configuredKey: "secret", providedKey: "guess", expectAdmin: false},
+		{name: "No key", configuredKey: "secret", providedKey: "", expectAdmin: false},
+		{name: "Admin disabled", configuredKey: "", providedKey: "", expectAdmin: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var admin bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				admin = handlers.IsAdmin(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+			req := httptest.NewRequest("GET", "/items", nil)
+			if tt.providedKey != "" {
+				req.Header.Set(AdminKeyHeader, tt.providedKey)
+			}
+			w := httptest.NewRecorder()
+
+			IdentifyAdmin(tt.configuredKey)(next).ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected the request to pass through, got %d", w.Code)
+			}
+			if admin != tt.expectAdmin {
+				t.Errorf("Expected admin=%v, got %v", tt.expectAdmin, admin)
+			}
+		})
+	}
+}
+
+func TestIdentifyPrincipal(t *testing.T) {
+	var principal string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal = handlers.PrincipalFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	req.Header.Set(DefaultPrincipalHeader, "alice")
+	IdentifyPrincipal(DefaultPrincipalHeader)(next).ServeHTTP(httptest.NewRecorder(), req)
+	if principal != "alice" {
+		t.Errorf("Expected principal 'alice', got %q", principal)
+	}
+
+	IdentifyPrincipal(DefaultPrincipalHeader)(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items", nil))
+	if principal != "" {
+		t.Errorf("Expected an anonymous request, got principal %q", principal)
+	}
+}
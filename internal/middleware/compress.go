@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CompressionConfig controls gzip response compression.
+//
+// Compressed bodies are binary, so behind API Gateway REST APIs the proxy
+// adapter returns them base64-encoded and the API must list */* (or the
+// response content types) under binary media types to decode them.
+type CompressionConfig struct {
+	Enabled bool
+
+	// UserAgentAllowlist, when non-empty, limits compression to clients whose
+	// User-Agent contains one of the patterns (case-insensitive)
+	UserAgentAllowlist []string
+
+	// UserAgentDenylist disables compression for clients whose User-Agent
+	// contains one of the patterns (case-insensitive), even when they send
+	// Accept-Encoding: gzip. It takes precedence over the allowlist.
+	UserAgentDenylist []string
+
+	// Routes, when non-empty, limits compression to the named routes so
+	// small single-item responses skip the CPU cost. The router decides
+	// which routes carry which names.
+	Routes []string
+
+	// MinSize is the smallest body, in bytes, worth compressing. Smaller
+	// bodies are sent as they are, since gzip's framing can make them
+	// bigger. Zero compresses every body.
+	MinSize int
+}
+
+// DefaultGzipMinSize is the compression threshold when GZIP_MIN_SIZE is unset
+const DefaultGzipMinSize = 1024
+
+// CompressionConfigFromEnv reads GZIP_ENABLED, GZIP_USER_AGENT_ALLOWLIST,
+// GZIP_USER_AGENT_DENYLIST, GZIP_ROUTES and GZIP_MIN_SIZE; the lists are
+// comma-separated
+func CompressionConfigFromEnv() CompressionConfig {
+	enabled, _ := strconv.ParseBool(os.Getenv("GZIP_ENABLED"))
+	return CompressionConfig{
+		Enabled:            enabled,
+		UserAgentAllowlist: splitPatterns(os.Getenv("GZIP_USER_AGENT_ALLOWLIST")),
+		UserAgentDenylist:  splitPatterns(os.Getenv("GZIP_USER_AGENT_DENYLIST")),
+		Routes:             splitPatterns(os.Getenv("GZIP_ROUTES")),
+		MinSize:            envInt("GZIP_MIN_SIZE", DefaultGzipMinSize),
+	}
+}
+
+// Global reports whether every route is compressed
+func (c CompressionConfig) Global() bool {
+	return c.Enabled && len(c.Routes) == 0
+}
+
+// ForRoute returns the middleware for a route group: gzip when the route is
+// named in Routes, and a no-op otherwise, including when compression is
+// global and so already applied router-wide
+func (c CompressionConfig) ForRoute(name string) func(http.Handler) http.Handler {
+	if c.Enabled && slices.Contains(c.Routes, strings.ToLower(name)) {
+		return Gzip(c)
+	}
+	return func(next http.Handler) http.Handler { return next }
+}
+
+// allowsUserAgent checks the User-Agent against the allow and deny lists
+func (c CompressionConfig) allowsUserAgent(userAgent string) bool {
+	userAgent = strings.ToLower(userAgent)
+	for _, pattern := range c.UserAgentDenylist {
+		if strings.Contains(userAgent, pattern) {
+			return false
+		}
+	}
+	if len(c.UserAgentAllowlist) == 0 {
+		return true
+	}
+	for _, pattern := range c.UserAgentAllowlist {
+		if strings.Contains(userAgent, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// Gzip compresses responses for clients that accept gzip and are not
+// excluded by their User-Agent. Responses are buffered, which matches how
+// the Lambda proxy adapter returns them anyway.
+func Gzip(cfg CompressionConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if len(cfg.UserAgentAllowlist) > 0 || len(cfg.UserAgentDenylist) > 0 {
+				w.Header().Add("Vary", "User-Agent")
+			}
+
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) || !cfg.allowsUserAgent(r.UserAgent()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bw := &bufferedResponseWriter{ResponseWriter: w, minSize: cfg.MinSize}
+			next.ServeHTTP(bw, r)
+			bw.flush()
+		})
+	}
+}
+
+// bufferedResponseWriter holds the response until the handler returns so
+// it can be compressed as a whole, unless the handler streams it
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	minSize   int
+	streaming bool // the handler flushed, so the rest passes straight through
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.streaming {
+		return w.ResponseWriter.Write(p)
+	}
+	return w.body.Write(p)
+}
+
+// Flush is called by handlers that stream, such as the item event stream.
+// A stream can't be compressed as a whole, so what is buffered is sent
+// uncompressed and later writes pass straight through.
+func (w *bufferedResponseWriter) Flush() {
+	if !w.streaming {
+		w.streaming = true
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// flush writes the buffered response, compressed when the body reaches the
+// minimum size and isn't already encoded
+func (w *bufferedResponseWriter) flush() {
+	if w.streaming {
+		return
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	header := w.ResponseWriter.Header()
+	if w.body.Len() == 0 || w.body.Len() < w.minSize || header.Get("Content-Encoding") != "" {
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(w.body.Bytes())
+		return
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(w.body.Bytes())
+	gz.Close()
+
+	header.Set("Content-Encoding", "gzip")
+	header.Set("Content-Length", strconv.Itoa(compressed.Len()))
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(compressed.Bytes())
+}
+
+// acceptsGzip checks whether an Accept-Encoding header allows gzip
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// splitPatterns parses a comma-separated list into lowercase patterns
+func splitPatterns(value string) []string {
+	var patterns []string
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const compressBody = `{"success":true,"data":{"items":[]}}`
+
+func serveCompressed(cfg CompressionConfig, acceptEncoding, userAgent string) *httptest.ResponseRecorder {
+	handler := Gzip(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(compressBody))
+	}))
+
+	req := httptest.NewRequest("GET", "/items", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestGzip_CompressesWhenAccepted(t *testing.T) {
+	w := serveCompressed(CompressionConfig{Enabled: true}, "gzip, deflate", "Mozilla/5.0")
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected gzip Content-Encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected Content-Type to stay application/json, got %q", w.Header().Get("Content-Type"))
+	}
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Expected gzip body, got %v", err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("Failed to decompress body: %v", err)
+	}
+	if string(body) != compressBody {
+		t.Errorf("Expected %s, got %s", compressBody, body)
+	}
+}
+
+func TestGzip_UserAgentLists(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            CompressionConfig
+		acceptEncoding string
+		userAgent      string
+		expectGzip     bool
+	}{
+		{
+			name:           "Denylisted agent advertising gzip",
+			cfg:            CompressionConfig{UserAgentDenylist: []string{"legacyclient/1."}},
+			acceptEncoding: "gzip",
+			userAgent:      "LegacyClient/1.4 (Android)",
+			expectGzip:     false,
+		},
+		{
+			name:           "Agent not on denylist",
+			cfg:            CompressionConfig{UserAgentDenylist: []string{"legacyclient/1."}},
+			acceptEncoding: "gzip",
+			userAgent:      "LegacyClient/2.0",
+			expectGzip:     true,
+		},
+		{
+			name:           "Agent not on allowlist",
+			cfg:            CompressionConfig{UserAgentAllowlist: []string{"mobileapp"}},
+			acceptEncoding: "gzip",
+			userAgent:      "curl/8.0",
+			expectGzip:     false,
+		},
+		{
+			name:           "Denylist wins over allowlist",
+			cfg:            CompressionConfig{UserAgentAllowlist: []string{"mobileapp"}, UserAgentDenylist: []string{"mobileapp/0.9"}},
+			acceptEncoding: "gzip",
+			userAgent:      "MobileApp/0.9",
+			expectGzip:     false,
+		},
+		{
+			name:           "Gzip not accepted",
+			cfg:            CompressionConfig{},
+			acceptEncoding: "br",
+			userAgent:      "Mozilla/5.0",
+			expectGzip:     false,
+		},
+		{
+			name:           "Gzip refused with q=0",
+			cfg:            CompressionConfig{},
+			acceptEncoding: "gzip;q=0, identity",
+			userAgent:      "Mozilla/5.0",
+			expectGzip:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveCompressed(tt.cfg, tt.acceptEncoding, tt.userAgent)
+
+			gzipped := w.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.expectGzip {
+				t.Fatalf("Expected gzip=%v, got Content-Encoding %q", tt.expectGzip, w.Header().Get("Content-Encoding"))
+			}
+			if !gzipped && w.Body.String() != compressBody {
+				t.Errorf("Expected uncompressed body %s, got %s", compressBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGzip_FlushedResponsesPassThrough(t *testing.T) {
+	handler := Gzip(CompressionConfig{Enabled: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: created\n\n"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Expected the writer to support flushing, got %v", err)
+		}
+		w.Write([]byte("event: deleted\n\n"))
+	}))
+
+	req := httptest.NewRequest("GET", "/items/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected a streamed response to stay uncompressed, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if !w.Flushed {
+		t.Error("Expected the flush to reach the underlying writer")
+	}
+	if got := w.Body.String(); got != "event: created\n\nevent: deleted\n\n" {
+		t.Errorf("Expected both events, got %q", got)
+	}
+}
+
+func TestCompressionConfig_ForRoute(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        CompressionConfig
+		route      string
+		expectGzip bool
+	}{
+		{"listed route", CompressionConfig{Enabled: true, Routes: []string{"list"}}, "list", true},
+		{"unlisted route", CompressionConfig{Enabled: true, Routes: []string{"list"}}, "facets", false},
+		{"disabled", CompressionConfig{Routes: []string{"list"}}, "list", false},
+		{"global compression is not applied twice", CompressionConfig{Enabled: true}, "list", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.cfg.ForRoute(tt.route)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(compressBody))
+			}))
+			req := httptest.NewRequest("GET", "/items", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if gzipped := w.Header().Get("Content-Encoding") == "gzip"; gzipped != tt.expectGzip {
+				t.Errorf("Expected gzip %v, got Content-Encoding %q", tt.expectGzip, w.Header().Get("Content-Encoding"))
+			}
+		})
+	}
+}
+
+func TestGzip_MinSize(t *testing.T) {
+	below := serveCompressed(CompressionConfig{Enabled: true, MinSize: len(compressBody) + 1}, "gzip", "Mozilla/5.0")
+	if below.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected a body under MinSize to be sent as is, got Content-Encoding %q", below.Header().Get("Content-Encoding"))
+	}
+	if below.Body.String() != compressBody {
+		t.Errorf("Expected %s, got %s", compressBody, below.Body.String())
+	}
+
+	at := serveCompressed(CompressionConfig{Enabled: true, MinSize: len(compressBody)}, "gzip", "Mozilla/5.0")
+	if at.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("Expected a body of MinSize to be compressed, got Content-Encoding %q", at.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestCompressionConfigFromEnv_MinSize(t *testing.T) {
+	if got := CompressionConfigFromEnv().MinSize; got != DefaultGzipMinSize {
+		t.Errorf("Expected the default threshold %d, got %d", DefaultGzipMinSize, got)
+	}
+	t.Setenv("GZIP_MIN_SIZE", "0")
+	if got := CompressionConfigFromEnv().MinSize; got != 0 {
+		t.Errorf("Expected GZIP_MIN_SIZE=0 to compress every body, got %d", got)
+	}
+}
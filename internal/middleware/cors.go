@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/cors"
+)
+
+// CORSConfig controls the CORS headers browsers need to call the API from
+// other origins
+type CORSConfig struct {
+	// AllowedOrigins are the origins, such as https://app.example.com, that
+	// may call the API. Origins are matched exactly; "*" allows every origin,
+	// but then requests can't carry credentials.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// Default CORS settings, used for each list that isn't configured
+var (
+	DefaultCORSOrigins = []string{"*"}
+	DefaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	DefaultCORSHeaders = []string{"Accept", "Authorization", "Content-Type", "Idempotency-Key", "If-Match", "If-None-Match", "X-API-Key", "X-CSRF-Token", "X-Requested-With"}
+)
+
+// corsExposedHeaders are the response headers browsers let scripts read
+var corsExposedHeaders = []string{"ETag", "Idempotent-Replayed", "Link", "Location", "Retry-After"}
+
+// CORSConfigFromEnv reads CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS and
+// CORS_ALLOWED_HEADERS, all comma-separated, falling back to the defaults
+// for any that are unset
+func CORSConfigFromEnv() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: envList("CORS_ALLOWED_ORIGINS", DefaultCORSOrigins),
+		AllowedMethods: envList("CORS_ALLOWED_METHODS", DefaultCORSMethods),
+		AllowedHeaders: envList("CORS_ALLOWED_HEADERS", DefaultCORSHeaders),
+	}
+}
+
+// AllowsAnyOrigin reports whether every origin is allowed
+func (c CORSConfig) AllowsAnyOrigin() bool {
+	return slices.Contains(c.AllowedOrigins, "*")
+}
+
+// CORS answers preflight OPTIONS requests and adds CORS headers to requests
+// from allowed origins. Requests from other origins get no CORS headers, so
+// browsers refuse to hand their responses to scripts. Credentials are only
+// allowed with an origin allowlist, since browsers reject them with "*".
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	options := cors.Options{
+		AllowedMethods: cfg.AllowedMethods,
+		AllowedHeaders: cfg.AllowedHeaders,
+		ExposedHeaders: corsExposedHeaders,
+		MaxAge:         300,
+	}
+	if cfg.AllowsAnyOrigin() {
+		options.AllowedOrigins = []string{"*"}
+	} else {
+		// The cors package treats '*' within an origin as a wildcard and
+		// ignores case, so the allowlist is matched here instead
+		options.AllowOriginFunc = func(r *http.Request, origin string) bool {
+			return slices.Contains(cfg.AllowedOrigins, origin)
+		}
+		options.AllowCredentials = true
+	}
+	return cors.Handler(options)
+}
+
+// envList reads a comma-separated list from the environment, returning def
+// when it is unset or empty
+func envList(name string, def []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return def
+	}
+	return values
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// preflight sends a CORS preflight for PUT /items/1 from origin through
+// the CORS middleware
+func preflight(cfg CORSConfig, origin string) *httptest.ResponseRecorder {
+	handler := CORS(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest("OPTIONS", "/items/1", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSConfigFromEnv(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://app.example.com , https://admin.example.com,")
+	t.Setenv("CORS_ALLOWED_METHODS", "GET,POST")
+	t.Setenv("CORS_ALLOWED_HEADERS", "")
+
+	cfg := CORSConfigFromEnv()
+	if want := []string{"https://app.example.com", "https://admin.example.com"}; !slices.Equal(cfg.AllowedOrigins, want) {
+		t.Errorf("Expected origins %v, got %v", want, cfg.AllowedOrigins)
+	}
+	if want := []string{"GET", "POST"}; !slices.Equal(cfg.AllowedMethods, want) {
+		t.Errorf("Expected methods %v, got %v", want, cfg.AllowedMethods)
+	}
+	if !slices.Equal(cfg.AllowedHeaders, DefaultCORSHeaders) {
+		t.Errorf("Expected the default headers, got %v", cfg.AllowedHeaders)
+	}
+	if cfg.AllowsAnyOrigin() {
+		t.Error("Expected an allowlist not to allow any origin")
+	}
+}
+
+func TestCORS_AllowlistedOrigin(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: DefaultCORSMethods,
+		AllowedHeaders: DefaultCORSHeaders,
+	}
+	w := preflight(cfg, "https://app.example.com")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	for header, want := range map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "PUT",
+		"Access-Control-Allow-Headers":     "Content-Type",
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("Expected %s %q, got %q", header, want, got)
+		}
+	}
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: DefaultCORSMethods,
+		AllowedHeaders: DefaultCORSHeaders,
+	}
+	// Matching is exact, so neither another origin nor a near miss passes
+	for _, origin := range []string{"https://evil.example.com", "https://app.example.com.evil.example", "HTTPS://APP.EXAMPLE.COM"} {
+		w := preflight(cfg, origin)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Expected no Access-Control-Allow-Origin for %s, got %q", origin, got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+			t.Errorf("Expected no Access-Control-Allow-Credentials for %s, got %q", origin, got)
+		}
+	}
+}
+
+func TestCORS_WildcardHasNoCredentials(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins: DefaultCORSOrigins,
+		AllowedMethods: DefaultCORSMethods,
+		AllowedHeaders: DefaultCORSHeaders,
+	}
+	w := preflight(cfg, "https://anywhere.example.com")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected Access-Control-Allow-Origin *, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected no credentials with a wildcard origin, got %q", got)
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"fis-playground/internal/handlers"
+)
+
+// CreateRateConfig limits how fast each principal can create items,
+// independently of the table-wide write limit
+type CreateRateConfig struct {
+	// Rate is the sustained creates per second allowed per principal; zero
+	// disables the limit
+	Rate float64
+	// Burst is how many creates a principal can make at once after being
+	// idle
+	Burst int
+}
+
+// CreateRateConfigFromEnv reads CREATE_RATE_PER_PRINCIPAL, in creates per
+// second, and CREATE_BURST_PER_PRINCIPAL, which defaults to one second's
+// worth of creates and at least one
+func CreateRateConfigFromEnv() CreateRateConfig {
+	var cfg CreateRateConfig
+	cfg.Rate = envRate("CREATE_RATE_PER_PRINCIPAL")
+	cfg.Burst = envInt("CREATE_BURST_PER_PRINCIPAL", defaultBurst(cfg.Rate))
+	return cfg
+}
+
+// LimitCreatesPerPrincipal gives each request a create budget, which the
+// handlers charge once per item they are about to create, so a batch costs
+// as much as creating its items one by one. Once the budget is spent,
+// creates are rejected with 429 telling the caller when to retry. Callers
+// are told apart by an authenticated identity, the verified token's
+// subject or the API key API Gateway validated, or else by client IP
+// address. The principal header is not used: a new value per request would
+// get a new budget each time. It must run after AuthenticateJWT.
+func LimitCreatesPerPrincipal(cfg CreateRateConfig) func(http.Handler) http.Handler {
+	if cfg.Rate <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	limiter := newKeyedLimiter(cfg.Rate, max(cfg.Burst, 1))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := clientKey(r)
+			if user, ok := handlers.UserFromContext(r.Context()); ok {
+				key = "user:" + user
+			}
+			budget := func(n int) time.Duration {
+				return limiter.takeN(key, n, time.Now())
+			}
+			next.ServeHTTP(w, r.WithContext(handlers.WithCreateBudget(r.Context(), budget)))
+		})
+	}
+}
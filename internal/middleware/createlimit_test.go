@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"fis-playground/internal/handlers"
+	"fis-playground/internal/models"
+)
+
+// chargingHandler charges the number of creates in the request's count
+// query parameter, defaulting to one, like the create handlers do
+var chargingHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	n := 1
+	if count := r.URL.Query().Get("count"); count != "" {
+		n, _ = strconv.Atoi(count)
+	}
+	if apiErr := handlers.ChargeCreates(r.Context(), n); apiErr != nil {
+		handlers.WriteErrorResponse(w, r, apiErr)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+})
+
+func TestLimitCreatesPerPrincipal(t *testing.T) {
+	// A rate this low never refills during the test
+	handler := LimitCreatesPerPrincipal(CreateRateConfig{Rate: 0.01, Burst: 2})(chargingHandler)
+
+	// create sends a create as the given authenticated user, or
+	// anonymously when user is empty
+	create := func(user, remoteAddr string, count int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", fmt.Sprintf("/items?count=%d", count), nil)
+		req.RemoteAddr = remoteAddr
+		if user != "" {
+			req = req.WithContext(handlers.WithUser(req.Context(), user))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := range 2 {
+		if w := create("alice", "192.0.2.1:1234", 1); w.Code != http.StatusCreated {
+			t.Fatalf("Expected create %d within the burst to succeed, got %d", i+1, w.Code)
+		}
+	}
+
+	w := create("alice", "192.0.2.2:1234", 1)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d once the budget is spent, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "100" {
+		t.Errorf("Expected Retry-After 100, got %q", got)
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Error == nil {
+		t.Fatalf("Expected an error response, got %s", w.Body.String())
+	}
+	if response.Error.Code != string(handlers.CodeRateLimitExceeded) {
+		t.Errorf("Expected code %s, got %s", handlers.CodeRateLimitExceeded, response.Error.Code)
+	}
+
+	if w := create("bob", "192.0.2.1:1234", 1); w.Code != http.StatusCreated {
+		t.Errorf("Expected another user to be unaffected, got %d", w.Code)
+	}
+
+	// Without a user, callers are limited by address
+	for i := range 2 {
+		if w := create("", "192.0.2.3:1234", 1); w.Code != http.StatusCreated {
+			t.Fatalf("Expected create %d within the burst to succeed, got %d", i+1, w.Code)
+		}
+	}
+	if w := create("", "192.0.2.3:1234", 1); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected requests without a user to be limited by address, got %d", w.Code)
+	}
+}
+
+func TestLimitCreatesPerPrincipal_IgnoresPrincipalHeader(t *testing.T) {
+	limit := LimitCreatesPerPrincipal(CreateRateConfig{Rate: 0.01, Burst: 2})
+	handler := IdentifyPrincipal(DefaultPrincipalHeader)(limit(chargingHandler))
+
+	// Claiming a new principal on each request doesn't get a new budget
+	for i := range 3 {
+		req := httptest.NewRequest("POST", "/items", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set(DefaultPrincipalHeader, fmt.Sprintf("principal-%d", i))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		want := http.StatusCreated
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if w.Code != want {
+			t.Errorf("Expected create %d to return %d, got %d", i+1, want, w.Code)
+		}
+	}
+}
+
+func TestLimitCreatesPerPrincipal_ChargesPerItem(t *testing.T) {
+	handler := LimitCreatesPerPrincipal(CreateRateConfig{Rate: 0.01, Burst: 3})(chargingHandler)
+
+	create := func(count int) int {
+		req := httptest.NewRequest("POST", fmt.Sprintf("/items/batch?count=%d", count), nil)
+		req = req.WithContext(handlers.WithUser(req.Context(), "alice"))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := create(2); code != http.StatusCreated {
+		t.Fatalf("Expected a batch within the burst to succeed, got %d", code)
+	}
+	if code := create(2); code != http.StatusTooManyRequests {
+		t.Errorf("Expected a batch of 2 with 1 create left to be limited, got %d", code)
+	}
+	if code := create(1); code != http.StatusCreated {
+		t.Errorf("Expected the refused batch not to be charged, got %d", code)
+	}
+}
+
+func TestLimitCreatesPerPrincipal_Disabled(t *testing.T) {
+	handler := LimitCreatesPerPrincipal(CreateRateConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for range 5 {
+		req := httptest.NewRequest("POST", "/items", nil)
+		req = req.WithContext(handlers.WithPrincipal(req.Context(), "alice"))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected no limit with a zero rate, got %d", w.Code)
+		}
+	}
+}
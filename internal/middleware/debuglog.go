@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"bytes"
+	"hash/fnv"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"fis-playground/internal/handlers"
+	"fis-playground/internal/logging"
+)
+
+// DefaultDebugLogMaxBodyBytes caps how much of each body is logged
+const DefaultDebugLogMaxBodyBytes = 4096
+
+// DebugLogConfig controls verbose, body-level logging of sampled requests.
+// Logged bodies contain user content, so keep the sample small.
+type DebugLogConfig struct {
+	// SamplePercent is the share of requests logged, from 0 to 100
+	SamplePercent float64
+	MaxBodyBytes  int
+
+	// MaxRequestBytes caps how much of a request body is buffered, and
+	// should match the handlers' MaxBodyBytes; zero means the handlers'
+	// default. Longer bodies are passed on to fail with 413 as usual.
+	MaxRequestBytes int64
+
+	// MaskUserContent logs only the sizes of the bodies, never their
+	// content, matching LOG_MASK_USER_CONTENT
+	MaskUserContent bool
+}
+
+// DebugLogConfigFromEnv reads DEBUG_LOG_SAMPLE_PERCENT and
+// DEBUG_LOG_MAX_BODY_BYTES, along with MAX_BODY_BYTES and
+// LOG_MASK_USER_CONTENT. Sampling is off unless a percentage is set.
+func DebugLogConfigFromEnv() DebugLogConfig {
+	cfg := DebugLogConfig{
+		MaxBodyBytes:    envInt("DEBUG_LOG_MAX_BODY_BYTES", DefaultDebugLogMaxBodyBytes),
+		MaxRequestBytes: handlers.NewHandlerConfig().MaxBodyBytes,
+		MaskUserContent: logging.NewConfig().MaskUserContent,
+	}
+	if value := os.Getenv("DEBUG_LOG_SAMPLE_PERCENT"); value != "" {
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil || percent < 0 || percent > 100 {
+			log.Printf("Ignoring invalid DEBUG_LOG_SAMPLE_PERCENT %q, debug logging disabled", value)
+		} else {
+			cfg.SamplePercent = percent
+		}
+	}
+	return cfg
+}
+
+// SampleRequest decides whether a request is sampled by hashing its request
+// ID, so the same request is always sampled the same way
+func SampleRequest(requestID string, percent float64) bool {
+	if percent <= 0 || requestID == "" {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(requestID))
+	return float64(h.Sum32()%10000) < percent*100
+}
+
+// DebugLogging logs the request and response bodies of sampled requests, or
+// just their sizes when user content is masked. It must run after the
+// RequestID middleware.
+func DebugLogging(logger *slog.Logger, cfg DebugLogConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := chimiddleware.GetReqID(r.Context())
+			if !SampleRequest(requestID, cfg.SamplePercent) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var requestBody []byte
+			if r.Body != nil {
+				limit := cfg.MaxRequestBytes
+				if limit <= 0 {
+					limit = handlers.DefaultMaxBodyBytes
+				}
+				var err error
+				requestBody, err = io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+				// The handler gets the same body, and the same error once
+				// it reads past the limit
+				replay := io.Reader(bytes.NewReader(requestBody))
+				if err != nil {
+					replay = io.MultiReader(replay, &errReader{err: err})
+				}
+				r.Body = io.NopCloser(replay)
+			}
+
+			start := time.Now()
+			rw := &teeResponseWriter{ResponseWriter: w, max: cfg.MaxBodyBytes}
+			if cfg.MaskUserContent {
+				rw.max = -1
+			}
+			next.ServeHTTP(rw, r)
+			if rw.status == 0 {
+				rw.status = http.StatusOK
+			}
+
+			attrs := []any{
+				"request_id", requestID,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"query", r.URL.RawQuery,
+				"status", rw.status,
+				"duration_ms", time.Since(start).Milliseconds(),
+			}
+			if cfg.MaskUserContent {
+				attrs = append(attrs,
+					"request_bytes", len(requestBody),
+					"response_bytes", rw.written,
+				)
+			} else {
+				attrs = append(attrs,
+					"request_body", truncateBody(requestBody, cfg.MaxBodyBytes),
+					"response_body", truncateBody(rw.body.Bytes(), cfg.MaxBodyBytes),
+				)
+			}
+			logger.Info("HTTP exchange", attrs...)
+		})
+	}
+}
+
+// teeResponseWriter copies up to max bytes of the response body, or none
+// when max is negative, and counts the bytes written
+type teeResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	body    bytes.Buffer
+	max     int
+	written int
+}
+
+func (w *teeResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *teeResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if remaining := w.max + 1 - w.body.Len(); remaining > 0 {
+		w.body.Write(p[:min(len(p), remaining)])
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.written += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streaming responses can still be flushed
+func (w *teeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// truncateBody renders a body for logging, marking it when cut off
+func truncateBody(body []byte, max int) string {
+	if len(body) > max {
+		return string(body[:max]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// errReader fails every read with err
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
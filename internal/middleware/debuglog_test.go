@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+func TestSampleRequest_RateHonored(t *testing.T) {
+	const requests = 20000
+	for _, percent := range []float64{1, 10, 50} {
+		sampled := 0
+		for i := 0; i < requests; i++ {
+			if SampleRequest(fmt.Sprintf("host/abc123-%06d", i), percent) {
+				sampled++
+			}
+		}
+
+		got := float64(sampled) / requests * 100
+		if math.Abs(got-percent) > percent*0.2+0.2 {
+			t.Errorf("Expected about %.0f%% sampled, got %.2f%%", percent, got)
+		}
+	}
+}
+
+func TestSampleRequest_Deterministic(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("req-%d", i)
+		if SampleRequest(id, 30) != SampleRequest(id, 30) {
+			t.Fatalf("Expected request %s to be sampled consistently", id)
+		}
+	}
+	if SampleRequest("req-1", 0) || !SampleRequest("req-1", 100) {
+		t.Error("Expected 0% to sample nothing and 100% to sample everything")
+	}
+}
+
+func TestDebugLogging_LogsBodies(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	handler := chimiddleware.RequestID(DebugLogging(logger, DebugLogConfig{SamplePercent: 100, MaxBodyBytes: 16})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"echo":` + string(body) + `}`))
+		})))
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"x"}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Body.String() != `{"echo":{"name":"x"}}` {
+		t.Errorf("Expected the handler to still see the request body, got %s", w.Body.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON log entry, got %q: %v", logs.String(), err)
+	}
+	if entry["request_body"] != `{"name":"x"}` {
+		t.Errorf("Expected request body to be logged, got %v", entry["request_body"])
+	}
+	if entry["response_body"] != `{"echo":{"name":...(truncated)` {
+		t.Errorf("Expected truncated response body, got %v", entry["response_body"])
+	}
+	if entry["status"] != float64(http.StatusCreated) || entry["request_id"] == "" {
+		t.Errorf("Expected status and request ID, got %v", entry)
+	}
+}
+
+func TestDebugLogging_SkipsUnsampled(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	handler := chimiddleware.RequestID(DebugLogging(logger, DebugLogConfig{})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items", nil))
+
+	if logs.Len() != 0 {
+		t.Errorf("Expected nothing logged, got %s", logs.String())
+	}
+}
+
+func TestDebugLogging_MasksBodies(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	handler := chimiddleware.RequestID(DebugLogging(logger, DebugLogConfig{SamplePercent: 100, MaxBodyBytes: 1024, MaskUserContent: true})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.Write(body)
+		})))
+
+	body := `{"name":"secret name"}`
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/items", strings.NewReader(body)))
+
+	if strings.Contains(logs.String(), "secret name") {
+		t.Fatalf("Expected no user content logged, got %s", logs.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON log entry, got %q: %v", logs.String(), err)
+	}
+	if entry["request_bytes"] != float64(len(body)) || entry["response_bytes"] != float64(len(body)) {
+		t.Errorf("Expected the body sizes to be logged, got %v", entry)
+	}
+}
+
+func TestDebugLogging_CapsRequestBody(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	var readErr error
+	handler := chimiddleware.RequestID(DebugLogging(logger, DebugLogConfig{SamplePercent: 100, MaxBodyBytes: 16, MaxRequestBytes: 32})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, readErr = io.ReadAll(r.Body)
+		})))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/items", strings.NewReader(strings.Repeat("x", 1<<20))))
+
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(readErr, &maxBytesErr) {
+		t.Errorf("Expected the handler to see the body limit, got %v", readErr)
+	}
+}
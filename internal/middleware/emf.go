@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"fis-playground/internal/emf"
+)
+
+// EmitMetrics makes emitter the request's business metrics emitter, which
+// handlers reach through emf.FromContext. With a nil emitter, as when
+// EMF_ENABLED is off, handlers are returned unchanged and record nothing.
+func EmitMetrics(emitter emf.Emitter) func(http.Handler) http.Handler {
+	if emitter == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(emf.WithEmitter(r.Context(), emitter)))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fis-playground/internal/emf"
+)
+
+func TestEmitMetrics_AddsEmitterToContext(t *testing.T) {
+	var out bytes.Buffer
+	handler := EmitMetrics(emf.NewLogger(&out, emf.DefaultNamespace, emf.DefaultServiceName))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		emf.FromContext(r.Context()).Count(emf.ItemsCreated, 1, nil)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/items", nil))
+
+	if !strings.Contains(out.String(), `"ItemsCreated":1`) {
+		t.Errorf("Expected the handler's metric to be logged, got %q", out.String())
+	}
+}
+
+func TestEmitMetrics_NilEmitter(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := EmitMetrics(nil)(next)
+
+	if _, ok := handler.(http.HandlerFunc); !ok {
+		t.Fatalf("Expected the handler to be returned unchanged, got %T", handler)
+	}
+}
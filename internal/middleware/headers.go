@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+
+	"fis-playground/internal/handlers"
+)
+
+// Default request header limits
+const (
+	DefaultMaxHeaderCount = 100
+	DefaultMaxHeaderBytes = 32 * 1024
+)
+
+// adapterHeaders are the headers the API Gateway proxy adapter injects to
+// carry the request context and stage variables. They are generated by the
+// adapter, not sent by the client, so they don't count towards the limits.
+// Only these exact names are exempt; other headers sharing their prefix
+// count like any other.
+var adapterHeaders = map[string]bool{
+	http.CanonicalHeaderKey(core.APIGwContextHeader):   true,
+	http.CanonicalHeaderKey(core.APIGwStageVarsHeader): true,
+}
+
+// HeaderLimits bounds the number and total size of request headers
+type HeaderLimits struct {
+	MaxCount int // Maximum number of header values (0 = unlimited)
+	MaxBytes int // Maximum total size of header names and values (0 = unlimited)
+}
+
+// HeaderLimitsFromEnv returns the limits configured via MAX_HEADER_COUNT and
+// MAX_HEADER_BYTES, falling back to the defaults when unset or invalid
+func HeaderLimitsFromEnv() HeaderLimits {
+	return HeaderLimits{
+		MaxCount: envInt("MAX_HEADER_COUNT", DefaultMaxHeaderCount),
+		MaxBytes: envInt("MAX_HEADER_BYTES", DefaultMaxHeaderBytes),
+	}
+}
+
+// LimitRequestHeaders rejects requests whose headers exceed the limits with
+// 431 Request Header Fields Too Large. Each value of a repeated header counts
+// separately, matching how the proxy adapter expands multi-value headers.
+func LimitRequestHeaders(limits HeaderLimits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			count, size := 0, 0
+			for name, values := range r.Header {
+				if adapterHeaders[name] {
+					continue
+				}
+				for _, value := range values {
+					count++
+					size += len(name) + len(value)
+				}
+			}
+
+			var details string
+			switch {
+			case limits.MaxCount > 0 && count > limits.MaxCount:
+				details = fmt.Sprintf("Request has %d headers, the maximum is %d", count, limits.MaxCount)
+			case limits.MaxBytes > 0 && size > limits.MaxBytes:
+				details = fmt.Sprintf("Request headers total %d bytes, the maximum is %d", size, limits.MaxBytes)
+			}
+			if details != "" {
+				handlers.WriteErrorResponse(w, r, &handlers.APIError{
+					Type:       handlers.ErrorTypeValidation,
+					Code:       handlers.CodeHeadersTooLarge,
+					Message:    "Request header fields too large",
+					Details:    details,
+					StatusCode: http.StatusRequestHeaderFieldsTooLarge,
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// envInt reads a non-negative integer environment variable, falling back to
+// the default when unset or invalid
+func envInt(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Ignoring invalid %s %q, using default %d", name, value, def)
+		return def
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitRequestHeaders(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	limits := HeaderLimits{MaxCount: 10, MaxBytes: 512}
+
+	tests := []struct {
+		name           string
+		headers        func(h http.Header)
+		expectedStatus int
+	}{
+		{
+			name:           "Within limits",
+			headers:        func(h http.Header) { h.Set("Accept", "application/json") },
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Too many headers",
+			headers: func(h http.Header) {
+				for i := 0; i < 11; i++ {
+					h.Set(fmt.Sprintf("X-Custom-%d", i), "v")
+				}
+			},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			name: "Repeated header values count separately",
+			headers: func(h http.Header) {
+				for i := 0; i < 11; i++ {
+					h.Add("X-Repeated", "v")
+				}
+			},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			name:           "Headers too large",
+			headers:        func(h http.Header) { h.Set("X-Large", strings.Repeat("a", 600)) },
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			name: "Adapter headers are not counted",
+			headers: func(h http.Header) {
+				h.Set("X-GoLambdaProxy-ApiGw-Context", strings.Repeat("c", 2048))
+				h.Set("X-GoLambdaProxy-ApiGw-StageVars", "{}")
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Other headers with the adapter prefix are counted",
+			headers: func(h http.Header) {
+				h.Set("X-GoLambdaProxy-Smuggled", strings.Repeat("s", 600))
+			},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/items", nil)
+			tt.headers(req.Header)
+			w := httptest.NewRecorder()
+
+			LimitRequestHeaders(limits)(ok).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus == http.StatusRequestHeaderFieldsTooLarge && !strings.Contains(w.Body.String(), "HEADERS_TOO_LARGE") {
+				t.Errorf("Expected HEADERS_TOO_LARGE error code, got %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"fis-playground/internal/handlers"
+)
+
+// DefaultJWKSRefresh is how long fetched signing keys are trusted when
+// JWT_JWKS_REFRESH is not set
+const DefaultJWKSRefresh = time.Hour
+
+// jwtClockSkew is the leeway allowed on a token's exp and nbf
+const jwtClockSkew = 30 * time.Second
+
+// jwksFetchTimeout bounds a JWKS request made with the default client
+const jwksFetchTimeout = 5 * time.Second
+
+// jwksMinRefetch is the least time between fetches for an unknown key ID,
+// so tokens naming made-up keys can't make every request fetch the JWKS
+const jwksMinRefetch = 30 * time.Second
+
+// JWTConfig controls bearer token validation
+type JWTConfig struct {
+	// JWKSURL serves the issuer's signing keys; empty disables validation
+	JWKSURL string
+
+	// Issuer, when set, must equal the token's iss claim
+	Issuer string
+
+	// Audiences, when non-empty, must include the token's aud claim or,
+	// for Cognito access tokens, which have no aud, its client_id claim
+	Audiences []string
+
+	// RefreshInterval is how long fetched keys are used before they are
+	// fetched again
+	RefreshInterval time.Duration
+}
+
+// JWTConfigFromEnv reads JWT_JWKS_URL, JWT_ISSUER, JWT_AUDIENCE (comma-
+// separated) and JWT_JWKS_REFRESH
+func JWTConfigFromEnv() JWTConfig {
+	cfg := JWTConfig{
+		JWKSURL:         os.Getenv("JWT_JWKS_URL"),
+		Issuer:          os.Getenv("JWT_ISSUER"),
+		RefreshInterval: DefaultJWKSRefresh,
+	}
+	for _, audience := range strings.Split(os.Getenv("JWT_AUDIENCE"), ",") {
+		if audience = strings.TrimSpace(audience); audience != "" {
+			cfg.Audiences = append(cfg.Audiences, audience)
+		}
+	}
+	if value := os.Getenv("JWT_JWKS_REFRESH"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			cfg.RefreshInterval = d
+		}
+	}
+	return cfg
+}
+
+// errJWKSUnavailable is returned when no signing keys could be fetched
+var errJWKSUnavailable = errors.New("signing keys are unavailable")
+
+// AuthenticateJWT validates the Bearer token in the Authorization header
+// and records its subject as the request's user and principal. Tokens must
+// be RS256-signed by a key in the JWKS and pass the issuer, audience and
+// expiry checks; other tokens are rejected with 401. Requests without a
+// token pass through anonymously. A nil client fetches keys with a short
+// timeout.
+func AuthenticateJWT(cfg JWTConfig, client *http.Client) func(http.Handler) http.Handler {
+	if client == nil {
+		client = &http.Client{Timeout: jwksFetchTimeout}
+	}
+	keys := &jwksCache{url: cfg.JWKSURL, client: client, refresh: cfg.RefreshInterval}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization := r.Header.Get("Authorization")
+			if authorization == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, _ := strings.Cut(authorization, " ")
+			if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+				writeInvalidToken(w, r, "The Authorization header must be a Bearer token")
+				return
+			}
+
+			subject, err := verifyJWT(r.Context(), strings.TrimSpace(token), cfg, keys, time.Now())
+			if errors.Is(err, errJWKSUnavailable) {
+				handlers.WriteErrorResponse(w, r, handlers.NewSystemError(handlers.CodeServiceUnavailable, "Token keys unavailable", err))
+				return
+			}
+			if err != nil {
+				writeInvalidToken(w, r, err.Error())
+				return
+			}
+
+			ctx := handlers.WithUser(r.Context(), subject)
+			ctx = handlers.WithPrincipal(ctx, subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// writeInvalidToken rejects a request whose bearer token failed validation
+func writeInvalidToken(w http.ResponseWriter, r *http.Request, details string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	handlers.WriteErrorResponse(w, r, &handlers.APIError{
+		Type:       handlers.ErrorTypeAuth,
+		Code:       handlers.CodeUnauthorized,
+		Message:    "Invalid bearer token",
+		Details:    details,
+		StatusCode: http.StatusUnauthorized,
+	})
+}
+
+// jwtHeader is the part of a token's header that validation uses
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims are the claims validation checks
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ClientID  string          `json:"client_id"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+}
+
+// audiences returns the aud claim, which may be one string or a list
+func (c *jwtClaims) audiences() []string {
+	var one string
+	if json.Unmarshal(c.Audience, &one) == nil {
+		return []string{one}
+	}
+	var many []string
+	json.Unmarshal(c.Audience, &many)
+	return many
+}
+
+// verifyJWT checks token's signature and claims, returning its subject
+func verifyJWT(ctx context.Context, token string, cfg JWTConfig, keys *jwksCache, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("the token is malformed")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", errors.New("the token header is malformed")
+	}
+	// Only RS256 is accepted, so "none" or HMAC tokens can't pass
+	if header.Alg != "RS256" {
+		return "", fmt.Errorf("the token algorithm %q is not allowed", header.Alg)
+	}
+
+	key, err := keys.key(ctx, header.Kid, now)
+	if err != nil {
+		return "", err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("the token signature is malformed")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return "", errors.New("the token signature is invalid")
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", errors.New("the token claims are malformed")
+	}
+	if claims.ExpiresAt == nil || now.After(time.Unix(int64(*claims.ExpiresAt), 0).Add(jwtClockSkew)) {
+		return "", errors.New("the token has expired")
+	}
+	if claims.NotBefore != nil && now.Add(jwtClockSkew).Before(time.Unix(int64(*claims.NotBefore), 0)) {
+		return "", errors.New("the token is not valid yet")
+	}
+	if cfg.Issuer != "" && claims.Issuer != cfg.Issuer {
+		return "", errors.New("the token issuer is not trusted")
+	}
+	if len(cfg.Audiences) > 0 {
+		audiences := append(claims.audiences(), claims.ClientID)
+		if !slices.ContainsFunc(audiences, func(audience string) bool {
+			return audience != "" && slices.Contains(cfg.Audiences, audience)
+		}) {
+			return "", errors.New("the token audience is not accepted")
+		}
+	}
+	if claims.Subject == "" {
+		return "", errors.New("the token has no subject")
+	}
+	return claims.Subject, nil
+}
+
+// decodeSegment decodes a base64url JSON token segment into v
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jwksCache holds the signing keys fetched from a JWKS URL, fetching them
+// again once they are older than the refresh interval or when a token
+// names a key it doesn't have, as happens after the issuer rotates keys.
+// Keys are fetched outside the lock, one fetch at a time.
+type jwksCache struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	// fetching is closed when the fetch in progress, if any, finishes
+	fetching chan struct{}
+}
+
+// key returns the public key with the given ID. While the keys are being
+// fetched, a token signed by a key already held is checked against it
+// rather than waiting for the fetch.
+func (c *jwksCache) key(ctx context.Context, kid string, now time.Time) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.needsFetch(kid, now) {
+		if c.fetching == nil {
+			if err := c.refetch(ctx, now); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if _, known := c.keys[kid]; known {
+			break
+		}
+
+		fetching := c.fetching
+		c.mu.Unlock()
+		select {
+		case <-fetching:
+			c.mu.Lock()
+		case <-ctx.Done():
+			c.mu.Lock()
+			return nil, fmt.Errorf("%w: %v", errJWKSUnavailable, ctx.Err())
+		}
+	}
+
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, errors.New("the token's signing key is unknown")
+	}
+	return key, nil
+}
+
+// needsFetch reports whether the keys are stale, or don't include kid and
+// weren't fetched too recently to try again. c.mu must be held.
+func (c *jwksCache) needsFetch(kid string, now time.Time) bool {
+	stale := c.keys == nil || now.Sub(c.fetchedAt) >= c.refresh
+	_, known := c.keys[kid]
+	return stale || (!known && now.Sub(c.fetchedAt) >= jwksMinRefetch)
+}
+
+// refetch fetches the keys with c.mu released, so requests aren't held up
+// by a slow fetch, and stores them. c.mu must be held.
+func (c *jwksCache) refetch(ctx context.Context, now time.Time) error {
+	done := make(chan struct{})
+	c.fetching = done
+	c.mu.Unlock()
+
+	keys, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	c.fetching = nil
+	close(done)
+	if err != nil && c.keys == nil {
+		return fmt.Errorf("%w: %v", errJWKSUnavailable, err)
+	}
+	// A failed refresh keeps using the keys already fetched
+	if err == nil {
+		c.keys = keys
+	}
+	c.fetchedAt = now
+	return nil
+}
+
+// fetch downloads the JWKS and parses its RSA signing keys
+func (c *jwksCache) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS request returned %d", resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,281 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fis-playground/internal/handlers"
+)
+
+// testIssuer signs tokens and serves its key as a JWKS
+type testIssuer struct {
+	key     *rsa.PrivateKey
+	kid     atomic.Value // the key ID the JWKS serves the key under
+	fetches atomic.Int32
+	server  *httptest.Server
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	issuer := &testIssuer{key: key}
+	issuer.kid.Store("key-1")
+	issuer.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kid": issuer.kid.Load().(string),
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+// sign returns an RS256 token with the given claims
+func (i *testIssuer) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": i.kid.Load().(string)})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (i *testIssuer) config() JWTConfig {
+	return JWTConfig{
+		JWKSURL:         i.server.URL,
+		Issuer:          "https://issuer.example.com",
+		Audiences:       []string{"client-1"},
+		RefreshInterval: time.Hour,
+	}
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub": "user-1",
+		"iss": "https://issuer.example.com",
+		"aud": "client-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	issuer := newTestIssuer(t)
+	middleware := AuthenticateJWT(issuer.config(), nil)
+
+	var user, principal string
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ = handlers.UserFromContext(r.Context())
+		principal = handlers.PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(authorization string) *httptest.ResponseRecorder {
+		user, principal = "", ""
+		req := httptest.NewRequest("GET", "/items", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	token := issuer.sign(t, validClaims())
+	if w := serve("Bearer " + token); w.Code != http.StatusOK {
+		t.Fatalf("Expected a valid token to pass, got %d: %s", w.Code, w.Body.String())
+	}
+	if user != "user-1" || principal != "user-1" {
+		t.Errorf("Expected user and principal user-1, got %q and %q", user, principal)
+	}
+
+	// Tampering with the claims breaks the signature
+	parts := strings.Split(token, ".")
+	claims := validClaims()
+	claims["sub"] = "admin"
+	payload, _ := json.Marshal(claims)
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+	w := serve("Bearer " + tampered)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected a tampered token to be rejected, got %d", w.Code)
+	}
+	if user != "" {
+		t.Errorf("Expected no user for a tampered token, got %q", user)
+	}
+	var response struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Error.Code != string(handlers.CodeUnauthorized) {
+		t.Errorf("Expected UNAUTHORIZED, got %+v (%v)", response, err)
+	}
+	if w.Header().Get("WWW-Authenticate") == "" {
+		t.Error("Expected a WWW-Authenticate header")
+	}
+
+	// Requests without a token stay anonymous
+	if w := serve(""); w.Code != http.StatusOK || user != "" {
+		t.Errorf("Expected an anonymous request to pass without a user, got %d and %q", w.Code, user)
+	}
+
+	if fetches := issuer.fetches.Load(); fetches != 1 {
+		t.Errorf("Expected the JWKS to be fetched once and cached, got %d fetches", fetches)
+	}
+}
+
+func TestAuthenticateJWT_RejectsInvalidClaims(t *testing.T) {
+	issuer := newTestIssuer(t)
+	handler := AuthenticateJWT(issuer.config(), nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		change func(claims map[string]any)
+	}{
+		{"expired", func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"no expiry", func(c map[string]any) { delete(c, "exp") }},
+		{"not yet valid", func(c map[string]any) { c["nbf"] = time.Now().Add(time.Hour).Unix() }},
+		{"wrong issuer", func(c map[string]any) { c["iss"] = "https://evil.example.com" }},
+		{"wrong audience", func(c map[string]any) { c["aud"] = "client-2" }},
+		{"no subject", func(c map[string]any) { delete(c, "sub") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.change(claims)
+			req := httptest.NewRequest("GET", "/items", nil)
+			req.Header.Set("Authorization", "Bearer "+issuer.sign(t, claims))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("Expected 401, got %d", w.Code)
+			}
+		})
+	}
+
+	// Cognito access tokens carry client_id instead of aud
+	claims := validClaims()
+	delete(claims, "aud")
+	claims["client_id"] = "client-1"
+	req := httptest.NewRequest("GET", "/items", nil)
+	req.Header.Set("Authorization", "Bearer "+issuer.sign(t, claims))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected client_id to satisfy the audience check, got %d", w.Code)
+	}
+
+	// An unsigned token is rejected whatever it claims
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"key-1"}`))
+	payload, _ := json.Marshal(validClaims())
+	req = httptest.NewRequest("GET", "/items", nil)
+	req.Header.Set("Authorization", "Bearer "+header+"."+base64.RawURLEncoding.EncodeToString(payload)+".")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected an alg none token to be rejected, got %d", w.Code)
+	}
+}
+
+func TestJWKSCache_Refresh(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cache := &jwksCache{url: issuer.server.URL, client: http.DefaultClient, refresh: time.Minute}
+	now := time.Now()
+
+	for range 3 {
+		if _, err := cache.key(context.Background(), "key-1", now); err != nil {
+			t.Fatalf("Failed to get key: %v", err)
+		}
+	}
+	if fetches := issuer.fetches.Load(); fetches != 1 {
+		t.Errorf("Expected one fetch within the refresh interval, got %d", fetches)
+	}
+
+	if _, err := cache.key(context.Background(), "key-1", now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+	if fetches := issuer.fetches.Load(); fetches != 2 {
+		t.Errorf("Expected the keys to be refetched after the interval, got %d fetches", fetches)
+	}
+
+	// A rotated key is picked up without waiting for the interval
+	issuer.kid.Store("key-2")
+	if _, err := cache.key(context.Background(), "key-2", now.Add(3*time.Minute)); err != nil {
+		t.Errorf("Expected the rotated key to be fetched, got %v", err)
+	}
+}
+
+func TestJWKSCache_FetchesOutsideLock(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cache := &jwksCache{url: issuer.server.URL, client: http.DefaultClient, refresh: time.Minute}
+	now := time.Now()
+	if _, err := cache.key(context.Background(), "key-1", now); err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+
+	// A refresh that hangs doesn't hold up tokens signed by a held key
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(hanging.Close)
+	cache.url = hanging.URL
+	refreshed := make(chan error)
+	go func() {
+		_, err := cache.key(context.Background(), "key-1", now.Add(2*time.Minute))
+		refreshed <- err
+	}()
+	for {
+		cache.mu.Lock()
+		fetching := cache.fetching != nil
+		cache.mu.Unlock()
+		if fetching {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := cache.key(context.Background(), "key-1", now.Add(2*time.Minute))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected the held key during the refresh, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the held key to be used without waiting for the refresh")
+	}
+
+	close(release)
+	if err := <-refreshed; err != nil {
+		t.Errorf("Expected a failed refresh to keep the held keys, got %v", err)
+	}
+}
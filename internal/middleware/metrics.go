@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the latency
+// histogram's buckets, the same as the Prometheus client's defaults
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// unmatchedRoute labels requests that matched no route, so unknown paths
+// can't add label values
+const unmatchedRoute = "unmatched"
+
+// MetricsEnabledFromEnv reports whether METRICS_ENABLED turns on the
+// /metrics endpoint, falling back to def when unset or invalid
+func MetricsEnabledFromEnv(def bool) bool {
+	value := os.Getenv("METRICS_ENABLED")
+	if value == "" {
+		return def
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Ignoring invalid METRICS_ENABLED %q, using default %t", value, def)
+		return def
+	}
+	return enabled
+}
+
+// Metrics counts requests and records their latencies by method and route,
+// and serves them in the Prometheus text format. Routes are chi's route
+// patterns, such as /items/{id}, rather than raw paths, so item IDs don't
+// each become a label value.
+type Metrics struct {
+	mu        sync.Mutex
+	requests  map[requestLabels]uint64
+	latencies map[routeLabels]*latencyHistogram
+}
+
+// routeLabels identify a route's latency histogram
+type routeLabels struct {
+	method string
+	route  string
+}
+
+// requestLabels identify a request counter
+type requestLabels struct {
+	routeLabels
+	status int
+}
+
+// latencyHistogram counts latencies per bucket of latencyBuckets, plus their
+// sum and total count
+type latencyHistogram struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// NewMetrics creates an empty set of request metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests:  map[requestLabels]uint64{},
+		latencies: map[routeLabels]*latencyHistogram{},
+	}
+}
+
+// Record records the count, status code and latency of every request. It
+// should run outside Recover, so panics are counted as the 500 they become.
+func (m *Metrics) Record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.observe(routeLabels{method: metricsMethod(r), route: matchedRoute(r)}, status, time.Since(start))
+	})
+}
+
+// metricsMethod returns the request's method, or "other" for methods the
+// API doesn't know, which clients could otherwise invent without limit
+func metricsMethod(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return r.Method
+	}
+	return "other"
+}
+
+// matchedRoute returns the route pattern the request matched, which chi
+// only knows once routing is done
+func matchedRoute(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return unmatchedRoute
+	}
+	pattern := rctx.RoutePattern()
+	if pattern == "" {
+		return unmatchedRoute
+	}
+	if len(pattern) > 1 {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return pattern
+}
+
+// observe adds one request to the metrics
+func (m *Metrics) observe(labels routeLabels, status int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestLabels{routeLabels: labels, status: status}]++
+
+	histogram, ok := m.latencies[labels]
+	if !ok {
+		histogram = &latencyHistogram{buckets: make([]uint64, len(latencyBuckets))}
+		m.latencies[labels] = histogram
+	}
+	seconds := latency.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			histogram.buckets[i]++
+		}
+	}
+	histogram.sum += seconds
+	histogram.count++
+}
+
+// ServeHTTP handles GET /metrics requests, writing the metrics in the
+// Prometheus text exposition format, sorted so scrapes are stable
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "# HELP http_requests_total Requests served, by method, route and status code.")
+	fmt.Fprintln(bw, "# TYPE http_requests_total counter")
+	requests := make([]requestLabels, 0, len(m.requests))
+	for labels := range m.requests {
+		requests = append(requests, labels)
+	}
+	slices.SortFunc(requests, func(a, b requestLabels) int {
+		if c := compareRouteLabels(a.routeLabels, b.routeLabels); c != 0 {
+			return c
+		}
+		return a.status - b.status
+	})
+	for _, labels := range requests {
+		fmt.Fprintf(bw, "http_requests_total{method=%q,route=%q,status=\"%d\"} %d\n", labels.method, labels.route, labels.status, m.requests[labels])
+	}
+
+	fmt.Fprintln(bw, "# HELP http_request_duration_seconds Request latency, by method and route.")
+	fmt.Fprintln(bw, "# TYPE http_request_duration_seconds histogram")
+	routes := make([]routeLabels, 0, len(m.latencies))
+	for labels := range m.latencies {
+		routes = append(routes, labels)
+	}
+	slices.SortFunc(routes, compareRouteLabels)
+	for _, labels := range routes {
+		histogram := m.latencies[labels]
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(bw, "http_request_duration_seconds_bucket{method=%q,route=%q,le=\"%s\"} %d\n", labels.method, labels.route, formatValue(bound), histogram.buckets[i])
+		}
+		fmt.Fprintf(bw, "http_request_duration_seconds_bucket{method=%q,route=%q,le=\"+Inf\"} %d\n", labels.method, labels.route, histogram.count)
+		fmt.Fprintf(bw, "http_request_duration_seconds_sum{method=%q,route=%q} %s\n", labels.method, labels.route, formatValue(histogram.sum))
+		fmt.Fprintf(bw, "http_request_duration_seconds_count{method=%q,route=%q} %d\n", labels.method, labels.route, histogram.count)
+	}
+
+	if err := bw.Flush(); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
+
+// compareRouteLabels orders labels by route, then method
+func compareRouteLabels(a, b routeLabels) int {
+	if c := strings.Compare(a.route, b.route); c != 0 {
+		return c
+	}
+	return strings.Compare(a.method, b.method)
+}
+
+// formatValue formats a float the way Prometheus clients do
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestMetrics_Record(t *testing.T) {
+	metrics := NewMetrics()
+	r := chi.NewRouter()
+	r.Use(metrics.Record)
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/items/a", nil),
+		httptest.NewRequest("GET", "/items/b", nil),
+		httptest.NewRequest("GET", "/nowhere/c", nil),
+		httptest.NewRequest("BREW", "/items/a", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, line := range []string{
+		`http_requests_total{method="GET",route="/items/{id}",status="204"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_requests_total{method="other",route="unmatched",status="405"} 1`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected metrics to contain %s, got:\n%s", line, body)
+		}
+	}
+}
+
+func TestMetrics_LatencyBuckets(t *testing.T) {
+	metrics := NewMetrics()
+	labels := routeLabels{method: "GET", route: "/items"}
+	metrics.observe(labels, http.StatusOK, 20*time.Millisecond)
+	metrics.observe(labels, http.StatusOK, 3*time.Second)
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, line := range []string{
+		`http_request_duration_seconds_bucket{method="GET",route="/items",le="0.01"} 0`,
+		`http_request_duration_seconds_bucket{method="GET",route="/items",le="0.025"} 1`,
+		`http_request_duration_seconds_bucket{method="GET",route="/items",le="2.5"} 1`,
+		`http_request_duration_seconds_bucket{method="GET",route="/items",le="5"} 2`,
+		`http_request_duration_seconds_bucket{method="GET",route="/items",le="+Inf"} 2`,
+		`http_request_duration_seconds_sum{method="GET",route="/items"} 3.02`,
+		`http_request_duration_seconds_count{method="GET",route="/items"} 2`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected metrics to contain %s, got:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+
+	"fis-playground/internal/handlers"
+)
+
+// DefaultPrincipalHeader is the request header carrying the caller's
+// principal when PRINCIPAL_HEADER is not set
+const DefaultPrincipalHeader = "X-Principal-ID"
+
+// PrincipalHeaderFromEnv returns the principal header configured via
+// PRINCIPAL_HEADER
+func PrincipalHeaderFromEnv() string {
+	if header := os.Getenv("PRINCIPAL_HEADER"); header != "" {
+		return header
+	}
+	return DefaultPrincipalHeader
+}
+
+// IdentifyPrincipal records the principal named in the given header on the
+// request context. The API does not authenticate principals itself: the
+// header must be set by a trusted authorizer or proxy that strips any
+// value sent by the client.
+func IdentifyPrincipal(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if principal := r.Header.Get(header); principal != "" {
+				r = r.WithContext(handlers.WithPrincipal(r.Context(), principal))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
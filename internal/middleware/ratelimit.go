@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+
+	"fis-playground/internal/handlers"
+)
+
+// APIKeyHeader is the request header carrying a client's API key, as sent
+// to API Gateway usage plans. Its value is only trusted once API Gateway
+// has validated it.
+const APIKeyHeader = "X-API-Key"
+
+// ClientRateConfig limits how fast each client can make requests, so a
+// single client can't use up the table's capacity
+type ClientRateConfig struct {
+	// Rate is the sustained requests per second allowed per client; zero
+	// disables the limit
+	Rate float64
+	// Burst is how many requests a client can make at once after being
+	// idle
+	Burst int
+}
+
+// ClientRateConfigFromEnv reads RATE_PER_CLIENT, in requests per second,
+// and BURST_PER_CLIENT, which defaults to one second's worth of requests
+// and at least one
+func ClientRateConfigFromEnv() ClientRateConfig {
+	var cfg ClientRateConfig
+	cfg.Rate = envRate("RATE_PER_CLIENT")
+	cfg.Burst = envInt("BURST_PER_CLIENT", defaultBurst(cfg.Rate))
+	return cfg
+}
+
+// LimitRequestsPerClient rejects requests with 429 once their client has
+// used up its budget, telling it when to retry. Clients are told apart by
+// the API key API Gateway validated for them, or by their IP address.
+func LimitRequestsPerClient(cfg ClientRateConfig) func(http.Handler) http.Handler {
+	if cfg.Rate <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	limiter := newKeyedLimiter(cfg.Rate, max(cfg.Burst, 1))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if wait := limiter.take(clientKey(r), time.Now()); wait > 0 {
+				handlers.WriteErrorResponse(w, r, &handlers.APIError{
+					Type:       handlers.ErrorTypeRate,
+					Code:       handlers.CodeRateLimitExceeded,
+					Message:    "Rate limit exceeded",
+					Details:    "Too many requests from this client; retry after the Retry-After delay",
+					StatusCode: http.StatusTooManyRequests,
+					RetryAfter: int(math.Ceil(wait.Seconds())),
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey identifies the client making the request. An X-API-Key the
+// client sent is not enough: a new value per request would get a new
+// budget each time, so only a key API Gateway authenticated is used. Keys
+// and addresses are prefixed so an API key can't share a bucket with an
+// address.
+func clientKey(r *http.Request) string {
+	if key := authenticatedAPIKey(r); key != "" {
+		return "key:" + key
+	}
+	return "ip:" + clientIP(r)
+}
+
+// authenticatedAPIKey returns the ID of the API key API Gateway validated
+// for the request, or "" when it didn't, as for methods without a usage
+// plan or outside Lambda
+func authenticatedAPIKey(r *http.Request) string {
+	gateway, ok := core.GetAPIGatewayContextFromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	if gateway.Identity.APIKeyID != "" {
+		return gateway.Identity.APIKeyID
+	}
+	return gateway.Identity.APIKey
+}
+
+// clientIP returns the address the request came from, without its port.
+// Behind API Gateway it is the caller's source IP.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return host
+}
+
+// envRate reads a rate environment variable in operations per second,
+// returning zero, which disables the limit, when it is unset or invalid
+func envRate(name string) float64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		log.Printf("Ignoring invalid %s %q, limit disabled", name, value)
+		return 0
+	}
+	return rate
+}
+
+// defaultBurst is one second's worth of operations at rate, and at least one
+func defaultBurst(rate float64) int {
+	return int(math.Max(1, math.Ceil(rate)))
+}
+
+// keyedLimiter keeps a token bucket per key, such as a principal or a
+// client. Unlike the write limiter's bucket it never waits: a request either
+// takes a token or is told how long until one is available.
+type keyedLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*keyedBucket
+	lastSweep time.Time
+}
+
+// keyedBucket is one key's remaining tokens as of last
+type keyedBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiterSweepInterval is how often buckets that have refilled completely,
+// which behave exactly like a fresh bucket, are dropped, so keys that stop
+// sending requests don't hold memory in a long-lived process
+const limiterSweepInterval = time.Minute
+
+// maxIdleBuckets is how many buckets are kept before full ones are dropped
+// without waiting for the next sweep
+const maxIdleBuckets = 10000
+
+func newKeyedLimiter(rate float64, burst int) *keyedLimiter {
+	return &keyedLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   map[string]*keyedBucket{},
+		lastSweep: time.Now(),
+	}
+}
+
+// take spends one of the key's tokens, returning zero, or returns how long
+// until a token is available without spending anything
+func (l *keyedLimiter) take(key string, now time.Time) time.Duration {
+	return l.takeN(key, 1, now)
+}
+
+// takeN spends n of the key's tokens, returning zero, or returns how long
+// until they are available without spending anything. Spending more than
+// the burst only needs a full bucket, and leaves the key in debt until the
+// rest has refilled.
+func (l *keyedLimiter) takeN(key string, n int, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= limiterSweepInterval {
+		l.dropFull(now)
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.dropFull(now)
+		}
+		bucket = &keyedBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+	need := min(float64(n), l.burst)
+	if bucket.tokens < need {
+		return time.Duration((need - bucket.tokens) / l.rate * float64(time.Second))
+	}
+	bucket.tokens -= float64(n)
+	return 0
+}
+
+// dropFull removes the buckets that have refilled completely
+func (l *keyedLimiter) dropFull(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+
+	"fis-playground/internal/handlers"
+	"fis-playground/internal/models"
+)
+
+func TestLimitRequestsPerClient(t *testing.T) {
+	// A rate this low never refills during the test
+	handler := LimitRequestsPerClient(ClientRateConfig{Rate: 0.01, Burst: 2})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(apiKey, remoteAddr string) *httptest.ResponseRecorder {
+		req := gatewayRequest(t, apiKey, remoteAddr)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := range 2 {
+		if w := send("key-1", "192.0.2.1:1234"); w.Code != http.StatusOK {
+			t.Fatalf("Expected request %d within the burst to succeed, got %d", i+1, w.Code)
+		}
+	}
+
+	w := send("key-1", "192.0.2.2:1234")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d once the key's budget is spent, got %d", http.StatusTooManyRequests, w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "100" {
+		t.Errorf("Expected Retry-After 100, got %q", got)
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Error == nil {
+		t.Fatalf("Expected an error response, got %s", w.Body.String())
+	}
+	if response.Error.Code != string(handlers.CodeRateLimitExceeded) {
+		t.Errorf("Expected code %s, got %s", handlers.CodeRateLimitExceeded, response.Error.Code)
+	}
+
+	if w := send("key-2", "192.0.2.1:1234"); w.Code != http.StatusOK {
+		t.Errorf("Expected another API key to be unaffected, got %d", w.Code)
+	}
+
+	// Without a key, clients are limited by address, whatever their port
+	for i, port := range []string{"1001", "1002"} {
+		if w := send("", "192.0.2.3:"+port); w.Code != http.StatusOK {
+			t.Fatalf("Expected request %d within the burst to succeed, got %d", i+1, w.Code)
+		}
+	}
+	if w := send("", "192.0.2.3:9"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected the address to be limited, got %d", w.Code)
+	}
+	if w := send("", "192.0.2.4:1"); w.Code != http.StatusOK {
+		t.Errorf("Expected another address to be unaffected, got %d", w.Code)
+	}
+}
+
+// gatewayRequest builds a request as the Lambda adapter does for an API
+// Gateway event from sourceIP, authenticated with the API key apiKeyID
+// unless it is empty
+func gatewayRequest(t *testing.T, apiKeyID, sourceIP string) *http.Request {
+	t.Helper()
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/items",
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Identity: events.APIGatewayRequestIdentity{SourceIP: sourceIP, APIKeyID: apiKeyID},
+		},
+	}
+	req, err := (&core.RequestAccessor{}).EventToRequestWithContext(context.Background(), event)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	return req
+}
+
+func TestLimitRequestsPerClient_IgnoresUnauthenticatedAPIKeys(t *testing.T) {
+	handler := LimitRequestsPerClient(ClientRateConfig{Rate: 0.01, Burst: 2})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// A client sending a new key each time still shares its address's budget
+	var codes []int
+	for i := range 3 {
+		req := httptest.NewRequest("GET", "/items", nil)
+		req.RemoteAddr = "192.0.2.5:1234"
+		req.Header.Set(APIKeyHeader, fmt.Sprintf("made-up-%d", i))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+	if codes[2] != http.StatusTooManyRequests {
+		t.Errorf("Expected the third request from the address to be limited, got %v", codes)
+	}
+}
+
+func TestLimitRequestsPerClient_Disabled(t *testing.T) {
+	handler := LimitRequestsPerClient(ClientRateConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for range 5 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/items", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected no limit with a zero rate, got %d", w.Code)
+		}
+	}
+}
+
+func TestClientRateConfigFromEnv(t *testing.T) {
+	t.Setenv("RATE_PER_CLIENT", "2.5")
+	if cfg := ClientRateConfigFromEnv(); cfg.Rate != 2.5 || cfg.Burst != 3 {
+		t.Errorf("Expected rate 2.5 with a default burst of 3, got %+v", cfg)
+	}
+
+	t.Setenv("BURST_PER_CLIENT", "10")
+	if cfg := ClientRateConfigFromEnv(); cfg.Burst != 10 {
+		t.Errorf("Expected burst 10, got %d", cfg.Burst)
+	}
+
+	t.Setenv("RATE_PER_CLIENT", "fast")
+	if cfg := ClientRateConfigFromEnv(); cfg.Rate != 0 {
+		t.Errorf("Expected an invalid rate to disable the limit, got %v", cfg.Rate)
+	}
+}
+
+func TestKeyedLimiter_Refills(t *testing.T) {
+	limiter := newKeyedLimiter(2, 1)
+	start := time.Now()
+
+	if wait := limiter.take("alice", start); wait != 0 {
+		t.Fatalf("Expected the first create to pass, got wait %v", wait)
+	}
+	if wait := limiter.take("alice", start); wait != 500*time.Millisecond {
+		t.Errorf("Expected to wait 500ms for the next token, got %v", wait)
+	}
+	if wait := limiter.take("alice", start.Add(500*time.Millisecond)); wait != 0 {
+		t.Errorf("Expected a token after refilling, got wait %v", wait)
+	}
+}
+
+func TestKeyedLimiter_TakeNBeyondBurst(t *testing.T) {
+	limiter := newKeyedLimiter(1, 2)
+	start := time.Now()
+
+	// A batch larger than the burst needs a full bucket and leaves a debt
+	if wait := limiter.takeN("alice", 5, start); wait != 0 {
+		t.Fatalf("Expected a full bucket to allow the batch, got wait %v", wait)
+	}
+	if wait := limiter.takeN("alice", 1, start); wait != 4*time.Second {
+		t.Errorf("Expected to wait 4s for the debt to be repaid, got %v", wait)
+	}
+}
+
+func TestKeyedLimiter_SweepsIdleBuckets(t *testing.T) {
+	limiter := newKeyedLimiter(1, 1)
+	start := time.Now()
+
+	limiter.take("idle", start)
+	limiter.take("busy", start.Add(limiterSweepInterval-time.Millisecond))
+	if len(limiter.buckets) != 2 {
+		t.Fatalf("Expected two buckets, got %d", len(limiter.buckets))
+	}
+
+	// The sweep drops the refilled bucket but keeps the one still in debt
+	limiter.take("busy", start.Add(limiterSweepInterval))
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Error("Expected the idle bucket to be swept")
+	}
+	if _, ok := limiter.buckets["busy"]; !ok {
+		t.Error("Expected the busy bucket to be kept")
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"fis-playground/internal/handlers"
+)
+
+// Panic kinds reported in the panic_kind log field
+const (
+	PanicKindNilMapWrite     = "nil_map_write"
+	PanicKindNilPointer      = "nil_pointer"
+	PanicKindTypeAssertion   = "type_assertion"
+	PanicKindIndexOutOfRange = "index_out_of_range"
+	PanicKindRuntime         = "runtime_error"
+	PanicKindError           = "error"
+	PanicKindValue           = "value"
+)
+
+// Recover recovers panics in later handlers, logs them with the route and a
+// stable panic_fingerprint, and responds with a 500 error.
+//
+// The fingerprint hashes the panic kind, the route pattern and the function
+// that panicked. It leaves out the panic message and line numbers, which
+// vary between occurrences and deploys, so repeats of the same bug group
+// together in log-based alerts.
+func Recover(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				kind := classifyPanic(rec)
+				route := routePattern(r)
+				location := panicLocation()
+				logger.Error("Recovered from panic",
+					"panic_kind", kind,
+					"panic_fingerprint", panicFingerprint(kind, route, location),
+					"panic", fmt.Sprint(rec),
+					"panic_location", location,
+					"request_id", chimiddleware.GetReqID(r.Context()),
+					"method", r.Method,
+					"route", route,
+					"stack", string(debug.Stack()),
+				)
+
+				handlers.WriteInternalErrorResponse(w, r, fmt.Errorf("panic: %v", rec))
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// classifyPanic maps a recovered value to one of the panic kinds
+func classifyPanic(rec interface{}) string {
+	var typeAssertion *runtime.TypeAssertionError
+	var runtimeErr runtime.Error
+	err, isErr := rec.(error)
+
+	switch {
+	case isErr && errors.As(err, &typeAssertion):
+		return PanicKindTypeAssertion
+	case isErr && errors.As(err, &runtimeErr):
+		message := runtimeErr.Error()
+		switch {
+		case strings.Contains(message, "assignment to entry in nil map"):
+			return PanicKindNilMapWrite
+		case strings.Contains(message, "nil pointer dereference"):
+			return PanicKindNilPointer
+		case strings.Contains(message, "index out of range"), strings.Contains(message, "slice bounds out of range"):
+			return PanicKindIndexOutOfRange
+		}
+		return PanicKindRuntime
+	case isErr:
+		return PanicKindError
+	}
+	return PanicKindValue
+}
+
+// routePattern returns the matched route pattern, falling back to the path
+// when the panic happened before routing
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return r.URL.Path
+}
+
+// panicLocation returns the function that panicked: the first frame below
+// runtime.gopanic outside the runtime package. It must be called from the
+// deferred recovery function.
+func panicLocation() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+
+	panicking := false
+	for {
+		frame, more := frames.Next()
+		if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			return frame.Function
+		}
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// panicFingerprint returns a short stable hash identifying a panic
+func panicFingerprint(kind, route, location string) string {
+	sum := sha256.Sum256([]byte(kind + "|" + route + "|" + location))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// assertedValue is a package variable so the assertion can't be optimized away
+var assertedValue interface{} = "not a number"
+
+func TestRecover_TypeAssertionFingerprint(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	r := chi.NewRouter()
+	r.Use(Recover(logger))
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_ = assertedValue.(int)
+	})
+
+	var fingerprints []string
+	for _, id := range []string{"item-1", "item-2"} {
+		logs.Reset()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/items/"+id, nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+		}
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected a JSON log entry, got %q: %v", logs.String(), err)
+		}
+		if entry["panic_kind"] != PanicKindTypeAssertion {
+			t.Errorf("Expected panic_kind %q, got %v", PanicKindTypeAssertion, entry["panic_kind"])
+		}
+		if entry["route"] != "/items/{id}" {
+			t.Errorf("Expected route pattern '/items/{id}', got %v", entry["route"])
+		}
+		fingerprint, _ := entry["panic_fingerprint"].(string)
+		if fingerprint == "" {
+			t.Fatalf("Expected a panic_fingerprint field, got %v", entry)
+		}
+		fingerprints = append(fingerprints, fingerprint)
+	}
+
+	if fingerprints[0] != fingerprints[1] {
+		t.Errorf("Expected the same crash on different IDs to share a fingerprint, got %v", fingerprints)
+	}
+}
+
+func TestRecover_FingerprintDistinguishesKinds(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	r := chi.NewRouter()
+	r.Use(Recover(logger))
+	r.Get("/assert", func(w http.ResponseWriter, r *http.Request) {
+		_ = assertedValue.(int)
+	})
+	r.Get("/map", func(w http.ResponseWriter, r *http.Request) {
+		var counts map[string]int
+		counts["x"]++
+	})
+
+	entries := map[string]map[string]interface{}{}
+	for _, path := range []string{"/assert", "/map"} {
+		logs.Reset()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected a JSON log entry, got %q: %v", logs.String(), err)
+		}
+		entries[path] = entry
+	}
+
+	if entries["/map"]["panic_kind"] != PanicKindNilMapWrite {
+		t.Errorf("Expected panic_kind %q, got %v", PanicKindNilMapWrite, entries["/map"]["panic_kind"])
+	}
+	if entries["/assert"]["panic_fingerprint"] == entries["/map"]["panic_fingerprint"] {
+		t.Error("Expected different crashes to have different fingerprints")
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"fis-playground/internal/logging"
+)
+
+// RequestLogger stores a logger stamped with the request's ID in the
+// request context, for handlers to fetch with logging.FromContext, and logs
+// one line per completed request. It must run after chi's RequestID
+// middleware.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestLogger := logger.With("request_id", chimiddleware.GetReqID(r.Context()))
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(logging.WithLogger(r.Context(), requestLogger)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			requestLogger.Info("HTTP request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"latency_ms", time.Since(start).Milliseconds(),
+			)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"fis-playground/internal/handlers"
+)
+
+// logEntries decodes one JSON log entry per line
+func logEntries(t *testing.T, logs *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected a JSON log entry, got %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestLogger_StampsRequestIDOnErrorAndAccessLogs(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID)
+	r.Use(RequestLogger(logger))
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		handlers.WriteErrorResponse(w, r, handlers.NewNotFoundError("Item", chi.URLParam(r, "id")))
+	})
+
+	req := httptest.NewRequest("GET", "/items/item-1", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	entries := logEntries(t, &logs)
+	if len(entries) != 2 {
+		t.Fatalf("Expected an error entry and an access entry, got %d: %s", len(entries), logs.String())
+	}
+
+	errorEntry, accessEntry := entries[0], entries[1]
+	for _, entry := range entries {
+		if entry["request_id"] != "req-123" {
+			t.Errorf("Expected request_id req-123, got %v in %v", entry["request_id"], entry)
+		}
+		if entry["method"] != "GET" || entry["path"] != "/items/item-1" {
+			t.Errorf("Expected method and path, got %v", entry)
+		}
+		if entry["status"] != float64(http.StatusNotFound) {
+			t.Errorf("Expected status 404, got %v in %v", entry["status"], entry)
+		}
+	}
+
+	if errorEntry["msg"] != "API error" || errorEntry["level"] != "WARN" {
+		t.Errorf("Expected a WARN 'API error' entry, got %v", errorEntry)
+	}
+	if errorEntry["code"] != string(handlers.CodeNotFound) {
+		t.Errorf("Expected code %s, got %v", handlers.CodeNotFound, errorEntry["code"])
+	}
+
+	if accessEntry["msg"] != "HTTP request" {
+		t.Errorf("Expected an 'HTTP request' entry, got %v", accessEntry)
+	}
+	if _, ok := accessEntry["latency_ms"].(float64); !ok {
+		t.Errorf("Expected a numeric latency_ms, got %v", accessEntry["latency_ms"])
+	}
+}
+
+func TestRequestLogger_DefaultsStatusToOK(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	handler := RequestLogger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+
+	entries := logEntries(t, &logs)
+	if len(entries) != 1 {
+		t.Fatalf("Expected one access entry, got %d: %s", len(entries), logs.String())
+	}
+	if entries[0]["status"] != float64(http.StatusOK) || entries[0]["bytes"] != float64(2) {
+		t.Errorf("Expected status 200 and 2 bytes, got %v", entries[0])
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"fis-playground/internal/repository"
+)
+
+// DefaultRetryBudget is how many retries one request may make across all
+// retry layers when RETRY_BUDGET is not set
+const DefaultRetryBudget = 10
+
+// RetryBudgetFromEnv returns the per-request retry budget configured via
+// RETRY_BUDGET. Zero disables the budget.
+func RetryBudgetFromEnv() int {
+	return envInt("RETRY_BUDGET", DefaultRetryBudget)
+}
+
+// RetryBudget gives each request a budget of n retries, shared by the SDK
+// retryer and the repository's own retries, and logs how much of it was
+// used by requests that retried
+func RetryBudget(logger *slog.Logger, n int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			budget := repository.NewRetryBudget(n)
+			next.ServeHTTP(w, r.WithContext(repository.WithRetryBudget(r.Context(), budget)))
+
+			if used := budget.Used(); used > 0 {
+				logger.Info("Retry budget used",
+					"request_id", chimiddleware.GetReqID(r.Context()),
+					"method", r.Method,
+					"path", r.URL.Path,
+					"used", used,
+					"remaining", budget.Remaining(),
+				)
+			}
+		})
+	}
+}
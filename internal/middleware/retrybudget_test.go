@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fis-playground/internal/repository"
+)
+
+func TestRetryBudget_AttachesBudgetAndLogsUse(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	var remaining int
+	handler := RetryBudget(logger, 4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budget := repository.RetryBudgetFromContext(r.Context())
+		if budget == nil {
+			t.Fatal("Expected a retry budget in the request context")
+		}
+		remaining = budget.Remaining()
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items", nil))
+
+	if remaining != 4 {
+		t.Errorf("Expected a fresh budget of 4, got %d", remaining)
+	}
+	if strings.Contains(logs.String(), "Retry budget used") {
+		t.Errorf("Expected no log for a request without retries, got %s", logs.String())
+	}
+}
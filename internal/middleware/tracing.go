@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"fis-playground/internal/tracing"
+)
+
+// Trace records an X-Ray segment for each request, continuing the trace of
+// an incoming X-Amzn-Trace-Id header, and keeps it in the request context
+// so the DynamoDB calls the request makes become its subsegments. The
+// segment is annotated with the matched route pattern, such as
+// /items/{id}, so traces can be searched by route. Clients get the trace ID
+// back in the X-Amzn-Trace-Id response header.
+//
+// With a nil tracer, as when ENABLE_XRAY is off, handlers are returned
+// unchanged.
+func Trace(tracer *tracing.Tracer) func(http.Handler) http.Handler {
+	if tracer == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			segment := tracer.StartSegment(r.Header.Get(tracing.TraceHeader))
+			if segment == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			segment.SetHTTPRequest(r.Method, r.URL.Path)
+			w.Header().Set(tracing.TraceHeader, "Root="+segment.TraceID())
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(tracing.WithSegment(r.Context(), segment)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			segment.SetAnnotation("route", matchedRoute(r))
+			segment.SetHTTPStatus(status)
+			segment.Close()
+		})
+	}
+}
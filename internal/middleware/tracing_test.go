@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"fis-playground/internal/tracing"
+)
+
+func TestTrace_DisabledIsNoOp(t *testing.T) {
+	var sawSegment bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawSegment = tracing.FromContext(r.Context()) != nil
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	handler := Trace(nil)(next)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/items", nil))
+
+	if sawSegment {
+		t.Error("Expected no segment in the context with tracing off")
+	}
+	if w.Header().Get(tracing.TraceHeader) != "" {
+		t.Errorf("Expected no trace header with tracing off, got %q", w.Header().Get(tracing.TraceHeader))
+	}
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected the handler's status, got %d", w.Code)
+	}
+}
+
+func TestTrace_RecordsSegment(t *testing.T) {
+	var daemon bytes.Buffer
+	r := chi.NewRouter()
+	r.Use(Trace(tracing.NewTracer("items-api", &daemon)))
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if tracing.FromContext(r.Context()) == nil {
+			t.Error("Expected the segment in the request context")
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest("GET", "/items/item-1", nil)
+	req.Header.Set(tracing.TraceHeader, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get(tracing.TraceHeader); got != "Root=1-5759e988-bd862e3fe1be46a994272793" {
+		t.Errorf("Expected the trace ID in the response, got %q", got)
+	}
+
+	_, body, _ := strings.Cut(daemon.String(), "\n")
+	var segment struct {
+		TraceID     string            `json:"trace_id"`
+		ParentID    string            `json:"parent_id"`
+		Error       bool              `json:"error"`
+		Annotations map[string]string `json:"annotations"`
+		HTTP        struct {
+			Request struct {
+				Method string `json:"method"`
+				URL    string `json:"url"`
+			} `json:"request"`
+			Response struct {
+				Status int `json:"status"`
+			} `json:"response"`
+		} `json:"http"`
+	}
+	if err := json.Unmarshal([]byte(body), &segment); err != nil {
+		t.Fatalf("Failed to decode segment %q: %v", daemon.String(), err)
+	}
+	if segment.TraceID != "1-5759e988-bd862e3fe1be46a994272793" || segment.ParentID != "53995c3f42cd8ad8" {
+		t.Errorf("Expected the caller's trace to continue, got %+v", segment)
+	}
+	if segment.Annotations["route"] != "/items/{id}" {
+		t.Errorf("Expected the route pattern annotated, got %v", segment.Annotations)
+	}
+	if segment.HTTP.Request.URL != "/items/item-1" || segment.HTTP.Response.Status != http.StatusNotFound || !segment.Error {
+		t.Errorf("Expected the request and its 404 recorded, got %+v", segment)
+	}
+}
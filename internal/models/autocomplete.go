@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// Autocomplete bounds. Queries shorter than the minimum match too much of
+// the table to be useful as suggestions.
+const (
+	MinAutocompleteQueryLength = 2
+	DefaultAutocompleteLimit   = 10
+	MaxAutocompleteLimit       = 20
+)
+
+// ErrAutocompleteQueryTooShort is returned for queries below the minimum length
+var ErrAutocompleteQueryTooShort = errors.New("autocomplete query is too short")
+
+// ItemSuggestion is an autocomplete match, with just enough to show and
+// link to the item
+type ItemSuggestion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AutocompleteResult is the response body of an autocomplete request
+type AutocompleteResult struct {
+	Items []ItemSuggestion `json:"items"`
+}
+
+// NameKey is the case-folded form of a name that autocomplete prefixes are
+// matched against
+func NameKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// ValidateAutocompleteQuery checks a query is long enough, counting
+// characters rather than bytes
+func ValidateAutocompleteQuery(query string) error {
+	if len([]rune(strings.TrimSpace(query))) < MinAutocompleteQueryLength {
+		return ErrAutocompleteQueryTooShort
+	}
+	return nil
+}
+
+// Suggestions reduces items to autocomplete suggestions
+func Suggestions(items []Item) []ItemSuggestion {
+	suggestions := make([]ItemSuggestion, 0, len(items))
+	for _, item := range items {
+		suggestions = append(suggestions, ItemSuggestion{ID: item.ID, Name: item.Name})
+	}
+	return suggestions
+}
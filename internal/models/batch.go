@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// BatchCreateRequest represents the request payload for creating several
+// items at once
+type BatchCreateRequest struct {
+	Items []CreateItemRequest `json:"items"`
+}
+
+// BatchGetRequest represents the request payload for fetching several
+// items by ID
+type BatchGetRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// Batch validation errors
+var (
+	ErrEmptyBatch  = errors.New("items cannot be empty")
+	ErrBatchItemID = errors.New("id cannot be set on items created in a batch")
+	ErrEmptyIDs    = errors.New("ids cannot be empty")
+	ErrEmptyID     = errors.New("ids cannot contain an empty id")
+)
+
+// Validate validates a BatchCreateRequest as a whole; each element is
+// checked separately with ValidateBatchItem so one bad item doesn't reject
+// the batch
+func (r *BatchCreateRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return ErrEmptyBatch
+	}
+	return nil
+}
+
+// ValidateBatchItem validates one element of a batch create. Client IDs
+// are not accepted because batch writes can't be conditional, so an item
+// with an existing ID would silently be overwritten.
+func ValidateBatchItem(r *CreateItemRequest) error {
+	if r.ID != "" {
+		return ErrBatchItemID
+	}
+	return r.Validate()
+}
+
+// Validate validates a BatchGetRequest
+func (r *BatchGetRequest) Validate() error {
+	if len(r.IDs) == 0 {
+		return ErrEmptyIDs
+	}
+	for _, id := range r.IDs {
+		if strings.TrimSpace(id) == "" {
+			return ErrEmptyID
+		}
+	}
+	return nil
+}
+
+// UniqueIDs returns the requested IDs without repeats, in first-seen order
+func (r *BatchGetRequest) UniqueIDs() []string {
+	seen := make(map[string]bool, len(r.IDs))
+	var ids []string
+	for _, id := range r.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultRule fills in fields of a new item when the item matches a
+// condition, e.g. "items in category inbox start out pending"
+type DefaultRule struct {
+	When ItemFilter    `json:"when"`
+	Set  DefaultValues `json:"set"`
+}
+
+// DefaultValues are the fields a DefaultRule sets. Category and
+// visibility are only set when the client left them empty; status can't be
+// given on create, so it is always set.
+type DefaultValues struct {
+	Status   string `json:"status,omitempty"`
+	Category string `json:"category,omitempty"`
+	// VisibleFor sets visible_until this long after the item becomes
+	// visible, e.g. "1h"
+	VisibleFor string `json:"visible_for,omitempty"`
+
+	visibleFor time.Duration
+}
+
+// ErrEmptyDefaultRule is returned for a default rule that sets nothing
+var ErrEmptyDefaultRule = errors.New("default rule must set status, category or visible_for")
+
+// ParseDefaultRules parses a JSON list of default rules and validates them
+func ParseDefaultRules(data []byte) ([]DefaultRule, error) {
+	var rules []DefaultRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if err := rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	return rules, nil
+}
+
+// validate checks the rule's condition and values, parsing VisibleFor
+func (r *DefaultRule) validate() error {
+	if err := r.When.Validate(); err != nil {
+		return err
+	}
+	if r.Set == (DefaultValues{}) {
+		return ErrEmptyDefaultRule
+	}
+	if r.Set.Status != "" && !IsValidStatus(r.Set.Status) {
+		return ErrInvalidStatus
+	}
+	if len(r.Set.Category) > 50 {
+		return ErrCategoryTooLong
+	}
+	if r.Set.VisibleFor != "" {
+		d, err := time.ParseDuration(r.Set.VisibleFor)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("visible_for must be a positive duration, got %q", r.Set.VisibleFor)
+		}
+		r.Set.visibleFor = d
+	}
+	return nil
+}
+
+// ApplyDefaultRules applies the rules to a new item in order. Each rule
+// sees the item as the rules before it left it, so one rule's status can
+// trigger another.
+func ApplyDefaultRules(item *Item, rules []DefaultRule) {
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.When.Matches(item) {
+			continue
+		}
+		if rule.Set.Status != "" {
+			item.Status = rule.Set.Status
+		}
+		if rule.Set.Category != "" && item.Category == "" {
+			item.Category = rule.Set.Category
+		}
+		if rule.Set.visibleFor > 0 && item.VisibleUntil == nil {
+			from := item.CreatedAt
+			if item.VisibleFrom != nil {
+				from = *item.VisibleFrom
+			}
+			until := from.Add(rule.Set.visibleFor)
+			item.VisibleUntil = &until
+		}
+	}
+}
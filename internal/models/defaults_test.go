@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApplyDefaultRules(t *testing.T) {
+	rules, err := ParseDefaultRules([]byte(`[
+		{"when": {"category": "inbox"}, "set": {"status": "pending"}},
+		{"when": {"status": "pending"}, "set": {"visible_for": "1h"}},
+		{"when": {}, "set": {"category": "misc"}}
+	]`))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		category      string
+		expectStatus  string
+		expectCat     string
+		expectExpires bool
+	}{
+		{name: "Rules fire and chain", category: "inbox", expectStatus: "pending", expectCat: "inbox", expectExpires: true},
+		{name: "Condition not met", category: "archive", expectStatus: "active", expectCat: "archive"},
+		{name: "Empty field defaulted", category: "", expectStatus: "active", expectCat: "misc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := NewItem("Item", "Description")
+			item.CreatedAt = created
+			item.Category = tt.category
+
+			ApplyDefaultRules(item, rules)
+
+			if item.Status != tt.expectStatus || item.Category != tt.expectCat {
+				t.Errorf("Expected status %q category %q, got %q %q", tt.expectStatus, tt.expectCat, item.Status, item.Category)
+			}
+			if tt.expectExpires {
+				if item.VisibleUntil == nil || !item.VisibleUntil.Equal(created.Add(time.Hour)) {
+					t.Errorf("Expected visible_until an hour after creation, got %v", item.VisibleUntil)
+				}
+			} else if item.VisibleUntil != nil {
+				t.Errorf("Expected no visible_until, got %v", item.VisibleUntil)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultRules_KeepsClientValues(t *testing.T) {
+	rules, err := ParseDefaultRules([]byte(`[{"when": {}, "set": {"category": "misc", "visible_for": "1h"}}]`))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := NewItem("Item", "Description")
+	item.Category = "tools"
+	item.VisibleUntil = &until
+
+	ApplyDefaultRules(item, rules)
+	if item.Category != "tools" || !item.VisibleUntil.Equal(until) {
+		t.Errorf("Expected client values kept, got category %q visible_until %v", item.Category, item.VisibleUntil)
+	}
+}
+
+func TestParseDefaultRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		err   error
+	}{
+		{name: "Sets nothing", rules: `[{"when": {"category": "inbox"}, "set": {}}]`, err: ErrEmptyDefaultRule},
+		{name: "Invalid status", rules: `[{"when": {}, "set": {"status": "archived"}}]`, err: ErrInvalidStatus},
+		{name: "Invalid condition", rules: `[{"when": {"status": "archived"}, "set": {"category": "misc"}}]`, err: ErrInvalidStatus},
+		{name: "Invalid duration", rules: `[{"when": {}, "set": {"visible_for": "soon"}}]`},
+		{name: "Not JSON", rules: `status=pending`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDefaultRules([]byte(tt.rules))
+			if err == nil || (tt.err != nil && !errors.Is(err, tt.err)) {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FieldDiff describes a single field that differs between two items
+type FieldDiff struct {
+	Field string      `json:"field"`
+	A     interface{} `json:"a"`
+	B     interface{} `json:"b"`
+}
+
+// DiffItems returns the field-level differences between two items, keyed by
+// JSON field name. The ID is not compared since it always differs.
+func DiffItems(a, b *Item) []FieldDiff {
+	diffs := []FieldDiff{}
+
+	va := reflect.ValueOf(a).Elem()
+	vb := reflect.ValueOf(b).Elem()
+	t := va.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" || name == "id" {
+			continue
+		}
+
+		fa := va.Field(i).Interface()
+		fb := vb.Field(i).Interface()
+		if !fieldEqual(fa, fb) {
+			diffs = append(diffs, FieldDiff{Field: name, A: fa, B: fb})
+		}
+	}
+
+	return diffs
+}
+
+// fieldEqual compares two field values, treating times as equal instants
+func fieldEqual(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		return ta.Equal(b.(time.Time))
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,57 @@
+package models
+
+// FacetFields are the item fields that can be faceted
+var FacetFields = []string{"status", "category"}
+
+// MaxFacetValues caps how many distinct values a facet reports
+const MaxFacetValues = 100
+
+// FacetResult counts items per distinct value of a field
+type FacetResult struct {
+	Field  string         `json:"field"`
+	Counts map[string]int `json:"counts"`
+	// Missing counts items without a value for the field
+	Missing int `json:"missing"`
+	// Truncated is set when values beyond MaxFacetValues were dropped
+	Truncated bool `json:"truncated"`
+}
+
+// IsFacetField checks whether a field can be faceted
+func IsFacetField(field string) bool {
+	for _, f := range FacetFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// FacetValue returns the item's value for a facet field
+func (i *Item) FacetValue(field string) string {
+	switch field {
+	case "status":
+		return i.Status
+	case "category":
+		return i.Category
+	}
+	return ""
+}
+
+// NewFacetResult creates an empty facet for the field
+func NewFacetResult(field string) *FacetResult {
+	return &FacetResult{Field: field, Counts: map[string]int{}}
+}
+
+// Add counts one item with the given value. New values beyond
+// MaxFacetValues are dropped and mark the result truncated.
+func (f *FacetResult) Add(value string) {
+	if value == "" {
+		f.Missing++
+		return
+	}
+	if _, ok := f.Counts[value]; !ok && len(f.Counts) >= MaxFacetValues {
+		f.Truncated = true
+		return
+	}
+	f.Counts[value]++
+}
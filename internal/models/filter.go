@@ -0,0 +1,46 @@
+package models
+
+import "errors"
+
+// ItemFilter selects items by field values; empty fields match everything
+type ItemFilter struct {
+	Status   string `json:"status,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// Validate validates an ItemFilter
+func (f *ItemFilter) Validate() error {
+	if f.Status != "" && !IsValidStatus(f.Status) {
+		return ErrInvalidStatus
+	}
+	return nil
+}
+
+// Matches checks whether the item satisfies the filter
+func (f *ItemFilter) Matches(item *Item) bool {
+	return (f.Status == "" || item.Status == f.Status) &&
+		(f.Category == "" || item.Category == f.Category)
+}
+
+// ErrFilterRequired is returned when an operation needs a non-empty filter
+var ErrFilterRequired = errors.New("filter is required: set status or category")
+
+// IsEmpty reports whether the filter matches every item
+func (f *ItemFilter) IsEmpty() bool {
+	return *f == ItemFilter{}
+}
+
+// BulkDeleteRequest represents the request payload for deleting all items
+// matching a filter. Without a confirm token it only previews the deletion.
+type BulkDeleteRequest struct {
+	Filter       ItemFilter `json:"filter"`
+	ConfirmToken string     `json:"confirm_token,omitempty"`
+}
+
+// Validate validates a BulkDeleteRequest
+func (r *BulkDeleteRequest) Validate() error {
+	if r.Filter.IsEmpty() {
+		return ErrFilterRequired
+	}
+	return r.Filter.Validate()
+}
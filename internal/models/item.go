@@ -2,8 +2,11 @@ package models
 
 import (
 	"errors"
+	"log/slog"
+	"maps"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Item represents an item in the FIS playground system
@@ -14,19 +17,107 @@ type Item struct {
 	CreatedAt   time.Time `json:"created_at" dynamodbav:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" dynamodbav:"updated_at"`
 	Status      string    `json:"status" dynamodbav:"status"`
+	Category    string    `json:"category,omitempty" dynamodbav:"category,omitempty"`
+	Tags        []string  `json:"tags,omitempty" dynamodbav:"tags,stringset,omitempty"`
+	Generation  int64     `json:"generation" dynamodbav:"generation"`
+	Version     int64     `json:"version" dynamodbav:"version"`
+	ViewCount   int64     `json:"view_count,omitempty" dynamodbav:"view_count,omitempty"`
+	// CreatedBy is the principal that created the item, when known
+	CreatedBy string `json:"created_by,omitempty" dynamodbav:"created_by,omitempty"`
+	// OwnerID is the verified user that created the item, when known
+	OwnerID string `json:"owner_id,omitempty" dynamodbav:"owner_id,omitempty"`
+	// DeletedAt marks a soft-deleted item awaiting compaction
+	DeletedAt *time.Time `json:"deleted_at,omitempty" dynamodbav:"deleted_at,omitempty"`
+	// PreviousStatus keeps a soft-deleted item's status for its restore
+	PreviousStatus string `json:"-" dynamodbav:"previous_status,omitempty"`
+	// VisibleFrom and VisibleUntil, when set, bound the window in which the
+	// item appears in reads
+	VisibleFrom  *time.Time `json:"visible_from,omitempty" dynamodbav:"visible_from,omitempty"`
+	VisibleUntil *time.Time `json:"visible_until,omitempty" dynamodbav:"visible_until,omitempty"`
+	// ExpiresAt, when set, is when DynamoDB TTL may delete the item. It is
+	// stored in ttl as Unix epoch seconds, the format TTL requires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" dynamodbav:"ttl,unixtime,omitempty"`
+	// LeaseHolder and LeaseExpiresAt record the principal editing the item
+	// and when its lease ends. The expiry is stored as Unix epoch seconds so
+	// conditions can compare it numerically.
+	LeaseHolder    string     `json:"lease_holder,omitempty" dynamodbav:"lease_holder,omitempty"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty" dynamodbav:"lease_expires_at,unixtime,omitempty"`
+	// Metadata holds free-form client key-value pairs
+	Metadata map[string]string `json:"metadata,omitempty" dynamodbav:"metadata,omitempty"`
 }
 
 // CreateItemRequest represents the request payload for creating an item
 type CreateItemRequest struct {
+	// ID is optional; one is generated when it is omitted
+	ID          string `json:"id,omitempty"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Category    string `json:"category,omitempty"`
+	// Tags are trimmed, lowercased and stored sorted
+	Tags []string `json:"tags,omitempty"`
+	// Metadata holds free-form key-value pairs
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// VisibleFrom and VisibleUntil optionally limit when the item is visible
+	VisibleFrom  *time.Time `json:"visible_from,omitempty"`
+	VisibleUntil *time.Time `json:"visible_until,omitempty"`
+	// TTLSeconds optionally expires the item this many seconds after it
+	// is created
+	TTLSeconds *int64 `json:"ttl_seconds,omitempty"`
 }
 
+// ImportItemRequest represents the request payload for importing an item
+// with its original creation time preserved
+type ImportItemRequest struct {
+	CreateItemRequest
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// MaxCreatedAtSkew is how far in the future an imported created_at may be,
+// allowing for clock differences between the importer and the server
+const MaxCreatedAtSkew = 5 * time.Minute
+
 // UpdateItemRequest represents the request payload for updating an item
 type UpdateItemRequest struct {
 	Name        string `json:"name,omitempty"`
 	Description string `json:"description,omitempty"`
 	Status      string `json:"status,omitempty"`
+	Category    string `json:"category,omitempty"`
+	// Tags, when present, replace the item's tags; an empty list clears them
+	Tags []string `json:"tags,omitempty"`
+	// Metadata, when present, changes the item's metadata as MetadataMode
+	// says. A null value removes its key.
+	Metadata map[string]*string `json:"metadata,omitempty"`
+	// MetadataMode is MetadataMerge or MetadataReplace, the default. It is
+	// set from the metadata_mode query parameter.
+	MetadataMode string `json:"-"`
+	// Generation is the client's expected current generation. When set, the
+	// update only succeeds if the stored generation is equal to it.
+	Generation *int64 `json:"generation,omitempty"`
+	// Version is the client's expected current version. When set, the
+	// update only succeeds if the stored version is equal to it.
+	Version *int64 `json:"version,omitempty"`
+	// RequireOwner, when set, only applies the update if the item was
+	// created by this principal. It is set by the server, never by clients.
+	RequireOwner string `json:"-"`
+}
+
+// PatchItemRequest represents the request payload for a partial update. A
+// nil field is left unchanged; an empty string clears an optional field.
+type PatchItemRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty"`
+	Category    *string `json:"category,omitempty"`
+	// Tags, Metadata and ExpiresAt replace the item's values when sent, and
+	// remove the attribute when sent as null. Tags and metadata are stored
+	// without empty values, so an empty list or map removes them too.
+	Tags      Nullable[[]string]          `json:"tags"`
+	Metadata  Nullable[map[string]string] `json:"metadata"`
+	ExpiresAt Nullable[time.Time]         `json:"expires_at"`
+	// Generation is the client's expected current generation, as for updates
+	Generation *int64 `json:"generation,omitempty"`
+	// RequireOwner restricts the patch to the item's creator, as for updates
+	RequireOwner string `json:"-"`
 }
 
 // APIResponse represents the standard API response format
@@ -34,6 +125,8 @@ type APIResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   *ErrorInfo  `json:"error,omitempty"`
+	// Warnings are non-fatal notices, such as use of deprecated fields
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ErrorInfo provides detailed error information
@@ -44,102 +137,428 @@ type ErrorInfo struct {
 	Details string `json:"details,omitempty"`
 }
 
+// ProblemDetails is an RFC 7807 error body, sent as application/problem+json
+// to clients that ask for it. Code carries the same error code as ErrorInfo.
+type ProblemDetails struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Code   string `json:"code"`
+}
+
 // Validation errors
 var (
 	ErrEmptyName          = errors.New("name cannot be empty")
 	ErrEmptyDescription   = errors.New("description cannot be empty")
-	ErrInvalidStatus      = errors.New("status must be one of: active, inactive, pending")
 	ErrNameTooLong        = errors.New("name cannot exceed 100 characters")
 	ErrDescriptionTooLong = errors.New("description cannot exceed 500 characters")
+	ErrInvalidCreatedAt   = errors.New("created_at has invalid format, expected RFC3339")
+	ErrCreatedAtInFuture  = errors.New("created_at cannot be in the future")
+	ErrInvalidGeneration  = errors.New("generation must be a non-negative integer")
+	ErrInvalidVersion     = errors.New("version must be a non-negative integer")
+	ErrCategoryTooLong    = errors.New("category cannot exceed 50 characters")
+	ErrInvalidID          = errors.New("id must be 1-64 characters of letters, digits, '-' or '_'")
 )
 
-// Validate validates a CreateItemRequest
+// Validate validates a CreateItemRequest, normalizing its name and
+// description first
 func (r *CreateItemRequest) Validate() error {
-	if strings.TrimSpace(r.Name) == "" {
-		return ErrEmptyName
+	for _, rule := range createRules {
+		if err := rule.check(r); err != nil {
+			return err
+		}
 	}
-	if len(r.Name) > 100 {
-		return ErrNameTooLong
+	return nil
+}
+
+// RuleResult is the outcome of one validation rule; Err is nil when the
+// rule passed
+type RuleResult struct {
+	Rule string
+	Err  error
+}
+
+// Explain runs every validation rule, in the order Validate does, and
+// reports each outcome instead of stopping at the first failure. Like
+// Validate, it normalizes the request as it goes.
+func (r *CreateItemRequest) Explain() []RuleResult {
+	results := make([]RuleResult, 0, len(createRules))
+	for _, rule := range createRules {
+		results = append(results, RuleResult{Rule: rule.name, Err: rule.check(r)})
 	}
-	if strings.TrimSpace(r.Description) == "" {
-		return ErrEmptyDescription
+	return results
+}
+
+// createRule is one named check of a CreateItemRequest
+type createRule struct {
+	name  string
+	check func(r *CreateItemRequest) error
+}
+
+// createRules are the checks a create request must pass, in order
+var createRules = []createRule{
+	{"name_required", func(r *CreateItemRequest) error {
+		r.Name = normalizeName(r.Name)
+		if r.Name == "" {
+			return ErrEmptyName
+		}
+		return nil
+	}},
+	{"name_length", func(r *CreateItemRequest) error {
+		if utf8.RuneCountInString(r.Name) > 100 {
+			return ErrNameTooLong
+		}
+		return nil
+	}},
+	{"description_required", func(r *CreateItemRequest) error {
+		r.Description = strings.TrimSpace(r.Description)
+		if r.Description == "" {
+			return ErrEmptyDescription
+		}
+		return nil
+	}},
+	{"description_length", func(r *CreateItemRequest) error {
+		if utf8.RuneCountInString(r.Description) > 500 {
+			return ErrDescriptionTooLong
+		}
+		return nil
+	}},
+	{"category_length", func(r *CreateItemRequest) error {
+		if len(r.Category) > 50 {
+			return ErrCategoryTooLong
+		}
+		return nil
+	}},
+	{"id_format", func(r *CreateItemRequest) error {
+		return r.ValidateID()
+	}},
+	{"tags", func(r *CreateItemRequest) error {
+		tags, err := prepareTags(r.Tags)
+		if err != nil {
+			return err
+		}
+		r.Tags = tags
+		return nil
+	}},
+	{"metadata", func(r *CreateItemRequest) error {
+		return ValidateMetadata(r.Metadata)
+	}},
+	{"ttl", func(r *CreateItemRequest) error {
+		return validateTTL(r.TTLSeconds)
+	}},
+	{"visibility_window", func(r *CreateItemRequest) error {
+		return validateVisibilityWindow(r.VisibleFrom, r.VisibleUntil)
+	}},
+}
+
+// normalizeName trims a name and collapses runs of whitespace inside it
+// to single spaces, so names that look the same are stored the same
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// ValidateID checks the client-supplied ID, if any. Unlike the other
+// rules it applies even to writes that skip validation, since IDs outside
+// the format can collide with the repository's bookkeeping items.
+func (r *CreateItemRequest) ValidateID() error {
+	if r.ID != "" && !isValidID(r.ID) {
+		return ErrInvalidID
 	}
-	if len(r.Description) > 500 {
-		return ErrDescriptionTooLong
+	return nil
+}
+
+// isValidID checks a client-supplied ID. The allowed characters exclude
+// '#', which the repository reserves for its own bookkeeping items.
+func isValidID(id string) bool {
+	if len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate validates an ImportItemRequest
+func (r *ImportItemRequest) Validate() error {
+	if err := r.CreateItemRequest.Validate(); err != nil {
+		return err
+	}
+	if r.CreatedAt == "" {
+		return nil
+	}
+	createdAt, err := time.Parse(time.RFC3339, r.CreatedAt)
+	if err != nil {
+		return ErrInvalidCreatedAt
+	}
+	if createdAt.After(time.Now().Add(MaxCreatedAtSkew)) {
+		return ErrCreatedAtInFuture
 	}
 	return nil
 }
 
-// Validate validates an UpdateItemRequest
+// NewItem creates a new Item from the import request, backdating CreatedAt
+// when provided. UpdatedAt is always the import time.
+func (r *ImportItemRequest) NewItem() *Item {
+	item := r.CreateItemRequest.NewItem()
+	if createdAt, err := time.Parse(time.RFC3339, r.CreatedAt); err == nil {
+		item.CreatedAt = createdAt
+	}
+	return item
+}
+
+// Validate validates an UpdateItemRequest, normalizing its name and
+// description first
 func (r *UpdateItemRequest) Validate() error {
 	// For updates, fields are optional but if provided must be valid
 	if r.Name != "" {
-		if strings.TrimSpace(r.Name) == "" {
+		r.Name = normalizeName(r.Name)
+		if r.Name == "" {
 			return ErrEmptyName
 		}
-		if len(r.Name) > 100 {
+		if utf8.RuneCountInString(r.Name) > 100 {
 			return ErrNameTooLong
 		}
 	}
 	if r.Description != "" {
-		if strings.TrimSpace(r.Description) == "" {
+		r.Description = strings.TrimSpace(r.Description)
+		if r.Description == "" {
 			return ErrEmptyDescription
 		}
-		if len(r.Description) > 500 {
+		if utf8.RuneCountInString(r.Description) > 500 {
 			return ErrDescriptionTooLong
 		}
 	}
 	if r.Status != "" {
-		if !isValidStatus(r.Status) {
+		if !IsValidStatus(r.Status) {
 			return ErrInvalidStatus
 		}
 	}
+	if len(r.Category) > 50 {
+		return ErrCategoryTooLong
+	}
+	if r.Generation != nil && *r.Generation < 0 {
+		return ErrInvalidGeneration
+	}
+	if r.Version != nil && *r.Version < 0 {
+		return ErrInvalidVersion
+	}
+	tags, err := prepareTags(r.Tags)
+	if err != nil {
+		return err
+	}
+	r.Tags = tags
+	if r.MetadataMode != "" && r.MetadataMode != MetadataMerge && r.MetadataMode != MetadataReplace {
+		return ErrInvalidMetadataMode
+	}
+	return validateMetadataChanges(r.Metadata)
+}
+
+// Validate validates a PatchItemRequest, normalizing its name and
+// description first. Name and status are required on an item, so they can
+// be changed but not cleared.
+func (r *PatchItemRequest) Validate() error {
+	if r.Name != nil {
+		name := normalizeName(*r.Name)
+		r.Name = &name
+		if name == "" {
+			return ErrEmptyName
+		}
+		if utf8.RuneCountInString(*r.Name) > 100 {
+			return ErrNameTooLong
+		}
+	}
+	if r.Description != nil {
+		description := strings.TrimSpace(*r.Description)
+		if *r.Description != "" && description == "" {
+			return ErrEmptyDescription
+		}
+		r.Description = &description
+		if utf8.RuneCountInString(*r.Description) > 500 {
+			return ErrDescriptionTooLong
+		}
+	}
+	if r.Status != nil && !IsValidStatus(*r.Status) {
+		return ErrInvalidStatus
+	}
+	if r.Category != nil && len(*r.Category) > 50 {
+		return ErrCategoryTooLong
+	}
+	if r.Generation != nil && *r.Generation < 0 {
+		return ErrInvalidGeneration
+	}
+	if r.Tags.Value != nil {
+		tags, err := prepareTags(*r.Tags.Value)
+		if err != nil {
+			return err
+		}
+		r.Tags.Value = &tags
+	}
+	if r.Metadata.Value != nil {
+		if err := ValidateMetadata(*r.Metadata.Value); err != nil {
+			return err
+		}
+	}
+	if r.ExpiresAt.Value != nil {
+		if err := validateExpiresAt(*r.ExpiresAt.Value, time.Now()); err != nil {
+			return err
+		}
+		// Only whole seconds are stored
+		expires := r.ExpiresAt.Value.Truncate(time.Second)
+		r.ExpiresAt.Value = &expires
+	}
 	return nil
 }
 
+// Changes splits the patch into string attributes to set and optional
+// attributes to remove, keyed by their model names. Tags, metadata and
+// expiry are removed when null or empty; setting them is left to the
+// caller.
+func (r *PatchItemRequest) Changes() (set map[string]string, remove []string) {
+	set = map[string]string{}
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"name", r.Name},
+		{"description", r.Description},
+		{"status", r.Status},
+		{"category", r.Category},
+	}
+	for _, f := range fields {
+		switch {
+		case f.value == nil:
+		case *f.value == "":
+			remove = append(remove, f.name)
+		default:
+			set[f.name] = *f.value
+		}
+	}
+	if r.Tags.Null() || r.Tags.Value != nil && len(*r.Tags.Value) == 0 {
+		remove = append(remove, "tags")
+	}
+	if r.Metadata.Null() || r.Metadata.Value != nil && len(*r.Metadata.Value) == 0 {
+		remove = append(remove, "metadata")
+	}
+	if r.ExpiresAt.Null() {
+		remove = append(remove, "ttl")
+	}
+	return set, remove
+}
+
 // Validate validates a complete Item struct
 func (i *Item) Validate() error {
 	if strings.TrimSpace(i.Name) == "" {
 		return ErrEmptyName
 	}
-	if len(i.Name) > 100 {
+	if utf8.RuneCountInString(i.Name) > 100 {
 		return ErrNameTooLong
 	}
 	if strings.TrimSpace(i.Description) == "" {
 		return ErrEmptyDescription
 	}
-	if len(i.Description) > 500 {
+	if utf8.RuneCountInString(i.Description) > 500 {
 		return ErrDescriptionTooLong
 	}
-	if !isValidStatus(i.Status) {
+	if !IsValidStatus(i.Status) {
 		return ErrInvalidStatus
 	}
-	return nil
+	if len(i.Category) > 50 {
+		return ErrCategoryTooLong
+	}
+	if err := ValidateTags(i.Tags); err != nil {
+		return err
+	}
+	if err := ValidateMetadata(i.Metadata); err != nil {
+		return err
+	}
+	return validateVisibilityWindow(i.VisibleFrom, i.VisibleUntil)
 }
 
-// isValidStatus checks if the status is one of the allowed values
-func isValidStatus(status string) bool {
-	validStatuses := []string{"active", "inactive", "pending"}
-	for _, validStatus := range validStatuses {
-		if status == validStatus {
-			return true
-		}
+// NewItem creates a new Item from the request
+func (r *CreateItemRequest) NewItem() *Item {
+	item := NewItem(r.Name, r.Description)
+	item.ID = r.ID
+	item.Category = r.Category
+	if len(r.Tags) > 0 {
+		item.Tags = append([]string(nil), r.Tags...)
 	}
-	return false
+	if len(r.Metadata) > 0 {
+		item.Metadata = maps.Clone(r.Metadata)
+	}
+	item.VisibleFrom = r.VisibleFrom
+	item.VisibleUntil = r.VisibleUntil
+	if r.TTLSeconds != nil {
+		item.ExpiresAt = expiresAt(item.CreatedAt, *r.TTLSeconds)
+	}
+	return item
 }
 
-// NewItem creates a new Item with default values
+// NewItem creates a new Item with default values. The name and description
+// are normalized as requests are.
 func NewItem(name, description string) *Item {
 	now := time.Now()
 	return &Item{
-		Name:        name,
-		Description: description,
-		Status:      "active", // default status
+		Name:        normalizeName(name),
+		Description: strings.TrimSpace(description),
+		Status:      DefaultStatus(),
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		Generation:  1,
+		Version:     1,
 	}
 }
 
+// LogValue implements slog.LogValuer so items are logged as structured
+// groups whose user content fields can be masked by the logging handler
+func (i *Item) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", i.ID),
+		slog.String("name", i.Name),
+		slog.String("description", i.Description),
+		slog.String("status", i.Status),
+		slog.String("category", i.Category),
+		slog.Int64("generation", i.Generation),
+	)
+}
+
+// ApplyPatch applies a validated PatchItemRequest to the item
+func (i *Item) ApplyPatch(req *PatchItemRequest) {
+	if req.Name != nil {
+		i.Name = *req.Name
+	}
+	if req.Description != nil {
+		i.Description = *req.Description
+	}
+	if req.Status != nil {
+		i.Status = *req.Status
+	}
+	if req.Category != nil {
+		i.Category = *req.Category
+	}
+	if req.Tags.Set {
+		i.Tags = nil
+		if req.Tags.Value != nil && len(*req.Tags.Value) > 0 {
+			i.Tags = append([]string(nil), *req.Tags.Value...)
+		}
+	}
+	if req.Metadata.Set {
+		i.Metadata = nil
+		if req.Metadata.Value != nil && len(*req.Metadata.Value) > 0 {
+			i.Metadata = maps.Clone(*req.Metadata.Value)
+		}
+	}
+	if req.ExpiresAt.Set {
+		i.ExpiresAt = req.ExpiresAt.Value
+	}
+	i.Generation++
+	i.Version++
+	i.UpdatedAt = time.Now()
+}
+
 // UpdateFields updates the item with new values from UpdateItemRequest
 func (i *Item) UpdateFields(req *UpdateItemRequest) {
 	if req.Name != "" {
@@ -151,5 +570,19 @@ func (i *Item) UpdateFields(req *UpdateItemRequest) {
 	if req.Status != "" {
 		i.Status = req.Status
 	}
+	if req.Category != "" {
+		i.Category = req.Category
+	}
+	if req.Tags != nil {
+		i.Tags = nil
+		if len(req.Tags) > 0 {
+			i.Tags = append([]string(nil), req.Tags...)
+		}
+	}
+	if req.Metadata != nil {
+		i.Metadata = ApplyMetadata(i.Metadata, req.Metadata, req.MetadataMode)
+	}
+	i.Generation++
+	i.Version++
 	i.UpdatedAt = time.Now()
 }
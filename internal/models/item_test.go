@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate_CountsCharactersNotBytes(t *testing.T) {
+	// Each emoji is 4 bytes and each accented letter 2, so these are within
+	// the character limits but well over the byte limits
+	name := strings.Repeat("🚀", 100)
+	description := strings.Repeat("é", 500)
+	tooLongName := strings.Repeat("🚀", 101)
+	tooLongDescription := strings.Repeat("é", 501)
+
+	tests := []struct {
+		name     string
+		validate func() error
+		err      error
+	}{
+		{"create at the limits", (&CreateItemRequest{Name: name, Description: description}).Validate, nil},
+		{"create name too long", (&CreateItemRequest{Name: tooLongName, Description: "Description"}).Validate, ErrNameTooLong},
+		{"create description too long", (&CreateItemRequest{Name: "Item", Description: tooLongDescription}).Validate, ErrDescriptionTooLong},
+		{"update at the limits", (&UpdateItemRequest{Name: name, Description: description}).Validate, nil},
+		{"update name too long", (&UpdateItemRequest{Name: tooLongName}).Validate, ErrNameTooLong},
+		{"update description too long", (&UpdateItemRequest{Description: tooLongDescription}).Validate, ErrDescriptionTooLong},
+		{"patch at the limits", (&PatchItemRequest{Name: &name, Description: &description}).Validate, nil},
+		{"patch name too long", (&PatchItemRequest{Name: &tooLongName}).Validate, ErrNameTooLong},
+		{"patch description too long", (&PatchItemRequest{Description: &tooLongDescription}).Validate, ErrDescriptionTooLong},
+		{"item at the limits", (&Item{Name: name, Description: description, Status: "active"}).Validate, nil},
+		{"item name too long", (&Item{Name: tooLongName, Description: "Description", Status: "active"}).Validate, ErrNameTooLong},
+		{"item description too long", (&Item{Name: "Item", Description: tooLongDescription, Status: "active"}).Validate, ErrDescriptionTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidate_NormalizesWhitespace(t *testing.T) {
+	create := &CreateItemRequest{Name: "  Foo \t  Bar  ", Description: "\n Described  "}
+	if err := create.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if create.Name != "Foo Bar" || create.Description != "Described" {
+		t.Errorf("Expected a normalized create, got name %q, description %q", create.Name, create.Description)
+	}
+
+	update := &UpdateItemRequest{Name: "  Foo  ", Description: " Described "}
+	if err := update.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if update.Name != "Foo" || update.Description != "Described" {
+		t.Errorf("Expected a normalized update, got name %q, description %q", update.Name, update.Description)
+	}
+
+	name, description := "  Foo  ", "  Described  "
+	patch := &PatchItemRequest{Name: &name, Description: &description}
+	if err := patch.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *patch.Name != "Foo" || *patch.Description != "Described" {
+		t.Errorf("Expected a normalized patch, got name %q, description %q", *patch.Name, *patch.Description)
+	}
+	if name != "  Foo  " {
+		t.Errorf("Expected the caller's string to be left alone, got %q", name)
+	}
+
+	if item := NewItem("  Foo  ", "  Described  "); item.Name != "Foo" || item.Description != "Described" {
+		t.Errorf("Expected NewItem to normalize, got name %q, description %q", item.Name, item.Description)
+	}
+}
+
+func TestValidate_WhitespaceOnlyNameIsEmpty(t *testing.T) {
+	blank := " \t "
+	for name, validate := range map[string]func() error{
+		"create": (&CreateItemRequest{Name: blank, Description: "Description"}).Validate,
+		"update": (&UpdateItemRequest{Name: blank}).Validate,
+		"patch":  (&PatchItemRequest{Name: &blank}).Validate,
+	} {
+		if err := validate(); !errors.Is(err, ErrEmptyName) {
+			t.Errorf("%s: expected ErrEmptyName, got %v", name, err)
+		}
+	}
+}
+
+func TestVersion_StartsAtOneAndIncreasesOnUpdates(t *testing.T) {
+	item := NewItem("Item", "Description")
+	if item.Version != 1 {
+		t.Fatalf("Expected a new item at version 1, got %d", item.Version)
+	}
+
+	item.UpdateFields(&UpdateItemRequest{Name: "Renamed"})
+	name := "Patched"
+	item.ApplyPatch(&PatchItemRequest{Name: &name})
+	if item.Version != 3 {
+		t.Errorf("Expected version 3 after two updates, got %d", item.Version)
+	}
+
+	negative := int64(-1)
+	if err := (&UpdateItemRequest{Version: &negative}).Validate(); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Expected ErrInvalidVersion, got %v", err)
+	}
+}
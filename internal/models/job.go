@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Create job statuses
+const (
+	JobPending   = "pending"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// CreateJob tracks an item create that was accepted for asynchronous
+// processing. ItemID is assigned up front, so clients can fetch the item
+// once the job succeeds.
+type CreateJob struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	ItemID      string     `json:"item_id"`
+	StatusURL   string     `json:"status_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Error explains why a failed job's create was rejected
+	Error *ErrorInfo `json:"error,omitempty"`
+	// OwnerID and CreatedBy are the user and principal the create was
+	// accepted for, who alone can see the job
+	OwnerID   string `json:"-"`
+	CreatedBy string `json:"-"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Lease bounds, in seconds. Leases are meant to cover one edit, so they
+// are kept short and an abandoned lease soon frees the item.
+const (
+	DefaultLeaseSeconds = 30
+	MaxLeaseSeconds     = 300
+)
+
+// ValidateLeaseSeconds checks a requested lease length is within bounds
+func ValidateLeaseSeconds(seconds int) error {
+	if seconds < 1 || seconds > MaxLeaseSeconds {
+		return fmt.Errorf("lease must be between 1 and %d seconds", MaxLeaseSeconds)
+	}
+	return nil
+}
+
+// LeaseExpiry returns when a lease of the given length taken at now ends.
+// It is whole seconds, the precision the lease is stored with.
+func LeaseExpiry(now time.Time, seconds int) time.Time {
+	return time.Unix(now.Unix()+int64(seconds), 0).UTC()
+}
+
+// LeasedAt reports whether the item is leased at now. An expired lease is
+// left on the item until the next one replaces it.
+func (i *Item) LeasedAt(now time.Time) bool {
+	return i.LeaseExpiresAt != nil && now.Before(*i.LeaseExpiresAt)
+}
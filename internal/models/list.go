@@ -0,0 +1,119 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ListResponse is the data payload of a paginated list response
+type ListResponse[T any] struct {
+	Items     []T    `json:"items"`
+	Count     int    `json:"count"`
+	HasMore   bool   `json:"has_more"`
+	NextToken string `json:"next_token,omitempty"`
+	// CollectionVersion changes whenever items are created, changed or
+	// deleted, when the repository tracks it
+	CollectionVersion string `json:"collection_version,omitempty"`
+}
+
+// ListItemsResponse is the data payload of GET /items
+type ListItemsResponse = ListResponse[Item]
+
+// NewListResponse builds a list payload, keeping Items non-nil so an empty
+// page serializes as [] rather than null
+func NewListResponse[T any](items []T, hasMore bool, nextToken string) *ListResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &ListResponse[T]{
+		Items:     items,
+		Count:     len(items),
+		HasMore:   hasMore,
+		NextToken: nextToken,
+	}
+}
+
+// Fields a listing can be sorted by
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortName      = "name"
+)
+
+// Sort orders a listing accepts
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+// ErrInvalidSortField and ErrInvalidSortOrder are returned for sort fields
+// and orders a listing doesn't support
+var (
+	ErrInvalidSortField = errors.New("sort must be one of created_at, updated_at, name")
+	ErrInvalidSortOrder = errors.New("order must be asc or desc")
+)
+
+// IsValidSortField checks if a listing can be sorted by field
+func IsValidSortField(field string) bool {
+	return field == SortCreatedAt || field == SortUpdatedAt || field == SortName
+}
+
+// SortByCreatedAt orders items oldest first, breaking ties between items
+// created at the same instant by ID so the order is deterministic
+func SortByCreatedAt(items []Item) {
+	SortItems(items, SortCreatedAt, false)
+}
+
+// SortItems orders items by field, case-insensitively for names, breaking
+// ties by ID. Descending reverses the whole order, ties included, the way
+// reading an index backwards does.
+func SortItems(items []Item, field string, descending bool) {
+	compare := func(a, b *Item) int {
+		switch field {
+		case SortUpdatedAt:
+			return a.UpdatedAt.Compare(b.UpdatedAt)
+		case SortName:
+			return strings.Compare(NameKey(a.Name), NameKey(b.Name))
+		default:
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := &items[i], &items[j]
+		if descending {
+			a, b = b, a
+		}
+		if c := compare(a, b); c != 0 {
+			return c < 0
+		}
+		return a.ID < b.ID
+	})
+}
+
+// ParseListResponse decodes a GET /items response body, returning an error
+// carrying the API error code when the request was not successful
+func ParseListResponse(body []byte) (*ListItemsResponse, error) {
+	var envelope struct {
+		Success bool               `json:"success"`
+		Data    *ListItemsResponse `json:"data"`
+		Error   *ErrorInfo         `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal list response: %w", err)
+	}
+
+	if !envelope.Success {
+		if envelope.Error != nil {
+			return nil, fmt.Errorf("list request failed: %s: %s", envelope.Error.Code, envelope.Error.Message)
+		}
+		return nil, errors.New("list request failed without error details")
+	}
+	if envelope.Data == nil {
+		return nil, errors.New("list response has no data")
+	}
+
+	return envelope.Data, nil
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseListResponse(t *testing.T) {
+	item := NewItem("Item", "Description")
+	body, err := json.Marshal(APIResponse{
+		Success: true,
+		Data:    NewListResponse([]Item{*item}, true, "token"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+
+	list, err := ParseListResponse(body)
+	if err != nil {
+		t.Fatalf("Expected response to parse, got %v", err)
+	}
+
+	if list.Count != 1 || len(list.Items) != 1 {
+		t.Fatalf("Expected 1 item, got count=%d items=%d", list.Count, len(list.Items))
+	}
+	if list.Items[0].ID != item.ID || list.Items[0].Name != "Item" {
+		t.Errorf("Expected item %s, got %+v", item.ID, list.Items[0])
+	}
+	if !list.HasMore || list.NextToken != "token" {
+		t.Errorf("Expected has_more with next_token 'token', got has_more=%v next_token=%q", list.HasMore, list.NextToken)
+	}
+}
+
+func TestParseListResponse_EmptyPage(t *testing.T) {
+	body, err := json.Marshal(APIResponse{
+		Success: true,
+		Data:    NewListResponse[Item](nil, false, ""),
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+	if !strings.Contains(string(body), `"items":[]`) {
+		t.Errorf("Expected empty page to serialize items as [], got %s", body)
+	}
+
+	list, err := ParseListResponse(body)
+	if err != nil {
+		t.Fatalf("Expected response to parse, got %v", err)
+	}
+	if list.Count != 0 || list.HasMore {
+		t.Errorf("Expected empty final page, got %+v", list)
+	}
+}
+
+func TestParseListResponse_Error(t *testing.T) {
+	body := []byte(`{"success": false, "error": {"code": "INVALID_VALUE", "message": "Invalid limit value", "type": "VALIDATION_ERROR"}}`)
+
+	_, err := ParseListResponse(body)
+	if err == nil || !strings.Contains(err.Error(), "INVALID_VALUE") {
+		t.Errorf("Expected error carrying INVALID_VALUE, got %v", err)
+	}
+}
+
+func TestSortByCreatedAt_TiebreakOnID(t *testing.T) {
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for run := 0; run < 5; run++ {
+		items := []Item{
+			{ID: "c", CreatedAt: createdAt},
+			{ID: "z", CreatedAt: createdAt.Add(-time.Millisecond)},
+			{ID: "a", CreatedAt: createdAt},
+			{ID: "b", CreatedAt: createdAt},
+		}
+		SortByCreatedAt(items)
+
+		var ids string
+		for _, item := range items {
+			ids += item.ID
+		}
+		if ids != "zabc" {
+			t.Fatalf("Expected order zabc, got %s", ids)
+		}
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	items := []Item{
+		{ID: "a", Name: "banana", CreatedAt: base.Add(2 * time.Second), UpdatedAt: base},
+		{ID: "b", Name: "Apple", CreatedAt: base, UpdatedAt: base.Add(time.Second)},
+		{ID: "c", Name: "cherry", CreatedAt: base.Add(time.Second), UpdatedAt: base.Add(3 * time.Second)},
+		{ID: "d", Name: "apple", CreatedAt: base, UpdatedAt: base.Add(2 * time.Second)},
+	}
+	tests := []struct {
+		field      string
+		descending bool
+		expected   string
+	}{
+		{field: SortCreatedAt, expected: "bdca"},
+		{field: SortCreatedAt, descending: true, expected: "acdb"},
+		{field: SortUpdatedAt, expected: "abdc"},
+		{field: SortUpdatedAt, descending: true, expected: "cdba"},
+		{field: SortName, expected: "bdac"},
+		{field: SortName, descending: true, expected: "cadb"},
+	}
+
+	for _, tt := range tests {
+		sorted := append([]Item(nil), items...)
+		SortItems(sorted, tt.field, tt.descending)
+
+		var ids string
+		for _, item := range sorted {
+			ids += item.ID
+		}
+		if ids != tt.expected {
+			t.Errorf("Expected %s (descending %t) order %s, got %s", tt.field, tt.descending, tt.expected, ids)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package models
+
+import "errors"
+
+// Metadata limits
+const (
+	MaxMetadataKeys        = 50
+	MaxMetadataKeyLength   = 64
+	MaxMetadataValueLength = 256
+)
+
+// Metadata update modes: merge changes only the keys given, replace
+// overwrites the whole map
+const (
+	MetadataMerge   = "merge"
+	MetadataReplace = "replace"
+)
+
+// Metadata validation errors
+var (
+	ErrInvalidMetadataKey   = errors.New("metadata keys must be 1-64 characters of letters, digits, '-', '_', '.' or ':'")
+	ErrMetadataValueTooLong = errors.New("a metadata value cannot exceed 256 characters")
+	ErrTooManyMetadataKeys  = errors.New("metadata cannot have more than 50 keys")
+	ErrInvalidMetadataMode  = errors.New("metadata_mode must be merge or replace")
+)
+
+// ValidateMetadataKey checks a metadata key. Keys reach update expressions
+// only through placeholders, so they may match DynamoDB reserved words.
+func ValidateMetadataKey(key string) error {
+	if key == "" || len(key) > MaxMetadataKeyLength {
+		return ErrInvalidMetadataKey
+	}
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == ':') {
+			return ErrInvalidMetadataKey
+		}
+	}
+	return nil
+}
+
+// ValidateMetadata checks the keys and values of an item's metadata
+func ValidateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataKeys {
+		return ErrTooManyMetadataKeys
+	}
+	for key, value := range metadata {
+		if err := ValidateMetadataKey(key); err != nil {
+			return err
+		}
+		if len(value) > MaxMetadataValueLength {
+			return ErrMetadataValueTooLong
+		}
+	}
+	return nil
+}
+
+// validateMetadataChanges checks metadata sent in an update, where a null
+// value removes the key
+func validateMetadataChanges(changes map[string]*string) error {
+	if len(changes) > MaxMetadataKeys {
+		return ErrTooManyMetadataKeys
+	}
+	for key, value := range changes {
+		if err := ValidateMetadataKey(key); err != nil {
+			return err
+		}
+		if value != nil && len(*value) > MaxMetadataValueLength {
+			return ErrMetadataValueTooLong
+		}
+	}
+	return nil
+}
+
+// ApplyMetadata returns metadata after an update's changes: in merge mode
+// the given keys are set, or removed when null, and the rest are kept; in
+// replace mode the non-null changes become the whole map. An empty result
+// is nil, so items without metadata don't carry an empty map.
+func ApplyMetadata(metadata map[string]string, changes map[string]*string, mode string) map[string]string {
+	result := map[string]string{}
+	if mode == MetadataMerge {
+		for key, value := range metadata {
+			result[key] = value
+		}
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(result, key)
+		} else {
+			result[key] = *value
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package models
+
+import "encoding/json"
+
+// Nullable is a patch field that tells an omitted field, which leaves the
+// attribute unchanged, apart from an explicit null, which removes it
+type Nullable[T any] struct {
+	// Set is true when the field was present, even as null
+	Set bool
+	// Value is the field's value, or nil when it was null
+	Value *T
+}
+
+// Null reports whether the field was sent as an explicit null
+func (n Nullable[T]) Null() bool {
+	return n.Set && n.Value == nil
+}
+
+// UnmarshalJSON records that the field was present, decoding its value
+// unless it is null
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Value = &value
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPatchItemRequest_OmittedNullAndValue(t *testing.T) {
+	var omitted, null, value PatchItemRequest
+	for body, req := range map[string]*PatchItemRequest{
+		`{"name":"Renamed"}`: &omitted,
+		`{"tags":null}`:      &null,
+		`{"tags":["a"]}`:     &value,
+	} {
+		if err := json.Unmarshal([]byte(body), req); err != nil {
+			t.Fatalf("Failed to decode %s: %v", body, err)
+		}
+	}
+
+	if omitted.Tags.Set || omitted.Tags.Null() {
+		t.Errorf("Expected omitted tags to be unset, got %+v", omitted.Tags)
+	}
+	if !null.Tags.Null() {
+		t.Errorf("Expected null tags to be set to null, got %+v", null.Tags)
+	}
+	if value.Tags.Null() || value.Tags.Value == nil || len(*value.Tags.Value) != 1 {
+		t.Errorf("Expected tags [a], got %+v", value.Tags)
+	}
+
+	_, remove := null.Changes()
+	if len(remove) != 1 || remove[0] != "tags" {
+		t.Errorf("Expected null tags to be removed, got %v", remove)
+	}
+}
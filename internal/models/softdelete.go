@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// StatusDeleted is the status of a soft-deleted item. It is set by deletes,
+// never accepted from clients.
+const StatusDeleted = "deleted"
+
+// IsDeleted reports whether the item has been soft-deleted
+func (i *Item) IsDeleted() bool {
+	return i.DeletedAt != nil
+}
+
+// SoftDelete marks the item deleted at now, keeping its status for Restore
+func (i *Item) SoftDelete(now time.Time) {
+	i.PreviousStatus = i.Status
+	i.Status = StatusDeleted
+	i.DeletedAt = &now
+	i.UpdatedAt = now
+	i.Generation++
+}
+
+// Restore undoes SoftDelete, returning the item to its previous status or
+// to active when that is unknown
+func (i *Item) Restore(now time.Time) {
+	i.Status = i.PreviousStatus
+	if i.Status == "" {
+		i.Status = "active"
+	}
+	i.PreviousStatus = ""
+	i.DeletedAt = nil
+	i.UpdatedAt = now
+	i.Generation++
+}
+
+// ExcludeDeleted returns the items that are not soft-deleted, preserving
+// their order
+func ExcludeDeleted(items []Item) []Item {
+	kept := items[:0:0]
+	for i := range items {
+		if !items[i].IsDeleted() {
+			kept = append(kept, items[i])
+		}
+	}
+	return kept
+}
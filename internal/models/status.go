@@ -0,0 +1,65 @@
+package models
+
+import (
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// defaultStatuses are the valid statuses when ITEM_STATUSES is not set
+var defaultStatuses = []string{"active", "inactive", "pending"}
+
+// statusPattern is what a configured status must look like
+var statusPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// validStatuses are the statuses items may have, loaded from ITEM_STATUSES
+// at startup. Every validation of a status checks against them.
+var validStatuses = statusesFromEnv()
+
+// statusesFromEnv reads the comma-separated ITEM_STATUSES, falling back to
+// defaultStatuses when it is unset or invalid
+func statusesFromEnv() []string {
+	value := os.Getenv("ITEM_STATUSES")
+	if value == "" {
+		return defaultStatuses
+	}
+	var statuses []string
+	for _, status := range strings.Split(value, ",") {
+		status = strings.TrimSpace(status)
+		if !statusPattern.MatchString(status) {
+			log.Printf("Ignoring invalid ITEM_STATUSES %q, statuses must be 1-32 lowercase letters, digits, '-' or '_'", value)
+			return defaultStatuses
+		}
+		if !slices.Contains(statuses, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// invalidStatusError is the type of ErrInvalidStatus, whose message lists
+// the configured statuses
+type invalidStatusError struct{}
+
+func (invalidStatusError) Error() string {
+	return "status must be one of: " + strings.Join(validStatuses, ", ")
+}
+
+// ErrInvalidStatus is returned for a status that isn't configured
+var ErrInvalidStatus error = invalidStatusError{}
+
+// IsValidStatus checks if the status is one of the allowed values
+func IsValidStatus(status string) bool {
+	return slices.Contains(validStatuses, status)
+}
+
+// DefaultStatus is the status new items start with: active, or the first
+// configured status when active isn't one of them
+func DefaultStatus() string {
+	if IsValidStatus("active") {
+		return "active"
+	}
+	return validStatuses[0]
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// configureStatuses loads validStatuses from ITEM_STATUSES set to value,
+// restoring them when the test ends
+func configureStatuses(t *testing.T, value string) {
+	t.Helper()
+	previous := validStatuses
+	t.Setenv("ITEM_STATUSES", value)
+	validStatuses = statusesFromEnv()
+	t.Cleanup(func() { validStatuses = previous })
+}
+
+func TestStatuses_Default(t *testing.T) {
+	configureStatuses(t, "")
+
+	for _, status := range []string{"active", "inactive", "pending"} {
+		if !IsValidStatus(status) {
+			t.Errorf("Expected %q to be valid by default", status)
+		}
+	}
+	if IsValidStatus("archived") {
+		t.Error("Expected archived to be invalid by default")
+	}
+	if got := ErrInvalidStatus.Error(); got != "status must be one of: active, inactive, pending" {
+		t.Errorf("Unexpected default error message %q", got)
+	}
+}
+
+func TestStatuses_Configured(t *testing.T) {
+	configureStatuses(t, "draft, active ,archived,draft")
+
+	if !slices.Equal(validStatuses, []string{"draft", "active", "archived"}) {
+		t.Errorf("Expected trimmed, deduplicated statuses, got %v", validStatuses)
+	}
+	if got := ErrInvalidStatus.Error(); got != "status must be one of: draft, active, archived" {
+		t.Errorf("Expected the error to list the configured statuses, got %q", got)
+	}
+
+	archived, pending := "archived", "pending"
+	tests := []struct {
+		name     string
+		validate func() error
+		wantErr  bool
+	}{
+		{"update accepts configured", (&UpdateItemRequest{Status: "archived"}).Validate, false},
+		{"update rejects unconfigured", (&UpdateItemRequest{Status: "pending"}).Validate, true},
+		{"patch accepts configured", (&PatchItemRequest{Status: &archived}).Validate, false},
+		{"patch rejects unconfigured", (&PatchItemRequest{Status: &pending}).Validate, true},
+		{"item accepts configured", (&Item{Name: "Item", Description: "Description", Status: "draft"}).Validate, false},
+		{"item rejects unconfigured", (&Item{Name: "Item", Description: "Description", Status: "inactive"}).Validate, true},
+		{"filter rejects unconfigured", (&ItemFilter{Status: "inactive"}).Validate, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidStatus) {
+				t.Errorf("Expected ErrInvalidStatus, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStatuses_NewItemDefault(t *testing.T) {
+	configureStatuses(t, "draft,active")
+	if got := NewItem("Item", "Description").Status; got != "active" {
+		t.Errorf("Expected new items to start active while it is configured, got %q", got)
+	}
+
+	configureStatuses(t, "draft,published")
+	item := (&CreateItemRequest{Name: "Item", Description: "Description"}).NewItem()
+	if item.Status != "draft" {
+		t.Errorf("Expected new items to start in the first configured status, got %q", item.Status)
+	}
+	if err := item.Validate(); err != nil {
+		t.Errorf("Expected the new item to validate, got %v", err)
+	}
+}
+
+func TestStatuses_InvalidConfigurationFallsBack(t *testing.T) {
+	configureStatuses(t, "active,Not Valid")
+
+	if !slices.Equal(validStatuses, defaultStatuses) {
+		t.Errorf("Expected the default statuses, got %v", validStatuses)
+	}
+}
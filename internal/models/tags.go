@@ -0,0 +1,164 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Tag limits
+const (
+	MaxTagsPerItem = 20
+	MaxTagLength   = 50
+)
+
+// Tag validation errors
+var (
+	ErrInvalidTag   = errors.New("tags must be 1-50 characters of lowercase letters, digits, '-', '_' or ':'")
+	ErrTagTooLong   = errors.New("a tag cannot exceed 50 characters")
+	ErrTooManyTags  = errors.New("an item cannot have more than 20 tags")
+	ErrDuplicateTag = errors.New("tags must not contain duplicates")
+)
+
+// ValidateTag checks a single tag against the tag rules
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return ErrInvalidTag
+	}
+	if len(tag) > MaxTagLength {
+		return ErrTagTooLong
+	}
+	for _, c := range tag {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == ':') {
+			return ErrInvalidTag
+		}
+	}
+	return nil
+}
+
+// ValidateTags checks every tag, the number of tags and that no tag
+// appears twice
+func ValidateTags(tags []string) error {
+	if len(tags) > MaxTagsPerItem {
+		return ErrTooManyTags
+	}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+		if seen[tag] {
+			return ErrDuplicateTag
+		}
+		seen[tag] = true
+	}
+	return nil
+}
+
+// prepareTags trims and lowercases client-supplied tags, sorts them and
+// checks them against the tag rules. Unlike NormalizeTags it rejects
+// duplicates rather than dropping them. A nil slice stays nil so updates
+// can tell "unchanged" from "cleared".
+func prepareTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	prepared := make([]string, len(tags))
+	for i, tag := range tags {
+		prepared[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+	sort.Strings(prepared)
+	if err := ValidateTags(prepared); err != nil {
+		return nil, err
+	}
+	return prepared, nil
+}
+
+// NormalizeTags lowercases and trims tags, dropping duplicates and
+// returning them sorted
+func NormalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// HasTag checks whether the item carries the tag
+func (i *Item) HasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// BulkTagRequest represents the request payload for tagging all items
+// matching a filter
+type BulkTagRequest struct {
+	Filter     ItemFilter `json:"filter"`
+	AddTags    []string   `json:"add_tags,omitempty"`
+	RemoveTags []string   `json:"remove_tags,omitempty"`
+	// DryRun reports the matching items without changing them
+	DryRun bool `json:"dry_run,omitempty"`
+	// Cursor continues a previous call that reported more items to process
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Bulk tag validation errors
+var (
+	ErrNoTagChanges    = errors.New("add_tags or remove_tags must not be empty")
+	ErrConflictingTags = errors.New("a tag cannot be both added and removed")
+)
+
+// Validate validates and normalizes a BulkTagRequest
+func (r *BulkTagRequest) Validate() error {
+	if err := r.Filter.Validate(); err != nil {
+		return err
+	}
+	r.AddTags = NormalizeTags(r.AddTags)
+	r.RemoveTags = NormalizeTags(r.RemoveTags)
+	if len(r.AddTags) == 0 && len(r.RemoveTags) == 0 {
+		return ErrNoTagChanges
+	}
+	if err := ValidateTags(r.AddTags); err != nil {
+		return err
+	}
+	if err := ValidateTags(r.RemoveTags); err != nil {
+		return err
+	}
+	for _, tag := range r.AddTags {
+		for _, removed := range r.RemoveTags {
+			if tag == removed {
+				return ErrConflictingTags
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyTagChanges adds and removes tags, keeping them sorted and unique
+func (i *Item) ApplyTagChanges(add, remove []string) {
+	tags := append(append([]string{}, i.Tags...), add...)
+	removed := map[string]bool{}
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+	kept := tags[:0]
+	for _, tag := range NormalizeTags(tags) {
+		if !removed[tag] {
+			kept = append(kept, tag)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	i.Tags = kept
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCreateItemRequest_Tags(t *testing.T) {
+	tooMany := make([]string, MaxTagsPerItem+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag-%d", i)
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+		err      error
+	}{
+		{name: "Trimmed, lowercased and sorted", tags: []string{" Sale", "featured "}, expected: []string{"featured", "sale"}},
+		{name: "Omitted", tags: nil, expected: nil},
+		{name: "At the limit", tags: tooMany[:MaxTagsPerItem], expected: nil},
+		{name: "Duplicate", tags: []string{"sale", "featured", "sale"}, err: ErrDuplicateTag},
+		{name: "Duplicate after trimming", tags: []string{"sale", " SALE "}, err: ErrDuplicateTag},
+		{name: "Empty", tags: []string{"  "}, err: ErrInvalidTag},
+		{name: "Too long", tags: []string{strings.Repeat("a", MaxTagLength+1)}, err: ErrTagTooLong},
+		{name: "Longest allowed", tags: []string{strings.Repeat("a", MaxTagLength)}, expected: []string{strings.Repeat("a", MaxTagLength)}},
+		{name: "Too many", tags: tooMany, err: ErrTooManyTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CreateItemRequest{Name: "Item", Description: "Description", Tags: tt.tags}
+			err := req.Validate()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if err == nil && tt.expected != nil && !reflect.DeepEqual(req.Tags, tt.expected) {
+				t.Errorf("Expected tags %v, got %v", tt.expected, req.Tags)
+			}
+		})
+	}
+}
+
+func TestUpdateItemRequest_TagsKeepNilAndEmptyApart(t *testing.T) {
+	unchanged := &UpdateItemRequest{Name: "Renamed"}
+	if err := unchanged.Validate(); err != nil || unchanged.Tags != nil {
+		t.Errorf("Expected omitted tags to stay nil, got %v (%v)", unchanged.Tags, err)
+	}
+
+	cleared := &UpdateItemRequest{Tags: []string{}}
+	if err := cleared.Validate(); err != nil || cleared.Tags == nil || len(cleared.Tags) != 0 {
+		t.Errorf("Expected an empty tag list to stay empty, got %v (%v)", cleared.Tags, err)
+	}
+
+	item := NewItem("Item", "Description")
+	item.Tags = []string{"sale"}
+	item.UpdateFields(unchanged)
+	if !reflect.DeepEqual(item.Tags, []string{"sale"}) {
+		t.Errorf("Expected tags unchanged, got %v", item.Tags)
+	}
+	item.UpdateFields(cleared)
+	if item.Tags != nil {
+		t.Errorf("Expected tags cleared, got %v", item.Tags)
+	}
+}
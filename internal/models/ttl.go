@@ -0,0 +1,39 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxTTL is the furthest in the future an item may be set to expire
+const MaxTTL = 365 * 24 * time.Hour
+
+// ErrInvalidTTL is returned for a ttl_seconds outside 1 second to MaxTTL
+var ErrInvalidTTL = errors.New("ttl_seconds must be between 1 and 31536000 (one year)")
+
+// ErrInvalidExpiresAt is returned for a patched expires_at that isn't in the
+// next MaxTTL
+var ErrInvalidExpiresAt = errors.New("expires_at must be in the future and at most one year away")
+
+// validateExpiresAt checks an expiry set directly rather than by ttl_seconds
+func validateExpiresAt(expires, now time.Time) error {
+	if !expires.After(now) || expires.Sub(now) > MaxTTL {
+		return ErrInvalidExpiresAt
+	}
+	return nil
+}
+
+// validateTTL checks an optional ttl_seconds
+func validateTTL(seconds *int64) error {
+	if seconds != nil && (*seconds <= 0 || *seconds > int64(MaxTTL/time.Second)) {
+		return ErrInvalidTTL
+	}
+	return nil
+}
+
+// expiresAt returns the expiry seconds after from. It is truncated to whole
+// seconds, since that is all the stored epoch keeps.
+func expiresAt(from time.Time, seconds int64) *time.Time {
+	expires := from.Add(time.Duration(seconds) * time.Second).Truncate(time.Second)
+	return &expires
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCreateItemRequest_TTLSeconds(t *testing.T) {
+	ptr := func(n int64) *int64 { return &n }
+	tests := []struct {
+		name string
+		ttl  *int64
+		err  error
+	}{
+		{name: "Omitted", ttl: nil},
+		{name: "One hour", ttl: ptr(3600)},
+		{name: "At the horizon", ttl: ptr(365 * 24 * 3600)},
+		{name: "Zero", ttl: ptr(0), err: ErrInvalidTTL},
+		{name: "Negative", ttl: ptr(-1), err: ErrInvalidTTL},
+		{name: "Beyond the horizon", ttl: ptr(365*24*3600 + 1), err: ErrInvalidTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CreateItemRequest{Name: "Item", Description: "Description", TTLSeconds: tt.ttl}
+			if err := req.Validate(); !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if tt.err != nil {
+				return
+			}
+			item := req.NewItem()
+			if (item.ExpiresAt != nil) != (tt.ttl != nil) {
+				t.Errorf("Expected expires_at set only with ttl_seconds, got %v", item.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestPatchItemRequest_ExpiresAt(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		expires time.Time
+		err     error
+	}{
+		{name: "Tomorrow", expires: now.Add(24 * time.Hour)},
+		{name: "In the past", expires: now.Add(-time.Minute), err: ErrInvalidExpiresAt},
+		{name: "Beyond the horizon", expires: now.Add(MaxTTL + time.Hour), err: ErrInvalidExpiresAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &PatchItemRequest{ExpiresAt: Nullable[time.Time]{Set: true, Value: &tt.expires}}
+			if err := req.Validate(); !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidVisibilityWindow is returned when an item's visibility window ends before it starts
+var ErrInvalidVisibilityWindow = errors.New("visible_from cannot be after visible_until")
+
+// validateVisibilityWindow checks that a visibility window, whose ends are
+// both optional, doesn't end before it starts
+func validateVisibilityWindow(from, until *time.Time) error {
+	if from != nil && until != nil && from.After(*until) {
+		return ErrInvalidVisibilityWindow
+	}
+	return nil
+}
+
+// IsVisibleAt reports whether the item is inside its visibility window at
+// now. Both ends of the window are inclusive and either may be unset.
+func (i *Item) IsVisibleAt(now time.Time) bool {
+	if i.VisibleFrom != nil && now.Before(*i.VisibleFrom) {
+		return false
+	}
+	if i.VisibleUntil != nil && now.After(*i.VisibleUntil) {
+		return false
+	}
+	return true
+}
+
+// VisibleItems returns the items visible at now, preserving their order
+func VisibleItems(items []Item, now time.Time) []Item {
+	visible := items[:0:0]
+	for i := range items {
+		if items[i].IsVisibleAt(now) {
+			visible = append(visible, items[i])
+		}
+	}
+	return visible
+}
@@ -0,0 +1,139 @@
+// Package querylimit rejects queries that are too long, or whose tree is
+// too deep or too large, before they run, so a client can't make the server
+// do unbounded work with one pathological request. Parsers enforce the
+// limits while they build the tree through a Counter, so a hostile query is
+// abandoned as soon as it crosses one; Check applies them to a tree that
+// was already built. Both work for any query language, so GraphQL selection
+// sets and others share the same limits.
+package querylimit
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+// Defaults used when QUERY_MAX_DEPTH, QUERY_MAX_COMPLEXITY or
+// QUERY_MAX_LENGTH are not set. They leave plenty of room for the API's
+// schema, whose deepest useful query is a few levels.
+const (
+	DefaultMaxDepth      = 10
+	DefaultMaxComplexity = 200
+	DefaultMaxLength     = 16 << 10
+)
+
+// ErrTooComplex is wrapped by the errors Check returns
+var ErrTooComplex = errors.New("query too complex")
+
+// Limits bound a query's tree. Zero disables a limit.
+type Limits struct {
+	// MaxDepth is how deeply nodes may nest; a query of only root nodes
+	// has depth 1
+	MaxDepth int
+	// MaxComplexity is how many nodes the query may have in total
+	MaxComplexity int
+	// MaxLength is how many bytes the query text may have
+	MaxLength int
+}
+
+// LimitsFromEnv reads QUERY_MAX_DEPTH, QUERY_MAX_COMPLEXITY and
+// QUERY_MAX_LENGTH, where 0 disables the limit
+func LimitsFromEnv() Limits {
+	return Limits{
+		MaxDepth:      envLimit("QUERY_MAX_DEPTH", DefaultMaxDepth),
+		MaxComplexity: envLimit("QUERY_MAX_COMPLEXITY", DefaultMaxComplexity),
+		MaxLength:     envLimit("QUERY_MAX_LENGTH", DefaultMaxLength),
+	}
+}
+
+func envLimit(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Ignoring invalid %s %q, using default %d", name, value, def)
+		return def
+	}
+	return n
+}
+
+// Check walks the tree under roots, counting each node's children with
+// children, and returns an error wrapping ErrTooComplex when it exceeds a
+// limit. It stops as soon as a limit is exceeded, so the cost of checking
+// a hostile query is bounded by the limits too.
+func Check[N any](roots []N, children func(N) []N, limits Limits) error {
+	nodes := 0
+	var walk func(level []N, depth int) error
+	walk = func(level []N, depth int) error {
+		if len(level) == 0 {
+			return nil
+		}
+		if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+			return fmt.Errorf("%w: depth exceeds the limit of %d", ErrTooComplex, limits.MaxDepth)
+		}
+		for _, node := range level {
+			nodes++
+			if limits.MaxComplexity > 0 && nodes > limits.MaxComplexity {
+				return fmt.Errorf("%w: more than %d fields", ErrTooComplex, limits.MaxComplexity)
+			}
+			if err := walk(children(node), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(roots, 1)
+}
+
+// CheckLength returns an error wrapping ErrTooComplex when query is longer
+// than the limit. Parsers call it before reading the query at all.
+func (l Limits) CheckLength(query string) error {
+	if l.MaxLength > 0 && len(query) > l.MaxLength {
+		return fmt.Errorf("%w: longer than %d bytes", ErrTooComplex, l.MaxLength)
+	}
+	return nil
+}
+
+// Counter enforces Limits while a parser builds a query's tree. The parser
+// calls Enter and Leave around each nested level and Node for each node, and
+// gives up on the first error, so no deeper or larger tree is ever built.
+// The zero Counter enforces no limits.
+type Counter struct {
+	limits Limits
+	depth  int
+	nodes  int
+}
+
+// NewCounter returns a Counter enforcing limits
+func NewCounter(limits Limits) *Counter {
+	return &Counter{limits: limits}
+}
+
+// Enter descends one level, returning an error wrapping ErrTooComplex when
+// that is deeper than the limit
+func (c *Counter) Enter() error {
+	c.depth++
+	if c.limits.MaxDepth > 0 && c.depth > c.limits.MaxDepth {
+		return fmt.Errorf("%w: depth exceeds the limit of %d", ErrTooComplex, c.limits.MaxDepth)
+	}
+	return nil
+}
+
+// Leave returns from the level entered last
+func (c *Counter) Leave() {
+	c.depth--
+}
+
+// Node counts a node, returning an error wrapping ErrTooComplex when there
+// are more than the limit
+func (c *Counter) Node() error {
+	c.nodes++
+	if c.limits.MaxComplexity > 0 && c.nodes > c.limits.MaxComplexity {
+		return fmt.Errorf("%w: more than %d fields", ErrTooComplex, c.limits.MaxComplexity)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package querylimit
+
+import (
+	"errors"
+	"testing"
+)
+
+// node is a query tree node for the tests
+type node struct {
+	children []*node
+}
+
+func children(n *node) []*node { return n.children }
+
+// chain returns a single path of depth nodes
+func chain(depth int) []*node {
+	root := &node{}
+	current := root
+	for range depth - 1 {
+		next := &node{}
+		current.children = []*node{next}
+		current = next
+	}
+	return []*node{root}
+}
+
+// wide returns width root nodes
+func wide(width int) []*node {
+	roots := make([]*node, width)
+	for i := range roots {
+		roots[i] = &node{}
+	}
+	return roots
+}
+
+func TestCheck(t *testing.T) {
+	limits := Limits{MaxDepth: 3, MaxComplexity: 5}
+	tests := []struct {
+		name    string
+		roots   []*node
+		limits  Limits
+		wantErr bool
+	}{
+		{name: "Empty", roots: nil, limits: limits},
+		{name: "At the depth limit", roots: chain(3), limits: limits},
+		{name: "Too deep", roots: chain(4), limits: limits, wantErr: true},
+		{name: "At the complexity limit", roots: wide(5), limits: limits},
+		{name: "Too many nodes", roots: wide(6), limits: limits, wantErr: true},
+		{name: "Nested nodes count", roots: []*node{{children: wide(5)}}, limits: limits, wantErr: true},
+		{name: "Limits disabled", roots: append(chain(50), wide(500)...), limits: Limits{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.roots, children, tt.limits)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Expected error %t, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrTooComplex) {
+				t.Errorf("Expected ErrTooComplex, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCounter(t *testing.T) {
+	counter := NewCounter(Limits{MaxDepth: 2, MaxComplexity: 3})
+
+	for range 2 {
+		if err := counter.Enter(); err != nil {
+			t.Fatalf("Expected depth 2 to be allowed, got %v", err)
+		}
+	}
+	if err := counter.Enter(); !errors.Is(err, ErrTooComplex) {
+		t.Errorf("Expected depth 3 to be rejected, got %v", err)
+	}
+	counter.Leave()
+	counter.Leave()
+	if err := counter.Enter(); err != nil {
+		t.Errorf("Expected leaving to free the level, got %v", err)
+	}
+
+	for range 3 {
+		if err := counter.Node(); err != nil {
+			t.Fatalf("Expected 3 nodes to be allowed, got %v", err)
+		}
+	}
+	if err := counter.Node(); !errors.Is(err, ErrTooComplex) {
+		t.Errorf("Expected a fourth node to be rejected, got %v", err)
+	}
+}
+
+func TestCheckLength(t *testing.T) {
+	limits := Limits{MaxLength: 5}
+	if err := limits.CheckLength("12345"); err != nil {
+		t.Errorf("Expected a query at the limit to be allowed, got %v", err)
+	}
+	if err := limits.CheckLength("123456"); !errors.Is(err, ErrTooComplex) {
+		t.Errorf("Expected a longer query to be rejected, got %v", err)
+	}
+	if err := (Limits{}).CheckLength("123456"); err != nil {
+		t.Errorf("Expected no limit to allow any length, got %v", err)
+	}
+}
+
+func TestLimitsFromEnv(t *testing.T) {
+	t.Setenv("QUERY_MAX_DEPTH", "")
+	t.Setenv("QUERY_MAX_COMPLEXITY", "")
+	t.Setenv("QUERY_MAX_LENGTH", "")
+	if limits := LimitsFromEnv(); limits != (Limits{MaxDepth: DefaultMaxDepth, MaxComplexity: DefaultMaxComplexity, MaxLength: DefaultMaxLength}) {
+		t.Errorf("Expected the defaults, got %+v", limits)
+	}
+
+	t.Setenv("QUERY_MAX_DEPTH", "0")
+	t.Setenv("QUERY_MAX_COMPLEXITY", "-1")
+	t.Setenv("QUERY_MAX_LENGTH", "100")
+	if limits := LimitsFromEnv(); limits != (Limits{MaxDepth: 0, MaxComplexity: DefaultMaxComplexity, MaxLength: 100}) {
+		t.Errorf("Expected depth disabled, the default complexity and a 100 byte length, got %+v", limits)
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// AttributeNames maps model attribute names (the dynamodbav tags) to the
+// attribute names used in the table, so the repository can work against an
+// existing table whose schema differs from the Go model. Unmapped attributes
+// keep their model name; a nil map stores everything under the model names.
+type AttributeNames map[string]string
+
+// ParseAttributeNames parses a mapping of the form "name=item_name,id=pk"
+func ParseAttributeNames(value string) (AttributeNames, error) {
+	names := AttributeNames{}
+	targets := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		model, storage, ok := strings.Cut(pair, "=")
+		model, storage = strings.TrimSpace(model), strings.TrimSpace(storage)
+		if !ok || model == "" || storage == "" {
+			return nil, fmt.Errorf("invalid attribute mapping %q, expected model=storage", pair)
+		}
+		if other, taken := targets[storage]; taken {
+			return nil, fmt.Errorf("attributes %q and %q both map to %q", other, model, storage)
+		}
+		names[model] = storage
+		targets[storage] = model
+	}
+	return names, nil
+}
+
+// Storage returns the table attribute name for a model attribute
+func (n AttributeNames) Storage(model string) string {
+	if storage, ok := n[model]; ok {
+		return storage
+	}
+	return model
+}
+
+// placeholders returns expression attribute names ("#model" -> storage name)
+// for the given model attributes. Expressions always refer to attributes
+// through these placeholders, which also covers reserved words.
+func (n AttributeNames) placeholders(models ...string) map[string]string {
+	names := make(map[string]string, len(models))
+	for _, model := range models {
+		names["#"+model] = n.Storage(model)
+	}
+	return names
+}
+
+// key returns the primary key of an item in storage names
+func (n AttributeNames) key(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		n.Storage("id"): &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+// toStorage renames a marshaled item's attributes to their table names
+func (n AttributeNames) toStorage(av map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if len(n) == 0 {
+		return av
+	}
+	result := make(map[string]types.AttributeValue, len(av))
+	for name, value := range av {
+		result[n.Storage(name)] = value
+	}
+	return result
+}
+
+// fromStorage renames a stored item's attributes back to their model names
+func (n AttributeNames) fromStorage(av map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if len(n) == 0 {
+		return av
+	}
+	reverse := make(map[string]string, len(n))
+	for model, storage := range n {
+		reverse[storage] = model
+	}
+	result := make(map[string]types.AttributeValue, len(av))
+	for name, value := range av {
+		if model, ok := reverse[name]; ok {
+			name = model
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// fromStorageList renames the attributes of each stored item
+func (n AttributeNames) fromStorageList(items []map[string]types.AttributeValue) []map[string]types.AttributeValue {
+	if len(n) == 0 {
+		return items
+	}
+	result := make([]map[string]types.AttributeValue, len(items))
+	for i, av := range items {
+		result[i] = n.fromStorage(av)
+	}
+	return result
+}
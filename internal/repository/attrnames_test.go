@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// newRemappedTableMock returns a mock table keyed by "pk" that stores
+// whatever attributes it is given
+func newRemappedTableMock(t *testing.T) (*mockDynamoDBClient, map[string]map[string]types.AttributeValue) {
+	table := map[string]map[string]types.AttributeValue{}
+	keyOf := func(key map[string]types.AttributeValue) string {
+		pk, ok := key["pk"].(*types.AttributeValueMemberS)
+		if !ok {
+			t.Fatalf("Expected key attribute 'pk', got %v", key)
+		}
+		return pk.Value
+	}
+
+	client := &mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			table[keyOf(params.Item)] = params.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		GetItemFn: func(ctx context.Context, params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: table[keyOf(params.Key)]}, nil
+		},
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			output := &dynamodb.ScanOutput{}
+			for _, av := range table {
+				output.Items = append(output.Items, av)
+			}
+			return output, nil
+		},
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			stored := table[keyOf(params.Key)]
+			// Apply the name update through the expression attribute names
+			if storageName, ok := params.ExpressionAttributeNames["#name"]; ok {
+				stored[storageName] = params.ExpressionAttributeValues[":name"]
+			}
+			return &dynamodb.UpdateItemOutput{Attributes: stored}, nil
+		},
+	}
+	return client, table
+}
+
+func TestAttributeNames_RoundTrip(t *testing.T) {
+	client, table := newRemappedTableMock(t)
+	repo := NewDynamoDBRepository(client, "legacy-items")
+	repo.attrNames = AttributeNames{"id": "pk", "name": "item_name", "description": "item_desc"}
+
+	item := models.NewItem("Widget", "A remapped item")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+
+	stored := table[item.ID]
+	for _, name := range []string{"pk", "item_name", "item_desc", "status"} {
+		if _, ok := stored[name]; !ok {
+			t.Errorf("Expected stored attribute %q, got %v", name, stored)
+		}
+	}
+	for _, name := range []string{"id", "name", "description"} {
+		if _, ok := stored[name]; ok {
+			t.Errorf("Expected model attribute %q to be stored under its mapped name", name)
+		}
+	}
+
+	got, err := repo.GetItem(context.Background(), item.ID, nil)
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if got.ID != item.ID || got.Name != "Widget" || got.Description != "A remapped item" {
+		t.Errorf("Expected round-tripped item, got %+v", got)
+	}
+
+	list, err := repo.ListItems(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "Widget" {
+		t.Errorf("Expected listed item 'Widget', got %+v", list.Items)
+	}
+
+	updated, err := repo.UpdateItem(context.Background(), item.ID, &models.UpdateItemRequest{Name: "Gadget"})
+	if err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+	if updated.Name != "Gadget" {
+		t.Errorf("Expected updated name 'Gadget', got '%s'", updated.Name)
+	}
+	if name, ok := stored["item_name"].(*types.AttributeValueMemberS); !ok || name.Value != "Gadget" {
+		t.Errorf("Expected update to write item_name, got %v", stored["item_name"])
+	}
+}
+
+func TestAttributeNames_ExpressionsUsePlaceholders(t *testing.T) {
+	var captured *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			captured = params
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "legacy-items")
+	repo.attrNames = AttributeNames{"id": "pk", "updated_at": "modified", "description": "item_desc"}
+
+	if _, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{Description: "New"}); err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+
+	expressions := *captured.UpdateExpression + " " + *captured.ConditionExpression
+	for _, raw := range []string{" updated_at =", " description =", "attribute_exists(id)"} {
+		if strings.Contains(expressions, raw) {
+			t.Errorf("Expected expressions to use placeholders, found %q in %q", raw, expressions)
+		}
+	}
+	for placeholder, expected := range map[string]string{"#id": "pk", "#updated_at": "modified", "#description": "item_desc"} {
+		if got := captured.ExpressionAttributeNames[placeholder]; got != expected {
+			t.Errorf("Expected %s to map to %q, got %q", placeholder, expected, got)
+		}
+	}
+}
+
+func TestParseAttributeNames(t *testing.T) {
+	names, err := ParseAttributeNames("name=item_name, description=item_desc")
+	if err != nil {
+		t.Fatalf("Expected mapping to parse, got %v", err)
+	}
+	if names.Storage("name") != "item_name" || names.Storage("status") != "status" {
+		t.Errorf("Unexpected mapping %v", names)
+	}
+
+	if _, err := ParseAttributeNames("name=label,description=label"); err == nil {
+		t.Error("Expected duplicate storage names to be rejected")
+	}
+	if _, err := ParseAttributeNames("name"); err == nil {
+		t.Error("Expected malformed pair to be rejected")
+	}
+}
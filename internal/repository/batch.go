@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"fis-playground/internal/models"
+)
+
+// BatchCreateItems creates items with BatchWriteItem in chunks of
+// batchWriteSize and returns one error per item, nil for those created.
+// The returned error is only set when the batch as a whole is rejected.
+//
+// Batch puts can't be conditional, so unlike CreateItem an existing item
+// with the same ID is overwritten; IDs are therefore always generated.
+// Requests DynamoDB leaves unprocessed are retried with backoff, and items
+// still unprocessed afterwards are reported as failed.
+func (r *DynamoDBRepository) BatchCreateItems(ctx context.Context, items []*models.Item) ([]error, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no items to create", ErrInvalidInput)
+	}
+
+	errs := make([]error, len(items))
+	requests := make([]types.WriteRequest, 0, len(items))
+	indexes := make(map[string]int, len(items))
+	releaseNames := make(map[string]func(), len(items))
+	for i, item := range items {
+		item.ID = uuid.New().String()
+		if err := validate(ctx, item); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		av, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to marshal item: %w", err)
+			continue
+		}
+		av = r.attrNames.toStorage(av)
+		addListIndexAttributes(av, item)
+		addNameIndexAttributes(av, item)
+		addDateIndexAttributes(av, item, r.dateLocation)
+
+		// Claim the name, when names are unique, and a slot under the item
+		// cap before writing
+		releaseName, err := r.claimName(ctx, item.ID, item.Name, item.Category)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if r.maxItems > 0 {
+			if err := r.reserveItemSlot(ctx); err != nil {
+				releaseName()
+				errs[i] = err
+				continue
+			}
+		}
+
+		indexes[item.ID] = i
+		releaseNames[item.ID] = releaseName
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: av},
+		})
+	}
+
+	for start := 0; start < len(requests); start += batchWriteSize {
+		end := min(start+batchWriteSize, len(requests))
+		failed, err := r.batchWrite(ctx, requests[start:end])
+		if err != nil {
+			failed = requests[start:end]
+		} else {
+			err = fmt.Errorf("%w: item still unprocessed after retries", ErrOperationFailed)
+		}
+
+		for _, request := range failed {
+			id, _ := request.PutRequest.Item[r.attrNames.Storage("id")].(*types.AttributeValueMemberS)
+			if id == nil {
+				continue
+			}
+			errs[indexes[id.Value]] = err
+			releaseNames[id.Value]()
+			if r.maxItems > 0 {
+				r.releaseItemSlot(ctx)
+			}
+		}
+	}
+
+	if slices.Contains(errs, nil) {
+		r.touchCollection(ctx)
+	}
+	return errs, nil
+}
+
+// BatchCreateItems creates each item in turn, returning one error per item
+func (r *MemoryRepository) BatchCreateItems(ctx context.Context, items []*models.Item) ([]error, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no items to create", ErrInvalidInput)
+	}
+
+	errs := make([]error, len(items))
+	for i, item := range items {
+		item.ID = ""
+		errs[i] = r.CreateItem(ctx, item)
+	}
+	return errs, nil
+}
+
+// BatchCreateItems creates the items and drops any stale entries for their IDs
+func (c *CachingRepository) BatchCreateItems(ctx context.Context, items []*models.Item) ([]error, error) {
+	errs, err := c.inner.BatchCreateItems(ctx, items)
+	for _, item := range items {
+		if item.ID != "" {
+			c.Invalidate(item.ID)
+		}
+	}
+	return errs, err
+}
+
+// batchGetSize is DynamoDB's maximum keys per BatchGetItem
+const batchGetSize = 100
+
+// BatchGetItems fetches the items with the given IDs using BatchGetItem in
+// chunks of batchGetSize. Duplicate IDs are fetched once and IDs that don't
+// exist are left out, so the result can be shorter than ids; items are
+// returned in the order their IDs were first given.
+func (r *DynamoDBRepository) BatchGetItems(ctx context.Context, ids []string) ([]models.Item, error) {
+	ids, err := uniqueIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]models.Item, len(ids))
+	for start := 0; start < len(ids); start += batchGetSize {
+		end := min(start+batchGetSize, len(ids))
+		keys := make([]map[string]types.AttributeValue, 0, end-start)
+		for _, id := range ids[start:end] {
+			if !isMetaItemID(id) {
+				keys = append(keys, r.attrNames.key(id))
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		rows, err := r.batchGet(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		var items []models.Item
+		if err := attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(rows), &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+		}
+		for _, item := range items {
+			found[item.ID] = item
+		}
+	}
+
+	items := make([]models.Item, 0, len(found))
+	for _, id := range ids {
+		if item, ok := found[id]; ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// batchGet reads up to batchGetSize keys, retrying any DynamoDB reports as
+// unprocessed while the request's retry budget allows
+func (r *DynamoDBRepository) batchGet(ctx context.Context, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	pending := map[string]types.KeysAndAttributes{r.tableName: {Keys: keys}}
+	var rows []map[string]types.AttributeValue
+	for attempt := 0; attempt <= batchWriteMaxRetries; attempt++ {
+		if attempt > 0 {
+			if !RetryBudgetFromContext(ctx).take() {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt*attempt) * 50 * time.Millisecond):
+			}
+		}
+
+		result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: pending,
+		})
+		if err != nil {
+			return nil, HandleDynamoDBError(err)
+		}
+		rows = append(rows, result.Responses[r.tableName]...)
+		if len(result.UnprocessedKeys) == 0 {
+			return rows, nil
+		}
+		pending = result.UnprocessedKeys
+	}
+
+	return nil, fmt.Errorf("%w: %d keys still unprocessed after retries", ErrOperationFailed, len(pending[r.tableName].Keys))
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence, and rejects
+// empty ones
+func uniqueIDs(ids []string) ([]string, error) {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
+
+// BatchGetItems returns the stored items with the given IDs, in the order
+// their IDs were first given
+func (r *MemoryRepository) BatchGetItems(ctx context.Context, ids []string) ([]models.Item, error) {
+	ids, err := uniqueIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]models.Item, 0, len(ids))
+	for _, id := range ids {
+		if item, ok := r.items[id]; ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// BatchGetItems is not cached
+func (c *CachingRepository) BatchGetItems(ctx context.Context, ids []string) ([]models.Item, error) {
+	return c.inner.BatchGetItems(ctx, ids)
+}
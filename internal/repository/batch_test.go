@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestBatchCreateItems_ChunksWrites(t *testing.T) {
+	var batches []int
+	client := &mockDynamoDBClient{
+		BatchWriteFn: func(ctx context.Context, params *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			batches = append(batches, len(params.RequestItems["items"]))
+			return &dynamodb.BatchWriteItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	items := make([]*models.Item, 30)
+	for i := range items {
+		items[i] = models.NewItem("Item", "Description")
+	}
+	items[3].Name = ""
+
+	errs, err := repo.BatchCreateItems(context.Background(), items)
+	if err != nil {
+		t.Fatalf("Expected batch to be accepted, got %v", err)
+	}
+
+	if len(batches) != 2 || batches[0] != batchWriteSize || batches[1] != 4 {
+		t.Errorf("Expected batches of [25 4], got %v", batches)
+	}
+	for i, itemErr := range errs {
+		if i == 3 {
+			if !IsValidationError(itemErr) {
+				t.Errorf("Expected item 3 to fail validation, got %v", itemErr)
+			}
+			continue
+		}
+		if itemErr != nil || items[i].ID == "" {
+			t.Errorf("Expected item %d to be created with an ID, got %v", i, itemErr)
+		}
+	}
+}
+
+func TestBatchCreateItems_RetriesUnprocessed(t *testing.T) {
+	calls := 0
+	var stuckID string
+	client := &mockDynamoDBClient{
+		BatchWriteFn: func(ctx context.Context, params *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			calls++
+			requests := params.RequestItems["items"]
+			if calls == 1 {
+				// Leave the first two unprocessed; the first of them never succeeds
+				stuckID = requests[0].PutRequest.Item["id"].(*types.AttributeValueMemberS).Value
+				return &dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{"items": requests[:2]},
+				}, nil
+			}
+			for _, req := range requests {
+				if req.PutRequest.Item["id"].(*types.AttributeValueMemberS).Value == stuckID {
+					return &dynamodb.BatchWriteItemOutput{
+						UnprocessedItems: map[string][]types.WriteRequest{"items": {req}},
+					}, nil
+				}
+			}
+			return &dynamodb.BatchWriteItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	items := []*models.Item{
+		models.NewItem("A", "Stuck"),
+		models.NewItem("B", "Retried"),
+		models.NewItem("C", "Written first time"),
+	}
+	errs, err := repo.BatchCreateItems(context.Background(), items)
+	if err != nil {
+		t.Fatalf("Expected batch to be accepted, got %v", err)
+	}
+
+	if calls != batchWriteMaxRetries+1 {
+		t.Errorf("Expected %d batch write calls, got %d", batchWriteMaxRetries+1, calls)
+	}
+	if !IsOperationError(errs[0]) {
+		t.Errorf("Expected the stuck item to fail, got %v", errs[0])
+	}
+	if errs[1] != nil || errs[2] != nil {
+		t.Errorf("Expected the other items to be created, got %v", errs[1:])
+	}
+}
+
+func TestBatchGetItems_ChunksAndSkipsMissing(t *testing.T) {
+	var chunks []int
+	client := &mockDynamoDBClient{
+		BatchGetFn: func(ctx context.Context, params *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+			keys := params.RequestItems["items"].Keys
+			chunks = append(chunks, len(keys))
+			// Only even-numbered items exist
+			var rows []map[string]types.AttributeValue
+			for _, key := range keys {
+				id := key["id"].(*types.AttributeValueMemberS).Value
+				var n int
+				fmt.Sscanf(id, "item-%d", &n)
+				if n%2 == 0 {
+					rows = append(rows, map[string]types.AttributeValue{"id": key["id"], "name": &types.AttributeValueMemberS{Value: id}})
+				}
+			}
+			return &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"items": rows}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	ids := make([]string, 0, 121)
+	for i := range 120 {
+		ids = append(ids, fmt.Sprintf("item-%03d", i))
+	}
+	ids = append(ids, "item-000")
+
+	items, err := repo.BatchGetItems(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("Failed to get items: %v", err)
+	}
+
+	if len(chunks) != 2 || chunks[0] != batchGetSize || chunks[1] != 20 {
+		t.Errorf("Expected chunks of [100 20], got %v", chunks)
+	}
+	if len(items) != 60 {
+		t.Fatalf("Expected 60 found items, got %d", len(items))
+	}
+	for i, item := range items {
+		if expected := fmt.Sprintf("item-%03d", i*2); item.ID != expected {
+			t.Errorf("Expected item %d to be %s, got %s", i, expected, item.ID)
+		}
+	}
+}
+
+func TestBatchGetItems_RetriesUnprocessedKeys(t *testing.T) {
+	calls := 0
+	client := &mockDynamoDBClient{
+		BatchGetFn: func(ctx context.Context, params *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+			calls++
+			keys := params.RequestItems["items"].Keys
+			output := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{
+				"items": {{"id": keys[0]["id"]}},
+			}}
+			if len(keys) > 1 {
+				output.UnprocessedKeys = map[string]types.KeysAndAttributes{"items": {Keys: keys[1:]}}
+			}
+			return output, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	items, err := repo.BatchGetItems(context.Background(), []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Failed to get items: %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 batch get calls, got %d", calls)
+	}
+	if len(items) != 3 || items[0].ID != "a" || items[1].ID != "b" || items[2].ID != "c" {
+		t.Errorf("Expected items [a b c], got %+v", items)
+	}
+}
+
+func TestBatchGetItems_RejectsEmptyID(t *testing.T) {
+	repo := NewDynamoDBRepository(&mockDynamoDBClient{}, "items")
+
+	if _, err := repo.BatchGetItems(context.Background(), []string{"a", ""}); !IsValidationError(err) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
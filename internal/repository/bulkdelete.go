@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+
+	"fis-playground/internal/models"
+)
+
+// DeleteItemsByFilter permanently deletes every item matching the filter
+// and returns how many were deleted, or with dryRun only counts them. An
+// empty filter is rejected rather than deleting the whole table.
+//
+// Matches are found with a full scan and deleted with unconditional batch
+// writes, so an item changed between the two steps to no longer match is
+// still deleted.
+func (r *DynamoDBRepository) DeleteItemsByFilter(ctx context.Context, filter models.ItemFilter, dryRun bool) (int, error) {
+	if filter.IsEmpty() {
+		return 0, fmt.Errorf("%w: a filter is required", ErrInvalidInput)
+	}
+
+	input := r.filterScanInput(filter)
+	var ids []string
+	for {
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return 0, HandleDynamoDBError(err)
+		}
+
+		var rows []struct {
+			ID string `dynamodbav:"id"`
+		}
+		if err := attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(result.Items), &rows); err != nil {
+			return 0, fmt.Errorf("failed to unmarshal items: %w", err)
+		}
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	if dryRun {
+		return len(ids), nil
+	}
+
+	deleted := 0
+	defer func() {
+		if deleted > 0 {
+			r.touchCollection(ctx)
+		}
+	}()
+	for start := 0; start < len(ids); start += batchWriteSize {
+		end := min(start+batchWriteSize, len(ids))
+		if err := r.batchDelete(ctx, ids[start:end]); err != nil {
+			return deleted, err
+		}
+		deleted += end - start
+
+		if r.maxItems > 0 {
+			for range ids[start:end] {
+				r.releaseItemSlot(ctx)
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// DeleteItemsByFilter deletes or, with dryRun, counts the items matching
+// the filter
+func (r *MemoryRepository) DeleteItemsByFilter(ctx context.Context, filter models.ItemFilter, dryRun bool) (int, error) {
+	if filter.IsEmpty() {
+		return 0, fmt.Errorf("%w: a filter is required", ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	matched := 0
+	for id, item := range r.items {
+		if !filter.Matches(&item) {
+			continue
+		}
+		matched++
+		if !dryRun {
+			delete(r.items, id)
+		}
+	}
+
+	return matched, nil
+}
+
+// DeleteItemsByFilter deletes the matching items and flushes the whole
+// cache, since the deleted IDs are not reported back
+func (c *CachingRepository) DeleteItemsByFilter(ctx context.Context, filter models.ItemFilter, dryRun bool) (int, error) {
+	deleted, err := c.inner.DeleteItemsByFilter(ctx, filter, dryRun)
+	if !dryRun {
+		c.InvalidateAll()
+	}
+	return deleted, err
+}
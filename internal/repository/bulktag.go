@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// DefaultBulkTagBatchSize is how many items one BulkTagItems call examines
+const DefaultBulkTagBatchSize = 100
+
+// BulkTagOptions describes a bulk tag change. Tags are expected to be
+// validated and normalized already.
+type BulkTagOptions struct {
+	Filter     models.ItemFilter
+	AddTags    []string
+	RemoveTags []string
+	// DryRun reports the matching items without changing them
+	DryRun bool
+	// Limit bounds the items examined per call, matching or not
+	Limit            int32
+	LastEvaluatedKey map[string]types.AttributeValue
+}
+
+// BulkTagResult reports one batch of a bulk tag change
+type BulkTagResult struct {
+	// MatchedIDs are the items in this batch that match the filter
+	MatchedIDs []string
+	// Updated counts the matching items changed; items deleted since the
+	// scan are skipped
+	Updated          int
+	LastEvaluatedKey map[string]types.AttributeValue
+	HasMore          bool
+}
+
+// batchLimit returns the options' limit within bounds
+func (o *BulkTagOptions) batchLimit() int32 {
+	if o.Limit <= 0 || o.Limit > DefaultBulkTagBatchSize {
+		return DefaultBulkTagBatchSize
+	}
+	return o.Limit
+}
+
+// BulkTagItems adds and removes tags on the items matching the filter, one
+// batch per call. Callers continue from LastEvaluatedKey while HasMore.
+//
+// Matches are found with a scan and each is updated with its own
+// conditional write, so the change is not atomic across items: an item
+// edited between the scan and its write is still tagged even if it no
+// longer matches.
+func (r *DynamoDBRepository) BulkTagItems(ctx context.Context, options *BulkTagOptions) (*BulkTagResult, error) {
+	if options == nil || len(options.AddTags)+len(options.RemoveTags) == 0 {
+		return nil, fmt.Errorf("%w: no tag changes given", ErrInvalidInput)
+	}
+
+	input := r.filterScanInput(options.Filter)
+	input.Limit = aws.Int32(options.batchLimit())
+	input.ExclusiveStartKey = options.LastEvaluatedKey
+
+	result, err := r.client.Scan(ctx, input)
+	if err != nil {
+		return nil, HandleDynamoDBError(err)
+	}
+
+	var rows []struct {
+		ID string `dynamodbav:"id"`
+	}
+	if err := attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(result.Items), &rows); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+
+	tagged := &BulkTagResult{
+		LastEvaluatedKey: result.LastEvaluatedKey,
+		HasMore:          result.LastEvaluatedKey != nil,
+	}
+	for _, row := range rows {
+		tagged.MatchedIDs = append(tagged.MatchedIDs, row.ID)
+		if options.DryRun {
+			continue
+		}
+		updated, err := r.updateTags(ctx, row.ID, options.AddTags, options.RemoveTags)
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			tagged.Updated++
+		}
+	}
+
+	if tagged.Updated > 0 {
+		r.touchCollection(ctx)
+	}
+	return tagged, nil
+}
+
+// filterScanInput builds a scan for the IDs of the items matching filter
+func (r *DynamoDBRepository) filterScanInput(filter models.ItemFilter) *dynamodb.ScanInput {
+	filterExpression := "NOT begins_with(#id, :meta_prefix)"
+	names := r.attrNames.placeholders("id")
+	values := map[string]types.AttributeValue{
+		":meta_prefix": &types.AttributeValueMemberS{Value: metaItemPrefix},
+	}
+	if filter.Status != "" {
+		filterExpression += " AND #status = :status"
+		names["#status"] = r.attrNames.Storage("status")
+		values[":status"] = &types.AttributeValueMemberS{Value: filter.Status}
+	}
+	if filter.Category != "" {
+		filterExpression += " AND #category = :category"
+		names["#category"] = r.attrNames.Storage("category")
+		values[":category"] = &types.AttributeValueMemberS{Value: filter.Category}
+	}
+
+	return &dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String(filterExpression),
+		ProjectionExpression:      aws.String("#id"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+}
+
+// updateTags applies set ADD and DELETE updates to an item's tags, bumping
+// its generation. DynamoDB rejects ADD and DELETE on the same attribute in
+// one expression, so when both are given the removal is a second write. It
+// reports false if the item no longer exists.
+func (r *DynamoDBRepository) updateTags(ctx context.Context, id string, add, remove []string) (bool, error) {
+	timestampAV, err := attributevalue.Marshal(time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal timestamp: %w", err)
+	}
+
+	update := func(expression string, names map[string]string, values map[string]types.AttributeValue) (bool, error) {
+		_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(r.tableName),
+			Key:                       r.attrNames.key(id),
+			UpdateExpression:          aws.String(expression),
+			ConditionExpression:       aws.String("attribute_exists(#id)"),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		})
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) {
+			return false, nil
+		}
+		if err != nil {
+			return false, HandleDynamoDBError(err)
+		}
+		return true, nil
+	}
+
+	set := "SET #updated_at = :updated_at, #generation = if_not_exists(#generation, :zero) + :one"
+	names := r.attrNames.placeholders("id", "tags", "updated_at", "generation")
+	values := map[string]types.AttributeValue{
+		":updated_at": timestampAV,
+		":zero":       &types.AttributeValueMemberN{Value: "0"},
+		":one":        &types.AttributeValueMemberN{Value: "1"},
+	}
+	if len(add) > 0 {
+		values[":add"] = &types.AttributeValueMemberSS{Value: add}
+		if ok, err := update(set+" ADD #tags :add", names, values); !ok || err != nil || len(remove) == 0 {
+			return ok, err
+		}
+		return update("DELETE #tags :remove", r.attrNames.placeholders("id", "tags"), map[string]types.AttributeValue{
+			":remove": &types.AttributeValueMemberSS{Value: remove},
+		})
+	}
+
+	values[":remove"] = &types.AttributeValueMemberSS{Value: remove}
+	return update(set+" DELETE #tags :remove", names, values)
+}
+
+// BulkTagItems adds and removes tags on the items matching the filter, one
+// batch per call, examining items in ID order
+func (r *MemoryRepository) BulkTagItems(ctx context.Context, options *BulkTagOptions) (*BulkTagResult, error) {
+	if options == nil || len(options.AddTags)+len(options.RemoveTags) == 0 {
+		return nil, fmt.Errorf("%w: no tag changes given", ErrInvalidInput)
+	}
+
+	var startAfter string
+	if key, ok := options.LastEvaluatedKey["id"].(*types.AttributeValueMemberS); ok {
+		startAfter = key.Value
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]string, 0, len(r.items))
+	for id := range r.items {
+		if id > startAfter {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	result := &BulkTagResult{}
+	if limit := int(options.batchLimit()); len(ids) > limit {
+		ids = ids[:limit]
+		result.HasMore = true
+		result.LastEvaluatedKey = map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: ids[limit-1]},
+		}
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		item := r.items[id]
+		if !options.Filter.Matches(&item) {
+			continue
+		}
+		result.MatchedIDs = append(result.MatchedIDs, id)
+		if options.DryRun {
+			continue
+		}
+		item.ApplyTagChanges(options.AddTags, options.RemoveTags)
+		item.UpdatedAt = now
+		item.Generation++
+		r.items[id] = item
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// BulkTagItems tags the matching items and invalidates their entries. A
+// failed batch may have tagged some items before failing, so it flushes the
+// whole cache.
+func (c *CachingRepository) BulkTagItems(ctx context.Context, options *BulkTagOptions) (*BulkTagResult, error) {
+	result, err := c.inner.BulkTagItems(ctx, options)
+	if err != nil {
+		c.InvalidateAll()
+		return nil, err
+	}
+	if !options.DryRun {
+		for _, id := range result.MatchedIDs {
+			c.Invalidate(id)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestBulkTagItems_SetUpdates(t *testing.T) {
+	var updates []*dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			if !strings.Contains(aws.ToString(params.FilterExpression), "#category = :category") {
+				t.Errorf("Expected a category filter expression, got %q", aws.ToString(params.FilterExpression))
+			}
+			return &dynamodb.ScanOutput{
+				Items: []map[string]types.AttributeValue{
+					{"id": &types.AttributeValueMemberS{Value: "item-1"}},
+					{"id": &types.AttributeValueMemberS{Value: "item-2"}},
+				},
+				LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "item-2"}},
+			}, nil
+		},
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			updates = append(updates, params)
+			if params.Key["id"].(*types.AttributeValueMemberS).Value == "item-2" {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "test-table")
+
+	result, err := repo.BulkTagItems(context.Background(), &BulkTagOptions{
+		Filter:     models.ItemFilter{Category: "tools"},
+		AddTags:    []string{"sale"},
+		RemoveTags: []string{"legacy"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to bulk tag: %v", err)
+	}
+
+	if !reflect.DeepEqual(result.MatchedIDs, []string{"item-1", "item-2"}) || result.Updated != 1 || !result.HasMore {
+		t.Errorf("Expected two matches, one updated and more to come, got %+v", result)
+	}
+
+	// item-1 gets an ADD then a DELETE; item-2 vanished before its first write
+	if len(updates) != 3 {
+		t.Fatalf("Expected 3 updates, got %d", len(updates))
+	}
+	if expr := aws.ToString(updates[0].UpdateExpression); !strings.Contains(expr, "ADD #tags :add") || strings.Contains(expr, "DELETE") {
+		t.Errorf("Expected the first write to add tags, got %q", expr)
+	}
+	if expr := aws.ToString(updates[1].UpdateExpression); expr != "DELETE #tags :remove" {
+		t.Errorf("Expected the second write to delete tags, got %q", expr)
+	}
+	if tags := updates[0].ExpressionAttributeValues[":add"].(*types.AttributeValueMemberSS).Value; !reflect.DeepEqual(tags, []string{"sale"}) {
+		t.Errorf("Expected string set [sale], got %v", tags)
+	}
+}
+
+func TestMemoryBulkTagItems_CursorCoversEveryItem(t *testing.T) {
+	repo := NewMemoryRepository()
+	for i := 0; i < 7; i++ {
+		item := models.NewItem(fmt.Sprintf("Item %d", i), "Description")
+		item.Category = "tools"
+		if i%2 == 1 {
+			item.Category = "toys"
+		}
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+
+	options := &BulkTagOptions{Filter: models.ItemFilter{Category: "tools"}, AddTags: []string{"sale"}, Limit: 2}
+	updated, batches := 0, 0
+	for {
+		result, err := repo.BulkTagItems(context.Background(), options)
+		if err != nil {
+			t.Fatalf("Failed to bulk tag: %v", err)
+		}
+		updated += result.Updated
+		batches++
+		if !result.HasMore {
+			break
+		}
+		options.LastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	if updated != 4 || batches != 4 {
+		t.Errorf("Expected 4 items updated over 4 batches, got %d over %d", updated, batches)
+	}
+	for _, item := range repo.items {
+		if item.HasTag("sale") != (item.Category == "tools") {
+			t.Errorf("Expected only tools items tagged, got %s with tags %v", item.Category, item.Tags)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// CachingRepository wraps an ItemRepository with a read-through GetItem
+// cache held in process memory.
+//
+// Writes made through this instance invalidate the affected entry directly.
+// Writes made by other instances only become visible once the entry expires,
+// unless an InvalidationSource delivers their events via Listen. The source
+// is fed by a DynamoDB Streams consumer that publishes InvalidationEvents
+// (see InvalidationEventsFromStream) to a fan-out channel such as SNS
+// with one SQS queue per subscriber.
+//
+// Lambda constrains how far that can go: an execution environment is frozen
+// between invocations, so a subscriber can only receive events while a
+// request is running, and there is no stable per-instance address for a
+// push subscription. A source for Lambda therefore has to drain its queue at
+// the start of each invocation, and the TTL must stay short enough to bound
+// staleness for whatever it misses.
+type CachingRepository struct {
+	inner ItemRepository
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is a cached item and the time it stops being served
+type cacheEntry struct {
+	item    models.Item
+	expires time.Time
+}
+
+// NewCachingRepository wraps inner with a GetItem cache whose entries live for ttl
+func NewCachingRepository(inner ItemRepository, ttl time.Duration) *CachingRepository {
+	return &CachingRepository{
+		inner:   inner,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// CacheTTLFromEnv returns the GetItem cache TTL configured via ITEM_CACHE_TTL.
+// Zero, the default, disables the cache.
+func CacheTTLFromEnv() time.Duration {
+	value := os.Getenv("ITEM_CACHE_TTL")
+	if value == "" {
+		return 0
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		log.Printf("Ignoring invalid ITEM_CACHE_TTL %q, cache disabled", value)
+		return 0
+	}
+	return ttl
+}
+
+// CreateItem creates the item and drops any stale entry for its ID
+func (c *CachingRepository) CreateItem(ctx context.Context, item *models.Item) error {
+	err := c.inner.CreateItem(ctx, item)
+	c.Invalidate(item.ID)
+	return err
+}
+
+// GetItem returns the cached item if fresh, otherwise reads through. A
+// projected read only has some of the item's fields, so it isn't cached,
+// and a consistent read always reads through.
+func (c *CachingRepository) GetItem(ctx context.Context, id string, options *GetItemOptions) (*models.Item, error) {
+	if options != nil && options.Consistent {
+		return c.inner.GetItem(ctx, id, options)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		item := entry.item
+		return &item, nil
+	}
+
+	item, err := c.inner.GetItem(ctx, id, options)
+	if err != nil {
+		return nil, err
+	}
+	if options != nil && len(options.Fields) > 0 {
+		return item, nil
+	}
+
+	c.mu.Lock()
+	c.entries[id] = cacheEntry{item: *item, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return item, nil
+}
+
+// ListItems is not cached
+func (c *CachingRepository) ListItems(ctx context.Context, options *ListItemsOptions) (*ListItemsResult, error) {
+	return c.inner.ListItems(ctx, options)
+}
+
+// UpdateItem updates the item and invalidates its entry
+func (c *CachingRepository) UpdateItem(ctx context.Context, id string, updates *models.UpdateItemRequest) (*models.Item, error) {
+	item, err := c.inner.UpdateItem(ctx, id, updates)
+	c.Invalidate(id)
+	return item, err
+}
+
+// PatchItem patches the item and invalidates its entry
+func (c *CachingRepository) PatchItem(ctx context.Context, id string, patch *models.PatchItemRequest) (*models.Item, error) {
+	item, err := c.inner.PatchItem(ctx, id, patch)
+	c.Invalidate(id)
+	return item, err
+}
+
+// DeleteItem deletes the item and invalidates its entry
+func (c *CachingRepository) DeleteItem(ctx context.Context, id string, options *DeleteItemOptions) error {
+	err := c.inner.DeleteItem(ctx, id, options)
+	c.Invalidate(id)
+	return err
+}
+
+// RestoreItem restores the item and invalidates its entry
+func (c *CachingRepository) RestoreItem(ctx context.Context, id string, requireOwner string) (*models.Item, error) {
+	item, err := c.inner.RestoreItem(ctx, id, requireOwner)
+	c.Invalidate(id)
+	return item, err
+}
+
+// PurgeItem purges the item and invalidates its entry
+func (c *CachingRepository) PurgeItem(ctx context.Context, id string) error {
+	err := c.inner.PurgeItem(ctx, id)
+	c.Invalidate(id)
+	return err
+}
+
+// CompactDeletedItems compacts and flushes the whole cache, since the
+// purged IDs are not reported back
+func (c *CachingRepository) CompactDeletedItems(ctx context.Context, before time.Time) (int, error) {
+	purged, err := c.inner.CompactDeletedItems(ctx, before)
+	c.InvalidateAll()
+	return purged, err
+}
+
+// GetRawItem is not cached, so it always shows what is stored
+func (c *CachingRepository) GetRawItem(ctx context.Context, id string) (map[string]types.AttributeValue, error) {
+	return c.inner.GetRawItem(ctx, id)
+}
+
+// FacetItems is not cached; facets are aggregated from the table each time
+func (c *CachingRepository) FacetItems(ctx context.Context, field string, scope *OwnerScope) (*models.FacetResult, error) {
+	return c.inner.FacetItems(ctx, field, scope)
+}
+
+// AutocompleteItems is not cached; suggestions change as the user types
+func (c *CachingRepository) AutocompleteItems(ctx context.Context, prefix string, limit int, scope *OwnerScope) ([]models.Item, error) {
+	return c.inner.AutocompleteItems(ctx, prefix, limit, scope)
+}
+
+// HealthCheck delegates to the wrapped repository when it supports it
+func (c *CachingRepository) HealthCheck(ctx context.Context) error {
+	if checker, ok := c.inner.(interface{ HealthCheck(context.Context) error }); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// GetTableName returns the wrapped repository's table name, if any
+func (c *CachingRepository) GetTableName() string {
+	if named, ok := c.inner.(interface{ GetTableName() string }); ok {
+		return named.GetTableName()
+	}
+	return ""
+}
+
+// Invalidate drops the cached entry for an item
+func (c *CachingRepository) Invalidate(id string) {
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.mu.Unlock()
+}
+
+// InvalidateAll drops every cached entry
+func (c *CachingRepository) InvalidateAll() {
+	c.mu.Lock()
+	c.entries = make(map[string]cacheEntry)
+	c.mu.Unlock()
+}
+
+// HandleInvalidation applies an invalidation event to the cache
+func (c *CachingRepository) HandleInvalidation(event InvalidationEvent) {
+	if event.ID == "" {
+		c.InvalidateAll()
+		return
+	}
+	c.Invalidate(event.ID)
+}
+
+// Listen applies events from the source until ctx is cancelled or the
+// source stops
+func (c *CachingRepository) Listen(ctx context.Context, source InvalidationSource) error {
+	return source.Subscribe(ctx, c.HandleInvalidation)
+}
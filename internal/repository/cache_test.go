@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"fis-playground/internal/models"
+)
+
+// countingRepository counts GetItem calls reaching the wrapped repository
+type countingRepository struct {
+	*MemoryRepository
+	gets int
+}
+
+func (r *countingRepository) GetItem(ctx context.Context, id string, options *GetItemOptions) (*models.Item, error) {
+	r.gets++
+	return r.MemoryRepository.GetItem(ctx, id, options)
+}
+
+func newCachedItem(t *testing.T) (*CachingRepository, *countingRepository, string) {
+	t.Helper()
+	inner := &countingRepository{MemoryRepository: NewMemoryRepository()}
+	item := models.NewItem("Item", "Description")
+	if err := inner.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	return NewCachingRepository(inner, time.Minute), inner, item.ID
+}
+
+func TestCachingRepository_ReadThrough(t *testing.T) {
+	cache, inner, id := newCachedItem(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.GetItem(context.Background(), id, nil); err != nil {
+			t.Fatalf("Failed to get item: %v", err)
+		}
+	}
+	if inner.gets != 1 {
+		t.Errorf("Expected 1 read through to the repository, got %d", inner.gets)
+	}
+
+	cache.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err := cache.GetItem(context.Background(), id, nil); err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if inner.gets != 2 {
+		t.Errorf("Expected an expired entry to be re-read, got %d reads", inner.gets)
+	}
+}
+
+func TestCachingRepository_ConsistentReadsThrough(t *testing.T) {
+	cache, inner, id := newCachedItem(t)
+	if _, err := cache.GetItem(context.Background(), id, nil); err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+
+	if _, err := cache.GetItem(context.Background(), id, &GetItemOptions{Consistent: true}); err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if inner.gets != 2 {
+		t.Errorf("Expected a consistent read to bypass the cache, got %d reads", inner.gets)
+	}
+}
+
+func TestCachingRepository_InvalidationEvent(t *testing.T) {
+	cache, inner, id := newCachedItem(t)
+	if _, err := cache.GetItem(context.Background(), id, nil); err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+
+	// Another instance renames the item and publishes an invalidation
+	if _, err := inner.MemoryRepository.UpdateItem(context.Background(), id, &models.UpdateItemRequest{Name: "Renamed"}); err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+
+	published := make(chan InvalidationEvent, 1)
+	published <- InvalidationEvent{ID: id}
+	close(published)
+	if err := cache.Listen(context.Background(), NewChannelInvalidationSource(published)); err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	item, err := cache.GetItem(context.Background(), id, nil)
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if item.Name != "Renamed" {
+		t.Errorf("Expected invalidated entry to be re-read as 'Renamed', got '%s'", item.Name)
+	}
+}
+
+func TestCachingRepository_InvalidateAllEvent(t *testing.T) {
+	cache, inner, id := newCachedItem(t)
+	if _, err := cache.GetItem(context.Background(), id, nil); err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+
+	cache.HandleInvalidation(InvalidationEvent{})
+
+	if _, err := cache.GetItem(context.Background(), id, nil); err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if inner.gets != 2 {
+		t.Errorf("Expected a flush to force a re-read, got %d reads", inner.gets)
+	}
+}
+
+func TestCachingRepository_LocalWriteInvalidates(t *testing.T) {
+	cache, _, id := newCachedItem(t)
+	if _, err := cache.GetItem(context.Background(), id, nil); err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+
+	if err := cache.DeleteItem(context.Background(), id, nil); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+
+	if _, err := cache.GetItem(context.Background(), id, nil); !IsNotFoundError(err) {
+		t.Errorf("Expected deleted item to be gone, got %v", err)
+	}
+}
+
+func TestInvalidationEventsFromStream(t *testing.T) {
+	event := events.DynamoDBEvent{
+		Records: []events.DynamoDBEventRecord{
+			{EventName: "MODIFY", Change: events.DynamoDBStreamRecord{
+				Keys: map[string]events.DynamoDBAttributeValue{"id": events.NewStringAttribute("item-1")},
+			}},
+			{EventName: "MODIFY", Change: events.DynamoDBStreamRecord{
+				Keys: map[string]events.DynamoDBAttributeValue{"id": events.NewStringAttribute(itemCounterID)},
+			}},
+			{EventName: "REMOVE", Change: events.DynamoDBStreamRecord{
+				Keys: map[string]events.DynamoDBAttributeValue{"id": events.NewStringAttribute("item-2")},
+			}},
+		},
+	}
+
+	got := InvalidationEventsFromStream(event)
+
+	if len(got) != 2 || got[0].ID != "item-1" || got[1].ID != "item-2" {
+		t.Errorf("Expected events for item-1 and item-2, got %+v", got)
+	}
+}
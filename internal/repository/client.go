@@ -4,16 +4,62 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"fis-playground/internal/tracing"
 )
 
+// DynamoDBAPI is the subset of the DynamoDB client used by the repository.
+// It is satisfied by *dynamodb.Client and allows tests to substitute a mock.
+type DynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+}
+
 // DynamoDBConfig holds configuration for DynamoDB client
 type DynamoDBConfig struct {
 	TableName string
 	Region    string
+	MaxItems  int64 // Maximum number of items allowed in the table (0 = unlimited)
+
+	// ListIndexName is the GSI used for ordered listing; listing falls back
+	// to a scan when the table does not have it
+	ListIndexName string
+
+	// StatusIndexName is the GSI used for listing by status; status
+	// listings filter the whole listing when the table does not have it
+	StatusIndexName string
+
+	// DateIndexName is the GSI used for listing the items created on a
+	// day; such listings cover the day's created_at range when the table
+	// does not have it
+	DateIndexName string
+
+	// NameIndexName is the GSI used for autocomplete; autocomplete scans
+	// the table when it does not have it
+	NameIndexName string
+
+	// AttributeNames maps model attribute names to the table's names
+	AttributeNames AttributeNames
+
+	// MaxWritesPerSec paces item writes to this rate (0 = unlimited)
+	MaxWritesPerSec float64
+
+	// MaxRetries bounds the retries of a throttled item read or write
+	MaxRetries int
 }
 
 // NewDynamoDBConfig creates a new DynamoDB configuration from environment variables
@@ -28,9 +74,69 @@ func NewDynamoDBConfig() (*DynamoDBConfig, error) {
 		region = "us-east-1" // Default region
 	}
 
+	var maxItems int64
+	if maxItemsStr := os.Getenv("MAX_ITEMS"); maxItemsStr != "" {
+		parsed, err := strconv.ParseInt(maxItemsStr, 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("MAX_ITEMS must be a non-negative integer, got %q", maxItemsStr)
+		}
+		maxItems = parsed
+	}
+
+	listIndexName := os.Getenv("LIST_INDEX_NAME")
+	if listIndexName == "" {
+		listIndexName = DefaultListIndexName
+	}
+
+	statusIndexName := os.Getenv("STATUS_INDEX_NAME")
+	if statusIndexName == "" {
+		statusIndexName = DefaultStatusIndexName
+	}
+
+	dateIndexName := os.Getenv("DATE_INDEX_NAME")
+	if dateIndexName == "" {
+		dateIndexName = DefaultDateIndexName
+	}
+
+	nameIndexName := os.Getenv("NAME_INDEX_NAME")
+	if nameIndexName == "" {
+		nameIndexName = DefaultNameIndexName
+	}
+
+	attributeNames, err := ParseAttributeNames(os.Getenv("ATTRIBUTE_NAME_MAP"))
+	if err != nil {
+		return nil, fmt.Errorf("ATTRIBUTE_NAME_MAP is invalid: %w", err)
+	}
+
+	var maxWritesPerSec float64
+	if maxWritesStr := os.Getenv("MAX_WRITES_PER_SEC"); maxWritesStr != "" {
+		parsed, err := strconv.ParseFloat(maxWritesStr, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("MAX_WRITES_PER_SEC must be a non-negative number, got %q", maxWritesStr)
+		}
+		maxWritesPerSec = parsed
+	}
+
+	maxRetries := DefaultMaxRetries
+	if maxRetriesStr := os.Getenv("DYNAMODB_MAX_RETRIES"); maxRetriesStr != "" {
+		parsed, err := strconv.Atoi(maxRetriesStr)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("DYNAMODB_MAX_RETRIES must be a non-negative integer, got %q", maxRetriesStr)
+		}
+		maxRetries = parsed
+	}
+
 	return &DynamoDBConfig{
-		TableName: tableName,
-		Region:    region,
+		TableName:       tableName,
+		Region:          region,
+		MaxItems:        maxItems,
+		ListIndexName:   listIndexName,
+		StatusIndexName: statusIndexName,
+		DateIndexName:   dateIndexName,
+		NameIndexName:   nameIndexName,
+		AttributeNames:  attributeNames,
+		MaxWritesPerSec: maxWritesPerSec,
+		MaxRetries:      maxRetries,
 	}, nil
 }
 
@@ -44,6 +150,20 @@ func NewDynamoDBClient(ctx context.Context, cfg *DynamoDBConfig) (*dynamodb.Clie
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	// Draw the SDK's own retries from the request's retry budget too
+	sdkRetryer := awsCfg.Retryer
+	awsCfg.Retryer = func() aws.Retryer {
+		if sdkRetryer == nil {
+			return NewBudgetRetryer(retry.NewStandard())
+		}
+		return NewBudgetRetryer(sdkRetryer())
+	}
+
+	// Record calls as subsegments of the request's X-Ray segment
+	if tracing.Enabled() {
+		awsCfg.APIOptions = append(awsCfg.APIOptions, traceDynamoDB)
+	}
+
 	// Create DynamoDB client
 	client := dynamodb.NewFromConfig(awsCfg)
 
@@ -76,6 +196,34 @@ func NewClientManager(ctx context.Context) (*ClientManager, error) {
 	}, nil
 }
 
+// clientManagerSingleton lazily creates a ClientManager exactly once
+type clientManagerSingleton struct {
+	once    sync.Once
+	create  func(ctx context.Context) (*ClientManager, error)
+	manager *ClientManager
+	err     error
+}
+
+// get returns the manager, creating it on the first call. Concurrent first
+// calls block until creation finishes; a creation error is returned to
+// every caller, since a misconfigured environment won't fix itself.
+func (s *clientManagerSingleton) get(ctx context.Context) (*ClientManager, error) {
+	s.once.Do(func() {
+		s.manager, s.err = s.create(ctx)
+	})
+	return s.manager, s.err
+}
+
+// sharedClientManager backs SharedClientManager
+var sharedClientManager = &clientManagerSingleton{create: NewClientManager}
+
+// SharedClientManager returns the process-wide ClientManager, creating it on
+// first use. Warm Lambda invocations reuse the same client and its
+// connection pool; prefer this over NewClientManager outside of tests.
+func SharedClientManager(ctx context.Context) (*ClientManager, error) {
+	return sharedClientManager.get(ctx)
+}
+
 // GetClient returns the DynamoDB client
 func (cm *ClientManager) GetClient() *dynamodb.Client {
 	return cm.client
@@ -91,6 +239,11 @@ func (cm *ClientManager) GetRegion() string {
 	return cm.config.Region
 }
 
+// GetConfig returns the DynamoDB configuration
+func (cm *ClientManager) GetConfig() *DynamoDBConfig {
+	return cm.config
+}
+
 // HealthCheck performs a basic health check on the DynamoDB connection
 func (cm *ClientManager) HealthCheck(ctx context.Context) error {
 	// Perform a simple DescribeTable operation to verify connectivity
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClientManagerSingleton_CreatesOnce(t *testing.T) {
+	var created atomic.Int32
+	singleton := &clientManagerSingleton{
+		create: func(ctx context.Context) (*ClientManager, error) {
+			created.Add(1)
+			return &ClientManager{config: &DynamoDBConfig{TableName: "items"}}, nil
+		},
+	}
+
+	const callers = 50
+	managers := make([]*ClientManager, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			manager, err := singleton.get(context.Background())
+			if err != nil {
+				t.Errorf("Expected manager, got %v", err)
+			}
+			managers[i] = manager
+		}(i)
+	}
+	wg.Wait()
+
+	if n := created.Load(); n != 1 {
+		t.Errorf("Expected manager to be created once, got %d", n)
+	}
+	for i, manager := range managers {
+		if manager != managers[0] {
+			t.Fatalf("Expected caller %d to share the same manager", i)
+		}
+	}
+}
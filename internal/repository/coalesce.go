@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"maps"
+	"os"
+	"sync"
+	"time"
+
+	"fis-playground/internal/models"
+)
+
+// CoalescingRepository merges rapid successive updates to the same item into
+// a single write, so a burst of updates to a hot key costs one conditional
+// write instead of a queue of them that throttles.
+//
+// The first update to an item opens a window; updates arriving within it are
+// merged in arrival order (later non-empty fields win) and written together
+// when it closes. Every caller receives the merged result.
+//
+// Consistency trade-offs:
+//   - Updates wait up to the window before being written.
+//   - Callers in a window share one outcome, including failures, and see
+//     fields set by the other callers.
+//   - Only updates handled by this instance are merged; other instances
+//     still write independently.
+//   - Updates with an expected generation or a required owner are never
+//     merged, since a conditional write can't represent several
+//     expectations at once. Nor are updates skipping validation, which
+//     would otherwise be written under another caller's context.
+type CoalescingRepository struct {
+	ItemRepository
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingUpdate
+}
+
+// coalesceFlushTimeout bounds the merged write, which no caller's
+// cancellation ends
+const coalesceFlushTimeout = 10 * time.Second
+
+// pendingUpdate is an open coalescing window for one item
+type pendingUpdate struct {
+	// ctx is the context of the caller that opened the window, without
+	// its cancellation, so the merged write keeps its logger and trace
+	ctx     context.Context
+	updates models.UpdateItemRequest
+	done    chan struct{}
+	item    *models.Item
+	err     error
+}
+
+// NewCoalescingRepository wraps inner, merging updates to the same item
+// within window
+func NewCoalescingRepository(inner ItemRepository, window time.Duration) *CoalescingRepository {
+	return &CoalescingRepository{
+		ItemRepository: inner,
+		window:         window,
+		pending:        make(map[string]*pendingUpdate),
+	}
+}
+
+// CoalesceWindowFromEnv returns the window configured via
+// UPDATE_COALESCE_WINDOW. Zero, the default, disables coalescing.
+func CoalesceWindowFromEnv() time.Duration {
+	value := os.Getenv("UPDATE_COALESCE_WINDOW")
+	if value == "" {
+		return 0
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil || window < 0 {
+		log.Printf("Ignoring invalid UPDATE_COALESCE_WINDOW %q, coalescing disabled", value)
+		return 0
+	}
+	return window
+}
+
+// UpdateItem merges the update into the item's open window, opening one if
+// needed, and waits for the merged write
+func (c *CoalescingRepository) UpdateItem(ctx context.Context, id string, updates *models.UpdateItemRequest) (*models.Item, error) {
+	if c.window <= 0 || id == "" || updates == nil || updates.Generation != nil || updates.Version != nil || updates.RequireOwner != "" || SkipsValidation(ctx) {
+		return c.ItemRepository.UpdateItem(ctx, id, updates)
+	}
+
+	// Reject invalid updates up front so they can't fail the whole window
+	if err := validate(ctx, updates); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	p, ok := c.pending[id]
+	if !ok {
+		p = &pendingUpdate{ctx: context.WithoutCancel(ctx), done: make(chan struct{})}
+		c.pending[id] = p
+		time.AfterFunc(c.window, func() { c.flush(id, p) })
+	}
+	mergeUpdate(&p.updates, updates)
+	c.mu.Unlock()
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	item := *p.item
+	return &item, nil
+}
+
+// flush closes the window and writes the merged update under the opening
+// caller's context, bounded by coalesceFlushTimeout
+func (c *CoalescingRepository) flush(id string, p *pendingUpdate) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	merged := p.updates
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(p.ctx, coalesceFlushTimeout)
+	defer cancel()
+	p.item, p.err = c.ItemRepository.UpdateItem(ctx, id, &merged)
+	close(p.done)
+}
+
+// mergeUpdate applies the non-empty fields of next on top of into
+func mergeUpdate(into *models.UpdateItemRequest, next *models.UpdateItemRequest) {
+	if next.Name != "" {
+		into.Name = next.Name
+	}
+	if next.Description != "" {
+		into.Description = next.Description
+	}
+	if next.Status != "" {
+		into.Status = next.Status
+	}
+	if next.Category != "" {
+		into.Category = next.Category
+	}
+	if next.Tags != nil {
+		into.Tags = next.Tags
+	}
+	if next.Metadata != nil {
+		mergeMetadata(into, next)
+	}
+}
+
+// mergeMetadata applies next's metadata change on top of into's. A replace
+// supersedes whatever came before it; a merge adds its keys to the earlier
+// change, keeping that change's mode.
+func mergeMetadata(into *models.UpdateItemRequest, next *models.UpdateItemRequest) {
+	if into.Metadata == nil || next.MetadataMode != models.MetadataMerge {
+		into.Metadata = maps.Clone(next.Metadata)
+		into.MetadataMode = next.MetadataMode
+		return
+	}
+	maps.Copy(into.Metadata, next.Metadata)
+}
+
+// HealthCheck delegates to the wrapped repository when it supports it
+func (c *CoalescingRepository) HealthCheck(ctx context.Context) error {
+	if checker, ok := c.ItemRepository.(interface{ HealthCheck(context.Context) error }); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// GetTableName returns the wrapped repository's table name, if any
+func (c *CoalescingRepository) GetTableName() string {
+	if named, ok := c.ItemRepository.(interface{ GetTableName() string }); ok {
+		return named.GetTableName()
+	}
+	return ""
+}
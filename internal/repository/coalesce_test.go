@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fis-playground/internal/models"
+)
+
+// updateCountingRepository counts UpdateItem calls reaching the wrapped repository
+type updateCountingRepository struct {
+	*MemoryRepository
+	updates atomic.Int32
+	seen    chan seenContext
+}
+
+// seenContext is the context an update was written under, with its error
+// at the time
+type seenContext struct {
+	ctx context.Context
+	err error
+}
+
+func (r *updateCountingRepository) UpdateItem(ctx context.Context, id string, updates *models.UpdateItemRequest) (*models.Item, error) {
+	r.updates.Add(1)
+	if r.seen != nil {
+		r.seen <- seenContext{ctx: ctx, err: ctx.Err()}
+	}
+	return r.MemoryRepository.UpdateItem(ctx, id, updates)
+}
+
+func TestCoalescingRepository_MergesBurst(t *testing.T) {
+	inner := &updateCountingRepository{MemoryRepository: NewMemoryRepository()}
+	item := models.NewItem("Item", "Description")
+	if err := inner.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	repo := NewCoalescingRepository(inner, 50*time.Millisecond)
+
+	requests := []*models.UpdateItemRequest{
+		{Name: "Renamed"},
+		{Description: "Redescribed"},
+		{Status: "inactive"},
+	}
+	results := make([]*models.Item, len(requests))
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req *models.UpdateItemRequest) {
+			defer wg.Done()
+			updated, err := repo.UpdateItem(context.Background(), item.ID, req)
+			if err != nil {
+				t.Errorf("Expected update to succeed, got %v", err)
+				return
+			}
+			results[i] = updated
+		}(i, req)
+	}
+	wg.Wait()
+
+	if n := inner.updates.Load(); n != 1 {
+		t.Errorf("Expected updates to collapse into 1 write, got %d", n)
+	}
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		if result.Name != "Renamed" || result.Description != "Redescribed" || result.Status != "inactive" {
+			t.Errorf("Expected caller %d to receive the merged item, got %+v", i, result)
+		}
+	}
+}
+
+func TestCoalescingRepository_GenerationBypassesWindow(t *testing.T) {
+	inner := &updateCountingRepository{MemoryRepository: NewMemoryRepository()}
+	item := models.NewItem("Item", "Description")
+	if err := inner.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	repo := NewCoalescingRepository(inner, time.Hour)
+
+	generation := item.Generation
+	if _, err := repo.UpdateItem(context.Background(), item.ID, &models.UpdateItemRequest{Name: "Renamed", Generation: &generation}); err != nil {
+		t.Fatalf("Expected conditional update to succeed, got %v", err)
+	}
+	if n := inner.updates.Load(); n != 1 {
+		t.Errorf("Expected conditional update to be written immediately, got %d writes", n)
+	}
+}
+
+type coalesceTestKey struct{}
+
+func TestCoalescingRepository_FlushKeepsCallerContext(t *testing.T) {
+	inner := &updateCountingRepository{MemoryRepository: NewMemoryRepository(), seen: make(chan seenContext, 1)}
+	item := models.NewItem("Item", "Description")
+	if err := inner.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	repo := NewCoalescingRepository(inner, 20*time.Millisecond)
+
+	// The caller that opened the window gives up before it closes
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), coalesceTestKey{}, "request-1"))
+	cancel()
+	if _, err := repo.UpdateItem(ctx, item.ID, &models.UpdateItemRequest{Name: "Renamed"}); err == nil {
+		t.Fatal("Expected the cancelled caller to stop waiting")
+	}
+
+	seen := <-inner.seen
+	if seen.ctx.Value(coalesceTestKey{}) != "request-1" {
+		t.Error("Expected the merged write to carry the opening caller's context values")
+	}
+	if seen.err != nil {
+		t.Errorf("Expected the caller's cancellation not to reach the merged write, got %v", seen.err)
+	}
+	if _, ok := seen.ctx.Deadline(); !ok {
+		t.Error("Expected the merged write to be bounded by a timeout")
+	}
+}
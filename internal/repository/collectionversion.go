@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// CollectionVersioner is implemented by repositories that can report a
+// version of the whole collection, so clients caching listings can tell
+// when they are stale
+type CollectionVersioner interface {
+	// CollectionVersion returns an opaque token that changes when items
+	// change, or "" when the repository doesn't track versions
+	CollectionVersion(ctx context.Context) (string, error)
+}
+
+// collectionRecordPrefix starts the keys of the records holding when items
+// last changed. Writes are spread over collectionShards records, so no
+// single key takes every write.
+const (
+	collectionRecordPrefix = metaItemPrefix + "collection#"
+	collectionShards       = 8
+)
+
+// collectionRecordID is the key of one collection shard record
+func collectionRecordID(shard int) string {
+	return collectionRecordPrefix + strconv.Itoa(shard)
+}
+
+// collectionVersionFromEnv reports whether COLLECTION_VERSION enables
+// collection versions
+func collectionVersionFromEnv() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("COLLECTION_VERSION"))
+	return enabled
+}
+
+// collectionVersion builds the version token from the item count and the
+// last change
+func collectionVersion(count int64, changedAt time.Time) string {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%d:%d", count, changedAt.UnixNano())
+	return strconv.FormatUint(hash.Sum64(), 36)
+}
+
+// CollectionVersion hashes the times recorded on the collection shards,
+// read with one BatchGetItem. Every write, deletes included, records its
+// time on a shard, so a change to any shard changes the version and no
+// item count is needed.
+func (r *DynamoDBRepository) CollectionVersion(ctx context.Context) (string, error) {
+	if !r.trackVersion {
+		return "", nil
+	}
+
+	keys := make([]map[string]types.AttributeValue, collectionShards)
+	for shard := range keys {
+		keys[shard] = r.attrNames.key(collectionRecordID(shard))
+	}
+	rows, err := r.batchGet(ctx, keys)
+	if err != nil {
+		return "", err
+	}
+
+	changes := make([]string, 0, len(rows))
+	idAttr := r.attrNames.Storage("id")
+	for _, row := range rows {
+		id, _ := row[idAttr].(*types.AttributeValueMemberS)
+		changedAt, _ := row["changed_at"].(*types.AttributeValueMemberN)
+		if id != nil && changedAt != nil {
+			changes = append(changes, id.Value+"="+changedAt.Value)
+		}
+	}
+	// BatchGetItem returns rows in no particular order
+	slices.Sort(changes)
+
+	hash := fnv.New64a()
+	for _, change := range changes {
+		fmt.Fprintf(hash, "%s;", change)
+	}
+	return strconv.FormatUint(hash.Sum64(), 36), nil
+}
+
+// touchCollection records that items changed on a random collection shard,
+// when collection versions are tracked. Failures are logged rather than
+// returned since the write itself succeeded; the version then changes with
+// the next write.
+func (r *DynamoDBRepository) touchCollection(ctx context.Context) {
+	if !r.trackVersion {
+		return
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:        aws.String(r.tableName),
+		Key:              r.attrNames.key(collectionRecordID(rand.IntN(collectionShards))),
+		UpdateExpression: aws.String("SET changed_at = :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().UnixNano(), 10)},
+		},
+	}
+	if _, err := r.client.UpdateItem(ctx, input); err != nil {
+		log.Printf("Failed to record collection change: %v", err)
+	}
+}
+
+// CollectionVersion derives the version from the number of stored items and
+// their latest updated_at, which soft deletes and restores also bump
+func (r *MemoryRepository) CollectionVersion(ctx context.Context) (string, error) {
+	if !r.trackVersion {
+		return "", nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var changedAt time.Time
+	for _, item := range r.items {
+		if item.UpdatedAt.After(changedAt) {
+			changedAt = item.UpdatedAt
+		}
+	}
+	return collectionVersion(int64(len(r.items)), changedAt), nil
+}
+
+// CollectionVersion delegates to the wrapped repository, returning "" when
+// it doesn't track versions
+func (c *CachingRepository) CollectionVersion(ctx context.Context) (string, error) {
+	if versioner, ok := c.inner.(CollectionVersioner); ok {
+		return versioner.CollectionVersion(ctx)
+	}
+	return "", nil
+}
+
+// CollectionVersion delegates to the wrapped repository, returning "" when
+// it doesn't track versions
+func (c *CoalescingRepository) CollectionVersion(ctx context.Context) (string, error) {
+	if versioner, ok := c.ItemRepository.(CollectionVersioner); ok {
+		return versioner.CollectionVersion(ctx)
+	}
+	return "", nil
+}
+
+// CollectionVersion reports the primary's version, which every read comes
+// from
+func (d *DualWriteRepository) CollectionVersion(ctx context.Context) (string, error) {
+	if versioner, ok := d.ItemRepository.(CollectionVersioner); ok {
+		return versioner.CollectionVersion(ctx)
+	}
+	return "", nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// readVersion returns the repository's collection version, failing the test
+// on errors
+func readVersion(t *testing.T, repo CollectionVersioner) string {
+	t.Helper()
+	version, err := repo.CollectionVersion(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to read collection version: %v", err)
+	}
+	return version
+}
+
+func TestMemoryRepository_CollectionVersion(t *testing.T) {
+	t.Setenv("COLLECTION_VERSION", "true")
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	empty := readVersion(t, repo)
+	if empty == "" {
+		t.Fatal("Expected a version when versions are tracked")
+	}
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(ctx, item); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	created := readVersion(t, repo)
+	if created == empty {
+		t.Error("Expected a create to change the version")
+	}
+	if again := readVersion(t, repo); again != created {
+		t.Errorf("Expected the version to stay %s without changes, got %s", created, again)
+	}
+
+	if _, err := repo.UpdateItem(ctx, item.ID, &models.UpdateItemRequest{Description: "Changed"}); err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+	updated := readVersion(t, repo)
+	if updated == created {
+		t.Error("Expected an update to change the version")
+	}
+
+	if err := repo.DeleteItem(ctx, item.ID, nil); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+	if deleted := readVersion(t, repo); deleted == updated {
+		t.Error("Expected a delete to change the version")
+	}
+}
+
+func TestMemoryRepository_CollectionVersionDisabled(t *testing.T) {
+	t.Setenv("COLLECTION_VERSION", "")
+	if version := readVersion(t, NewMemoryRepository()); version != "" {
+		t.Errorf("Expected no version when versions aren't tracked, got %q", version)
+	}
+}
+
+func TestDynamoDBRepository_CollectionVersionChangesOnUpdate(t *testing.T) {
+	t.Setenv("COLLECTION_VERSION", "true")
+	item := models.NewItem("Item", "Description")
+	item.ID = "item-1"
+	stored, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		t.Fatalf("Failed to marshal item: %v", err)
+	}
+
+	shards := map[string]map[string]types.AttributeValue{}
+	mock := &mockDynamoDBClient{
+		BatchGetFn: func(ctx context.Context, params *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+			var rows []map[string]types.AttributeValue
+			for _, key := range params.RequestItems["items"].Keys {
+				id := key["id"].(*types.AttributeValueMemberS).Value
+				if !strings.HasPrefix(id, collectionRecordPrefix) {
+					t.Errorf("Expected only collection records to be read, got %s", id)
+				}
+				if row, ok := shards[id]; ok {
+					rows = append(rows, row)
+				}
+			}
+			return &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"items": rows}}, nil
+		},
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			if id := params.Key["id"].(*types.AttributeValueMemberS).Value; strings.HasPrefix(id, collectionRecordPrefix) {
+				shards[id] = map[string]types.AttributeValue{
+					"id":         params.Key["id"],
+					"changed_at": params.ExpressionAttributeValues[":now"],
+				}
+				return &dynamodb.UpdateItemOutput{}, nil
+			}
+			return &dynamodb.UpdateItemOutput{Attributes: stored}, nil
+		},
+		DescribeTableFn: func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+			t.Error("Expected the version to be read without describing the table")
+			return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(mock, "items")
+
+	version := readVersion(t, repo)
+	for i := 0; i < 50; i++ {
+		if _, err := repo.UpdateItem(context.Background(), item.ID, &models.UpdateItemRequest{Description: "Changed"}); err != nil {
+			t.Fatalf("Failed to update item: %v", err)
+		}
+		after := readVersion(t, repo)
+		if after == version {
+			t.Fatalf("Expected update %d to change the version %s", i, version)
+		}
+		version = after
+	}
+
+	// Writes are spread over the shards rather than one hot record
+	if len(shards) < 2 {
+		t.Errorf("Expected changes recorded on several shards, got %d", len(shards))
+	}
+}
+
+func TestDynamoDBRepository_CollectionVersionDisabled(t *testing.T) {
+	t.Setenv("COLLECTION_VERSION", "")
+	writes := 0
+	mock := &mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			writes++
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			t.Error("Expected no collection record writes when versions aren't tracked")
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(mock, "items")
+
+	if err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description")); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	if writes != 1 {
+		t.Errorf("Expected only the item to be written, got %d writes", writes)
+	}
+	if version := readVersion(t, repo); version != "" {
+		t.Errorf("Expected no version, got %q", version)
+	}
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Batch write limits
+const (
+	batchWriteSize       = 25 // DynamoDB's maximum requests per BatchWriteItem
+	batchWriteMaxRetries = 5
+)
+
+// CompactDeletedItems permanently removes items soft-deleted before the
+// cutoff and returns how many were removed.
+//
+// Candidates are found a scan page at a time, and each is deleted on the
+// condition that it is still deleted before the cutoff, so an item restored
+// or deleted again after it was scanned is kept.
+func (r *DynamoDBRepository) CompactDeletedItems(ctx context.Context, before time.Time) (int, error) {
+	input := &dynamodb.ScanInput{
+		TableName:                aws.String(r.tableName),
+		FilterExpression:         aws.String("attribute_exists(#deleted_at)"),
+		ProjectionExpression:     aws.String("#id, #deleted_at"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id", "deleted_at"),
+	}
+
+	purged := 0
+	for {
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return purged, HandleDynamoDBError(err)
+		}
+
+		rows, err := r.deletedBefore(result.Items, before)
+		if err != nil {
+			return purged, err
+		}
+		for _, row := range rows {
+			removed, err := r.compactItem(ctx, row.ID, row.DeletedAt, before)
+			if err != nil {
+				return purged, err
+			}
+			if removed {
+				purged++
+			}
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return purged, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// deletedRow is the part of a soft-deleted item compaction reads
+type deletedRow struct {
+	ID        string    `dynamodbav:"id"`
+	DeletedAt time.Time `dynamodbav:"deleted_at"`
+}
+
+// deletedBefore returns the rows of a scan page deleted before the cutoff.
+// Timestamps are compared after unmarshaling because the stored RFC3339
+// strings don't sort correctly when fractional seconds differ.
+func (r *DynamoDBRepository) deletedBefore(items []map[string]types.AttributeValue, before time.Time) ([]deletedRow, error) {
+	var rows []deletedRow
+	if err := attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(items), &rows); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+	candidates := rows[:0]
+	for _, row := range rows {
+		if row.DeletedAt.Before(before) && !isMetaItemID(row.ID) {
+			candidates = append(candidates, row)
+		}
+	}
+	return candidates, nil
+}
+
+// compactItem deletes one item if it is still soft-deleted before the
+// cutoff, and reports whether it did. The cutoff is written in the offset
+// of the scanned deleted_at, so the condition's string comparison agrees
+// with the scan's except within the cutoff's second, where the item is left
+// for the next run.
+func (r *DynamoDBRepository) compactItem(ctx context.Context, id string, deletedAt, before time.Time) (bool, error) {
+	cutoff, err := attributevalue.Marshal(before.In(deletedAt.Location()))
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal timestamp: %w", err)
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		TableName:                aws.String(r.tableName),
+		Key:                      r.attrNames.key(id),
+		ConditionExpression:      aws.String("attribute_exists(#deleted_at) AND #deleted_at < :before"),
+		ExpressionAttributeNames: r.attrNames.placeholders("deleted_at"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":before": cutoff,
+		},
+	}
+
+	_, err = withRetry(ctx, r.maxRetries, func() (*dynamodb.DeleteItemOutput, error) {
+		return r.client.DeleteItem(ctx, input)
+	})
+	if err != nil {
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) {
+			return false, nil
+		}
+		return false, HandleDynamoDBError(err)
+	}
+
+	if r.maxItems > 0 {
+		r.releaseItemSlot(ctx)
+	}
+	return true, nil
+}
+
+// batchDelete deletes up to batchWriteSize items, retrying any requests
+// DynamoDB reports as unprocessed
+func (r *DynamoDBRepository) batchDelete(ctx context.Context, ids []string) error {
+	requests := make([]types.WriteRequest, 0, len(ids))
+	for _, id := range ids {
+		requests = append(requests, types.WriteRequest{
+			DeleteRequest: &types.DeleteRequest{
+				Key: r.attrNames.key(id),
+			},
+		})
+	}
+
+	unprocessed, err := r.batchWrite(ctx, requests)
+	if err != nil {
+		return err
+	}
+	if len(unprocessed) > 0 {
+		return fmt.Errorf("%w: %d delete requests still unprocessed after retries", ErrOperationFailed, len(unprocessed))
+	}
+	return nil
+}
+
+// batchWrite sends up to batchWriteSize write requests, retrying any that
+// DynamoDB reports as unprocessed with backoff, and returns those still
+// unprocessed after batchWriteMaxRetries retries or once the request's
+// retry budget is spent
+func (r *DynamoDBRepository) batchWrite(ctx context.Context, requests []types.WriteRequest) ([]types.WriteRequest, error) {
+	pending := map[string][]types.WriteRequest{r.tableName: requests}
+	for attempt := 0; attempt <= batchWriteMaxRetries; attempt++ {
+		if attempt > 0 {
+			if !RetryBudgetFromContext(ctx).take() {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt*attempt) * 50 * time.Millisecond):
+			}
+		}
+
+		result, err := r.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: pending,
+		})
+		if err != nil {
+			return nil, HandleDynamoDBError(err)
+		}
+		if len(result.UnprocessedItems) == 0 {
+			return nil, nil
+		}
+		pending = result.UnprocessedItems
+	}
+
+	return pending[r.tableName], nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// deletedRows returns scan rows for items deleted at the given times
+func deletedRows(t *testing.T, deletedAt map[string]time.Time) map[string]map[string]types.AttributeValue {
+	t.Helper()
+	rows := map[string]map[string]types.AttributeValue{}
+	for id, at := range deletedAt {
+		av, err := attributevalue.MarshalMap(map[string]interface{}{
+			"id":         id,
+			"deleted_at": at,
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal row: %v", err)
+		}
+		rows[id] = av
+	}
+	return rows
+}
+
+// compactionTable returns a mock that scans stored in the given pages and
+// evaluates compaction's delete condition against stored
+func compactionTable(stored map[string]map[string]types.AttributeValue, pages [][]string) *mockDynamoDBClient {
+	page := 0
+	return &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			output := &dynamodb.ScanOutput{}
+			for _, id := range pages[page] {
+				if row, ok := stored[id]; ok {
+					output.Items = append(output.Items, row)
+				}
+			}
+			page++
+			if page < len(pages) {
+				output.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: fmt.Sprint(page)}}
+			}
+			return output, nil
+		},
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			id := params.Key["id"].(*types.AttributeValueMemberS).Value
+			row, ok := stored[id]
+			deletedAt, deleted := row["deleted_at"].(*types.AttributeValueMemberS)
+			if !ok || !deleted || deletedAt.Value >= params.ExpressionAttributeValues[":before"].(*types.AttributeValueMemberS).Value {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			delete(stored, id)
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}
+}
+
+func TestCompactDeletedItems_OnlyBeforeCutoff(t *testing.T) {
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// 30 items deleted before the cutoff, over two scan pages, and 3 after
+	deletedAt := map[string]time.Time{}
+	var pages [][]string
+	for i := 0; i < 33; i++ {
+		id := fmt.Sprintf("item-%02d", i)
+		deletedAt[id] = cutoff.Add(-time.Duration(i+1) * time.Hour)
+		if i >= 30 {
+			deletedAt[id] = cutoff.Add(time.Duration(i) * time.Millisecond)
+		}
+		if i%20 == 0 {
+			pages = append(pages, nil)
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], id)
+	}
+	stored := deletedRows(t, deletedAt)
+	repo := NewDynamoDBRepository(compactionTable(stored, pages), "items")
+
+	purged, err := repo.CompactDeletedItems(context.Background(), cutoff)
+	if err != nil {
+		t.Fatalf("Expected compaction to succeed, got %v", err)
+	}
+
+	if purged != 30 {
+		t.Errorf("Expected 30 items purged, got %d", purged)
+	}
+	for i := 30; i < 33; i++ {
+		if id := fmt.Sprintf("item-%02d", i); stored[id] == nil {
+			t.Errorf("Expected %s deleted after the cutoff to be kept", id)
+		}
+	}
+}
+
+func TestCompactDeletedItems_KeepsItemsRestoredAfterScan(t *testing.T) {
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	stored := deletedRows(t, map[string]time.Time{
+		"restored":  cutoff.Add(-time.Hour),
+		"redeleted": cutoff.Add(-time.Hour),
+		"purged":    cutoff.Add(-time.Hour),
+	})
+	client := compactionTable(stored, [][]string{{"restored", "redeleted", "purged"}})
+
+	// Between the scan and the deletes, one item is restored and another
+	// restored and deleted again after the cutoff
+	scan := client.ScanFn
+	client.ScanFn = func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		output, err := scan(ctx, params)
+		delete(stored["restored"], "deleted_at")
+		stored["redeleted"] = deletedRows(t, map[string]time.Time{"redeleted": cutoff.Add(time.Hour)})["redeleted"]
+		return output, err
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	purged, err := repo.CompactDeletedItems(context.Background(), cutoff)
+	if err != nil {
+		t.Fatalf("Expected compaction to succeed, got %v", err)
+	}
+	if purged != 1 || stored["purged"] != nil {
+		t.Errorf("Expected only the still deleted item purged, got %d", purged)
+	}
+	if stored["restored"] == nil || stored["redeleted"] == nil {
+		t.Error("Expected the items changed after the scan to be kept")
+	}
+}
@@ -0,0 +1,74 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/repository"
+	"fis-playground/internal/repository/repositorytest"
+)
+
+func TestMemoryRepository_Conformance(t *testing.T) {
+	t.Setenv("SOFT_DELETE", "")
+	repositorytest.RunRepositoryConformanceTests(t, func() repository.ItemRepository {
+		return repository.NewMemoryRepository()
+	})
+}
+
+// TestDynamoDBRepository_Conformance runs the suite against DynamoDB Local
+// at DYNAMODB_LOCAL_ENDPOINT, e.g. http://localhost:8000, in a table it
+// creates for the run and deletes afterwards
+func TestDynamoDBRepository_Conformance(t *testing.T) {
+	endpoint := os.Getenv("DYNAMODB_LOCAL_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_LOCAL_ENDPOINT not set, skipping DynamoDB Local conformance tests")
+	}
+	t.Setenv("SOFT_DELETE", "")
+
+	client := dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(endpoint),
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "local", SecretAccessKey: "local"}, nil
+		}),
+	})
+	table := createLocalTable(t, client)
+
+	repositorytest.RunRepositoryConformanceTests(t, func() repository.ItemRepository {
+		return repository.NewDynamoDBRepository(client, table)
+	})
+}
+
+// createLocalTable creates an items table keyed on id, deleting it when the
+// test ends
+func createLocalTable(t *testing.T, client *dynamodb.Client) string {
+	t.Helper()
+	ctx := context.Background()
+	table := "conformance-" + time.Now().Format("20060102150405.000000")
+
+	_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(table),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(table)}); err != nil {
+			t.Errorf("DeleteTable(%s) error = %v", table, err)
+		}
+	})
+	return table
+}
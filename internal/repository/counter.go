@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// metaItemPrefix marks bookkeeping records stored alongside items in the table.
+// Records with this prefix are never returned by item reads or listings.
+const metaItemPrefix = "_meta#"
+
+// itemCounterID is the key of the record holding the maintained item count
+const itemCounterID = metaItemPrefix + "item_count"
+
+// isMetaItemID checks if the ID belongs to an internal bookkeeping record
+func isMetaItemID(id string) bool {
+	return strings.HasPrefix(id, metaItemPrefix)
+}
+
+// reserveItemSlot atomically increments the item counter, failing with
+// ErrLimitReached when the table already holds maxItems items.
+//
+// The counter is maintained on create/delete only, so it starts from zero
+// when the cap is first enabled on a table that already contains items.
+// Items removed by DynamoDB TTL never reach releaseItemSlot, so they keep
+// their slot after they are gone.
+func (r *DynamoDBRepository) reserveItemSlot(ctx context.Context) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 r.attrNames.key(itemCounterID),
+		UpdateExpression:    aws.String("ADD item_count :one"),
+		ConditionExpression: aws.String("attribute_not_exists(item_count) OR item_count < :max"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{Value: "1"},
+			":max": &types.AttributeValueMemberN{Value: strconv.FormatInt(r.maxItems, 10)},
+		},
+	}
+
+	_, err := r.client.UpdateItem(ctx, input)
+	if err != nil {
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) {
+			return fmt.Errorf("%w: table holds the maximum of %d items", ErrLimitReached, r.maxItems)
+		}
+		return HandleDynamoDBError(err)
+	}
+
+	return nil
+}
+
+// releaseItemSlot atomically decrements the item counter. Failures are logged
+// rather than returned since the primary write has already been decided.
+func (r *DynamoDBRepository) releaseItemSlot(ctx context.Context) {
+	input := &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 r.attrNames.key(itemCounterID),
+		UpdateExpression:    aws.String("ADD item_count :minus_one"),
+		ConditionExpression: aws.String("item_count > :zero"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":minus_one": &types.AttributeValueMemberN{Value: "-1"},
+			":zero":      &types.AttributeValueMemberN{Value: "0"},
+		},
+	}
+
+	if _, err := r.client.UpdateItem(ctx, input); err != nil {
+		log.Printf("Failed to release item counter slot: %v", err)
+	}
+}
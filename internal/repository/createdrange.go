@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// hasCreatedRange reports whether the options bound created_at
+func (o *ListItemsOptions) hasCreatedRange() bool {
+	return !o.CreatedAfter.IsZero() || !o.CreatedBefore.IsZero()
+}
+
+// inCreatedRange reports whether the item was created within the options'
+// bounds; both bounds are inclusive and either may be unset
+func (o *ListItemsOptions) inCreatedRange(item *models.Item) bool {
+	if !o.CreatedAfter.IsZero() && item.CreatedAt.Before(o.CreatedAfter) {
+		return false
+	}
+	if !o.CreatedBefore.IsZero() && item.CreatedAt.After(o.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// createdRangeFilter returns a filter expression bounding created_at to the
+// options' range and adds its placeholders to names and values, or returns
+// "" when no bound is set.
+//
+// Stored created_at values don't compare correctly as strings when
+// fractional seconds differ, so each bound is widened to the neighbouring
+// whole second. The expression only narrows what is read; callers apply
+// the exact bounds with filterCreatedRange after unmarshaling.
+func (r *DynamoDBRepository) createdRangeFilter(options *ListItemsOptions, names map[string]string, values map[string]types.AttributeValue) (string, error) {
+	var conditions []string
+	if !options.CreatedAfter.IsZero() {
+		bound, err := attributevalue.Marshal(options.CreatedAfter.UTC().Truncate(time.Second).Add(-time.Second))
+		if err != nil {
+			return "", err
+		}
+		values[":created_after"] = bound
+		conditions = append(conditions, "#created_at >= :created_after")
+	}
+	if !options.CreatedBefore.IsZero() {
+		bound, err := attributevalue.Marshal(options.CreatedBefore.UTC().Truncate(time.Second).Add(time.Second))
+		if err != nil {
+			return "", err
+		}
+		values[":created_before"] = bound
+		conditions = append(conditions, "#created_at <= :created_before")
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	names["#created_at"] = r.attrNames.Storage("created_at")
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return conditions[0] + " AND " + conditions[1], nil
+}
+
+// filterCreatedRange drops items outside the options' created_at bounds
+func filterCreatedRange(items []models.Item, options *ListItemsOptions) []models.Item {
+	if !options.hasCreatedRange() {
+		return items
+	}
+	filtered := items[:0]
+	for _, item := range items {
+		if options.inCreatedRange(&item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// createdRangeScanMock simulates a scan that, like DynamoDB, compares the
+// stored created_at strings against the filter's bounds
+func createdRangeScanMock(t *testing.T, createdAt []time.Time) *mockDynamoDBClient {
+	var rows []map[string]types.AttributeValue
+	for i, ts := range createdAt {
+		item := models.NewItem("Item", "Description")
+		item.ID = string(rune('a' + i))
+		item.CreatedAt = ts
+		av, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			t.Fatalf("Failed to marshal item: %v", err)
+		}
+		rows = append(rows, av)
+	}
+
+	return &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			filter := aws.ToString(params.FilterExpression)
+			bound := func(name string) string {
+				if v, ok := params.ExpressionAttributeValues[name].(*types.AttributeValueMemberS); ok {
+					return v.Value
+				}
+				return ""
+			}
+			after, before := bound(":created_after"), bound(":created_before")
+			if strings.Contains(filter, ":created_after") != (after != "") || strings.Contains(filter, ":created_before") != (before != "") {
+				t.Errorf("Expected filter %q to match its values", filter)
+			}
+
+			output := &dynamodb.ScanOutput{}
+			for _, av := range rows {
+				stored := av["created_at"].(*types.AttributeValueMemberS).Value
+				if (after != "" && stored < after) || (before != "" && stored > before) {
+					continue
+				}
+				output.Items = append(output.Items, av)
+			}
+			return output, nil
+		},
+	}
+}
+
+func TestListItems_CreatedRange(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	createdAt := []time.Time{
+		base.Add(-time.Hour),
+		base.Add(-500 * time.Millisecond),
+		base,
+		base.Add(500 * time.Millisecond),
+		base.Add(time.Hour),
+		base.Add(time.Hour + 500*time.Millisecond),
+	}
+
+	tests := []struct {
+		name     string
+		options  ListItemsOptions
+		expected string
+	}{
+		{name: "After only", options: ListItemsOptions{CreatedAfter: base}, expected: "c,d,e,f"},
+		{name: "Before only", options: ListItemsOptions{CreatedBefore: base.Add(time.Hour)}, expected: "a,b,c,d,e"},
+		{name: "Both bounds", options: ListItemsOptions{CreatedAfter: base.Add(100 * time.Millisecond), CreatedBefore: base.Add(time.Hour)}, expected: "d,e"},
+		{name: "No bounds", options: ListItemsOptions{}, expected: "a,b,c,d,e,f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewDynamoDBRepository(createdRangeScanMock(t, createdAt), "items")
+			result, err := repo.ListItems(context.Background(), &tt.options)
+			if err != nil {
+				t.Fatalf("Failed to list items: %v", err)
+			}
+
+			var ids []string
+			for _, item := range result.Items {
+				ids = append(ids, item.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMemoryListItems_CreatedRange(t *testing.T) {
+	repo := NewMemoryRepository()
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for day := 0; day < 10; day++ {
+		item := models.NewItem("Item", "Description")
+		item.CreatedAt = base.AddDate(0, 0, day)
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+
+	result, err := repo.ListItems(context.Background(), &ListItemsOptions{CreatedAfter: base.AddDate(0, 0, 3)})
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if len(result.Items) != 7 {
+		t.Fatalf("Expected 7 items created since day 3, got %d", len(result.Items))
+	}
+	if !result.Items[0].CreatedAt.Equal(base.AddDate(0, 0, 3)) {
+		t.Errorf("Expected the lower bound to be inclusive, got first item at %v", result.Items[0].CreatedAt)
+	}
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// Creation date index.
+//
+// Listing the items created on one calendar day queries a GSI partitioned
+// by created_date, the day the item was created in CREATED_DATE_TIMEZONE,
+// and sorted by the listing index's "<created_at>#<id>", so only that day's
+// items are read. created_date is written on create; items written before
+// the index was introduced, or before the timezone last changed, need it
+// backfilled to appear. Without the index the day is listed as a created_at
+// range instead, which honors the timezone for every item.
+const (
+	// DefaultDateIndexName is the GSI used for listing by creation date
+	DefaultDateIndexName = "created_date-index"
+
+	datePartitionAttr = "created_date"
+	dateSortAttr      = listSortAttr
+
+	// CreatedDateFormat is the layout of created_date and of the dates
+	// listings are requested for
+	CreatedDateFormat = "2006-01-02"
+)
+
+// CreatedDateLocationFromEnv reads the timezone that calendar days are
+// counted in from CREATED_DATE_TIMEZONE, an IANA name such as
+// America/New_York. Unset or invalid values fall back to UTC.
+func CreatedDateLocationFromEnv() *time.Location {
+	value := os.Getenv("CREATED_DATE_TIMEZONE")
+	if value == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		log.Printf("Ignoring invalid CREATED_DATE_TIMEZONE %q, using UTC: %v", value, err)
+		return time.UTC
+	}
+	return location
+}
+
+// addDateIndexAttributes adds the creation date index partition key to a
+// marshaled item. It is owned by the repository and not subject to
+// attribute name mapping.
+func addDateIndexAttributes(av map[string]types.AttributeValue, item *models.Item, location *time.Location) {
+	av[datePartitionAttr] = &types.AttributeValueMemberS{Value: item.CreatedAt.In(location).Format(CreatedDateFormat)}
+}
+
+// withCreatedDate returns a copy of the options whose created_at bounds are
+// narrowed to the calendar day CreatedDate in location. Days are midnight
+// to midnight, so they span 23 or 25 hours across DST changes.
+func (o *ListItemsOptions) withCreatedDate(location *time.Location) (*ListItemsOptions, error) {
+	start, err := time.ParseInLocation(CreatedDateFormat, o.CreatedDate, location)
+	if err != nil {
+		return nil, fmt.Errorf("%w: created date must be a YYYY-MM-DD date, got %q", ErrInvalidInput, o.CreatedDate)
+	}
+	end := start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	narrowed := *o
+	if narrowed.CreatedAfter.IsZero() || narrowed.CreatedAfter.Before(start) {
+		narrowed.CreatedAfter = start
+	}
+	if narrowed.CreatedBefore.IsZero() || narrowed.CreatedBefore.After(end) {
+		narrowed.CreatedBefore = end
+	}
+	return &narrowed, nil
+}
+
+// queryItemsByDate lists the items created on options.CreatedDate in
+// created_at#id order, or its reverse, using the creation date GSI. The
+// options' created_at bounds, which cover the day, still filter the
+// results, so items bucketed under another timezone are left out.
+func (r *DynamoDBRepository) queryItemsByDate(ctx context.Context, options *ListItemsOptions) (*ListItemsResult, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(r.dateIndexName),
+		KeyConditionExpression: aws.String("#created_date = :created_date"),
+		ExpressionAttributeNames: map[string]string{
+			"#created_date": datePartitionAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":created_date": &types.AttributeValueMemberS{Value: options.CreatedDate},
+		},
+		ScanIndexForward: aws.Bool(!options.Descending),
+	}
+	optionsFilter, err := r.listFilter(options, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	// The day's created_at bounds always make the filter non-empty
+	if options.StatusFilter != "" {
+		input.ExpressionAttributeNames["#status"] = r.attrNames.Storage(statusPartitionAttr)
+		input.ExpressionAttributeValues[":status"] = &types.AttributeValueMemberS{Value: options.StatusFilter}
+		optionsFilter += " AND #status = :status"
+	}
+	input.FilterExpression = aws.String(optionsFilter)
+	input.ProjectionExpression = r.projection(options.projectionFields(), input.ExpressionAttributeNames)
+
+	rows, lastKey, err := readPage(options.Limit, options.LastEvaluatedKey, func(limit int32, startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+		input.Limit = aws.Int32(limit)
+		input.ExclusiveStartKey = startKey
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Items, result.LastEvaluatedKey, nil
+	})
+	if err != nil {
+		return nil, r.indexQueryError(r.dateIndexName, err)
+	}
+
+	var items []models.Item
+	if err := attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(rows), &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+	items = filterCreatedRange(items, options)
+	options.sortPage(items)
+
+	return &ListItemsResult{
+		Items:            items,
+		LastEvaluatedKey: lastKey,
+		HasMore:          lastKey != nil,
+	}, nil
+}
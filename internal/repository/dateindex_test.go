@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// Items around 2024-01-15 in New York (UTC-5). Days there start at 05:00
+// UTC, so UTC and New York disagree on the day of the items created
+// between midnight and 05:00 UTC.
+var dateBoundaryItems = map[string]time.Time{
+	"before-start": time.Date(2024, 1, 15, 4, 30, 0, 0, time.UTC), // 14th in New York
+	"at-start":     time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC),
+	"late-evening": time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC), // 16th in UTC
+	"after-end":    time.Date(2024, 1, 16, 5, 0, 0, 0, time.UTC), // 16th in New York
+}
+
+// dateBoundaryItem returns the boundary item with the given ID
+func dateBoundaryItem(id string) *models.Item {
+	item := models.NewItem("Item "+id, "Description")
+	item.ID = id
+	item.CreatedAt = dateBoundaryItems[id]
+	item.UpdatedAt = item.CreatedAt
+	return item
+}
+
+// itemIDs returns the sorted IDs of items
+func itemIDs(items []models.Item) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestCreatedDateLocationFromEnv(t *testing.T) {
+	for value, want := range map[string]string{
+		"":                 "UTC",
+		"America/New_York": "America/New_York",
+		"Mars/Olympus":     "UTC",
+	} {
+		t.Setenv("CREATED_DATE_TIMEZONE", value)
+		if got := CreatedDateLocationFromEnv().String(); got != want {
+			t.Errorf("Expected %q to give %s, got %s", value, want, got)
+		}
+	}
+}
+
+func TestMemoryRepository_ListItemsByCreatedDate(t *testing.T) {
+	t.Setenv("CREATED_DATE_TIMEZONE", "America/New_York")
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	for id := range dateBoundaryItems {
+		if err := repo.CreateItem(ctx, dateBoundaryItem(id)); err != nil {
+			t.Fatalf("Failed to create item: %v", err)
+		}
+	}
+
+	result, err := repo.ListItems(ctx, &ListItemsOptions{CreatedDate: "2024-01-15"})
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if got, want := itemIDs(result.Items), []string{"at-start", "late-evening"}; !slices.Equal(got, want) {
+		t.Errorf("Expected items %v, got %v", want, got)
+	}
+
+	if _, err := repo.ListItems(ctx, &ListItemsOptions{CreatedDate: "2024-13-01"}); !IsValidationError(err) {
+		t.Errorf("Expected an invalid date to be rejected, got %v", err)
+	}
+}
+
+func TestDynamoDBRepository_CreateItemSetsCreatedDate(t *testing.T) {
+	t.Setenv("CREATED_DATE_TIMEZONE", "America/New_York")
+	var stored map[string]types.AttributeValue
+	mock := &mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			stored = params.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(mock, "items")
+
+	if err := repo.CreateItem(context.Background(), dateBoundaryItem("late-evening")); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	if got := stored[datePartitionAttr].(*types.AttributeValueMemberS).Value; got != "2024-01-15" {
+		t.Errorf("Expected created_date 2024-01-15 in New York, got %s", got)
+	}
+}
+
+func TestDynamoDBRepository_ListItemsByCreatedDateQueriesIndex(t *testing.T) {
+	t.Setenv("CREATED_DATE_TIMEZONE", "America/New_York")
+	var queries []*dynamodb.QueryInput
+	mock := &mockDynamoDBClient{
+		DescribeTableFn: func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+			return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+				GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+					{IndexName: aws.String(DefaultDateIndexName)},
+				},
+			}}, nil
+		},
+		QueryFn: func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			queries = append(queries, params)
+			// An item bucketed before the timezone changed is still in the
+			// partition, but outside the day
+			var rows []map[string]types.AttributeValue
+			for _, id := range []string{"at-start", "late-evening", "after-end"} {
+				row, err := attributevalue.MarshalMap(dateBoundaryItem(id))
+				if err != nil {
+					t.Fatalf("Failed to marshal item: %v", err)
+				}
+				rows = append(rows, row)
+			}
+			return &dynamodb.QueryOutput{Items: rows}, nil
+		},
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			panic("date listings should not scan")
+		},
+	}
+	repo := NewDynamoDBRepository(mock, "items")
+	repo.dateIndexName = DefaultDateIndexName
+
+	result, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 10, CreatedDate: "2024-01-15"})
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if got, want := itemIDs(result.Items), []string{"at-start", "late-evening"}; !slices.Equal(got, want) {
+		t.Errorf("Expected items %v, got %v", want, got)
+	}
+
+	if len(queries) != 1 {
+		t.Fatalf("Expected 1 query, got %d", len(queries))
+	}
+	query := queries[0]
+	if aws.ToString(query.IndexName) != DefaultDateIndexName {
+		t.Errorf("Expected index %s, got %s", DefaultDateIndexName, aws.ToString(query.IndexName))
+	}
+	if got := query.ExpressionAttributeValues[":created_date"].(*types.AttributeValueMemberS).Value; got != "2024-01-15" {
+		t.Errorf("Expected the 2024-01-15 partition, got %s", got)
+	}
+}
+
+func TestDynamoDBRepository_ListItemsByCreatedDateWithoutIndex(t *testing.T) {
+	t.Setenv("CREATED_DATE_TIMEZONE", "America/New_York")
+	mock := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			if _, ok := params.ExpressionAttributeValues[":created_after"]; !ok {
+				t.Error("Expected the scan to be bounded by the day's created_at range")
+			}
+			var rows []map[string]types.AttributeValue
+			for id := range dateBoundaryItems {
+				row, err := attributevalue.MarshalMap(dateBoundaryItem(id))
+				if err != nil {
+					t.Fatalf("Failed to marshal item: %v", err)
+				}
+				rows = append(rows, row)
+			}
+			return &dynamodb.ScanOutput{Items: rows}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(mock, "items")
+
+	result, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 10, CreatedDate: "2024-01-15"})
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if got, want := itemIDs(result.Items), []string{"at-start", "late-evening"}; !slices.Equal(got, want) {
+		t.Errorf("Expected items %v, got %v", want, got)
+	}
+}
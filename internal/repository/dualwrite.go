@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"fis-playground/internal/models"
+)
+
+// DualWriteRepository writes to a primary and a secondary repository, such
+// as the old and new tables during a schema migration, and reads from the
+// primary only.
+//
+// Every write goes to the primary first; only once it succeeds is the same
+// write repeated on the secondary. The primary's outcome is what callers
+// see: a failed secondary write is logged and otherwise ignored, so the
+// secondary can fall behind, for example on items written before dual
+// writes were enabled, until it is backfilled.
+type DualWriteRepository struct {
+	ItemRepository
+	secondary ItemRepository
+}
+
+// NewDualWriteRepository wraps primary, repeating its writes on secondary
+func NewDualWriteRepository(primary, secondary ItemRepository) *DualWriteRepository {
+	return &DualWriteRepository{ItemRepository: primary, secondary: secondary}
+}
+
+// DualWriteTableFromEnv returns the secondary table configured via
+// DUAL_WRITE_TABLE_NAME when DUAL_WRITE_ENABLED is true. An empty result,
+// the default, disables dual writes.
+func DualWriteTableFromEnv() string {
+	value := os.Getenv("DUAL_WRITE_ENABLED")
+	if value == "" {
+		return ""
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Ignoring invalid DUAL_WRITE_ENABLED %q, dual writes disabled", value)
+		return ""
+	}
+	if !enabled {
+		return ""
+	}
+	table := os.Getenv("DUAL_WRITE_TABLE_NAME")
+	if table == "" {
+		log.Printf("DUAL_WRITE_ENABLED is set without DUAL_WRITE_TABLE_NAME, dual writes disabled")
+	}
+	return table
+}
+
+// mirror logs a failed secondary write
+func (d *DualWriteRepository) mirror(operation string, err error) {
+	if err != nil {
+		log.Printf("Dual write: secondary %s failed: %v", operation, err)
+	}
+}
+
+// CreateItem creates the item in both repositories, under the ID the
+// primary gave it
+func (d *DualWriteRepository) CreateItem(ctx context.Context, item *models.Item) error {
+	if err := d.ItemRepository.CreateItem(ctx, item); err != nil {
+		return err
+	}
+	copied := *item
+	d.mirror("create of item "+item.ID, d.secondary.CreateItem(ctx, &copied))
+	return nil
+}
+
+// BatchCreateItems creates the items in the primary, then the ones it
+// accepted in the secondary. Batch creates generate new IDs, so the
+// secondary creates each item in turn under the primary's ID instead.
+func (d *DualWriteRepository) BatchCreateItems(ctx context.Context, items []*models.Item) ([]error, error) {
+	errs, err := d.ItemRepository.BatchCreateItems(ctx, items)
+	if err != nil {
+		return errs, err
+	}
+	for i, item := range items {
+		if i < len(errs) && errs[i] != nil {
+			continue
+		}
+		copied := *item
+		d.mirror("create of item "+item.ID, d.secondary.CreateItem(ctx, &copied))
+	}
+	return errs, nil
+}
+
+// UpdateItem updates the item in both repositories
+func (d *DualWriteRepository) UpdateItem(ctx context.Context, id string, updates *models.UpdateItemRequest) (*models.Item, error) {
+	item, err := d.ItemRepository.UpdateItem(ctx, id, updates)
+	if err != nil {
+		return nil, err
+	}
+	_, err = d.secondary.UpdateItem(ctx, id, updates)
+	d.mirror("update of item "+id, err)
+	return item, nil
+}
+
+// PatchItem patches the item in both repositories
+func (d *DualWriteRepository) PatchItem(ctx context.Context, id string, patch *models.PatchItemRequest) (*models.Item, error) {
+	item, err := d.ItemRepository.PatchItem(ctx, id, patch)
+	if err != nil {
+		return nil, err
+	}
+	_, err = d.secondary.PatchItem(ctx, id, patch)
+	d.mirror("patch of item "+id, err)
+	return item, nil
+}
+
+// DeleteItem deletes the item from both repositories
+func (d *DualWriteRepository) DeleteItem(ctx context.Context, id string, options *DeleteItemOptions) error {
+	if err := d.ItemRepository.DeleteItem(ctx, id, options); err != nil {
+		return err
+	}
+	d.mirror("delete of item "+id, d.secondary.DeleteItem(ctx, id, options))
+	return nil
+}
+
+// RestoreItem restores the item in both repositories
+func (d *DualWriteRepository) RestoreItem(ctx context.Context, id string, requireOwner string) (*models.Item, error) {
+	item, err := d.ItemRepository.RestoreItem(ctx, id, requireOwner)
+	if err != nil {
+		return nil, err
+	}
+	_, err = d.secondary.RestoreItem(ctx, id, requireOwner)
+	d.mirror("restore of item "+id, err)
+	return item, nil
+}
+
+// GetForUpdate takes the item's lease in both repositories
+func (d *DualWriteRepository) GetForUpdate(ctx context.Context, id string, holder string, leaseSeconds int) (*models.Item, error) {
+	item, err := d.ItemRepository.GetForUpdate(ctx, id, holder, leaseSeconds)
+	if err != nil {
+		return nil, err
+	}
+	_, err = d.secondary.GetForUpdate(ctx, id, holder, leaseSeconds)
+	d.mirror("lease of item "+id, err)
+	return item, nil
+}
+
+// PurgeItem purges the item from both repositories
+func (d *DualWriteRepository) PurgeItem(ctx context.Context, id string) error {
+	if err := d.ItemRepository.PurgeItem(ctx, id); err != nil {
+		return err
+	}
+	d.mirror("purge of item "+id, d.secondary.PurgeItem(ctx, id))
+	return nil
+}
+
+// IncrementViewCount counts the view in both repositories, returning the
+// primary's count
+func (d *DualWriteRepository) IncrementViewCount(ctx context.Context, id string) (int64, error) {
+	count, err := d.ItemRepository.IncrementViewCount(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	_, err = d.secondary.IncrementViewCount(ctx, id)
+	d.mirror("view count of item "+id, err)
+	return count, nil
+}
+
+// CompactDeletedItems compacts both repositories, returning the primary's
+// count
+func (d *DualWriteRepository) CompactDeletedItems(ctx context.Context, before time.Time) (int, error) {
+	purged, err := d.ItemRepository.CompactDeletedItems(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	_, err = d.secondary.CompactDeletedItems(ctx, before)
+	d.mirror("compaction", err)
+	return purged, nil
+}
+
+// BulkTagItems changes the tags in both repositories, returning the
+// primary's result. Dry runs only read the primary.
+func (d *DualWriteRepository) BulkTagItems(ctx context.Context, options *BulkTagOptions) (*BulkTagResult, error) {
+	result, err := d.ItemRepository.BulkTagItems(ctx, options)
+	if err != nil || options.DryRun {
+		return result, err
+	}
+	_, err = d.secondary.BulkTagItems(ctx, options)
+	d.mirror("bulk tag", err)
+	return result, nil
+}
+
+// DeleteItemsByFilter deletes the matching items from both repositories,
+// returning the primary's count. Dry runs only read the primary.
+func (d *DualWriteRepository) DeleteItemsByFilter(ctx context.Context, filter models.ItemFilter, dryRun bool) (int, error) {
+	deleted, err := d.ItemRepository.DeleteItemsByFilter(ctx, filter, dryRun)
+	if err != nil || dryRun {
+		return deleted, err
+	}
+	_, err = d.secondary.DeleteItemsByFilter(ctx, filter, dryRun)
+	d.mirror("bulk delete", err)
+	return deleted, nil
+}
+
+// HealthCheck delegates to the primary repository when it supports it
+func (d *DualWriteRepository) HealthCheck(ctx context.Context) error {
+	if checker, ok := d.ItemRepository.(interface{ HealthCheck(context.Context) error }); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// GetTableName returns the primary repository's table name, if any
+func (d *DualWriteRepository) GetTableName() string {
+	if named, ok := d.ItemRepository.(interface{ GetTableName() string }); ok {
+		return named.GetTableName()
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"fis-playground/internal/models"
+)
+
+// failingWriteRepository fails every create, update and delete
+type failingWriteRepository struct {
+	*MemoryRepository
+}
+
+var errSecondaryDown = errors.New("secondary unavailable")
+
+func (r *failingWriteRepository) CreateItem(ctx context.Context, item *models.Item) error {
+	return errSecondaryDown
+}
+
+func (r *failingWriteRepository) UpdateItem(ctx context.Context, id string, updates *models.UpdateItemRequest) (*models.Item, error) {
+	return nil, errSecondaryDown
+}
+
+func (r *failingWriteRepository) DeleteItem(ctx context.Context, id string, options *DeleteItemOptions) error {
+	return errSecondaryDown
+}
+
+func TestDualWriteRepository_WritesBoth(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := NewMemoryRepository(), NewMemoryRepository()
+	repo := NewDualWriteRepository(primary, secondary)
+
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(ctx, item); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	for name, r := range map[string]ItemRepository{"primary": primary, "secondary": secondary} {
+		if _, err := r.GetItem(ctx, item.ID, nil); err != nil {
+			t.Errorf("Expected the item in the %s, got %v", name, err)
+		}
+	}
+
+	if _, err := repo.UpdateItem(ctx, item.ID, &models.UpdateItemRequest{Name: "Renamed"}); err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+	mirrored, err := secondary.GetItem(ctx, item.ID, nil)
+	if err != nil || mirrored.Name != "Renamed" {
+		t.Errorf("Expected the update in the secondary, got %+v, %v", mirrored, err)
+	}
+
+	if err := repo.DeleteItem(ctx, item.ID, nil); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+	for name, r := range map[string]ItemRepository{"primary": primary, "secondary": secondary} {
+		if _, err := r.GetItem(ctx, item.ID, nil); !errors.Is(err, ErrItemNotFound) {
+			t.Errorf("Expected the item deleted from the %s, got %v", name, err)
+		}
+	}
+}
+
+func TestDualWriteRepository_BatchCreateWritesBoth(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := NewMemoryRepository(), NewMemoryRepository()
+	repo := NewDualWriteRepository(primary, secondary)
+
+	items := []*models.Item{models.NewItem("First", "Description"), models.NewItem("Second", "Description")}
+	if _, err := repo.BatchCreateItems(ctx, items); err != nil {
+		t.Fatalf("Failed to create items: %v", err)
+	}
+	for _, item := range items {
+		if _, err := secondary.GetItem(ctx, item.ID, nil); err != nil {
+			t.Errorf("Expected %s in the secondary, got %v", item.ID, err)
+		}
+	}
+}
+
+func TestDualWriteRepository_SecondaryFailureIsNotFatal(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemoryRepository()
+	repo := NewDualWriteRepository(primary, &failingWriteRepository{MemoryRepository: NewMemoryRepository()})
+
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(ctx, item); err != nil {
+		t.Fatalf("Expected the create to succeed, got %v", err)
+	}
+	updated, err := repo.UpdateItem(ctx, item.ID, &models.UpdateItemRequest{Name: "Renamed"})
+	if err != nil || updated.Name != "Renamed" {
+		t.Fatalf("Expected the primary's update, got %+v, %v", updated, err)
+	}
+	if err := repo.DeleteItem(ctx, item.ID, nil); err != nil {
+		t.Fatalf("Expected the delete to succeed, got %v", err)
+	}
+	if _, err := primary.GetItem(ctx, item.ID, nil); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expected the item deleted from the primary, got %v", err)
+	}
+}
+
+func TestDualWriteRepository_ReadsPrimaryAndSkipsFailedWrites(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := NewMemoryRepository(), NewMemoryRepository()
+	repo := NewDualWriteRepository(primary, secondary)
+
+	onlySecondary := models.NewItem("Secondary", "Description")
+	if err := secondary.CreateItem(ctx, onlySecondary); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	if _, err := repo.GetItem(ctx, onlySecondary.ID, nil); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expected reads to use the primary only, got %v", err)
+	}
+
+	// The primary rejects the update, so the secondary must not apply it
+	if _, err := repo.UpdateItem(ctx, onlySecondary.ID, &models.UpdateItemRequest{Name: "Renamed"}); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("Expected the primary's error, got %v", err)
+	}
+	unchanged, err := secondary.GetItem(ctx, onlySecondary.ID, nil)
+	if err != nil || unchanged.Name != "Secondary" {
+		t.Errorf("Expected the secondary left unchanged, got %+v, %v", unchanged, err)
+	}
+}
+
+func TestDualWriteTableFromEnv(t *testing.T) {
+	tests := []struct {
+		enabled, table, expected string
+	}{
+		{enabled: "", table: "items-v2", expected: ""},
+		{enabled: "false", table: "items-v2", expected: ""},
+		{enabled: "yes please", table: "items-v2", expected: ""},
+		{enabled: "true", table: "", expected: ""},
+		{enabled: "true", table: "items-v2", expected: "items-v2"},
+	}
+	for _, tt := range tests {
+		t.Setenv("DUAL_WRITE_ENABLED", tt.enabled)
+		t.Setenv("DUAL_WRITE_TABLE_NAME", tt.table)
+		if got := DualWriteTableFromEnv(); got != tt.expected {
+			t.Errorf("Expected %q for enabled=%q table=%q, got %q", tt.expected, tt.enabled, tt.table, got)
+		}
+	}
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -19,6 +24,60 @@ import (
 type ListItemsOptions struct {
 	Limit            int32
 	LastEvaluatedKey map[string]types.AttributeValue
+	// StatusFilter, when set, only lists items with this status
+	StatusFilter string
+	// CreatedAfter and CreatedBefore, when set, only list items created at
+	// or after and at or before them
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// CreatedDate, when set, only lists items created on this calendar
+	// day, a CreatedDateFormat date in the repository's timezone
+	CreatedDate string
+	// TagFilter, when set, only lists items carrying every one of these
+	// tags; several tags narrow the listing (AND), they don't widen it.
+	// With TagMatchAny, items carrying any one of them are listed (OR).
+	TagFilter   []string
+	TagMatchAny bool
+	// OwnerFilter, when set, only lists items owned by this user;
+	// UnownedOnly only lists items without an owner
+	OwnerFilter string
+	UnownedOnly bool
+	// IncludeDeleted also lists soft-deleted items
+	IncludeDeleted bool
+	// SortField orders the listing by created_at, the default, updated_at
+	// or name; Descending reverses the order. Only created_at order spans
+	// pages when an index is used; other fields sort each page.
+	SortField  string
+	Descending bool
+	// ProjectionFields, when set, only reads these item fields, by their
+	// JSON names, plus those the listing itself needs
+	ProjectionFields []string
+}
+
+// OwnerScope limits a read to one user's items, or to the items without
+// an owner. A nil scope reads every item.
+type OwnerScope struct {
+	// Owner, when set, only reads items owned by this user
+	Owner string
+	// UnownedOnly only reads items without an owner
+	UnownedOnly bool
+}
+
+// projectionFields returns the fields a projected listing reads, including
+// its sort field
+func (o *ListItemsOptions) projectionFields() []string {
+	if len(o.ProjectionFields) == 0 {
+		return nil
+	}
+	return append(slices.Clone(o.ProjectionFields), o.SortField)
+}
+
+// sortPage orders a page read from an index, which is already in created_at
+// order in the requested direction, by any other sort field
+func (o *ListItemsOptions) sortPage(items []models.Item) {
+	if o.SortField != "" && o.SortField != models.SortCreatedAt {
+		models.SortItems(items, o.SortField, o.Descending)
+	}
 }
 
 // ListItemsResult contains the result of listing items with pagination info
@@ -28,39 +87,114 @@ type ListItemsResult struct {
 	HasMore          bool
 }
 
+// DeleteItemOptions contains conditions a deletion must satisfy
+type DeleteItemOptions struct {
+	// RequireStatus, when set, only deletes the item if it has this status
+	RequireStatus string
+	// RequireOwner, when set, only deletes the item if it was created by
+	// this principal
+	RequireOwner string
+	// Generation, when set, only deletes the item if its generation matches
+	Generation *int64
+}
+
+// GetItemOptions controls how GetItem reads an item
+type GetItemOptions struct {
+	// Fields, when set, only reads these item fields, by their JSON names,
+	// plus those callers check before returning an item
+	Fields []string
+	// Consistent reads the item with a strongly consistent read, which
+	// reflects every write that succeeded before it
+	Consistent bool
+}
+
 // ItemRepository defines the interface for item data operations
 type ItemRepository interface {
 	CreateItem(ctx context.Context, item *models.Item) error
-	GetItem(ctx context.Context, id string) (*models.Item, error)
+	BatchCreateItems(ctx context.Context, items []*models.Item) ([]error, error)
+	GetItem(ctx context.Context, id string, options *GetItemOptions) (*models.Item, error)
+	BatchGetItems(ctx context.Context, ids []string) ([]models.Item, error)
 	ListItems(ctx context.Context, options *ListItemsOptions) (*ListItemsResult, error)
 	UpdateItem(ctx context.Context, id string, updates *models.UpdateItemRequest) (*models.Item, error)
-	DeleteItem(ctx context.Context, id string) error
+	PatchItem(ctx context.Context, id string, patch *models.PatchItemRequest) (*models.Item, error)
+	DeleteItem(ctx context.Context, id string, options *DeleteItemOptions) error
+	RestoreItem(ctx context.Context, id string, requireOwner string) (*models.Item, error)
+	GetForUpdate(ctx context.Context, id string, holder string, leaseSeconds int) (*models.Item, error)
+	PurgeItem(ctx context.Context, id string) error
+	IncrementViewCount(ctx context.Context, id string) (int64, error)
+	CompactDeletedItems(ctx context.Context, before time.Time) (int, error)
+	GetRawItem(ctx context.Context, id string) (map[string]types.AttributeValue, error)
+	FacetItems(ctx context.Context, field string, scope *OwnerScope) (*models.FacetResult, error)
+	AutocompleteItems(ctx context.Context, prefix string, limit int, scope *OwnerScope) ([]models.Item, error)
+	BulkTagItems(ctx context.Context, options *BulkTagOptions) (*BulkTagResult, error)
+	DeleteItemsByFilter(ctx context.Context, filter models.ItemFilter, dryRun bool) (int, error)
 }
 
 // DynamoDBRepository implements ItemRepository using DynamoDB
 type DynamoDBRepository struct {
-	client    *dynamodb.Client
-	tableName string
+	client          DynamoDBAPI
+	tableName       string
+	maxItems        int64  // 0 disables the item cap
+	listIndexName   string // empty lists with a scan
+	statusIndexName string // empty filters status listings instead
+	dateIndexName   string // empty lists a day as a created_at range instead
+	nameIndexName   string // empty autocompletes with a scan
+	attrNames       AttributeNames
+	dateLocation    *time.Location
+	maxRetries      int  // retries of throttled single-item calls
+	softDelete      bool // deletes mark items deleted instead of removing them
+	nameUniqueness  NameUniqueness
+	trackVersion    bool // writes record when items last changed, for CollectionVersion
+
+	indexMu    sync.Mutex
+	indexNames map[string]bool // GSIs on the table; nil until looked up
 }
 
 // NewDynamoDBRepository creates a new DynamoDB repository instance
-func NewDynamoDBRepository(client *dynamodb.Client, tableName string) *DynamoDBRepository {
+func NewDynamoDBRepository(client DynamoDBAPI, tableName string) *DynamoDBRepository {
 	return &DynamoDBRepository{
-		client:    client,
-		tableName: tableName,
+		client:         client,
+		tableName:      tableName,
+		maxRetries:     DefaultMaxRetries,
+		softDelete:     softDeleteFromEnv(),
+		nameUniqueness: NameUniquenessFromEnv(),
+		trackVersion:   collectionVersionFromEnv(),
+		dateLocation:   CreatedDateLocationFromEnv(),
 	}
 }
 
 // NewDynamoDBRepositoryFromManager creates a new DynamoDB repository using ClientManager
 func NewDynamoDBRepositoryFromManager(clientManager *ClientManager) *DynamoDBRepository {
+	return NewDynamoDBRepositoryForTable(clientManager, clientManager.GetTableName())
+}
+
+// NewDynamoDBRepositoryForTable creates a repository for tableName that
+// shares the manager's client and configuration, such as the secondary
+// table of dual writes
+func NewDynamoDBRepositoryForTable(clientManager *ClientManager, tableName string) *DynamoDBRepository {
+	var client DynamoDBAPI = clientManager.GetClient()
+	if rate := clientManager.GetConfig().MaxWritesPerSec; rate > 0 {
+		client = NewWriteLimitedClient(client, rate)
+	}
 	return &DynamoDBRepository{
-		client:    clientManager.GetClient(),
-		tableName: clientManager.GetTableName(),
+		client:          client,
+		tableName:       tableName,
+		maxItems:        clientManager.GetConfig().MaxItems,
+		listIndexName:   clientManager.GetConfig().ListIndexName,
+		statusIndexName: clientManager.GetConfig().StatusIndexName,
+		dateIndexName:   clientManager.GetConfig().DateIndexName,
+		nameIndexName:   clientManager.GetConfig().NameIndexName,
+		attrNames:       clientManager.GetConfig().AttributeNames,
+		maxRetries:      clientManager.GetConfig().MaxRetries,
+		softDelete:      softDeleteFromEnv(),
+		nameUniqueness:  NameUniquenessFromEnv(),
+		trackVersion:    collectionVersionFromEnv(),
+		dateLocation:    CreatedDateLocationFromEnv(),
 	}
 }
 
 // GetClient returns the DynamoDB client (for testing purposes)
-func (r *DynamoDBRepository) GetClient() *dynamodb.Client {
+func (r *DynamoDBRepository) GetClient() DynamoDBAPI {
 	return r.client
 }
 
@@ -71,13 +205,14 @@ func (r *DynamoDBRepository) GetTableName() string {
 
 // HealthCheck verifies the repository can connect to DynamoDB
 func (r *DynamoDBRepository) HealthCheck(ctx context.Context) error {
-	cm := &ClientManager{
-		client: r.client,
-		config: &DynamoDBConfig{
-			TableName: r.tableName,
-		},
+	_, err := r.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(r.tableName),
+	})
+	if err != nil {
+		return fmt.Errorf("DynamoDB health check failed: %w", err)
 	}
-	return cm.HealthCheck(ctx)
+
+	return nil
 }
 
 // CreateItem creates a new item in DynamoDB with proper error handling
@@ -86,10 +221,15 @@ func (r *DynamoDBRepository) CreateItem(ctx context.Context, item *models.Item)
 	if item.ID == "" {
 		item.ID = uuid.New().String()
 	}
+	// Bookkeeping records are never written as items, even when validation
+	// is skipped
+	if isMetaItemID(item.ID) {
+		return fmt.Errorf("%w: item ID %q is reserved", ErrInvalidInput, item.ID)
+	}
 
 	// Validate the item
-	if err := item.Validate(); err != nil {
-		return fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	if err := validate(ctx, item); err != nil {
+		return err
 	}
 
 	// Convert item to DynamoDB attribute values
@@ -97,16 +237,50 @@ func (r *DynamoDBRepository) CreateItem(ctx context.Context, item *models.Item)
 	if err != nil {
 		return fmt.Errorf("failed to marshal item: %w", err)
 	}
+	av = r.attrNames.toStorage(av)
+	addListIndexAttributes(av, item)
+	addNameIndexAttributes(av, item)
+	addDateIndexAttributes(av, item, r.dateLocation)
+
+	// Claim the name, when names are unique, and a slot under the item cap
+	// before writing
+	releaseName, err := r.claimName(ctx, item.ID, item.Name, item.Category)
+	if err != nil {
+		return err
+	}
+	if r.maxItems > 0 {
+		if err := r.reserveItemSlot(ctx); err != nil {
+			releaseName()
+			return err
+		}
+	}
 
-	// Create the item with conditional check to prevent duplicates
+	// Create the item with conditional check to prevent duplicates. The
+	// stored item is returned when the check fails, so a retry that fails
+	// because its first attempt went through can be told apart from a
+	// duplicate.
 	input := &dynamodb.PutItemInput{
-		TableName:           aws.String(r.tableName),
-		Item:                av,
-		ConditionExpression: aws.String("attribute_not_exists(id)"),
+		TableName:                           aws.String(r.tableName),
+		Item:                                av,
+		ConditionExpression:                 aws.String("attribute_not_exists(#id)"),
+		ExpressionAttributeNames:            r.attrNames.placeholders("id"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	}
 
-	_, err = r.client.PutItem(ctx, input)
+	attempts := 0
+	_, err = withRetry(ctx, r.maxRetries, func() (*dynamodb.PutItemOutput, error) {
+		attempts++
+		return r.client.PutItem(ctx, input)
+	})
+	if err != nil && attempts > 1 && writtenByAttempt(err, av) {
+		err = nil
+	}
 	if err != nil {
+		releaseName()
+		if r.maxItems > 0 {
+			r.releaseItemSlot(ctx)
+		}
+
 		// For create, ConditionalCheckFailedException means item already exists
 		var conditionalCheckFailed *types.ConditionalCheckFailedException
 		if errors.As(err, &conditionalCheckFailed) {
@@ -115,23 +289,40 @@ func (r *DynamoDBRepository) CreateItem(ctx context.Context, item *models.Item)
 		return HandleDynamoDBError(err)
 	}
 
+	r.touchCollection(ctx)
 	return nil
 }
 
-// GetItem retrieves a single item from DynamoDB by ID
-func (r *DynamoDBRepository) GetItem(ctx context.Context, id string) (*models.Item, error) {
+// GetItem retrieves a single item from DynamoDB by ID. Reads are eventually
+// consistent unless options ask for a consistent read.
+func (r *DynamoDBRepository) GetItem(ctx context.Context, id string, options *GetItemOptions) (*models.Item, error) {
 	if id == "" {
 		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
 	}
+	if isMetaItemID(id) {
+		return nil, ErrItemNotFound
+	}
+	if isMetaItemID(id) {
+		return nil, ErrItemNotFound
+	}
 
 	input := &dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: id},
-		},
+		Key:       r.attrNames.key(id),
+	}
+	if options != nil {
+		if options.Consistent {
+			input.ConsistentRead = aws.Bool(true)
+		}
+		if len(options.Fields) > 0 {
+			input.ExpressionAttributeNames = map[string]string{}
+			input.ProjectionExpression = r.projection(options.Fields, input.ExpressionAttributeNames)
+		}
 	}
 
-	result, err := r.client.GetItem(ctx, input)
+	result, err := withRetry(ctx, r.maxRetries, func() (*dynamodb.GetItemOutput, error) {
+		return r.client.GetItem(ctx, input)
+	})
 	if err != nil {
 		return nil, HandleDynamoDBError(err)
 	}
@@ -143,7 +334,7 @@ func (r *DynamoDBRepository) GetItem(ctx context.Context, id string) (*models.It
 
 	// Unmarshal the item
 	var item models.Item
-	err = attributevalue.UnmarshalMap(result.Item, &item)
+	err = attributevalue.UnmarshalMap(r.attrNames.fromStorage(result.Item), &item)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal item: %w", err)
 	}
@@ -151,7 +342,10 @@ func (r *DynamoDBRepository) GetItem(ctx context.Context, id string) (*models.It
 	return &item, nil
 }
 
-// ListItems retrieves items with pagination support
+// ListItems retrieves items with pagination support. When the listing GSI is
+// available items are returned in created_at, then ID, order, or its
+// reverse, with a cursor that is stable across concurrent deletes;
+// otherwise the table is scanned and only each page is sorted.
 func (r *DynamoDBRepository) ListItems(ctx context.Context, options *ListItemsOptions) (*ListItemsResult, error) {
 	if options == nil {
 		options = &ListItemsOptions{
@@ -167,52 +361,282 @@ func (r *DynamoDBRepository) ListItems(ctx context.Context, options *ListItemsOp
 		options.Limit = 100
 	}
 
-	input := &dynamodb.ScanInput{
-		TableName: aws.String(r.tableName),
-		Limit:     aws.Int32(options.Limit),
+	// A day is listed from the creation date index, or else as the range of
+	// created_at it covers. A missing index falls through to the next way
+	// of listing.
+	if options.CreatedDate != "" {
+		narrowed, err := options.withCreatedDate(r.dateLocation)
+		if err != nil {
+			return nil, err
+		}
+		options = narrowed
+		if r.hasIndex(ctx, r.dateIndexName) {
+			result, err := r.queryItemsByDate(ctx, options)
+			if !errors.Is(err, errIndexMissing) {
+				return result, err
+			}
+		}
+	}
+	if options.StatusFilter != "" && r.hasIndex(ctx, r.statusIndexName) {
+		result, err := r.QueryItemsByStatus(ctx, options.StatusFilter, options)
+		if !errors.Is(err, errIndexMissing) {
+			return result, err
+		}
+	}
+	if r.hasListIndex(ctx) {
+		result, err := r.queryListIndex(ctx, options)
+		if !errors.Is(err, errIndexMissing) {
+			return result, err
+		}
 	}
 
-	// Add pagination token if provided
-	if options.LastEvaluatedKey != nil {
-		input.ExclusiveStartKey = options.LastEvaluatedKey
+	input := &dynamodb.ScanInput{
+		TableName:                aws.String(r.tableName),
+		FilterExpression:         aws.String("NOT begins_with(#id, :meta_prefix)"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":meta_prefix": &types.AttributeValueMemberS{Value: metaItemPrefix},
+		},
+	}
+	if options.StatusFilter != "" {
+		input.FilterExpression = aws.String(*input.FilterExpression + " AND #status = :status")
+		input.ExpressionAttributeNames["#status"] = r.attrNames.Storage("status")
+		input.ExpressionAttributeValues[":status"] = &types.AttributeValueMemberS{Value: options.StatusFilter}
+	}
+	optionsFilter, err := r.listFilter(options, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	if optionsFilter != "" {
+		input.FilterExpression = aws.String(*input.FilterExpression + " AND " + optionsFilter)
 	}
+	input.ProjectionExpression = r.projection(options.projectionFields(), input.ExpressionAttributeNames)
 
-	result, err := r.client.Scan(ctx, input)
+	rows, lastKey, err := readPage(options.Limit, r.tableStartKey(options.LastEvaluatedKey), func(limit int32, startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+		input.Limit = aws.Int32(limit)
+		input.ExclusiveStartKey = startKey
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Items, result.LastEvaluatedKey, nil
+	})
 	if err != nil {
 		return nil, HandleDynamoDBError(err)
 	}
 
 	// Unmarshal items
 	var items []models.Item
-	err = attributevalue.UnmarshalListOfMaps(result.Items, &items)
+	err = attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(rows), &items)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
 	}
+	items = filterCreatedRange(items, options)
+
+	// Scan order is arbitrary; at least keep each page deterministic
+	sortField := options.SortField
+	if sortField == "" {
+		sortField = models.SortCreatedAt
+	}
+	models.SortItems(items, sortField, options.Descending)
 
 	return &ListItemsResult{
 		Items:            items,
-		LastEvaluatedKey: result.LastEvaluatedKey,
-		HasMore:          result.LastEvaluatedKey != nil,
+		LastEvaluatedKey: lastKey,
+		HasMore:          lastKey != nil,
 	}, nil
 }
 
+// maxPageReads bounds the reads spent filling one page of a filtered listing
+const maxPageReads = 10
+
+// pageReader reads up to limit items starting after startKey, returning them
+// with the key to continue from
+type pageReader func(limit int32, startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error)
+
+// readPage calls read until limit items are collected, the table is
+// exhausted or maxPageReads is reached. Filters apply after DynamoDB's Limit,
+// so a single read can return fewer items than requested, even none; each
+// further read asks only for the remainder, so the last read's key is a
+// valid cursor for the next page.
+func readPage(limit int32, startKey map[string]types.AttributeValue, read pageReader) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+	var rows []map[string]types.AttributeValue
+	for reads := 0; reads < maxPageReads; reads++ {
+		page, lastKey, err := read(limit-int32(len(rows)), startKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, page...)
+		startKey = lastKey
+		if lastKey == nil || int32(len(rows)) >= limit {
+			break
+		}
+	}
+	return rows, startKey, nil
+}
+
 // UpdateItem updates an existing item with conditional checks
 func (r *DynamoDBRepository) UpdateItem(ctx context.Context, id string, updates *models.UpdateItemRequest) (*models.Item, error) {
 	if id == "" {
 		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
 	}
+	if isMetaItemID(id) {
+		return nil, ErrItemNotFound
+	}
 
 	if updates == nil {
 		return nil, fmt.Errorf("%w: updates cannot be nil", ErrInvalidInput)
 	}
 
 	// Validate the update request
-	if err := updates.Validate(); err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	if err := validate(ctx, updates); err != nil {
+		return nil, err
+	}
+
+	// Empty fields are left unchanged
+	set := map[string]string{}
+	for name, value := range map[string]string{
+		"name":        updates.Name,
+		"description": updates.Description,
+		"status":      updates.Status,
+		"category":    updates.Category,
+	} {
+		if value != "" {
+			set[name] = value
+		}
+	}
+
+	// Tags are replaced as a whole; a string set can't be empty, so an
+	// empty list removes the attribute
+	var remove []string
+	if updates.Tags != nil && len(updates.Tags) == 0 {
+		remove = append(remove, "tags")
+	}
+
+	rename, err := r.claimRename(ctx, id, set, remove)
+	if err != nil {
+		return nil, err
+	}
+	item, err := r.applyUpdate(ctx, id, set, tagValues(updates.Tags), remove, newMetadataChange(updates), updateConditions{generation: updates.Generation, version: updates.Version, owner: updates.RequireOwner})
+	return rename.finish(r, ctx, id, item, err)
+}
+
+// PatchItem partially updates an existing item: only the fields present in
+// the patch are written, and optional fields set to "" or null are removed
+func (r *DynamoDBRepository) PatchItem(ctx context.Context, id string, patch *models.PatchItemRequest) (*models.Item, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if isMetaItemID(id) {
+		return nil, ErrItemNotFound
 	}
 
-	// Build update expression and attribute values
-	updateExpression := "SET updated_at = :updated_at"
+	if patch == nil {
+		return nil, fmt.Errorf("%w: patch cannot be nil", ErrInvalidInput)
+	}
+
+	if err := validate(ctx, patch); err != nil {
+		return nil, err
+	}
+
+	set, remove := patch.Changes()
+	values := map[string]types.AttributeValue{}
+	if patch.Tags.Value != nil {
+		values = tagValues(*patch.Tags.Value)
+	}
+	if patch.ExpiresAt.Value != nil {
+		values["ttl"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(patch.ExpiresAt.Value.Unix(), 10)}
+	}
+	var metadata *metadataChange
+	if patch.Metadata.Value != nil && len(*patch.Metadata.Value) > 0 {
+		metadata = replaceMetadata(*patch.Metadata.Value)
+	}
+	rename, err := r.claimRename(ctx, id, set, remove)
+	if err != nil {
+		return nil, err
+	}
+	item, err := r.applyUpdate(ctx, id, set, values, remove, metadata, updateConditions{generation: patch.Generation, owner: patch.RequireOwner})
+	return rename.finish(r, ctx, id, item, err)
+}
+
+// tagValues returns the attribute value setting the item's tags, or none
+// when there are no tags to set
+func tagValues(tags []string) map[string]types.AttributeValue {
+	values := map[string]types.AttributeValue{}
+	if len(tags) > 0 {
+		values["tags"] = &types.AttributeValueMemberSS{Value: tags}
+	}
+	return values
+}
+
+// updatableAttributes are the item attributes clients may change, in the
+// order they appear in update expressions
+var updatableAttributes = []string{"name", "description", "status", "category"}
+
+// updateConditions are the optional conditions an update must satisfy
+type updateConditions struct {
+	// generation, when set, must equal the stored generation
+	generation *int64
+	// version, when set, must equal the stored version
+	version *int64
+	// owner, when set, must equal the stored created_by
+	owner string
+}
+
+// generationCondition builds the condition that an item is at the expected
+// generation, adding its names and values. Items written before generations
+// existed are treated as generation 0.
+func (r *DynamoDBRepository) generationCondition(expected int64, names map[string]string, values map[string]types.AttributeValue) string {
+	names["#generation"] = r.attrNames.Storage("generation")
+	if expected == 0 {
+		return "attribute_not_exists(#generation)"
+	}
+	values[":expected_generation"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expected, 10)}
+	return "#generation = :expected_generation"
+}
+
+// versionCondition builds the condition that an item is at the expected
+// version, adding its names and values. Items written before versions
+// existed are treated as version 0.
+func (r *DynamoDBRepository) versionCondition(expected int64, names map[string]string, values map[string]types.AttributeValue) string {
+	names["#version"] = r.attrNames.Storage("version")
+	if expected == 0 {
+		return "attribute_not_exists(#version)"
+	}
+	values[":expected_version"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expected, 10)}
+	return "#version = :expected_version"
+}
+
+// storedVersion returns the version of a stored item, such as the one a
+// failed condition check returns
+func (r *DynamoDBRepository) storedVersion(av map[string]types.AttributeValue) int64 {
+	if n, ok := av[r.attrNames.Storage("version")].(*types.AttributeValueMemberN); ok {
+		version, _ := strconv.ParseInt(n.Value, 10, 64)
+		return version
+	}
+	return 0
+}
+
+// storedGeneration returns the generation of a stored item, such as the one
+// a failed condition check returns
+func (r *DynamoDBRepository) storedGeneration(av map[string]types.AttributeValue) int64 {
+	if n, ok := av[r.attrNames.Storage("generation")].(*types.AttributeValueMemberN); ok {
+		generation, _ := strconv.ParseInt(n.Value, 10, 64)
+		return generation
+	}
+	return 0
+}
+
+// applyUpdate sets and removes the given attributes on an existing item,
+// bumping its generation, version and updated_at. Values sets attributes that aren't
+// strings, such as the tag set, and a non-nil metadata change is merged
+// into or replaces the stored metadata. The update only succeeds if the item meets the
+// conditions.
+func (r *DynamoDBRepository) applyUpdate(ctx context.Context, id string, set map[string]string, values map[string]types.AttributeValue, remove []string, metadata *metadataChange, conditions updateConditions) (*models.Item, error) {
+	// Build update expression and attribute values; every update bumps the
+	// generation and version
+	updateExpression := "SET #updated_at = :updated_at, #generation = if_not_exists(#generation, :zero) + :one, " +
+		"#version = if_not_exists(#version, :zero) + :one"
 
 	// Create a timestamp for the update
 	now := time.Now()
@@ -223,80 +647,200 @@ func (r *DynamoDBRepository) UpdateItem(ctx context.Context, id string, updates
 
 	expressionAttributeValues := map[string]types.AttributeValue{
 		":updated_at": timestampAV,
+		":zero":       &types.AttributeValueMemberN{Value: "0"},
+		":one":        &types.AttributeValueMemberN{Value: "1"},
 	}
 
+	// Expression attribute names, mapped to the table's attribute names
+	expressionAttributeNames := r.attrNames.placeholders("id", "updated_at", "generation", "version")
+
 	// Add fields to update if they are provided
-	if updates.Name != "" {
-		updateExpression += ", #name = :name"
-		expressionAttributeValues[":name"] = &types.AttributeValueMemberS{Value: updates.Name}
+	for _, name := range updatableAttributes {
+		if value, ok := set[name]; ok {
+			updateExpression += fmt.Sprintf(", #%s = :%s", name, name)
+			expressionAttributeValues[":"+name] = &types.AttributeValueMemberS{Value: value}
+			expressionAttributeNames["#"+name] = r.attrNames.Storage(name)
+		}
 	}
-	if updates.Description != "" {
-		updateExpression += ", description = :description"
-		expressionAttributeValues[":description"] = &types.AttributeValueMemberS{Value: updates.Description}
+	// A rename moves the item in the name index
+	if name, ok := set["name"]; ok {
+		updateExpression += ", #name_sk = :name_sk"
+		expressionAttributeValues[":name_sk"] = &types.AttributeValueMemberS{Value: models.NameKey(name)}
+		expressionAttributeNames["#name_sk"] = nameSortAttr
+	}
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		updateExpression += fmt.Sprintf(", #%s = :%s", name, name)
+		expressionAttributeValues[":"+name] = values[name]
+		expressionAttributeNames["#"+name] = r.attrNames.Storage(name)
+	}
+
+	// Remove cleared fields
+	var removed []string
+	for _, name := range remove {
+		removed = append(removed, "#"+name)
+		expressionAttributeNames["#"+name] = r.attrNames.Storage(name)
+	}
+	metadataCondition := ""
+	if metadata != nil {
+		metadataSet, metadataRemoved, condition, err := r.metadataExpression(metadata, expressionAttributeNames, expressionAttributeValues)
+		if err != nil {
+			return nil, err
+		}
+		for _, clause := range metadataSet {
+			updateExpression += ", " + clause
+		}
+		removed = append(removed, metadataRemoved...)
+		metadataCondition = condition
 	}
-	if updates.Status != "" {
-		updateExpression += ", #status = :status"
-		expressionAttributeValues[":status"] = &types.AttributeValueMemberS{Value: updates.Status}
+	if len(removed) > 0 {
+		updateExpression += " REMOVE " + strings.Join(removed, ", ")
 	}
 
-	// Expression attribute names for reserved keywords
-	expressionAttributeNames := map[string]string{}
-	if updates.Name != "" {
-		expressionAttributeNames["#name"] = "name"
+	// Ensure item exists and isn't soft-deleted, and that the generation
+	// and version match when they are expected
+	conditionExpression := "attribute_exists(#id) AND attribute_not_exists(#deleted_at)"
+	expressionAttributeNames["#deleted_at"] = r.attrNames.Storage("deleted_at")
+	if conditions.generation != nil {
+		conditionExpression += " AND " + r.generationCondition(*conditions.generation, expressionAttributeNames, expressionAttributeValues)
 	}
-	if updates.Status != "" {
-		expressionAttributeNames["#status"] = "status"
+	if conditions.version != nil {
+		conditionExpression += " AND " + r.versionCondition(*conditions.version, expressionAttributeNames, expressionAttributeValues)
 	}
-
-	input := &dynamodb.UpdateItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: id},
-		},
-		UpdateExpression:          aws.String(updateExpression),
-		ExpressionAttributeValues: expressionAttributeValues,
-		ConditionExpression:       aws.String("attribute_exists(id)"), // Ensure item exists
-		ReturnValues:              types.ReturnValueAllNew,
+	if conditions.owner != "" {
+		conditionExpression += " AND #created_by = :owner"
+		expressionAttributeNames["#created_by"] = r.attrNames.Storage("created_by")
+		expressionAttributeValues[":owner"] = &types.AttributeValueMemberS{Value: conditions.owner}
 	}
-
-	// Add expression attribute names if needed
-	if len(expressionAttributeNames) > 0 {
-		input.ExpressionAttributeNames = expressionAttributeNames
+	if metadataCondition != "" {
+		conditionExpression += " AND " + metadataCondition
 	}
 
-	result, err := r.client.UpdateItem(ctx, input)
+	input := &dynamodb.UpdateItemInput{
+		TableName:                           aws.String(r.tableName),
+		Key:                                 r.attrNames.key(id),
+		UpdateExpression:                    aws.String(updateExpression),
+		ExpressionAttributeNames:            expressionAttributeNames,
+		ExpressionAttributeValues:           expressionAttributeValues,
+		ConditionExpression:                 aws.String(conditionExpression),
+		ReturnValues:                        types.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+
+	result, err := withRetry(ctx, r.maxRetries, func() (*dynamodb.UpdateItemOutput, error) {
+		return r.client.UpdateItem(ctx, input)
+	})
 	if err != nil {
+		// A failed condition on an existing item means it was deleted,
+		// belongs to someone else or the generation or version was stale
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) && conditionalCheckFailed.Item != nil {
+			if r.storedDeleted(conditionalCheckFailed.Item) {
+				return nil, fmt.Errorf("%w: %s", ErrItemNotFound, id)
+			}
+			if conditions.owner != "" && r.storedOwner(conditionalCheckFailed.Item) != conditions.owner {
+				return nil, fmt.Errorf("%w: %s", ErrNotOwner, id)
+			}
+			// A merge into an item without metadata writes the keys as a
+			// new map instead
+			if metadata.merging() && !r.storedHasMetadata(conditionalCheckFailed.Item) {
+				return r.applyUpdate(ctx, id, set, values, remove, metadata.asReplace(), conditions)
+			}
+			if conditions.version != nil && r.storedVersion(conditionalCheckFailed.Item) != *conditions.version {
+				return nil, fmt.Errorf("%w: expected version %d", ErrVersionConflict, *conditions.version)
+			}
+			if conditions.generation != nil {
+				return nil, fmt.Errorf("%w: expected generation %d", ErrStaleGeneration, *conditions.generation)
+			}
+		}
 		return nil, HandleDynamoDBError(err)
 	}
 
 	// Unmarshal the updated item
 	var item models.Item
-	err = attributevalue.UnmarshalMap(result.Attributes, &item)
+	err = attributevalue.UnmarshalMap(r.attrNames.fromStorage(result.Attributes), &item)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal updated item: %w", err)
 	}
 
+	r.touchCollection(ctx)
 	return &item, nil
 }
 
-// DeleteItem deletes an item with existence validation
-func (r *DynamoDBRepository) DeleteItem(ctx context.Context, id string) error {
+// DeleteItem deletes an item with existence validation and any conditions
+// in options. With soft deletes enabled the item is only marked deleted.
+func (r *DynamoDBRepository) DeleteItem(ctx context.Context, id string, options *DeleteItemOptions) error {
 	if id == "" {
 		return fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
 	}
+	if isMetaItemID(id) {
+		return ErrItemNotFound
+	}
+	if r.softDelete {
+		if err := r.softDeleteItem(ctx, id, options); err != nil {
+			return err
+		}
+		r.touchCollection(ctx)
+		return nil
+	}
 
 	input := &dynamodb.DeleteItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: id},
-		},
-		ConditionExpression: aws.String("attribute_exists(id)"), // Ensure item exists before deletion
+		TableName:                aws.String(r.tableName),
+		Key:                      r.attrNames.key(id),
+		ConditionExpression:      aws.String("attribute_exists(#id)"), // Ensure item exists before deletion
+		ExpressionAttributeNames: r.attrNames.placeholders("id"),
+	}
+
+	// Return the stored item on a failed condition to tell a missing item
+	// apart from one that doesn't meet the required status, owner or
+	// generation
+	if options != nil && (options.RequireStatus != "" || options.RequireOwner != "" || options.Generation != nil) {
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{}
+		input.ReturnValuesOnConditionCheckFailure = types.ReturnValuesOnConditionCheckFailureAllOld
+	}
+	if options != nil && options.RequireStatus != "" {
+		input.ConditionExpression = aws.String(*input.ConditionExpression + " AND #status = :required_status")
+		input.ExpressionAttributeNames["#status"] = r.attrNames.Storage("status")
+		input.ExpressionAttributeValues[":required_status"] = &types.AttributeValueMemberS{Value: options.RequireStatus}
+	}
+	if options != nil && options.RequireOwner != "" {
+		input.ConditionExpression = aws.String(*input.ConditionExpression + " AND #created_by = :owner")
+		input.ExpressionAttributeNames["#created_by"] = r.attrNames.Storage("created_by")
+		input.ExpressionAttributeValues[":owner"] = &types.AttributeValueMemberS{Value: options.RequireOwner}
+	}
+	if options != nil && options.Generation != nil {
+		input.ConditionExpression = aws.String(*input.ConditionExpression + " AND " +
+			r.generationCondition(*options.Generation, input.ExpressionAttributeNames, input.ExpressionAttributeValues))
+	}
+	if len(input.ExpressionAttributeValues) == 0 {
+		input.ExpressionAttributeValues = nil
 	}
 
-	_, err := r.client.DeleteItem(ctx, input)
+	attempts := 0
+	_, err := withRetry(ctx, r.maxRetries, func() (*dynamodb.DeleteItemOutput, error) {
+		attempts++
+		return r.client.DeleteItem(ctx, input)
+	})
+	if err != nil && attempts > 1 && deletedByAttempt(err) {
+		err = nil
+	}
 	if err != nil {
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) && conditionalCheckFailed.Item != nil {
+			if options.RequireOwner != "" && r.storedOwner(conditionalCheckFailed.Item) != options.RequireOwner {
+				return fmt.Errorf("%w: %s", ErrNotOwner, id)
+			}
+			if options.Generation != nil && r.storedGeneration(conditionalCheckFailed.Item) != *options.Generation {
+				return fmt.Errorf("%w: expected generation %d", ErrStaleGeneration, *options.Generation)
+			}
+			return fmt.Errorf("%w: item status is not %q", ErrPreconditionFailed, options.RequireStatus)
+		}
 		return HandleDynamoDBError(err)
 	}
 
+	if r.maxItems > 0 {
+		r.releaseItemSlot(ctx)
+	}
+	r.touchCollection(ctx)
+
 	return nil
 }
@@ -0,0 +1,579 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// mockDynamoDBClient is a DynamoDBAPI whose behavior is supplied per test
+type mockDynamoDBClient struct {
+	GetItemFn       func(ctx context.Context, params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error)
+	PutItemFn       func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error)
+	UpdateItemFn    func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error)
+	DeleteItemFn    func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error)
+	ScanFn          func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error)
+	BatchWriteFn    func(ctx context.Context, params *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error)
+	BatchGetFn      func(ctx context.Context, params *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error)
+	QueryFn         func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error)
+	DescribeTableFn func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error)
+}
+
+func (m *mockDynamoDBClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	if m.GetItemFn == nil {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+	return m.GetItemFn(ctx, params)
+}
+
+func (m *mockDynamoDBClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if m.PutItemFn == nil {
+		return &dynamodb.PutItemOutput{}, nil
+	}
+	return m.PutItemFn(ctx, params)
+}
+
+func (m *mockDynamoDBClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if m.UpdateItemFn == nil {
+		return &dynamodb.UpdateItemOutput{}, nil
+	}
+	return m.UpdateItemFn(ctx, params)
+}
+
+func (m *mockDynamoDBClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	if m.DeleteItemFn == nil {
+		return &dynamodb.DeleteItemOutput{}, nil
+	}
+	return m.DeleteItemFn(ctx, params)
+}
+
+func (m *mockDynamoDBClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	if m.ScanFn == nil {
+		return &dynamodb.ScanOutput{}, nil
+	}
+	return m.ScanFn(ctx, params)
+}
+
+func (m *mockDynamoDBClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	if m.BatchWriteFn == nil {
+		return &dynamodb.BatchWriteItemOutput{}, nil
+	}
+	return m.BatchWriteFn(ctx, params)
+}
+
+func (m *mockDynamoDBClient) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	if m.BatchGetFn == nil {
+		return &dynamodb.BatchGetItemOutput{}, nil
+	}
+	return m.BatchGetFn(ctx, params)
+}
+
+func (m *mockDynamoDBClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if m.QueryFn == nil {
+		return &dynamodb.QueryOutput{}, nil
+	}
+	return m.QueryFn(ctx, params)
+}
+
+func (m *mockDynamoDBClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	if m.DescribeTableFn == nil {
+		return &dynamodb.DescribeTableOutput{}, nil
+	}
+	return m.DescribeTableFn(ctx, params)
+}
+
+// newCountingMock returns a mock that emulates the conditional item counter
+func newCountingMock(count *int64, puts *int) *mockDynamoDBClient {
+	return &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			delta, _ := strconv.ParseInt(firstNumber(params.ExpressionAttributeValues, ":one", ":minus_one"), 10, 64)
+			if maxAV, ok := params.ExpressionAttributeValues[":max"].(*types.AttributeValueMemberN); ok {
+				max, _ := strconv.ParseInt(maxAV.Value, 10, 64)
+				if *count >= max {
+					return nil, &types.ConditionalCheckFailedException{}
+				}
+			}
+			*count += delta
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			*puts++
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+}
+
+// firstNumber returns the first numeric attribute value present among the given names
+func firstNumber(values map[string]types.AttributeValue, names ...string) string {
+	for _, name := range names {
+		if n, ok := values[name].(*types.AttributeValueMemberN); ok {
+			return n.Value
+		}
+	}
+	return "0"
+}
+
+func TestCreateItem_UnderItemCap(t *testing.T) {
+	var count int64
+	var puts int
+	repo := NewDynamoDBRepository(newCountingMock(&count, &puts), "items")
+	repo.maxItems = 2
+
+	for i := 0; i < 2; i++ {
+		if err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description")); err != nil {
+			t.Fatalf("Expected create %d to succeed, got %v", i+1, err)
+		}
+	}
+
+	if count != 2 {
+		t.Errorf("Expected counter to be 2, got %d", count)
+	}
+	if puts != 2 {
+		t.Errorf("Expected 2 PutItem calls, got %d", puts)
+	}
+}
+
+func TestCreateItem_AtItemCap(t *testing.T) {
+	count := int64(2)
+	var puts int
+	repo := NewDynamoDBRepository(newCountingMock(&count, &puts), "items")
+	repo.maxItems = 2
+
+	err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description"))
+	if !errors.Is(err, ErrLimitReached) {
+		t.Fatalf("Expected ErrLimitReached, got %v", err)
+	}
+
+	if puts != 0 {
+		t.Errorf("Expected no PutItem calls at the cap, got %d", puts)
+	}
+	if count != 2 {
+		t.Errorf("Expected counter to remain 2, got %d", count)
+	}
+}
+
+func TestCreateItem_ReleasesSlotOnFailedPut(t *testing.T) {
+	var count int64
+	var puts int
+	client := newCountingMock(&count, &puts)
+	client.PutItemFn = func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+		return nil, &types.InternalServerError{}
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.maxItems = 5
+
+	if err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description")); err == nil {
+		t.Fatal("Expected create to fail")
+	}
+
+	if count != 0 {
+		t.Errorf("Expected counter slot to be released, got %d", count)
+	}
+}
+
+// generationMock emulates conditional updates against a stored generation
+func generationMock(stored int64) *mockDynamoDBClient {
+	return &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			if expected, ok := params.ExpressionAttributeValues[":expected_generation"].(*types.AttributeValueMemberN); ok {
+				if expected.Value != strconv.FormatInt(stored, 10) {
+					return nil, &types.ConditionalCheckFailedException{
+						Item: map[string]types.AttributeValue{
+							"generation": &types.AttributeValueMemberN{Value: strconv.FormatInt(stored, 10)},
+						},
+					}
+				}
+			}
+			return &dynamodb.UpdateItemOutput{
+				Attributes: map[string]types.AttributeValue{
+					"id":         params.Key["id"],
+					"name":       &types.AttributeValueMemberS{Value: "Item"},
+					"generation": &types.AttributeValueMemberN{Value: strconv.FormatInt(stored+1, 10)},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestUpdateItem_FreshGeneration(t *testing.T) {
+	repo := NewDynamoDBRepository(generationMock(3), "items")
+	expected := int64(3)
+
+	item, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{
+		Name:       "Renamed",
+		Generation: &expected,
+	})
+	if err != nil {
+		t.Fatalf("Expected update to succeed, got %v", err)
+	}
+
+	if item.Generation != 4 {
+		t.Errorf("Expected generation to be incremented to 4, got %d", item.Generation)
+	}
+}
+
+func TestUpdateItem_Tags(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       []string
+		expression string
+	}{
+		{name: "Replaces tags", tags: []string{"sale", "featured"}, expression: ", #tags = :tags"},
+		{name: "Clears tags", tags: []string{}, expression: " REMOVE #tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captured *dynamodb.UpdateItemInput
+			client := &mockDynamoDBClient{
+				UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+					captured = params
+					return &dynamodb.UpdateItemOutput{}, nil
+				},
+			}
+			repo := NewDynamoDBRepository(client, "items")
+
+			if _, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{Tags: tt.tags}); err != nil {
+				t.Fatalf("Failed to update item: %v", err)
+			}
+			if !strings.Contains(*captured.UpdateExpression, tt.expression) {
+				t.Errorf("Expected %q in update expression %q", tt.expression, *captured.UpdateExpression)
+			}
+			if len(tt.tags) > 0 {
+				set, ok := captured.ExpressionAttributeValues[":tags"].(*types.AttributeValueMemberSS)
+				if !ok || !reflect.DeepEqual(set.Value, []string{"featured", "sale"}) {
+					t.Errorf("Expected tags written as a sorted string set, got %#v", captured.ExpressionAttributeValues[":tags"])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateItem_StaleGeneration(t *testing.T) {
+	repo := NewDynamoDBRepository(generationMock(5), "items")
+	expected := int64(3)
+
+	_, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{
+		Name:       "Renamed",
+		Generation: &expected,
+	})
+	if !errors.Is(err, ErrStaleGeneration) {
+		t.Fatalf("Expected ErrStaleGeneration, got %v", err)
+	}
+}
+
+func TestUpdateItem_RacingWritersSecondFails(t *testing.T) {
+	// Each successful write advances the stored generation, like DynamoDB
+	stored := int64(1)
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			output, err := generationMock(stored).UpdateItemFn(ctx, params)
+			if err == nil {
+				stored++
+			}
+			return output, err
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	// Both writers read the item at generation 1
+	readGeneration := int64(1)
+	first := &models.UpdateItemRequest{Name: "First", Generation: &readGeneration}
+	second := &models.UpdateItemRequest{Name: "Second", Generation: &readGeneration}
+
+	if _, err := repo.UpdateItem(context.Background(), "item-1", first); err != nil {
+		t.Fatalf("Expected the first write to succeed, got %v", err)
+	}
+	if _, err := repo.UpdateItem(context.Background(), "item-1", second); !errors.Is(err, ErrStaleGeneration) {
+		t.Fatalf("Expected the second write to fail with ErrStaleGeneration, got %v", err)
+	}
+	if stored != 2 {
+		t.Errorf("Expected only one write to land, stored generation is %d", stored)
+	}
+}
+
+func TestUpdateItem_RacingWritersSecondConflictsOnVersion(t *testing.T) {
+	// Each successful write advances the stored version, like DynamoDB
+	stored := int64(1)
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			if !strings.Contains(*params.UpdateExpression, "#version = if_not_exists(#version, :zero) + :one") {
+				t.Errorf("Expected the update to bump the version, got %q", *params.UpdateExpression)
+			}
+			if expected, ok := params.ExpressionAttributeValues[":expected_version"].(*types.AttributeValueMemberN); ok {
+				if expected.Value != strconv.FormatInt(stored, 10) {
+					return nil, &types.ConditionalCheckFailedException{
+						Item: map[string]types.AttributeValue{
+							"version": &types.AttributeValueMemberN{Value: strconv.FormatInt(stored, 10)},
+						},
+					}
+				}
+			}
+			stored++
+			return &dynamodb.UpdateItemOutput{
+				Attributes: map[string]types.AttributeValue{
+					"id":      params.Key["id"],
+					"name":    &types.AttributeValueMemberS{Value: "Item"},
+					"version": &types.AttributeValueMemberN{Value: strconv.FormatInt(stored, 10)},
+				},
+			}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	// Both writers read the item at version 1
+	readVersion := int64(1)
+	first := &models.UpdateItemRequest{Name: "First", Version: &readVersion}
+	second := &models.UpdateItemRequest{Name: "Second", Version: &readVersion}
+
+	item, err := repo.UpdateItem(context.Background(), "item-1", first)
+	if err != nil {
+		t.Fatalf("Expected the first write to succeed, got %v", err)
+	}
+	if item.Version != 2 {
+		t.Errorf("Expected the first write to return version 2, got %d", item.Version)
+	}
+	if _, err := repo.UpdateItem(context.Background(), "item-1", second); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("Expected the second write to fail with ErrVersionConflict, got %v", err)
+	}
+	if stored != 2 {
+		t.Errorf("Expected only one write to land, stored version is %d", stored)
+	}
+}
+
+func TestUpdateItem_MissingItemWithGeneration(t *testing.T) {
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			return nil, &types.ConditionalCheckFailedException{}
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	expected := int64(1)
+
+	_, err := repo.UpdateItem(context.Background(), "missing", &models.UpdateItemRequest{
+		Name:       "Renamed",
+		Generation: &expected,
+	})
+	if !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("Expected ErrItemNotFound, got %v", err)
+	}
+}
+
+// statusMock returns a mock table holding one item with the given status
+// that evaluates DeleteItem's status condition
+func statusMock(status string) *mockDynamoDBClient {
+	stored := map[string]types.AttributeValue{
+		"id":     &types.AttributeValueMemberS{Value: "item-1"},
+		"status": &types.AttributeValueMemberS{Value: status},
+	}
+	return &mockDynamoDBClient{
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			required, ok := params.ExpressionAttributeValues[":required_status"].(*types.AttributeValueMemberS)
+			if ok && required.Value != status {
+				return nil, &types.ConditionalCheckFailedException{Item: stored}
+			}
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}
+}
+
+func TestDeleteItem_RequiredStatusMet(t *testing.T) {
+	repo := NewDynamoDBRepository(statusMock("inactive"), "items")
+
+	if err := repo.DeleteItem(context.Background(), "item-1", &DeleteItemOptions{RequireStatus: "inactive"}); err != nil {
+		t.Fatalf("Expected delete to succeed, got %v", err)
+	}
+}
+
+func TestDeleteItem_RequiredStatusNotMet(t *testing.T) {
+	repo := NewDynamoDBRepository(statusMock("active"), "items")
+
+	err := repo.DeleteItem(context.Background(), "item-1", &DeleteItemOptions{RequireStatus: "inactive"})
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("Expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestDeleteItem_Generation(t *testing.T) {
+	stored := map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: "item-1"},
+		"generation": &types.AttributeValueMemberN{Value: "3"},
+	}
+	var conditions []string
+	client := &mockDynamoDBClient{
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			conditions = append(conditions, aws.ToString(params.ConditionExpression))
+			expected, _ := params.ExpressionAttributeValues[":expected_generation"].(*types.AttributeValueMemberN)
+			if expected == nil || expected.Value != "3" {
+				return nil, &types.ConditionalCheckFailedException{Item: stored}
+			}
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	stale := int64(2)
+	if err := repo.DeleteItem(context.Background(), "item-1", &DeleteItemOptions{Generation: &stale}); !errors.Is(err, ErrStaleGeneration) {
+		t.Fatalf("Expected ErrStaleGeneration, got %v", err)
+	}
+	current := int64(3)
+	if err := repo.DeleteItem(context.Background(), "item-1", &DeleteItemOptions{Generation: &current}); err != nil {
+		t.Fatalf("Expected delete at the current generation to succeed, got %v", err)
+	}
+	if conditions[1] != "attribute_exists(#id) AND #generation = :expected_generation" {
+		t.Errorf("Unexpected condition %q", conditions[1])
+	}
+}
+
+func TestDeleteItem_MissingItemWithRequiredStatus(t *testing.T) {
+	client := &mockDynamoDBClient{
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			return nil, &types.ConditionalCheckFailedException{}
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	err := repo.DeleteItem(context.Background(), "missing", &DeleteItemOptions{RequireStatus: "inactive"})
+	if !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("Expected ErrItemNotFound, got %v", err)
+	}
+}
+
+func TestPatchItem_BuildsSetAndRemove(t *testing.T) {
+	var captured *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			captured = params
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	name, description := "Renamed", ""
+	if _, err := repo.PatchItem(context.Background(), "item-1", &models.PatchItemRequest{Name: &name, Description: &description}); err != nil {
+		t.Fatalf("Expected patch to succeed, got %v", err)
+	}
+
+	expression := aws.ToString(captured.UpdateExpression)
+	if !strings.Contains(expression, ", #name = :name") || !strings.HasSuffix(expression, " REMOVE #description") {
+		t.Errorf("Expected name to be set and description removed, got %q", expression)
+	}
+	if strings.Contains(expression, "#status") || strings.Contains(expression, "#category") {
+		t.Errorf("Expected omitted fields to be left out, got %q", expression)
+	}
+	if _, ok := captured.ExpressionAttributeValues[":description"]; ok {
+		t.Error("Expected no value for a removed attribute")
+	}
+}
+
+func TestPatchItem_NullsRemoveOptionalAttributes(t *testing.T) {
+	stored := map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: "item-1"},
+		"name":     &types.AttributeValueMemberS{Value: "Item"},
+		"labels":   &types.AttributeValueMemberSS{Value: []string{"sale"}},
+		"metadata": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"color": &types.AttributeValueMemberS{Value: "red"}}},
+		"ttl":      &types.AttributeValueMemberN{Value: "1893456000"},
+	}
+	var captured *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			captured = params
+			// Apply the REMOVE clause to the stored item
+			_, removed, _ := strings.Cut(aws.ToString(params.UpdateExpression), " REMOVE ")
+			for _, placeholder := range strings.Split(removed, ", ") {
+				delete(stored, params.ExpressionAttributeNames[placeholder])
+			}
+			return &dynamodb.UpdateItemOutput{Attributes: stored}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.attrNames = AttributeNames{"tags": "labels"}
+
+	var patch models.PatchItemRequest
+	if err := json.Unmarshal([]byte(`{"tags":null,"metadata":null,"expires_at":null}`), &patch); err != nil {
+		t.Fatalf("Failed to decode patch: %v", err)
+	}
+	item, err := repo.PatchItem(context.Background(), "item-1", &patch)
+	if err != nil {
+		t.Fatalf("Expected patch to succeed, got %v", err)
+	}
+
+	expression := aws.ToString(captured.UpdateExpression)
+	if !strings.HasSuffix(expression, " REMOVE #tags, #metadata, #ttl") {
+		t.Errorf("Expected tags, metadata and ttl to be removed, got %q", expression)
+	}
+	for _, name := range []string{"labels", "metadata", "ttl"} {
+		if _, ok := stored[name]; ok {
+			t.Errorf("Expected %s to be absent from the stored item", name)
+		}
+	}
+	if item.Tags != nil || item.Metadata != nil || item.ExpiresAt != nil {
+		t.Errorf("Expected no tags, metadata or expiry, got %+v", item)
+	}
+}
+
+func TestPatchItem_SetsOptionalAttributes(t *testing.T) {
+	var captured *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			captured = params
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	expires := time.Now().Add(time.Hour)
+	tags := []string{"Sale"}
+	patch := &models.PatchItemRequest{
+		Tags:      models.Nullable[[]string]{Set: true, Value: &tags},
+		Metadata:  models.Nullable[map[string]string]{Set: true, Value: &map[string]string{}},
+		ExpiresAt: models.Nullable[time.Time]{Set: true, Value: &expires},
+	}
+	if _, err := repo.PatchItem(context.Background(), "item-1", patch); err != nil {
+		t.Fatalf("Expected patch to succeed, got %v", err)
+	}
+
+	expression := aws.ToString(captured.UpdateExpression)
+	if !strings.Contains(expression, ", #tags = :tags, #ttl = :ttl") || !strings.HasSuffix(expression, " REMOVE #metadata") {
+		t.Errorf("Expected tags and ttl to be set and empty metadata removed, got %q", expression)
+	}
+	if got := captured.ExpressionAttributeValues[":tags"].(*types.AttributeValueMemberSS).Value; len(got) != 1 || got[0] != "sale" {
+		t.Errorf("Expected normalized tags [sale], got %v", got)
+	}
+	if got := captured.ExpressionAttributeValues[":ttl"].(*types.AttributeValueMemberN).Value; got != strconv.FormatInt(expires.Unix(), 10) {
+		t.Errorf("Expected ttl %d, got %s", expires.Unix(), got)
+	}
+}
+
+func TestGetItem_ConsistentRead(t *testing.T) {
+	for _, consistent := range []bool{false, true} {
+		var input *dynamodb.GetItemInput
+		client := &mockDynamoDBClient{
+			GetItemFn: func(ctx context.Context, params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+				input = params
+				return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"id": &types.AttributeValueMemberS{Value: "item-1"},
+				}}, nil
+			},
+		}
+		repo := NewDynamoDBRepository(client, "items")
+
+		if _, err := repo.GetItem(context.Background(), "item-1", &GetItemOptions{Consistent: consistent}); err != nil {
+			t.Fatalf("Failed to get item: %v", err)
+		}
+		if got := aws.ToBool(input.ConsistentRead); got != consistent {
+			t.Errorf("Expected ConsistentRead %v, got %v", consistent, got)
+		}
+	}
+}
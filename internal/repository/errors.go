@@ -9,11 +9,17 @@ import (
 
 // Common repository errors
 var (
-	ErrItemNotFound      = errors.New("item not found")
-	ErrItemAlreadyExists = errors.New("item already exists")
-	ErrInvalidInput      = errors.New("invalid input")
-	ErrConnectionFailed  = errors.New("database connection failed")
-	ErrOperationFailed   = errors.New("database operation failed")
+	ErrItemNotFound       = errors.New("item not found")
+	ErrItemAlreadyExists  = errors.New("item already exists")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrConnectionFailed   = errors.New("database connection failed")
+	ErrOperationFailed    = errors.New("database operation failed")
+	ErrLimitReached       = errors.New("item limit reached")
+	ErrStaleGeneration    = errors.New("stale generation")
+	ErrVersionConflict    = errors.New("version conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrNotOwner           = errors.New("item belongs to another principal")
+	ErrLeaseHeld          = errors.New("item is leased to another holder")
 )
 
 // HandleDynamoDBError converts DynamoDB-specific errors to repository errors
@@ -112,6 +118,39 @@ func IsConflictError(err error) bool {
 	return errors.Is(err, ErrItemAlreadyExists)
 }
 
+// IsLimitReachedError checks if the error indicates the item cap was reached
+func IsLimitReachedError(err error) bool {
+	return errors.Is(err, ErrLimitReached)
+}
+
+// IsStaleGenerationError checks if the error indicates the expected generation was stale
+func IsStaleGenerationError(err error) bool {
+	return errors.Is(err, ErrStaleGeneration)
+}
+
+// IsVersionConflictError checks if the error indicates the expected version was stale
+func IsVersionConflictError(err error) bool {
+	return errors.Is(err, ErrVersionConflict)
+}
+
+// IsPreconditionFailedError checks if the error indicates a required
+// condition on the item did not hold
+func IsPreconditionFailedError(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed)
+}
+
+// IsNotOwnerError checks if the error is due to writing another
+// principal's item
+func IsNotOwnerError(err error) bool {
+	return errors.Is(err, ErrNotOwner)
+}
+
+// IsLeaseHeldError checks if the error is due to another holder's lease
+// on the item
+func IsLeaseHeldError(err error) bool {
+	return errors.Is(err, ErrLeaseHeld)
+}
+
 // IsValidationError checks if the error indicates invalid input
 func IsValidationError(err error) bool {
 	return errors.Is(err, ErrInvalidInput)
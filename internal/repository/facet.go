@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// FacetItems counts the items within scope per distinct value of a field.
+//
+// Facets are aggregated from a full table scan projecting only the field, so
+// every call consumes read capacity proportional to the table size. They
+// suit dashboards refreshed occasionally, not per-request use.
+func (r *DynamoDBRepository) FacetItems(ctx context.Context, field string, scope *OwnerScope) (*models.FacetResult, error) {
+	if !models.IsFacetField(field) {
+		return nil, fmt.Errorf("%w: field %q cannot be faceted", ErrInvalidInput, field)
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName:                aws.String(r.tableName),
+		FilterExpression:         aws.String("NOT begins_with(#id, :meta_prefix)"),
+		ProjectionExpression:     aws.String("#field"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":meta_prefix": &types.AttributeValueMemberS{Value: metaItemPrefix},
+		},
+	}
+	input.ExpressionAttributeNames["#field"] = r.attrNames.Storage(field)
+	if ownerFilter := r.ownerFilter(scope, input.ExpressionAttributeNames, input.ExpressionAttributeValues); ownerFilter != "" {
+		input.FilterExpression = aws.String(*input.FilterExpression + " AND " + ownerFilter)
+	}
+
+	facet := models.NewFacetResult(field)
+	for {
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, HandleDynamoDBError(err)
+		}
+
+		var rows []map[string]string
+		if err := attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(result.Items), &rows); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+		}
+		for _, row := range rows {
+			facet.Add(row[field])
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return facet, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
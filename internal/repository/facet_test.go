@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestFacetItems_CountsAcrossPages(t *testing.T) {
+	row := func(category string) map[string]types.AttributeValue {
+		if category == "" {
+			return map[string]types.AttributeValue{}
+		}
+		return map[string]types.AttributeValue{"category": &types.AttributeValueMemberS{Value: category}}
+	}
+	pages := [][]map[string]types.AttributeValue{
+		{row("tools"), row("toys"), row("tools")},
+		{row("tools"), row("")},
+	}
+
+	var projections []string
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			projections = append(projections, params.ExpressionAttributeNames["#field"])
+			page := len(projections) - 1
+			output := &dynamodb.ScanOutput{Items: pages[page]}
+			if page < len(pages)-1 {
+				output.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "cursor"}}
+			}
+			return output, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "test-table")
+
+	facet, err := repo.FacetItems(context.Background(), "category", nil)
+	if err != nil {
+		t.Fatalf("Expected facet to succeed, got %v", err)
+	}
+
+	if len(projections) != 2 || projections[0] != "category" {
+		t.Errorf("Expected two scans projecting category, got %v", projections)
+	}
+	if facet.Counts["tools"] != 3 || facet.Counts["toys"] != 1 || len(facet.Counts) != 2 {
+		t.Errorf("Expected tools=3 toys=1, got %v", facet.Counts)
+	}
+	if facet.Missing != 1 {
+		t.Errorf("Expected 1 item without a category, got %d", facet.Missing)
+	}
+}
+
+func TestFacetItems_RejectsUnknownField(t *testing.T) {
+	repo := NewDynamoDBRepository(&mockDynamoDBClient{}, "test-table")
+
+	if _, err := repo.FacetItems(context.Background(), "description", nil); err == nil {
+		t.Error("Expected unknown facet field to be rejected")
+	}
+}
+
+func TestFacetResult_CapsCardinality(t *testing.T) {
+	facet := models.NewFacetResult("category")
+	for i := 0; i < models.MaxFacetValues+5; i++ {
+		facet.Add(string(rune('A'+i%26)) + string(rune('a'+i/26)))
+	}
+	facet.Add("Aa")
+
+	if len(facet.Counts) != models.MaxFacetValues || !facet.Truncated {
+		t.Errorf("Expected %d values and truncation, got %d truncated=%v", models.MaxFacetValues, len(facet.Counts), facet.Truncated)
+	}
+	if facet.Counts["Aa"] != 2 {
+		t.Errorf("Expected existing values to keep counting, got %d", facet.Counts["Aa"])
+	}
+}
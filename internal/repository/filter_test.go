@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// filteringScanMock simulates a table scan that, like DynamoDB, evaluates
+// Limit before the status filter
+func filteringScanMock(t *testing.T, statuses []string) (*mockDynamoDBClient, *int) {
+	scans := 0
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			scans++
+			if !strings.Contains(aws.ToString(params.FilterExpression), "#status = :status") {
+				t.Errorf("Expected a status filter expression, got %q", aws.ToString(params.FilterExpression))
+			}
+			want := params.ExpressionAttributeValues[":status"].(*types.AttributeValueMemberS).Value
+
+			start := 0
+			if key, ok := params.ExclusiveStartKey["id"].(*types.AttributeValueMemberS); ok {
+				fmt.Sscanf(key.Value, "item-%d", &start)
+				start++
+			}
+			end := min(start+int(aws.ToInt32(params.Limit)), len(statuses))
+
+			output := &dynamodb.ScanOutput{}
+			for i := start; i < end; i++ {
+				if statuses[i] == want {
+					output.Items = append(output.Items, map[string]types.AttributeValue{
+						"id":     &types.AttributeValueMemberS{Value: fmt.Sprintf("item-%d", i)},
+						"status": &types.AttributeValueMemberS{Value: statuses[i]},
+					})
+				}
+			}
+			if end < len(statuses) {
+				output.LastEvaluatedKey = map[string]types.AttributeValue{
+					"id": &types.AttributeValueMemberS{Value: fmt.Sprintf("item-%d", end-1)},
+				}
+			}
+			return output, nil
+		},
+	}
+	return client, &scans
+}
+
+func TestListItems_StatusFilterPaginates(t *testing.T) {
+	statuses := []string{"inactive", "active", "inactive", "inactive", "inactive", "active", "active", "inactive", "active"}
+	client, _ := filteringScanMock(t, statuses)
+	repo := NewDynamoDBRepository(client, "items")
+
+	var ids []string
+	options := &ListItemsOptions{Limit: 2, StatusFilter: "active"}
+	for pages := 0; ; pages++ {
+		if pages > len(statuses) {
+			t.Fatal("Pagination did not terminate")
+		}
+		result, err := repo.ListItems(context.Background(), options)
+		if err != nil {
+			t.Fatalf("Failed to list items: %v", err)
+		}
+		if len(result.Items) > 2 {
+			t.Errorf("Expected at most 2 items per page, got %d", len(result.Items))
+		}
+		if result.HasMore && len(result.Items) < 2 {
+			t.Errorf("Expected a short page only at the end, got %d items with more to come", len(result.Items))
+		}
+		for _, item := range result.Items {
+			if item.Status != "active" {
+				t.Errorf("Expected only active items, got %s with status %s", item.ID, item.Status)
+			}
+			ids = append(ids, item.ID)
+		}
+		if !result.HasMore {
+			break
+		}
+		options.LastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	if strings.Join(ids, ",") != "item-1,item-5,item-6,item-8" {
+		t.Errorf("Expected every active item exactly once, got %v", ids)
+	}
+}
+
+func TestListItems_StatusFilterBoundsReads(t *testing.T) {
+	statuses := make([]string, 100)
+	for i := range statuses {
+		statuses[i] = "inactive"
+	}
+	client, scans := filteringScanMock(t, statuses)
+	repo := NewDynamoDBRepository(client, "items")
+
+	result, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 5, StatusFilter: "active"})
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+
+	if *scans != maxPageReads {
+		t.Errorf("Expected %d scans, got %d", maxPageReads, *scans)
+	}
+	if len(result.Items) != 0 || !result.HasMore {
+		t.Errorf("Expected an empty page that can be continued, got %d items has_more=%v", len(result.Items), result.HasMore)
+	}
+}
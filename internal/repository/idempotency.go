@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// IdempotencyRecord is what a create sent with an Idempotency-Key leaves
+// behind: a hash of its request and the ID of the item it created
+type IdempotencyRecord struct {
+	RequestHash string
+	ItemID      string
+}
+
+// IdempotencyStore is implemented by repositories that can remember the
+// Idempotency-Key of creates, so a retried create returns the item the
+// first attempt made instead of creating another
+type IdempotencyStore interface {
+	// ClaimIdempotencyKey records key for a create of itemID with the given
+	// request hash, keeping it for ttl. When an unexpired record already
+	// holds key it is left alone and returned; otherwise the result is nil.
+	ClaimIdempotencyKey(ctx context.Context, key, requestHash, itemID string, ttl time.Duration) (*IdempotencyRecord, error)
+
+	// ReleaseIdempotencyKey removes key when it is still claimed for itemID,
+	// so a create that failed can be retried with the same key
+	ReleaseIdempotencyKey(ctx context.Context, key, itemID string) error
+}
+
+// idempotencyRecordID is the key of the record holding an idempotency key
+func idempotencyRecordID(key string) string {
+	return metaItemPrefix + "idempotency#" + key
+}
+
+// ClaimIdempotencyKey records key with a conditional put that only succeeds
+// when no record holds it or the record has expired. Records carry their
+// expiry in the ttl attribute, so the table's TTL removes them eventually.
+func (r *DynamoDBRepository) ClaimIdempotencyKey(ctx context.Context, key, requestHash, itemID string, ttl time.Duration) (*IdempotencyRecord, error) {
+	if key == "" {
+		return nil, fmt.Errorf("%w: idempotency key cannot be empty", ErrInvalidInput)
+	}
+
+	now := time.Now()
+	record := r.attrNames.key(idempotencyRecordID(key))
+	record["item_id"] = &types.AttributeValueMemberS{Value: itemID}
+	record["request_hash"] = &types.AttributeValueMemberS{Value: requestHash}
+	record[r.attrNames.Storage("ttl")] = &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(ttl).Unix(), 10)}
+
+	input := &dynamodb.PutItemInput{
+		TableName:                aws.String(r.tableName),
+		Item:                     record,
+		ConditionExpression:      aws.String("attribute_not_exists(#id) OR #ttl <= :now"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id", "ttl"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+	_, err := r.client.PutItem(ctx, input)
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionalCheckFailed) && conditionalCheckFailed.Item != nil {
+		existing := &IdempotencyRecord{}
+		if value, ok := conditionalCheckFailed.Item["request_hash"].(*types.AttributeValueMemberS); ok {
+			existing.RequestHash = value.Value
+		}
+		if value, ok := conditionalCheckFailed.Item["item_id"].(*types.AttributeValueMemberS); ok {
+			existing.ItemID = value.Value
+		}
+		return existing, nil
+	}
+	if err != nil {
+		return nil, HandleDynamoDBError(err)
+	}
+	return nil, nil
+}
+
+// ReleaseIdempotencyKey deletes key's record, conditional on it still
+// naming itemID
+func (r *DynamoDBRepository) ReleaseIdempotencyKey(ctx context.Context, key, itemID string) error {
+	input := &dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 r.attrNames.key(idempotencyRecordID(key)),
+		ConditionExpression: aws.String("item_id = :item_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":item_id": &types.AttributeValueMemberS{Value: itemID},
+		},
+	}
+	_, err := r.client.DeleteItem(ctx, input)
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if err != nil && !errors.As(err, &conditionalCheckFailed) {
+		return HandleDynamoDBError(err)
+	}
+	return nil
+}
+
+// memoryIdempotencyKey is an idempotency record kept by the memory
+// repository
+type memoryIdempotencyKey struct {
+	record  IdempotencyRecord
+	expires time.Time
+}
+
+// ClaimIdempotencyKey records key, as for the DynamoDB repository
+func (r *MemoryRepository) ClaimIdempotencyKey(ctx context.Context, key, requestHash, itemID string, ttl time.Duration) (*IdempotencyRecord, error) {
+	if key == "" {
+		return nil, fmt.Errorf("%w: idempotency key cannot be empty", ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := r.idempotencyKeys[key]; ok && now.Before(existing.expires) {
+		record := existing.record
+		return &record, nil
+	}
+	if r.idempotencyKeys == nil {
+		r.idempotencyKeys = map[string]memoryIdempotencyKey{}
+	}
+	r.idempotencyKeys[key] = memoryIdempotencyKey{
+		record:  IdempotencyRecord{RequestHash: requestHash, ItemID: itemID},
+		expires: now.Add(ttl),
+	}
+	return nil, nil
+}
+
+// ReleaseIdempotencyKey removes key when it is still claimed for itemID
+func (r *MemoryRepository) ReleaseIdempotencyKey(ctx context.Context, key, itemID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.idempotencyKeys[key]; ok && existing.record.ItemID == itemID {
+		delete(r.idempotencyKeys, key)
+	}
+	return nil
+}
+
+// ClaimIdempotencyKey delegates to the wrapped repository, claiming nothing
+// when it doesn't store idempotency keys
+func (c *CachingRepository) ClaimIdempotencyKey(ctx context.Context, key, requestHash, itemID string, ttl time.Duration) (*IdempotencyRecord, error) {
+	if store, ok := c.inner.(IdempotencyStore); ok {
+		return store.ClaimIdempotencyKey(ctx, key, requestHash, itemID, ttl)
+	}
+	return nil, nil
+}
+
+// ReleaseIdempotencyKey delegates to the wrapped repository
+func (c *CachingRepository) ReleaseIdempotencyKey(ctx context.Context, key, itemID string) error {
+	if store, ok := c.inner.(IdempotencyStore); ok {
+		return store.ReleaseIdempotencyKey(ctx, key, itemID)
+	}
+	return nil
+}
+
+// ClaimIdempotencyKey delegates to the wrapped repository, claiming nothing
+// when it doesn't store idempotency keys
+func (c *CoalescingRepository) ClaimIdempotencyKey(ctx context.Context, key, requestHash, itemID string, ttl time.Duration) (*IdempotencyRecord, error) {
+	if store, ok := c.ItemRepository.(IdempotencyStore); ok {
+		return store.ClaimIdempotencyKey(ctx, key, requestHash, itemID, ttl)
+	}
+	return nil, nil
+}
+
+// ReleaseIdempotencyKey delegates to the wrapped repository
+func (c *CoalescingRepository) ReleaseIdempotencyKey(ctx context.Context, key, itemID string) error {
+	if store, ok := c.ItemRepository.(IdempotencyStore); ok {
+		return store.ReleaseIdempotencyKey(ctx, key, itemID)
+	}
+	return nil
+}
+
+// ClaimIdempotencyKey claims the key in the primary only, since the item
+// a replay returns is read from the primary
+func (d *DualWriteRepository) ClaimIdempotencyKey(ctx context.Context, key, requestHash, itemID string, ttl time.Duration) (*IdempotencyRecord, error) {
+	if store, ok := d.ItemRepository.(IdempotencyStore); ok {
+		return store.ClaimIdempotencyKey(ctx, key, requestHash, itemID, ttl)
+	}
+	return nil, nil
+}
+
+// ReleaseIdempotencyKey releases the key in the primary
+func (d *DualWriteRepository) ReleaseIdempotencyKey(ctx context.Context, key, itemID string) error {
+	if store, ok := d.ItemRepository.(IdempotencyStore); ok {
+		return store.ReleaseIdempotencyKey(ctx, key, itemID)
+	}
+	return nil
+}
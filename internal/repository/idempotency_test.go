@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// newIdempotencyTable returns a mock that stores records by ID and
+// evaluates the conditions used for claiming and releasing idempotency keys
+func newIdempotencyTable(records map[string]map[string]types.AttributeValue) *mockDynamoDBClient {
+	idOf := func(key map[string]types.AttributeValue) string {
+		return key["id"].(*types.AttributeValueMemberS).Value
+	}
+	number := func(value types.AttributeValue) int64 {
+		n, _ := strconv.ParseInt(value.(*types.AttributeValueMemberN).Value, 10, 64)
+		return n
+	}
+	return &mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			id := idOf(params.Item)
+			if old, exists := records[id]; exists && number(old["ttl"]) > number(params.ExpressionAttributeValues[":now"]) {
+				return nil, &types.ConditionalCheckFailedException{Item: old}
+			}
+			records[id] = params.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			id := idOf(params.Key)
+			old, exists := records[id]
+			if !exists || old["item_id"].(*types.AttributeValueMemberS).Value != params.ExpressionAttributeValues[":item_id"].(*types.AttributeValueMemberS).Value {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			delete(records, id)
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}
+}
+
+func TestDynamoDBRepository_ClaimIdempotencyKey(t *testing.T) {
+	records := map[string]map[string]types.AttributeValue{}
+	repo := NewDynamoDBRepository(newIdempotencyTable(records), "items")
+	ctx := context.Background()
+
+	existing, err := repo.ClaimIdempotencyKey(ctx, "alice#key-1", "hash-1", "item-1", time.Hour)
+	if err != nil || existing != nil {
+		t.Fatalf("Expected the key to be claimed, got %+v, %v", existing, err)
+	}
+	record := records[idempotencyRecordID("alice#key-1")]
+	if record == nil || record["ttl"] == nil {
+		t.Fatalf("Expected a record expiring through TTL, got %v", record)
+	}
+
+	existing, err = repo.ClaimIdempotencyKey(ctx, "alice#key-1", "hash-2", "item-2", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to claim key: %v", err)
+	}
+	if existing == nil || existing.RequestHash != "hash-1" || existing.ItemID != "item-1" {
+		t.Errorf("Expected the first claim to be returned, got %+v", existing)
+	}
+
+	// Only the create that claimed the key can release it
+	if err := repo.ReleaseIdempotencyKey(ctx, "alice#key-1", "item-2"); err != nil {
+		t.Fatalf("Failed to release key: %v", err)
+	}
+	if _, held := records[idempotencyRecordID("alice#key-1")]; !held {
+		t.Error("Expected another item's release to leave the claim")
+	}
+	if err := repo.ReleaseIdempotencyKey(ctx, "alice#key-1", "item-1"); err != nil {
+		t.Fatalf("Failed to release key: %v", err)
+	}
+	if _, held := records[idempotencyRecordID("alice#key-1")]; held {
+		t.Error("Expected the claim to be released")
+	}
+}
+
+func TestDynamoDBRepository_ClaimsExpiredIdempotencyKey(t *testing.T) {
+	records := map[string]map[string]types.AttributeValue{}
+	repo := NewDynamoDBRepository(newIdempotencyTable(records), "items")
+	ctx := context.Background()
+
+	// DynamoDB removes expired records lazily, so they may still be read
+	if _, err := repo.ClaimIdempotencyKey(ctx, "key-1", "hash-1", "item-1", -time.Minute); err != nil {
+		t.Fatalf("Failed to claim key: %v", err)
+	}
+	existing, err := repo.ClaimIdempotencyKey(ctx, "key-1", "hash-2", "item-2", time.Hour)
+	if err != nil || existing != nil {
+		t.Errorf("Expected the expired key to be claimed again, got %+v, %v", existing, err)
+	}
+}
+
+func TestMemoryRepository_ClaimIdempotencyKey(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	if existing, err := repo.ClaimIdempotencyKey(ctx, "key-1", "hash-1", "item-1", time.Hour); err != nil || existing != nil {
+		t.Fatalf("Expected the key to be claimed, got %+v, %v", existing, err)
+	}
+	existing, err := repo.ClaimIdempotencyKey(ctx, "key-1", "hash-2", "item-2", time.Hour)
+	if err != nil || existing == nil || existing.ItemID != "item-1" {
+		t.Errorf("Expected the first claim to be returned, got %+v, %v", existing, err)
+	}
+
+	if err := repo.ReleaseIdempotencyKey(ctx, "key-1", "item-1"); err != nil {
+		t.Fatalf("Failed to release key: %v", err)
+	}
+	if existing, err := repo.ClaimIdempotencyKey(ctx, "key-1", "hash-2", "item-2", time.Hour); err != nil || existing != nil {
+		t.Errorf("Expected the released key to be claimed again, got %+v, %v", existing, err)
+	}
+}
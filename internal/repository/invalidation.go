@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// InvalidationEvent tells cache holders that an item changed. An empty ID
+// invalidates every entry, e.g. after a bulk operation or a missed event.
+type InvalidationEvent struct {
+	ID string `json:"id"`
+}
+
+// InvalidationSource delivers invalidation events published by other
+// instances. Implementations wrap a transport such as an SQS queue
+// subscribed to the SNS topic the Streams consumer publishes to.
+type InvalidationSource interface {
+	// Subscribe calls handle for each event until ctx is cancelled or the
+	// source is exhausted
+	Subscribe(ctx context.Context, handle func(InvalidationEvent)) error
+}
+
+// ChannelInvalidationSource delivers events sent on a channel. It suits
+// in-process fan-out and tests.
+type ChannelInvalidationSource struct {
+	events <-chan InvalidationEvent
+}
+
+// NewChannelInvalidationSource creates a source reading from events
+func NewChannelInvalidationSource(events <-chan InvalidationEvent) *ChannelInvalidationSource {
+	return &ChannelInvalidationSource{events: events}
+}
+
+// Subscribe delivers events until ctx is cancelled or the channel is closed
+func (s *ChannelInvalidationSource) Subscribe(ctx context.Context, handle func(InvalidationEvent)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-s.events:
+			if !ok {
+				return nil
+			}
+			handle(event)
+		}
+	}
+}
+
+// InvalidationEventsFromStream converts a DynamoDB Streams batch into the
+// invalidation events a Streams consumer should publish. Bookkeeping
+// records are skipped.
+func InvalidationEventsFromStream(event events.DynamoDBEvent) []InvalidationEvent {
+	var result []InvalidationEvent
+	for _, record := range event.Records {
+		key, ok := record.Change.Keys["id"]
+		if !ok || key.DataType() != events.DataTypeString {
+			continue
+		}
+		if id := key.String(); !isMetaItemID(id) {
+			result = append(result, InvalidationEvent{ID: id})
+		}
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// leaseHeldError describes the lease that kept holder from leasing an item
+func leaseHeldError(item *models.Item) error {
+	return fmt.Errorf("%w: held by %s until %s", ErrLeaseHeld, item.LeaseHolder, item.LeaseExpiresAt.Format(time.RFC3339))
+}
+
+// GetForUpdate leases the item to holder for leaseSeconds and returns it,
+// in one conditional update, so an editor gets the current item and the
+// lease together. The lease is taken when the item has none, its lease has
+// expired, or holder already holds it, in which case it is extended.
+// Otherwise it fails with ErrLeaseHeld naming the current holder.
+//
+// Leases are advisory: they don't block writes, and like views they don't
+// bump the generation or updated_at.
+func (r *DynamoDBRepository) GetForUpdate(ctx context.Context, id string, holder string, leaseSeconds int) (*models.Item, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if holder == "" {
+		return nil, fmt.Errorf("%w: lease holder cannot be empty", ErrInvalidInput)
+	}
+	if err := models.ValidateLeaseSeconds(leaseSeconds); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+	if isMetaItemID(id) {
+		return nil, ErrItemNotFound
+	}
+
+	now := time.Now()
+	input := &dynamodb.UpdateItemInput{
+		TableName:        aws.String(r.tableName),
+		Key:              r.attrNames.key(id),
+		UpdateExpression: aws.String("SET #lease_holder = :holder, #lease_expires_at = :expires"),
+		ConditionExpression: aws.String("attribute_exists(#id) AND attribute_not_exists(#deleted_at) AND " +
+			"(attribute_not_exists(#lease_expires_at) OR #lease_expires_at <= :now OR #lease_holder = :holder)"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id", "deleted_at", "lease_holder", "lease_expires_at"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":holder":  &types.AttributeValueMemberS{Value: holder},
+			":expires": &types.AttributeValueMemberN{Value: strconv.FormatInt(models.LeaseExpiry(now, leaseSeconds).Unix(), 10)},
+			":now":     &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+		ReturnValues:                        types.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+
+	result, err := withRetry(ctx, r.maxRetries, func() (*dynamodb.UpdateItemOutput, error) {
+		return r.client.UpdateItem(ctx, input)
+	})
+	if err != nil {
+		// A deleted item looks missing, as it does to reads
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) && conditionalCheckFailed.Item != nil && !r.storedDeleted(conditionalCheckFailed.Item) {
+			var current models.Item
+			if err := attributevalue.UnmarshalMap(r.attrNames.fromStorage(conditionalCheckFailed.Item), &current); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal leased item: %w", err)
+			}
+			if current.LeaseExpiresAt != nil {
+				return nil, leaseHeldError(&current)
+			}
+		}
+		return nil, HandleDynamoDBError(err)
+	}
+
+	var item models.Item
+	if err := attributevalue.UnmarshalMap(r.attrNames.fromStorage(result.Attributes), &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal leased item: %w", err)
+	}
+	return &item, nil
+}
+
+// GetForUpdate leases the item to holder, as for the DynamoDB repository
+func (r *MemoryRepository) GetForUpdate(ctx context.Context, id string, holder string, leaseSeconds int) (*models.Item, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if holder == "" {
+		return nil, fmt.Errorf("%w: lease holder cannot be empty", ErrInvalidInput)
+	}
+	if err := models.ValidateLeaseSeconds(leaseSeconds); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok || item.IsDeleted() {
+		return nil, fmt.Errorf("%w: conditional check failed", ErrItemNotFound)
+	}
+	now := time.Now()
+	if item.LeasedAt(now) && item.LeaseHolder != holder {
+		return nil, leaseHeldError(&item)
+	}
+	expires := models.LeaseExpiry(now, leaseSeconds)
+	item.LeaseHolder = holder
+	item.LeaseExpiresAt = &expires
+	r.items[id] = item
+
+	return &item, nil
+}
+
+// GetForUpdate leases the item and invalidates its entry
+func (c *CachingRepository) GetForUpdate(ctx context.Context, id string, holder string, leaseSeconds int) (*models.Item, error) {
+	item, err := c.inner.GetForUpdate(ctx, id, holder, leaseSeconds)
+	c.Invalidate(id)
+	return item, err
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestMemoryGetForUpdate(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(ctx, item); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+
+	leased, err := repo.GetForUpdate(ctx, item.ID, "alice", 30)
+	if err != nil {
+		t.Fatalf("Failed to lease item: %v", err)
+	}
+	if leased.Name != "Item" || leased.LeaseHolder != "alice" || !leased.LeasedAt(time.Now()) {
+		t.Errorf("Expected the item leased to alice, got %+v", leased)
+	}
+	if leased.Generation != item.Generation {
+		t.Errorf("Expected a lease not to bump the generation, got %d", leased.Generation)
+	}
+
+	// Contention: another holder is refused and told who holds the lease
+	_, err = repo.GetForUpdate(ctx, item.ID, "bob", 30)
+	if !errors.Is(err, ErrLeaseHeld) || !strings.Contains(err.Error(), "alice") {
+		t.Errorf("Expected bob to be refused with alice as holder, got %v", err)
+	}
+
+	// The holder can renew its own lease
+	if _, err := repo.GetForUpdate(ctx, item.ID, "alice", 60); err != nil {
+		t.Errorf("Expected alice to renew the lease, got %v", err)
+	}
+
+	// Expiry: once the lease ends anyone may take it
+	expired := time.Now().Add(-time.Second)
+	stored := repo.items[item.ID]
+	stored.LeaseExpiresAt = &expired
+	repo.items[item.ID] = stored
+	leased, err = repo.GetForUpdate(ctx, item.ID, "bob", 30)
+	if err != nil {
+		t.Fatalf("Expected bob to take the expired lease, got %v", err)
+	}
+	if leased.LeaseHolder != "bob" {
+		t.Errorf("Expected bob to hold the lease, got %q", leased.LeaseHolder)
+	}
+
+	if _, err := repo.GetForUpdate(ctx, "missing", "bob", 30); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expected a missing item to be not found, got %v", err)
+	}
+	if _, err := repo.GetForUpdate(ctx, item.ID, "bob", models.MaxLeaseSeconds+1); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Expected an overlong lease to be invalid, got %v", err)
+	}
+}
+
+func TestGetForUpdate_ConditionalUpdate(t *testing.T) {
+	var captured *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			captured = params
+			return &dynamodb.UpdateItemOutput{Attributes: map[string]types.AttributeValue{
+				"id":               &types.AttributeValueMemberS{Value: "item-1"},
+				"name":             &types.AttributeValueMemberS{Value: "Item"},
+				"lease_holder":     params.ExpressionAttributeValues[":holder"],
+				"lease_expires_at": params.ExpressionAttributeValues[":expires"],
+			}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	before := time.Now().Unix()
+	item, err := repo.GetForUpdate(context.Background(), "item-1", "alice", 30)
+	if err != nil {
+		t.Fatalf("Failed to lease item: %v", err)
+	}
+	if item.Name != "Item" || item.LeaseHolder != "alice" || item.LeaseExpiresAt == nil {
+		t.Fatalf("Expected the leased item back, got %+v", item)
+	}
+	if got := item.LeaseExpiresAt.Unix(); got < before+30 || got > time.Now().Unix()+30 {
+		t.Errorf("Expected the lease to end in 30 seconds, got %d (now %d)", got, before)
+	}
+
+	if captured.ReturnValues != types.ReturnValueAllNew {
+		t.Errorf("Expected the update to return the item, got %q", captured.ReturnValues)
+	}
+	condition := *captured.ConditionExpression
+	for _, want := range []string{"attribute_not_exists(#lease_expires_at)", "#lease_expires_at <= :now", "#lease_holder = :holder", "attribute_not_exists(#deleted_at)"} {
+		if !strings.Contains(condition, want) {
+			t.Errorf("Expected condition to contain %q, got %q", want, condition)
+		}
+	}
+	if now, _ := strconv.ParseInt(captured.ExpressionAttributeValues[":now"].(*types.AttributeValueMemberN).Value, 10, 64); now < before {
+		t.Errorf("Expected :now to be the current epoch second, got %d", now)
+	}
+}
+
+func TestGetForUpdate_Contended(t *testing.T) {
+	expires := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			return nil, &types.ConditionalCheckFailedException{Item: map[string]types.AttributeValue{
+				"id":               &types.AttributeValueMemberS{Value: "item-1"},
+				"lease_holder":     &types.AttributeValueMemberS{Value: "alice"},
+				"lease_expires_at": &types.AttributeValueMemberN{Value: expires},
+			}}
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	_, err := repo.GetForUpdate(context.Background(), "item-1", "bob", 30)
+	if !errors.Is(err, ErrLeaseHeld) || !strings.Contains(err.Error(), "alice") {
+		t.Errorf("Expected the lease to be held by alice, got %v", err)
+	}
+}
+
+func TestGetForUpdate_DeletedItemNotFound(t *testing.T) {
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			return nil, &types.ConditionalCheckFailedException{Item: map[string]types.AttributeValue{
+				"id":         &types.AttributeValueMemberS{Value: "item-1"},
+				"deleted_at": &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+			}}
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	if _, err := repo.GetForUpdate(context.Background(), "item-1", "bob", 30); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expected a deleted item to be not found, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// listFilter returns the filter expression excluding soft-deleted items and
+// applying the options' created_at bounds, tag and owner filters, adding their
+// placeholders to names and values, or "" when there is nothing to filter.
+// The status filter is left to each listing path, since the status index
+// applies it as a key condition instead.
+func (r *DynamoDBRepository) listFilter(options *ListItemsOptions, names map[string]string, values map[string]types.AttributeValue) (string, error) {
+	var conditions []string
+	if !options.IncludeDeleted {
+		conditions = append(conditions, "attribute_not_exists(#deleted_at)")
+		names["#deleted_at"] = r.attrNames.Storage("deleted_at")
+	}
+	rangeFilter, err := r.createdRangeFilter(options, names, values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal created_at bounds: %w", err)
+	}
+	if rangeFilter != "" {
+		conditions = append(conditions, rangeFilter)
+	}
+	if tagFilter := r.tagFilter(options, names, values); tagFilter != "" {
+		conditions = append(conditions, tagFilter)
+	}
+	if ownerFilter := r.ownerFilter(options.ownerScope(), names, values); ownerFilter != "" {
+		conditions = append(conditions, ownerFilter)
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// ownerFilter returns a filter expression keeping to the scope's items,
+// adding its placeholders to names and values, or "" when the scope allows
+// every item
+func (r *DynamoDBRepository) ownerFilter(scope *OwnerScope, names map[string]string, values map[string]types.AttributeValue) string {
+	switch {
+	case scope == nil:
+		return ""
+	case scope.Owner != "":
+		names["#owner_id"] = r.attrNames.Storage("owner_id")
+		values[":owner_id"] = &types.AttributeValueMemberS{Value: scope.Owner}
+		return "#owner_id = :owner_id"
+	case scope.UnownedOnly:
+		names["#owner_id"] = r.attrNames.Storage("owner_id")
+		return "attribute_not_exists(#owner_id)"
+	}
+	return ""
+}
+
+// tagFilter returns a filter expression requiring every tag in the options'
+// TagFilter, or any of them with TagMatchAny, adding its placeholders to
+// names and values, or "" when no tag is required. Tags are a string set,
+// so contains() matches whole tags.
+func (r *DynamoDBRepository) tagFilter(options *ListItemsOptions, names map[string]string, values map[string]types.AttributeValue) string {
+	if len(options.TagFilter) == 0 {
+		return ""
+	}
+	conditions := make([]string, len(options.TagFilter))
+	for i, tag := range options.TagFilter {
+		placeholder := fmt.Sprintf(":tag%d", i)
+		values[placeholder] = &types.AttributeValueMemberS{Value: tag}
+		conditions[i] = fmt.Sprintf("contains(#tags, %s)", placeholder)
+	}
+	names["#tags"] = r.attrNames.Storage("tags")
+	if options.TagMatchAny {
+		// Parenthesized, since the filter is ANDed with other conditions
+		return "(" + strings.Join(conditions, " OR ") + ")"
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// matchesTags reports whether the item carries every tag in the options'
+// TagFilter, or any of them with TagMatchAny
+func (o *ListItemsOptions) matchesTags(item *models.Item) bool {
+	if len(o.TagFilter) == 0 {
+		return true
+	}
+	for _, tag := range o.TagFilter {
+		if item.HasTag(tag) == o.TagMatchAny {
+			return o.TagMatchAny
+		}
+	}
+	return !o.TagMatchAny
+}
+
+// ownerScope returns the scope of the options' owner filters
+func (o *ListItemsOptions) ownerScope() *OwnerScope {
+	return &OwnerScope{Owner: o.OwnerFilter, UnownedOnly: o.UnownedOnly}
+}
+
+// matchesOwner reports whether the item passes the options' owner filter
+func (o *ListItemsOptions) matchesOwner(item *models.Item) bool {
+	return o.ownerScope().includes(item)
+}
+
+// includes reports whether the item is within the scope
+func (s *OwnerScope) includes(item *models.Item) bool {
+	return s.IncludesOwner(item.OwnerID)
+}
+
+// IncludesOwner reports whether an item with the given owner, "" for none,
+// is within the scope
+func (s *OwnerScope) IncludesOwner(ownerID string) bool {
+	switch {
+	case s == nil:
+		return true
+	case s.Owner != "":
+		return ownerID == s.Owner
+	}
+	return !s.UnownedOnly || ownerID == ""
+}
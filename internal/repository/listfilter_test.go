@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestListItems_TagFilterExpression(t *testing.T) {
+	var captured *dynamodb.ScanInput
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			captured = params
+			return &dynamodb.ScanOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.attrNames = AttributeNames{"tags": "labels"}
+
+	if _, err := repo.ListItems(context.Background(), &ListItemsOptions{StatusFilter: "active", TagFilter: []string{"sale", "urgent"}}); err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+
+	filter := aws.ToString(captured.FilterExpression)
+	for _, condition := range []string{"#status = :status", "contains(#tags, :tag0)", "contains(#tags, :tag1)"} {
+		if !strings.Contains(filter, condition) {
+			t.Errorf("Expected %q in filter %q", condition, filter)
+		}
+	}
+	if strings.Contains(filter, " OR ") {
+		t.Errorf("Expected tags to be combined with AND, got %q", filter)
+	}
+	if captured.ExpressionAttributeNames["#tags"] != "labels" {
+		t.Errorf("Expected #tags to map to the stored name, got %q", captured.ExpressionAttributeNames["#tags"])
+	}
+	for placeholder, tag := range map[string]string{":tag0": "sale", ":tag1": "urgent"} {
+		if v, ok := captured.ExpressionAttributeValues[placeholder].(*types.AttributeValueMemberS); !ok || v.Value != tag {
+			t.Errorf("Expected %s to be %q, got %#v", placeholder, tag, captured.ExpressionAttributeValues[placeholder])
+		}
+	}
+}
+
+func TestListItems_TagMatchAnyExpression(t *testing.T) {
+	var captured *dynamodb.ScanInput
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			captured = params
+			return &dynamodb.ScanOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	if _, err := repo.ListItems(context.Background(), &ListItemsOptions{TagFilter: []string{"sale", "urgent"}, TagMatchAny: true}); err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+
+	filter := aws.ToString(captured.FilterExpression)
+	if !strings.Contains(filter, "(contains(#tags, :tag0) OR contains(#tags, :tag1))") {
+		t.Errorf("Expected the tags to be ORed in parentheses, got %q", filter)
+	}
+	if !strings.Contains(filter, "attribute_not_exists(#deleted_at) AND (") {
+		t.Errorf("Expected the OR group to be ANDed with the other conditions, got %q", filter)
+	}
+}
+
+func TestMemoryListItems_TagFilter(t *testing.T) {
+	repo := NewMemoryRepository()
+	for name, tags := range map[string][]string{
+		"sale":        {"sale"},
+		"urgent":      {"urgent"},
+		"sale-urgent": {"sale", "urgent"},
+		"all":         {"featured", "sale", "urgent"},
+		"untagged":    nil,
+	} {
+		item := models.NewItem(name, "Description")
+		item.Tags = tags
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+
+	tests := []struct {
+		tags     []string
+		any      bool
+		expected string
+	}{
+		{tags: []string{"sale"}, expected: "all,sale,sale-urgent"},
+		{tags: []string{"sale", "urgent"}, expected: "all,sale-urgent"},
+		{tags: []string{"featured", "urgent"}, expected: "all"},
+		{tags: []string{"missing"}, expected: ""},
+		{tags: []string{"sale", "urgent"}, any: true, expected: "all,sale,sale-urgent,urgent"},
+		{tags: []string{"featured", "missing"}, any: true, expected: "all"},
+		{tags: []string{"missing"}, any: true, expected: ""},
+	}
+	for _, tt := range tests {
+		result, err := repo.ListItems(context.Background(), &ListItemsOptions{TagFilter: tt.tags, TagMatchAny: tt.any})
+		if err != nil {
+			t.Fatalf("Failed to list items: %v", err)
+		}
+		var names []string
+		for _, item := range result.Items {
+			names = append(names, item.Name)
+		}
+		sort.Strings(names)
+		if got := strings.Join(names, ","); got != tt.expected {
+			t.Errorf("Expected %q for tags %v (any %v), got %q", tt.expected, tt.tags, tt.any, got)
+		}
+	}
+}
+
+func TestListItems_OwnerFilterExpression(t *testing.T) {
+	var captured *dynamodb.ScanInput
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			captured = params
+			return &dynamodb.ScanOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	if _, err := repo.ListItems(context.Background(), &ListItemsOptions{OwnerFilter: "user-1"}); err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if filter := aws.ToString(captured.FilterExpression); !strings.Contains(filter, "#owner_id = :owner_id") {
+		t.Errorf("Expected the owner filter, got %q", filter)
+	}
+	if v, ok := captured.ExpressionAttributeValues[":owner_id"].(*types.AttributeValueMemberS); !ok || v.Value != "user-1" {
+		t.Errorf("Expected :owner_id to be user-1, got %#v", captured.ExpressionAttributeValues[":owner_id"])
+	}
+
+	if _, err := repo.ListItems(context.Background(), &ListItemsOptions{UnownedOnly: true}); err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if filter := aws.ToString(captured.FilterExpression); !strings.Contains(filter, "attribute_not_exists(#owner_id)") {
+		t.Errorf("Expected only unowned items, got %q", filter)
+	}
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"fis-playground/internal/models"
+)
+
+// Ordered listing index.
+//
+// Scan-based cursors are positions in the table's hash order, so items
+// deleted while a client is paging can make later pages skip or repeat
+// items. Listing instead queries a GSI whose partition key is a constant
+// and whose sort key is "<created_at>#<id>": the cursor is then a sort key
+// value rather than a table position, so pages continue from the same
+// point whether or not the item that ended the previous page still exists.
+//
+// Both attributes are written on create. Items written before the index
+// was introduced need them backfilled to appear in listings, as do items
+// whose sort key predates the fixed-width timestamp format.
+const (
+	// DefaultListIndexName is the GSI used for ordered listing
+	DefaultListIndexName = "list-created_at-index"
+
+	listPartitionAttr  = "list_pk"
+	listSortAttr       = "list_sk"
+	listPartitionValue = "ITEM"
+)
+
+// listSortTimeFormat is a fixed-width RFC3339 timestamp, so sort keys
+// compare lexically in time order. RFC3339Nano trims trailing zeros, which
+// would sort "10:00:00Z" after "10:00:00.5Z".
+const listSortTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// listSortKey builds the stable sort key for an item; the ID breaks ties
+// between items created at the same instant
+func listSortKey(item *models.Item) string {
+	return item.CreatedAt.UTC().Format(listSortTimeFormat) + "#" + item.ID
+}
+
+// addListIndexAttributes adds the listing index keys to a marshaled item.
+// The keys are owned by the repository and are not subject to attribute
+// name mapping.
+func addListIndexAttributes(av map[string]types.AttributeValue, item *models.Item) {
+	av[listPartitionAttr] = &types.AttributeValueMemberS{Value: listPartitionValue}
+	av[listSortAttr] = &types.AttributeValueMemberS{Value: listSortKey(item)}
+}
+
+// hasListIndex reports whether the configured listing GSI exists on the table
+func (r *DynamoDBRepository) hasListIndex(ctx context.Context) bool {
+	return r.hasIndex(ctx, r.listIndexName)
+}
+
+// hasIndex reports whether the named GSI exists on the table. The table's
+// indexes are looked up once; a failed lookup is retried next call.
+func (r *DynamoDBRepository) hasIndex(ctx context.Context, name string) bool {
+	if name == "" {
+		return false
+	}
+
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
+
+	if r.indexNames == nil {
+		output, err := r.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(r.tableName),
+		})
+		if err != nil {
+			log.Printf("Failed to look up indexes on table %s, falling back to scan: %v", r.tableName, err)
+			return false
+		}
+
+		r.indexNames = map[string]bool{}
+		if output.Table != nil {
+			for _, index := range output.Table.GlobalSecondaryIndexes {
+				r.indexNames[aws.ToString(index.IndexName)] = true
+			}
+		}
+		for _, configured := range []string{r.listIndexName, r.statusIndexName, r.dateIndexName} {
+			if configured != "" && !r.indexNames[configured] {
+				log.Printf("Index %s not found on table %s, listing will use scan", configured, r.tableName)
+			}
+		}
+		if r.nameIndexName != "" && !r.indexNames[r.nameIndexName] {
+			log.Printf("Index %s not found on table %s, autocomplete will use scan", r.nameIndexName, r.tableName)
+		}
+	}
+
+	return r.indexNames[name]
+}
+
+// errIndexMissing marks a query against an index the table doesn't have
+var errIndexMissing = errors.New("index not found")
+
+// indexQueryError converts the error of a query against index. The indexes
+// are looked up once, so an index deleted since, or named wrongly in an
+// environment where the lookup wasn't allowed to list it, only shows up
+// as a failed query. The index is then remembered as missing, with one log
+// line, so later calls scan instead, and the error wraps errIndexMissing
+// for the caller to fall back to a scan right away.
+func (r *DynamoDBRepository) indexQueryError(index string, err error) error {
+	if !isMissingIndexError(err) {
+		return HandleDynamoDBError(err)
+	}
+
+	r.indexMu.Lock()
+	if r.indexNames[index] {
+		r.indexNames[index] = false
+		log.Printf("Index %s not found on table %s, falling back to scan", index, r.tableName)
+	}
+	r.indexMu.Unlock()
+
+	return fmt.Errorf("%w: %s: %w", errIndexMissing, index, HandleDynamoDBError(err))
+}
+
+// isMissingIndexError reports whether a query failed because its index
+// doesn't exist: DynamoDB rejects the query as invalid, or reports the
+// resource as not found
+func isMissingIndexError(err error) bool {
+	var resourceNotFound *types.ResourceNotFoundException
+	if errors.As(err, &resourceNotFound) {
+		return true
+	}
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "ValidationException" &&
+		strings.Contains(apiErr.ErrorMessage(), "specified index")
+}
+
+// tableStartKey keeps only the table's key of a listing cursor. Cursors
+// from an index query also hold the index keys, which a scan rejects, so a
+// listing that falls back to a scan mid-way continues from the same item.
+func (r *DynamoDBRepository) tableStartKey(key map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if key == nil {
+		return nil
+	}
+	id := r.attrNames.Storage("id")
+	return map[string]types.AttributeValue{id: key[id]}
+}
+
+// queryListIndex lists items in created_at#id order, or its reverse, using
+// the listing GSI
+func (r *DynamoDBRepository) queryListIndex(ctx context.Context, options *ListItemsOptions) (*ListItemsResult, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(r.listIndexName),
+		KeyConditionExpression: aws.String("#pk = :pk"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk": listPartitionAttr,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: listPartitionValue},
+		},
+		ScanIndexForward: aws.Bool(!options.Descending),
+	}
+	if options.StatusFilter != "" {
+		input.FilterExpression = aws.String("#status = :status")
+		input.ExpressionAttributeNames["#status"] = r.attrNames.Storage("status")
+		input.ExpressionAttributeValues[":status"] = &types.AttributeValueMemberS{Value: options.StatusFilter}
+	}
+	optionsFilter, err := r.listFilter(options, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	if optionsFilter != "" {
+		if input.FilterExpression != nil {
+			optionsFilter = *input.FilterExpression + " AND " + optionsFilter
+		}
+		input.FilterExpression = aws.String(optionsFilter)
+	}
+	input.ProjectionExpression = r.projection(options.projectionFields(), input.ExpressionAttributeNames)
+
+	rows, lastKey, err := readPage(options.Limit, options.LastEvaluatedKey, func(limit int32, startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+		input.Limit = aws.Int32(limit)
+		input.ExclusiveStartKey = startKey
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Items, result.LastEvaluatedKey, nil
+	})
+	if err != nil {
+		return nil, r.indexQueryError(r.listIndexName, err)
+	}
+
+	var items []models.Item
+	if err := attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(rows), &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+	items = filterCreatedRange(items, options)
+	options.sortPage(items)
+
+	return &ListItemsResult{
+		Items:            items,
+		LastEvaluatedKey: lastKey,
+		HasMore:          lastKey != nil,
+	}, nil
+}
@@ -0,0 +1,490 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"fis-playground/internal/models"
+)
+
+// newListIndexMock returns a mock table that stores items and emulates
+// queries against the listing GSI, optionally reporting the index as absent
+func newListIndexMock(withIndex bool) (*mockDynamoDBClient, map[string]map[string]types.AttributeValue) {
+	table := map[string]map[string]types.AttributeValue{}
+	sortKey := func(av map[string]types.AttributeValue) string {
+		return av[listSortAttr].(*types.AttributeValueMemberS).Value
+	}
+
+	client := &mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			table[params.Item["id"].(*types.AttributeValueMemberS).Value] = params.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			delete(table, params.Key["id"].(*types.AttributeValueMemberS).Value)
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+		DescribeTableFn: func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+			desc := &types.TableDescription{TableName: params.TableName}
+			if withIndex {
+				desc.GlobalSecondaryIndexes = []types.GlobalSecondaryIndexDescription{
+					{IndexName: aws.String(DefaultListIndexName)},
+				}
+			}
+			return &dynamodb.DescribeTableOutput{Table: desc}, nil
+		},
+		QueryFn: func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			var rows []map[string]types.AttributeValue
+			for _, av := range table {
+				rows = append(rows, av)
+			}
+			sort.Slice(rows, func(i, j int) bool { return sortKey(rows[i]) < sortKey(rows[j]) })
+
+			var after string
+			if params.ExclusiveStartKey != nil {
+				after = sortKey(params.ExclusiveStartKey)
+			}
+			output := &dynamodb.QueryOutput{}
+			for _, av := range rows {
+				if sortKey(av) <= after {
+					continue
+				}
+				if int32(len(output.Items)) == aws.ToInt32(params.Limit) {
+					last := output.Items[len(output.Items)-1]
+					output.LastEvaluatedKey = map[string]types.AttributeValue{
+						"id":              last["id"],
+						listPartitionAttr: last[listPartitionAttr],
+						listSortAttr:      last[listSortAttr],
+					}
+					break
+				}
+				output.Items = append(output.Items, av)
+			}
+			return output, nil
+		},
+	}
+	return client, table
+}
+
+// seedItems creates items one second apart so their list order is known
+func seedItems(t *testing.T, repo *DynamoDBRepository, names ...string) []*models.Item {
+	t.Helper()
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	items := make([]*models.Item, 0, len(names))
+	for i, name := range names {
+		item := models.NewItem(name, "Description")
+		item.CreatedAt = base.Add(time.Duration(i) * time.Second)
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to create item %s: %v", name, err)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestCreateItem_WritesListIndexKeys(t *testing.T) {
+	client, table := newListIndexMock(true)
+	repo := NewDynamoDBRepository(client, "items")
+
+	item := seedItems(t, repo, "First")[0]
+
+	stored := table[item.ID]
+	if pk := stored[listPartitionAttr].(*types.AttributeValueMemberS).Value; pk != listPartitionValue {
+		t.Errorf("Expected %s to be %q, got %q", listPartitionAttr, listPartitionValue, pk)
+	}
+	expected := "2024-01-15T10:00:00.000000000Z#" + item.ID
+	if sk := stored[listSortAttr].(*types.AttributeValueMemberS).Value; sk != expected {
+		t.Errorf("Expected %s to be %q, got %q", listSortAttr, expected, sk)
+	}
+}
+
+func TestCreateItem_ListSortKeysUniqueForSameTimestamp(t *testing.T) {
+	client, table := newListIndexMock(true)
+	repo := NewDynamoDBRepository(client, "items")
+	repo.listIndexName = DefaultListIndexName
+
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	keys := map[string]bool{}
+	for range 5 {
+		item := models.NewItem("Item", "Description")
+		item.CreatedAt = createdAt
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to create item: %v", err)
+		}
+
+		sk := table[item.ID][listSortAttr].(*types.AttributeValueMemberS).Value
+		if expected := "2024-01-15T10:00:00.000000000Z#" + item.ID; sk != expected {
+			t.Errorf("Expected sort key %q, got %q", expected, sk)
+		}
+		if keys[sk] {
+			t.Errorf("Expected unique sort keys, got %q twice", sk)
+		}
+		keys[sk] = true
+	}
+
+	// Paging one item at a time returns each item exactly once
+	seen := map[string]bool{}
+	options := &ListItemsOptions{Limit: 1}
+	for {
+		page, err := repo.ListItems(context.Background(), options)
+		if err != nil {
+			t.Fatalf("Failed to list page: %v", err)
+		}
+		for _, item := range page.Items {
+			if seen[item.ID] {
+				t.Errorf("Expected %s to be listed once", item.ID)
+			}
+			seen[item.ID] = true
+		}
+		if page.LastEvaluatedKey == nil {
+			break
+		}
+		options.LastEvaluatedKey = page.LastEvaluatedKey
+	}
+	if len(seen) != len(keys) {
+		t.Errorf("Expected %d items listed, got %d", len(keys), len(seen))
+	}
+}
+
+func TestListItems_DeleteDuringPagination(t *testing.T) {
+	client, _ := newListIndexMock(true)
+	repo := NewDynamoDBRepository(client, "items")
+	repo.listIndexName = DefaultListIndexName
+
+	items := seedItems(t, repo, "A", "B", "C", "D", "E", "F")
+
+	first, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("Failed to list first page: %v", err)
+	}
+
+	// Delete the item the cursor points at and one not yet returned
+	for _, id := range []string{items[1].ID, items[3].ID} {
+		if err := repo.DeleteItem(context.Background(), id, nil); err != nil {
+			t.Fatalf("Failed to delete item: %v", err)
+		}
+	}
+
+	var seen []string
+	for _, item := range first.Items {
+		seen = append(seen, item.Name)
+	}
+	key := first.LastEvaluatedKey
+	for key != nil {
+		page, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 2, LastEvaluatedKey: key})
+		if err != nil {
+			t.Fatalf("Failed to list page: %v", err)
+		}
+		for _, item := range page.Items {
+			seen = append(seen, item.Name)
+		}
+		key = page.LastEvaluatedKey
+	}
+
+	if got := strings.Join(seen, ","); got != "A,B,C,E,F" {
+		t.Errorf("Expected pages A,B,C,E,F with no duplicates or skips, got %s", got)
+	}
+}
+
+func TestListItems_ScanWithoutListIndex(t *testing.T) {
+	client, _ := newListIndexMock(false)
+	var scanned bool
+	client.ScanFn = func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		scanned = true
+		return &dynamodb.ScanOutput{}, nil
+	}
+	client.QueryFn = func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		t.Error("Expected no query when the list index is absent")
+		return &dynamodb.QueryOutput{}, nil
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.listIndexName = DefaultListIndexName
+
+	if _, err := repo.ListItems(context.Background(), nil); err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if !scanned {
+		t.Error("Expected listing to fall back to scan")
+	}
+}
+
+func TestListItems_ScanWhenListIndexMissing(t *testing.T) {
+	client, table := newListIndexMock(true)
+	queries := 0
+	client.QueryFn = func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		queries++
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "The table does not have the specified index: " + aws.ToString(params.IndexName)}
+	}
+	var startKeys []map[string]types.AttributeValue
+	client.ScanFn = func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		startKeys = append(startKeys, params.ExclusiveStartKey)
+		output := &dynamodb.ScanOutput{}
+		for _, av := range table {
+			output.Items = append(output.Items, av)
+		}
+		return output, nil
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.listIndexName = DefaultListIndexName
+	seeded := seedItems(t, repo, "C", "A", "B")
+
+	result, err := repo.ListItems(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	if got := strings.Join(names, ","); got != "C,A,B" {
+		t.Errorf("Expected the scanned items in created order C,A,B, got %s", got)
+	}
+
+	// A cursor from an earlier index query continues the scan by ID alone
+	cursor := map[string]types.AttributeValue{
+		"id":              &types.AttributeValueMemberS{Value: seeded[0].ID},
+		listPartitionAttr: &types.AttributeValueMemberS{Value: listPartitionValue},
+		listSortAttr:      &types.AttributeValueMemberS{Value: listSortKey(seeded[0])},
+	}
+	if _, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 10, LastEvaluatedKey: cursor}); err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if queries != 1 {
+		t.Errorf("Expected the missing index to be queried once, got %d queries", queries)
+	}
+	if len(startKeys) != 2 || len(startKeys[1]) != 1 || startKeys[1]["id"] == nil {
+		t.Errorf("Expected the second scan to start after the item's ID only, got %v", startKeys)
+	}
+}
+
+func TestListItems_StatusIndexMissingUsesListIndex(t *testing.T) {
+	client, _ := newListIndexMock(true)
+	client.DescribeTableFn = func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+		return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+				{IndexName: aws.String(DefaultListIndexName)},
+				{IndexName: aws.String(DefaultStatusIndexName)},
+			},
+		}}, nil
+	}
+	listQuery := client.QueryFn
+	var indexes []string
+	client.QueryFn = func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		indexes = append(indexes, aws.ToString(params.IndexName))
+		if aws.ToString(params.IndexName) == DefaultStatusIndexName {
+			return nil, &types.ResourceNotFoundException{Message: aws.String("Requested resource not found")}
+		}
+		return listQuery(ctx, params)
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.listIndexName = DefaultListIndexName
+	repo.statusIndexName = DefaultStatusIndexName
+	seedItems(t, repo, "A", "B")
+
+	result, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 10, StatusFilter: "active"})
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if len(result.Items) != 2 {
+		t.Errorf("Expected 2 items from the list index, got %d", len(result.Items))
+	}
+	if got := strings.Join(indexes, ","); got != DefaultStatusIndexName+","+DefaultListIndexName {
+		t.Errorf("Expected the status index, then the list index, to be queried, got %s", got)
+	}
+}
+
+func TestListItems_OtherQueryErrorsAreReturned(t *testing.T) {
+	client, _ := newListIndexMock(true)
+	client.QueryFn = func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "Invalid FilterExpression"}
+	}
+	client.ScanFn = func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+		t.Error("Expected no scan for an error unrelated to the index")
+		return &dynamodb.ScanOutput{}, nil
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.listIndexName = DefaultListIndexName
+
+	if _, err := repo.ListItems(context.Background(), nil); err == nil {
+		t.Fatal("Expected the query error to be returned")
+	}
+	if !repo.hasListIndex(context.Background()) {
+		t.Error("Expected the list index to still be used")
+	}
+}
+
+func TestListItems_SortsIndexPageByName(t *testing.T) {
+	client, _ := newListIndexMock(true)
+	repo := NewDynamoDBRepository(client, "items")
+	repo.listIndexName = DefaultListIndexName
+	seedItems(t, repo, "cherry", "Apple", "banana", "date")
+
+	result, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 3, SortField: models.SortName})
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Name)
+	}
+	// The page is the first three created, sorted by name
+	if got := strings.Join(names, ","); got != "Apple,banana,cherry" {
+		t.Errorf("Expected the page sorted by name, got %s", got)
+	}
+	if !result.HasMore {
+		t.Error("Expected more items after the page")
+	}
+}
+
+func TestListItems_ScanSortsPage(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	row := func(id, name string, updated time.Duration) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"id":         &types.AttributeValueMemberS{Value: id},
+			"name":       &types.AttributeValueMemberS{Value: name},
+			"created_at": &types.AttributeValueMemberS{Value: base.Format(time.RFC3339Nano)},
+			"updated_at": &types.AttributeValueMemberS{Value: base.Add(updated).Format(time.RFC3339Nano)},
+		}
+	}
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+				row("item-1", "banana", time.Second),
+				row("item-2", "cherry", 3*time.Second),
+				row("item-3", "apple", 2*time.Second),
+			}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	tests := []struct {
+		options  ListItemsOptions
+		expected string
+	}{
+		{options: ListItemsOptions{Limit: 10, SortField: models.SortName}, expected: "apple,banana,cherry"},
+		{options: ListItemsOptions{Limit: 10, SortField: models.SortName, Descending: true}, expected: "cherry,banana,apple"},
+		{options: ListItemsOptions{Limit: 10, SortField: models.SortUpdatedAt, Descending: true}, expected: "cherry,apple,banana"},
+	}
+	for _, tt := range tests {
+		result, err := repo.ListItems(context.Background(), &tt.options)
+		if err != nil {
+			t.Fatalf("Failed to list items: %v", err)
+		}
+		var names []string
+		for _, item := range result.Items {
+			names = append(names, item.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.expected {
+			t.Errorf("Expected %s for %+v, got %s", tt.expected, tt.options, got)
+		}
+	}
+}
+
+func TestMemoryListItems_DescendingPages(t *testing.T) {
+	repo := NewMemoryRepository()
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i, name := range []string{"A", "B", "C", "D", "E"} {
+		item := models.NewItem(name, "Description")
+		item.CreatedAt = base.Add(time.Duration(i) * time.Second)
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to create item %s: %v", name, err)
+		}
+	}
+
+	var seen []string
+	options := &ListItemsOptions{Limit: 2, Descending: true}
+	for {
+		result, err := repo.ListItems(context.Background(), options)
+		if err != nil {
+			t.Fatalf("Failed to list items: %v", err)
+		}
+		for _, item := range result.Items {
+			seen = append(seen, item.Name)
+		}
+		if !result.HasMore {
+			break
+		}
+		options.LastEvaluatedKey = result.LastEvaluatedKey
+	}
+	if got := strings.Join(seen, ","); got != "E,D,C,B,A" {
+		t.Errorf("Expected newest first across pages, got %s", got)
+	}
+}
+
+func TestListSortKey_OrdersLikeTimestamps(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	earlier := &models.Item{ID: "b", CreatedAt: base}
+	later := &models.Item{ID: "a", CreatedAt: base.Add(500 * time.Millisecond)}
+	tied := &models.Item{ID: "c", CreatedAt: base}
+
+	if !(listSortKey(earlier) < listSortKey(later)) {
+		t.Errorf("Expected a whole-second key to sort before a later fractional one: %q, %q", listSortKey(earlier), listSortKey(later))
+	}
+	if !(listSortKey(earlier) < listSortKey(tied)) {
+		t.Errorf("Expected the ID to break the tie: %q, %q", listSortKey(earlier), listSortKey(tied))
+	}
+
+	// Keys sort in time order whatever the precision or zone of created_at
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load location: %v", err)
+	}
+	times := []time.Time{
+		base,
+		base.Add(time.Nanosecond),
+		base.Add(10 * time.Microsecond),
+		base.Add(100 * time.Millisecond),
+		base.Add(time.Second).In(newYork),
+		base.Add(time.Second + time.Nanosecond),
+	}
+	for i := 1; i < len(times); i++ {
+		prev := listSortKey(&models.Item{ID: "z", CreatedAt: times[i-1]})
+		next := listSortKey(&models.Item{ID: "a", CreatedAt: times[i]})
+		if len(prev) != len(next) || !(prev < next) {
+			t.Errorf("Expected fixed-width keys in time order: %q, %q", prev, next)
+		}
+	}
+}
+
+func TestMemoryListItems_StableTiebreak(t *testing.T) {
+	repo := NewMemoryRepository()
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, id := range []string{"item-b", "item-c", "item-a"} {
+		item := models.NewItem("Item", "Description")
+		item.ID = id
+		item.CreatedAt = createdAt
+		if err := repo.CreateItem(context.Background(), item); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+
+	for call := 0; call < 5; call++ {
+		var ids []string
+		options := &ListItemsOptions{Limit: 2}
+		for {
+			result, err := repo.ListItems(context.Background(), options)
+			if err != nil {
+				t.Fatalf("Failed to list items: %v", err)
+			}
+			for _, item := range result.Items {
+				ids = append(ids, item.ID)
+			}
+			if !result.HasMore {
+				break
+			}
+			options.LastEvaluatedKey = result.LastEvaluatedKey
+		}
+
+		if got := strings.Join(ids, ","); got != "item-a,item-b,item-c" {
+			t.Fatalf("Expected items sharing a timestamp in ID order on call %d, got %s", call, got)
+		}
+	}
+}
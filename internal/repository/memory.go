@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"fis-playground/internal/models"
+)
+
+// MemoryRepository implements ItemRepository in memory. It is intended for
+// local development and tests, and mirrors the DynamoDB repository's semantics.
+type MemoryRepository struct {
+	mu             sync.RWMutex
+	items          map[string]models.Item
+	softDelete     bool // deletes mark items deleted instead of removing them
+	nameUniqueness NameUniqueness
+	dateLocation   *time.Location
+	trackVersion   bool // CollectionVersion reports a version
+
+	idempotencyKeys map[string]memoryIdempotencyKey
+}
+
+// NewMemoryRepository creates a new, empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		items:          make(map[string]models.Item),
+		softDelete:     softDeleteFromEnv(),
+		nameUniqueness: NameUniquenessFromEnv(),
+		trackVersion:   collectionVersionFromEnv(),
+		dateLocation:   CreatedDateLocationFromEnv(),
+	}
+}
+
+// CreateItem stores a new item, generating an ID if not provided
+func (r *MemoryRepository) CreateItem(ctx context.Context, item *models.Item) error {
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+	if isMetaItemID(item.ID) {
+		return fmt.Errorf("%w: item ID %q is reserved", ErrInvalidInput, item.ID)
+	}
+
+	if err := validate(ctx, item); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.items[item.ID]; exists {
+		return fmt.Errorf("%w: item with this ID already exists", ErrItemAlreadyExists)
+	}
+	if err := r.checkName(item); err != nil {
+		return err
+	}
+	r.items[item.ID] = *item
+
+	return nil
+}
+
+// GetItem retrieves a single item by ID. Reads are always consistent and
+// return every field, so options make no difference.
+func (r *MemoryRepository) GetItem(ctx context.Context, id string, options *GetItemOptions) (*models.Item, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, ErrItemNotFound
+	}
+
+	return &item, nil
+}
+
+// ListItems retrieves items in created_at, then ID, order, or its reverse,
+// with pagination support, mirroring the DynamoDB listing index and its
+// cursor. Like it, other sort fields only order each page.
+func (r *MemoryRepository) ListItems(ctx context.Context, options *ListItemsOptions) (*ListItemsResult, error) {
+	limit := int32(50)
+	var startAfter, statusFilter string
+	var descending bool
+	if options != nil && options.CreatedDate != "" {
+		narrowed, err := options.withCreatedDate(r.dateLocation)
+		if err != nil {
+			return nil, err
+		}
+		options = narrowed
+	}
+	if options != nil {
+		descending = options.Descending
+		statusFilter = options.StatusFilter
+		if options.Limit > 0 && options.Limit <= 100 {
+			limit = options.Limit
+		} else if options.Limit > 100 {
+			limit = 100
+		}
+		if key, ok := options.LastEvaluatedKey[listSortAttr].(*types.AttributeValueMemberS); ok {
+			startAfter = key.Value
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]models.Item, 0, len(r.items))
+	for _, item := range r.items {
+		if statusFilter != "" && item.Status != statusFilter {
+			continue
+		}
+		if item.IsDeleted() && (options == nil || !options.IncludeDeleted) {
+			continue
+		}
+		if options != nil && (!options.inCreatedRange(&item) || !options.matchesTags(&item) || !options.matchesOwner(&item)) {
+			continue
+		}
+		if startAfter == "" || descending && listSortKey(&item) < startAfter || !descending && listSortKey(&item) > startAfter {
+			items = append(items, item)
+		}
+	}
+	models.SortItems(items, models.SortCreatedAt, descending)
+
+	result := &ListItemsResult{Items: items}
+	if int32(len(items)) > limit {
+		result.Items = items[:limit]
+		result.HasMore = true
+
+		last := &result.Items[len(result.Items)-1]
+		result.LastEvaluatedKey = map[string]types.AttributeValue{
+			"id":         &types.AttributeValueMemberS{Value: last.ID},
+			listSortAttr: &types.AttributeValueMemberS{Value: listSortKey(last)},
+		}
+	}
+	if options != nil {
+		options.sortPage(result.Items)
+	}
+
+	return result, nil
+}
+
+// UpdateItem updates an existing item
+func (r *MemoryRepository) UpdateItem(ctx context.Context, id string, updates *models.UpdateItemRequest) (*models.Item, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if isMetaItemID(id) {
+		return nil, ErrItemNotFound
+	}
+
+	if updates == nil {
+		return nil, fmt.Errorf("%w: updates cannot be nil", ErrInvalidInput)
+	}
+
+	if err := validate(ctx, updates); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok || item.IsDeleted() {
+		return nil, fmt.Errorf("%w: conditional check failed", ErrItemNotFound)
+	}
+	if updates.RequireOwner != "" && item.CreatedBy != updates.RequireOwner {
+		return nil, fmt.Errorf("%w: %s", ErrNotOwner, id)
+	}
+	if updates.Generation != nil && *updates.Generation != item.Generation {
+		return nil, fmt.Errorf("%w: expected generation %d", ErrStaleGeneration, *updates.Generation)
+	}
+	if updates.Version != nil && *updates.Version != item.Version {
+		return nil, fmt.Errorf("%w: expected version %d", ErrVersionConflict, *updates.Version)
+	}
+
+	item.UpdateFields(updates)
+	if err := r.checkName(&item); err != nil {
+		return nil, err
+	}
+	r.items[id] = item
+
+	return &item, nil
+}
+
+// PatchItem partially updates an existing item
+func (r *MemoryRepository) PatchItem(ctx context.Context, id string, patch *models.PatchItemRequest) (*models.Item, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if isMetaItemID(id) {
+		return nil, ErrItemNotFound
+	}
+
+	if patch == nil {
+		return nil, fmt.Errorf("%w: patch cannot be nil", ErrInvalidInput)
+	}
+
+	if err := validate(ctx, patch); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok || item.IsDeleted() {
+		return nil, fmt.Errorf("%w: conditional check failed", ErrItemNotFound)
+	}
+	if patch.RequireOwner != "" && item.CreatedBy != patch.RequireOwner {
+		return nil, fmt.Errorf("%w: %s", ErrNotOwner, id)
+	}
+	if patch.Generation != nil && *patch.Generation != item.Generation {
+		return nil, fmt.Errorf("%w: expected generation %d", ErrStaleGeneration, *patch.Generation)
+	}
+
+	item.ApplyPatch(patch)
+	if err := r.checkName(&item); err != nil {
+		return nil, err
+	}
+	r.items[id] = item
+
+	return &item, nil
+}
+
+// DeleteItem deletes an existing item that meets the conditions in options,
+// or only marks it deleted with soft deletes enabled
+func (r *MemoryRepository) DeleteItem(ctx context.Context, id string, options *DeleteItemOptions) error {
+	if id == "" {
+		return fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if isMetaItemID(id) {
+		return ErrItemNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok || (r.softDelete && item.IsDeleted()) {
+		return fmt.Errorf("%w: conditional check failed", ErrItemNotFound)
+	}
+	if options != nil && options.RequireOwner != "" && item.CreatedBy != options.RequireOwner {
+		return fmt.Errorf("%w: %s", ErrNotOwner, id)
+	}
+	if options != nil && options.Generation != nil && *options.Generation != item.Generation {
+		return fmt.Errorf("%w: expected generation %d", ErrStaleGeneration, *options.Generation)
+	}
+	if options != nil && options.RequireStatus != "" && item.Status != options.RequireStatus {
+		return fmt.Errorf("%w: item status is not %q", ErrPreconditionFailed, options.RequireStatus)
+	}
+	if r.softDelete {
+		item.SoftDelete(time.Now())
+		r.items[id] = item
+		return nil
+	}
+	delete(r.items, id)
+
+	return nil
+}
+
+// CompactDeletedItems removes items soft-deleted before the cutoff
+func (r *MemoryRepository) CompactDeletedItems(ctx context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, item := range r.items {
+		if item.DeletedAt != nil && item.DeletedAt.Before(before) {
+			delete(r.items, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// GetRawItem returns an item marshaled the way the DynamoDB repository stores it
+func (r *MemoryRepository) GetRawItem(ctx context.Context, id string) (map[string]types.AttributeValue, error) {
+	item, err := r.GetItem(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal item: %w", err)
+	}
+	return av, nil
+}
+
+// AutocompleteItems returns up to limit items within scope whose name
+// starts with prefix, case-insensitively, in name order
+func (r *MemoryRepository) AutocompleteItems(ctx context.Context, prefix string, limit int, scope *OwnerScope) ([]models.Item, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("%w: prefix cannot be empty", ErrInvalidInput)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", ErrInvalidInput)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prefix = models.NameKey(prefix)
+	var items []models.Item
+	for _, item := range r.items {
+		if !item.IsDeleted() && scope.includes(&item) && strings.HasPrefix(models.NameKey(item.Name), prefix) {
+			items = append(items, item)
+		}
+	}
+	sortByName(items)
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items, nil
+}
+
+// FacetItems counts the items within scope per distinct value of a field
+func (r *MemoryRepository) FacetItems(ctx context.Context, field string, scope *OwnerScope) (*models.FacetResult, error) {
+	if !models.IsFacetField(field) {
+		return nil, fmt.Errorf("%w: field %q cannot be faceted", ErrInvalidInput, field)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	facet := models.NewFacetResult(field)
+	for _, item := range r.items {
+		if scope.includes(&item) {
+			facet.Add(item.FacetValue(field))
+		}
+	}
+
+	return facet, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// metadataChange is an update's change to an item's metadata map
+type metadataChange struct {
+	// changes maps keys to their new values, with nil removing the key
+	changes map[string]*string
+	mode    string
+	// absent requires the item to have no metadata. It is set when a merge
+	// into an item without metadata is retried as a replace, so a map
+	// written in between isn't overwritten.
+	absent bool
+}
+
+// newMetadataChange returns the metadata change an update asks for, or nil
+// when it leaves metadata alone
+func newMetadataChange(updates *models.UpdateItemRequest) *metadataChange {
+	if updates.Metadata == nil {
+		return nil
+	}
+	return &metadataChange{changes: updates.Metadata, mode: updates.MetadataMode}
+}
+
+// replaceMetadata returns the change replacing an item's metadata with
+// metadata
+func replaceMetadata(metadata map[string]string) *metadataChange {
+	changes := make(map[string]*string, len(metadata))
+	for key, value := range metadata {
+		changes[key] = &value
+	}
+	return &metadataChange{changes: changes, mode: models.MetadataReplace}
+}
+
+// merging reports whether the change updates keys within the stored map
+func (m *metadataChange) merging() bool {
+	return m != nil && m.mode == models.MetadataMerge && !m.absent
+}
+
+// asReplace returns the merge retried against an item without metadata,
+// where the merged map is just the keys being set
+func (m *metadataChange) asReplace() *metadataChange {
+	return &metadataChange{changes: m.changes, mode: models.MetadataReplace, absent: true}
+}
+
+// metadataExpression returns the SET and REMOVE clauses and the condition
+// that apply the change, adding their names and values. A merge writes each
+// key by its own document path, which fails when the item has no metadata
+// map, so it is conditioned on the map existing. Keys go through name
+// placeholders, so reserved words and keys containing dots are taken
+// literally rather than as paths.
+func (r *DynamoDBRepository) metadataExpression(change *metadataChange, names map[string]string, values map[string]types.AttributeValue) (set, remove []string, condition string, err error) {
+	names["#metadata"] = r.attrNames.Storage("metadata")
+
+	if change.merging() {
+		for i, key := range slices.Sorted(maps.Keys(change.changes)) {
+			name := fmt.Sprintf("#metadata_key%d", i)
+			names[name] = key
+			value := change.changes[key]
+			if value == nil {
+				remove = append(remove, "#metadata."+name)
+				continue
+			}
+			placeholder := fmt.Sprintf(":metadata_value%d", i)
+			values[placeholder] = &types.AttributeValueMemberS{Value: *value}
+			set = append(set, fmt.Sprintf("#metadata.%s = %s", name, placeholder))
+		}
+		return set, remove, "attribute_exists(#metadata)", nil
+	}
+
+	if change.absent {
+		condition = "attribute_not_exists(#metadata)"
+	}
+	metadata := models.ApplyMetadata(nil, change.changes, models.MetadataReplace)
+	if metadata == nil {
+		return nil, []string{"#metadata"}, condition, nil
+	}
+	av, err := attributevalue.Marshal(metadata)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	values[":metadata"] = av
+	return []string{"#metadata = :metadata"}, nil, condition, nil
+}
+
+// storedHasMetadata reports whether a stored item, such as the one a failed
+// condition check returns, has a metadata map
+func (r *DynamoDBRepository) storedHasMetadata(av map[string]types.AttributeValue) bool {
+	_, ok := av[r.attrNames.Storage("metadata")]
+	return ok
+}
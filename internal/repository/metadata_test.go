@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestMemoryUpdateItem_Metadata(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	req := &models.CreateItemRequest{Name: "Item", Description: "Description", Metadata: map[string]string{"color": "red", "size": "large", "name": "kept"}}
+	item := req.NewItem()
+	if err := repo.CreateItem(ctx, item); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+
+	// Merge sets and removes only the keys given
+	updated, err := repo.UpdateItem(ctx, item.ID, &models.UpdateItemRequest{
+		Metadata:     map[string]*string{"color": aws.String("blue"), "size": nil, "origin": aws.String("uk")},
+		MetadataMode: models.MetadataMerge,
+	})
+	if err != nil {
+		t.Fatalf("Failed to merge metadata: %v", err)
+	}
+	want := map[string]string{"color": "blue", "name": "kept", "origin": "uk"}
+	if !reflect.DeepEqual(updated.Metadata, want) {
+		t.Errorf("Expected merged metadata %v, got %v", want, updated.Metadata)
+	}
+
+	// Replace overwrites the whole map
+	updated, err = repo.UpdateItem(ctx, item.ID, &models.UpdateItemRequest{
+		Metadata:     map[string]*string{"weight": aws.String("2kg")},
+		MetadataMode: models.MetadataReplace,
+	})
+	if err != nil {
+		t.Fatalf("Failed to replace metadata: %v", err)
+	}
+	if want := map[string]string{"weight": "2kg"}; !reflect.DeepEqual(updated.Metadata, want) {
+		t.Errorf("Expected replaced metadata %v, got %v", want, updated.Metadata)
+	}
+
+	// Leaving metadata out of an update keeps it
+	updated, err = repo.UpdateItem(ctx, item.ID, &models.UpdateItemRequest{Name: "Renamed"})
+	if err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+	if len(updated.Metadata) != 1 {
+		t.Errorf("Expected metadata untouched, got %v", updated.Metadata)
+	}
+}
+
+func TestUpdateItem_MetadataMerge(t *testing.T) {
+	var captured *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			captured = params
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	// "name" is a reserved word and "a.b" would be a path if used directly
+	_, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{
+		Metadata:     map[string]*string{"name": aws.String("x"), "a.b": nil},
+		MetadataMode: models.MetadataMerge,
+	})
+	if err != nil {
+		t.Fatalf("Failed to merge metadata: %v", err)
+	}
+
+	expression := *captured.UpdateExpression
+	if !strings.Contains(expression, "#metadata.#metadata_key1 = :metadata_value1") {
+		t.Errorf("Expected the key set by its own path, got %q", expression)
+	}
+	if !strings.Contains(expression, "REMOVE #metadata.#metadata_key0") {
+		t.Errorf("Expected the null key removed, got %q", expression)
+	}
+	if strings.Contains(expression, "#metadata = :metadata") {
+		t.Errorf("Expected a merge not to replace the map, got %q", expression)
+	}
+	if captured.ExpressionAttributeNames["#metadata_key0"] != "a.b" || captured.ExpressionAttributeNames["#metadata_key1"] != "name" {
+		t.Errorf("Expected keys bound to placeholders, got %v", captured.ExpressionAttributeNames)
+	}
+	if !strings.Contains(*captured.ConditionExpression, "attribute_exists(#metadata)") {
+		t.Errorf("Expected the merge to require a metadata map, got %q", *captured.ConditionExpression)
+	}
+}
+
+func TestUpdateItem_MetadataMergeWithoutMap(t *testing.T) {
+	var inputs []*dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			inputs = append(inputs, params)
+			if strings.Contains(*params.ConditionExpression, "attribute_exists(#metadata)") {
+				// The stored item has no metadata map to merge into
+				return nil, &types.ConditionalCheckFailedException{Item: map[string]types.AttributeValue{
+					"id": &types.AttributeValueMemberS{Value: "item-1"},
+				}}
+			}
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	_, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{
+		Metadata:     map[string]*string{"color": aws.String("red"), "size": nil},
+		MetadataMode: models.MetadataMerge,
+	})
+	if err != nil {
+		t.Fatalf("Failed to merge metadata: %v", err)
+	}
+	if len(inputs) != 2 {
+		t.Fatalf("Expected the merge to be retried as a new map, got %d writes", len(inputs))
+	}
+
+	retry := inputs[1]
+	if !strings.Contains(*retry.UpdateExpression, "#metadata = :metadata") || !strings.Contains(*retry.ConditionExpression, "attribute_not_exists(#metadata)") {
+		t.Errorf("Expected the retry to write a new map, got %q if %q", *retry.UpdateExpression, *retry.ConditionExpression)
+	}
+	var metadata map[string]string
+	if err := attributevalue.Unmarshal(retry.ExpressionAttributeValues[":metadata"], &metadata); err != nil || !reflect.DeepEqual(metadata, map[string]string{"color": "red"}) {
+		t.Errorf("Expected the new map to hold the set keys, got %v (%v)", metadata, err)
+	}
+}
+
+func TestUpdateItem_MetadataReplace(t *testing.T) {
+	var captured *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			captured = params
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	_, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{
+		Metadata: map[string]*string{"color": aws.String("red"), "size": nil},
+	})
+	if err != nil {
+		t.Fatalf("Failed to replace metadata: %v", err)
+	}
+	if !strings.Contains(*captured.UpdateExpression, "#metadata = :metadata") {
+		t.Errorf("Expected the map replaced, got %q", *captured.UpdateExpression)
+	}
+	if strings.Contains(*captured.ConditionExpression, "metadata") {
+		t.Errorf("Expected a replace to be unconditional on metadata, got %q", *captured.ConditionExpression)
+	}
+	var metadata map[string]string
+	if err := attributevalue.Unmarshal(captured.ExpressionAttributeValues[":metadata"], &metadata); err != nil || !reflect.DeepEqual(metadata, map[string]string{"color": "red"}) {
+		t.Errorf("Expected the map to hold only non-null keys, got %v (%v)", metadata, err)
+	}
+
+	// Replacing with an empty map removes the attribute
+	if _, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{Metadata: map[string]*string{}}); err != nil {
+		t.Fatalf("Failed to clear metadata: %v", err)
+	}
+	if !strings.Contains(*captured.UpdateExpression, "REMOVE #metadata") {
+		t.Errorf("Expected an empty replace to remove metadata, got %q", *captured.UpdateExpression)
+	}
+}
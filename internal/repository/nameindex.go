@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// Name prefix index.
+//
+// Autocomplete queries a GSI sharing the listing index's constant partition
+// key and sorted by the case-folded name, so a prefix is a begins_with key
+// condition that reads only matching items. The index only needs to project
+// the attributes autocomplete returns or filters on. Like the listing index
+// keys, the sort key is written on create and kept in step on rename; items
+// written before the index was introduced need it backfilled to appear.
+const (
+	// DefaultNameIndexName is the GSI used for autocomplete
+	DefaultNameIndexName = "name-prefix-index"
+
+	nameSortAttr = "name_sk"
+)
+
+// addNameIndexAttributes adds the name index sort key to a marshaled item.
+// It is owned by the repository and not subject to attribute name mapping.
+func addNameIndexAttributes(av map[string]types.AttributeValue, item *models.Item) {
+	av[nameSortAttr] = &types.AttributeValueMemberS{Value: models.NameKey(item.Name)}
+}
+
+// AutocompleteItems returns up to limit items within scope whose name
+// starts with prefix, case-insensitively, in name order. Only the ID, name and
+// visibility window are read. Without the name index, or when it turns out
+// to be missing, the table is scanned instead, which reads every item.
+func (r *DynamoDBRepository) AutocompleteItems(ctx context.Context, prefix string, limit int, scope *OwnerScope) ([]models.Item, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("%w: prefix cannot be empty", ErrInvalidInput)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", ErrInvalidInput)
+	}
+
+	names := r.attrNames.placeholders("id", "name", "visible_from", "visible_until", "deleted_at")
+	names["#name_sk"] = nameSortAttr
+	values := map[string]types.AttributeValue{
+		":prefix": &types.AttributeValueMemberS{Value: models.NameKey(prefix)},
+	}
+	projection := aws.String("#id, #name, #visible_from, #visible_until")
+	filter := "attribute_not_exists(#deleted_at)"
+	if ownerFilter := r.ownerFilter(scope, names, values); ownerFilter != "" {
+		filter += " AND " + ownerFilter
+	}
+
+	var rows []map[string]types.AttributeValue
+	var err error
+	useIndex := r.hasIndex(ctx, r.nameIndexName)
+	if useIndex {
+		names["#pk"] = listPartitionAttr
+		values[":pk"] = &types.AttributeValueMemberS{Value: listPartitionValue}
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			IndexName:                 aws.String(r.nameIndexName),
+			KeyConditionExpression:    aws.String("#pk = :pk AND begins_with(#name_sk, :prefix)"),
+			FilterExpression:          aws.String(filter),
+			ProjectionExpression:      projection,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ScanIndexForward:          aws.Bool(true),
+		}
+		rows, _, err = readPage(int32(limit), nil, func(limit int32, startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+			input.Limit = aws.Int32(limit)
+			input.ExclusiveStartKey = startKey
+			result, err := r.client.Query(ctx, input)
+			if err != nil {
+				return nil, nil, err
+			}
+			return result.Items, result.LastEvaluatedKey, nil
+		})
+		if err != nil {
+			if err = r.indexQueryError(r.nameIndexName, err); !errors.Is(err, errIndexMissing) {
+				return nil, err
+			}
+			// Scan instead, without the index key, which an unused
+			// placeholder would make DynamoDB reject
+			delete(names, "#pk")
+			delete(values, ":pk")
+			useIndex, err = false, nil
+		}
+	}
+	if !useIndex {
+		// The scan's Limit would apply before the prefix filter, so whole
+		// pages are read until enough items match
+		input := &dynamodb.ScanInput{
+			TableName:                 aws.String(r.tableName),
+			FilterExpression:          aws.String("begins_with(#name_sk, :prefix) AND " + filter),
+			ProjectionExpression:      projection,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}
+		for {
+			result, scanErr := r.client.Scan(ctx, input)
+			if scanErr != nil {
+				err = scanErr
+				break
+			}
+			rows = append(rows, result.Items...)
+			if result.LastEvaluatedKey == nil || len(rows) >= limit {
+				break
+			}
+			input.ExclusiveStartKey = result.LastEvaluatedKey
+		}
+	}
+	if err != nil {
+		return nil, HandleDynamoDBError(err)
+	}
+
+	var items []models.Item
+	if err := attributevalue.UnmarshalListOfMaps(r.attrNames.fromStorageList(rows), &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+	sortByName(items)
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// sortByName orders items by case-folded name, then ID, matching the name
+// index's order
+func sortByName(items []models.Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := models.NameKey(items[i].Name), models.NameKey(items[j].Name)
+		if a != b {
+			return a < b
+		}
+		return items[i].ID < items[j].ID
+	})
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"fis-playground/internal/models"
+)
+
+func suggestionRow(id, name string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id":   &types.AttributeValueMemberS{Value: id},
+		"name": &types.AttributeValueMemberS{Value: name},
+	}
+}
+
+func TestAutocompleteItems_QueriesNameIndex(t *testing.T) {
+	var queries []*dynamodb.QueryInput
+	client := &mockDynamoDBClient{
+		DescribeTableFn: func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+			return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+				GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+					{IndexName: aws.String(DefaultNameIndexName)},
+				},
+			}}, nil
+		},
+		QueryFn: func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			queries = append(queries, params)
+			return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+				suggestionRow("item-1", "Apple"),
+				suggestionRow("item-2", "apricot"),
+			}}, nil
+		},
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			panic("autocomplete should not scan when the name index exists")
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.nameIndexName = DefaultNameIndexName
+
+	items, err := repo.AutocompleteItems(context.Background(), "AP", 5, nil)
+	if err != nil {
+		t.Fatalf("Failed to autocomplete: %v", err)
+	}
+	if len(items) != 2 || items[0].Name != "Apple" || items[1].Name != "apricot" {
+		t.Errorf("Expected Apple and apricot, got %+v", items)
+	}
+
+	if len(queries) != 1 {
+		t.Fatalf("Expected 1 query, got %d", len(queries))
+	}
+	query := queries[0]
+	if aws.ToString(query.IndexName) != DefaultNameIndexName {
+		t.Errorf("Expected index %s, got %s", DefaultNameIndexName, aws.ToString(query.IndexName))
+	}
+	if got := aws.ToString(query.KeyConditionExpression); got != "#pk = :pk AND begins_with(#name_sk, :prefix)" {
+		t.Errorf("Unexpected key condition %q", got)
+	}
+	if prefix := query.ExpressionAttributeValues[":prefix"].(*types.AttributeValueMemberS).Value; prefix != "ap" {
+		t.Errorf("Expected the case-folded prefix 'ap', got %q", prefix)
+	}
+	if got := aws.ToString(query.ProjectionExpression); got != "#id, #name, #visible_from, #visible_until" {
+		t.Errorf("Expected only the suggestion attributes to be projected, got %q", got)
+	}
+	if aws.ToInt32(query.Limit) != 5 {
+		t.Errorf("Expected limit 5, got %d", aws.ToInt32(query.Limit))
+	}
+}
+
+func TestAutocompleteItems_ScansWithoutNameIndex(t *testing.T) {
+	scans := 0
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			scans++
+			if params.Limit != nil {
+				t.Errorf("Expected whole pages to be scanned, got limit %d", aws.ToInt32(params.Limit))
+			}
+			if scans == 1 {
+				return &dynamodb.ScanOutput{
+					Items:            []map[string]types.AttributeValue{suggestionRow("item-2", "Bananas")},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "item-2"}},
+				}, nil
+			}
+			return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+				suggestionRow("item-1", "banana"),
+				suggestionRow("item-3", "Band"),
+			}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	items, err := repo.AutocompleteItems(context.Background(), "ban", 2, nil)
+	if err != nil {
+		t.Fatalf("Failed to autocomplete: %v", err)
+	}
+	if scans != 2 {
+		t.Errorf("Expected scanning to continue until enough items matched, got %d scans", scans)
+	}
+	if len(items) != 2 || items[0].Name != "banana" || items[1].Name != "Bananas" {
+		t.Errorf("Expected the first 2 matches in name order, got %+v", items)
+	}
+}
+
+func TestAutocompleteItems_ScansWhenNameIndexMissing(t *testing.T) {
+	queries := 0
+	var scans []*dynamodb.ScanInput
+	client := &mockDynamoDBClient{
+		DescribeTableFn: func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+			return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+				GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+					{IndexName: aws.String(DefaultNameIndexName)},
+				},
+			}}, nil
+		},
+		QueryFn: func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			queries++
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "The table does not have the specified index: " + DefaultNameIndexName}
+		},
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			scans = append(scans, params)
+			return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+				suggestionRow("item-2", "Cherry"),
+				suggestionRow("item-1", "cherimoya"),
+			}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.nameIndexName = DefaultNameIndexName
+
+	for range 2 {
+		items, err := repo.AutocompleteItems(context.Background(), "che", 5, nil)
+		if err != nil {
+			t.Fatalf("Failed to autocomplete: %v", err)
+		}
+		if len(items) != 2 || items[0].Name != "cherimoya" || items[1].Name != "Cherry" {
+			t.Errorf("Expected cherimoya and Cherry, got %+v", items)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("Expected the missing index to be queried once, got %d queries", queries)
+	}
+	if len(scans) != 2 {
+		t.Fatalf("Expected 2 scans, got %d", len(scans))
+	}
+	if _, ok := scans[0].ExpressionAttributeNames["#pk"]; ok {
+		t.Error("Expected the scan not to carry the index key placeholder")
+	}
+	if _, ok := scans[0].ExpressionAttributeValues[":pk"]; ok {
+		t.Error("Expected the scan not to carry the index key value")
+	}
+}
+
+func TestUpdateItem_RenameUpdatesNameIndexKey(t *testing.T) {
+	var input *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			input = params
+			return &dynamodb.UpdateItemOutput{Attributes: suggestionRow("item-1", "Renamed")}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	if _, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{Name: "Renamed"}); err != nil {
+		t.Fatalf("Failed to update item: %v", err)
+	}
+	if input.ExpressionAttributeNames["#name_sk"] != nameSortAttr {
+		t.Fatalf("Expected the update to set the name index key, got %q", aws.ToString(input.UpdateExpression))
+	}
+	if key := input.ExpressionAttributeValues[":name_sk"].(*types.AttributeValueMemberS).Value; key != "renamed" {
+		t.Errorf("Expected name index key 'renamed', got %q", key)
+	}
+}
+
+func TestMemoryRepository_AutocompleteItems(t *testing.T) {
+	t.Setenv("SOFT_DELETE", "true")
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	var deleted string
+	for _, name := range []string{"Cherry", "cheese", "Chestnut", "Banana"} {
+		item := models.NewItem(name, "Description")
+		if err := repo.CreateItem(ctx, item); err != nil {
+			t.Fatalf("Failed to seed %s: %v", name, err)
+		}
+		if name == "Chestnut" {
+			deleted = item.ID
+		}
+	}
+	if err := repo.DeleteItem(ctx, deleted, nil); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+
+	items, err := repo.AutocompleteItems(ctx, "CHE", 10, nil)
+	if err != nil {
+		t.Fatalf("Failed to autocomplete: %v", err)
+	}
+	if len(items) != 2 || items[0].Name != "cheese" || items[1].Name != "Cherry" {
+		t.Errorf("Expected cheese and Cherry, got %+v", items)
+	}
+}
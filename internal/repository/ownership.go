@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// storedOwner returns the created_by of a stored item, such as the one a
+// failed condition check returns, or "" when it has none
+func (r *DynamoDBRepository) storedOwner(av map[string]types.AttributeValue) string {
+	if owner, ok := av[r.attrNames.Storage("created_by")].(*types.AttributeValueMemberS); ok {
+		return owner.Value
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// ownedItemMock emulates conditional writes against an item created by owner
+func ownedItemMock(t *testing.T, owner string) *mockDynamoDBClient {
+	stored := map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: "item-1"},
+		"name":       &types.AttributeValueMemberS{Value: "Item"},
+		"status":     &types.AttributeValueMemberS{Value: "active"},
+		"created_by": &types.AttributeValueMemberS{Value: owner},
+	}
+	check := func(condition *string, values map[string]types.AttributeValue) error {
+		if !strings.Contains(aws.ToString(condition), "#created_by = :owner") {
+			t.Errorf("Expected an owner condition, got %q", aws.ToString(condition))
+		}
+		if values[":owner"].(*types.AttributeValueMemberS).Value != owner {
+			return &types.ConditionalCheckFailedException{Item: stored}
+		}
+		return nil
+	}
+
+	return &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			if err := check(params.ConditionExpression, params.ExpressionAttributeValues); err != nil {
+				return nil, err
+			}
+			return &dynamodb.UpdateItemOutput{Attributes: stored}, nil
+		},
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			if err := check(params.ConditionExpression, params.ExpressionAttributeValues); err != nil {
+				return nil, err
+			}
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}
+}
+
+func TestUpdateItem_RequireOwner(t *testing.T) {
+	repo := NewDynamoDBRepository(ownedItemMock(t, "alice"), "items")
+
+	if _, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{Name: "Renamed", RequireOwner: "alice"}); err != nil {
+		t.Errorf("Expected the owner's update to succeed, got %v", err)
+	}
+
+	generation := int64(1)
+	_, err := repo.UpdateItem(context.Background(), "item-1", &models.UpdateItemRequest{Name: "Renamed", RequireOwner: "bob", Generation: &generation})
+	if !errors.Is(err, ErrNotOwner) {
+		t.Errorf("Expected ErrNotOwner for another principal, got %v", err)
+	}
+}
+
+func TestDeleteItem_RequireOwner(t *testing.T) {
+	repo := NewDynamoDBRepository(ownedItemMock(t, "alice"), "items")
+
+	err := repo.DeleteItem(context.Background(), "item-1", &DeleteItemOptions{RequireOwner: "bob"})
+	if !errors.Is(err, ErrNotOwner) {
+		t.Errorf("Expected ErrNotOwner for another principal, got %v", err)
+	}
+	if err := repo.DeleteItem(context.Background(), "item-1", &DeleteItemOptions{RequireOwner: "alice"}); err != nil {
+		t.Errorf("Expected the owner's delete to succeed, got %v", err)
+	}
+}
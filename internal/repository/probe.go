@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+)
+
+// ProbeClientFunc creates a client for a region, used to probe tables
+// outside the running configuration
+type ProbeClientFunc func(ctx context.Context, region string) (DynamoDBAPI, error)
+
+// NewProbeClient creates a standalone client for region, configured like
+// the repository's own but sharing nothing with it, so probing another
+// region or table leaves the running configuration untouched
+func NewProbeClient(ctx context.Context, region string) (DynamoDBAPI, error) {
+	return NewDynamoDBClient(ctx, &DynamoDBConfig{Region: region})
+}
+
+// tableNamePattern and regionPattern match valid DynamoDB table names and
+// AWS region names, such as us-east-1 or us-gov-west-1
+var (
+	tableNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,255}$`)
+	regionPattern    = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+)
+
+// IsValidTableName reports whether name is a valid DynamoDB table name
+func IsValidTableName(name string) bool {
+	return tableNamePattern.MatchString(name)
+}
+
+// IsValidRegion reports whether region looks like an AWS region name. The
+// region becomes part of the endpoint's host name, so nothing else is
+// accepted.
+func IsValidRegion(region string) bool {
+	return regionPattern.MatchString(region)
+}
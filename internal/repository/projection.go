@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+
+	"fis-playground/internal/models"
+)
+
+// projectionRequired are the attributes every projected read fetches
+// whatever was asked for: the key, what listing cursors and date sorts use, and
+// what callers check before returning an item, such as its visibility
+// window, soft delete, owner and generation
+var projectionRequired = []string{
+	"id", "created_at", "updated_at", "status", "generation",
+	"deleted_at", "owner_id", "visible_from", "visible_until",
+}
+
+// itemAttributes maps an item's JSON field names to their model attribute
+// names, which differ where the stored format does, as expires_at is
+// stored in ttl
+var itemAttributes = func() map[string]string {
+	attributes := map[string]string{}
+	t := reflect.TypeOf(models.Item{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		attrName, _, _ := strings.Cut(field.Tag.Get("dynamodbav"), ",")
+		if jsonName != "" && jsonName != "-" && attrName != "" && attrName != "-" {
+			attributes[jsonName] = attrName
+		}
+	}
+	return attributes
+}()
+
+// projection returns a projection expression reading only the attributes
+// for the given JSON fields, plus projectionRequired, adding their
+// placeholders to names. Fields that aren't stored attributes are ignored;
+// no fields returns nil, reading the whole item.
+func (r *DynamoDBRepository) projection(fields []string, names map[string]string) *string {
+	if len(fields) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	var placeholders []string
+	add := func(attribute string) {
+		if seen[attribute] {
+			return
+		}
+		seen[attribute] = true
+		names["#"+attribute] = r.attrNames.Storage(attribute)
+		placeholders = append(placeholders, "#"+attribute)
+	}
+	for _, attribute := range projectionRequired {
+		add(attribute)
+	}
+	for _, field := range fields {
+		if attribute, ok := itemAttributes[field]; ok {
+			add(attribute)
+		}
+	}
+	expression := strings.Join(placeholders, ", ")
+	return &expression
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestGetItem_ProjectsRequestedFields(t *testing.T) {
+	var input *dynamodb.GetItemInput
+	client := &mockDynamoDBClient{
+		GetItemFn: func(ctx context.Context, params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			input = params
+			return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+				"id":        &types.AttributeValueMemberS{Value: "item-1"},
+				"item_name": &types.AttributeValueMemberS{Value: "Item"},
+			}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+	repo.attrNames = AttributeNames{"name": "item_name"}
+
+	item, err := repo.GetItem(context.Background(), "item-1", &GetItemOptions{Fields: []string{"name", "expires_at"}})
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if item.Name != "Item" {
+		t.Errorf("Expected the projected name, got %q", item.Name)
+	}
+
+	if input.ProjectionExpression == nil {
+		t.Fatal("Expected a projection expression")
+	}
+	projected := strings.Split(*input.ProjectionExpression, ", ")
+	for _, placeholder := range []string{"#id", "#name", "#ttl", "#status", "#deleted_at"} {
+		if !slices.Contains(projected, placeholder) {
+			t.Errorf("Expected %s in projection %q", placeholder, *input.ProjectionExpression)
+		}
+	}
+	if slices.Contains(projected, "#description") {
+		t.Errorf("Expected description to be left out of projection %q", *input.ProjectionExpression)
+	}
+	if input.ExpressionAttributeNames["#name"] != "item_name" || input.ExpressionAttributeNames["#ttl"] != "ttl" {
+		t.Errorf("Expected placeholders for the storage names, got %v", input.ExpressionAttributeNames)
+	}
+}
+
+func TestGetItem_ReadsWholeItemWithoutFields(t *testing.T) {
+	var input *dynamodb.GetItemInput
+	client := &mockDynamoDBClient{
+		GetItemFn: func(ctx context.Context, params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			input = params
+			return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: "item-1"},
+			}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	if _, err := repo.GetItem(context.Background(), "item-1", nil); err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if input.ProjectionExpression != nil || input.ExpressionAttributeNames != nil {
+		t.Errorf("Expected no projection, got %v with %v", input.ProjectionExpression, input.ExpressionAttributeNames)
+	}
+}
+
+func TestListItems_ScanProjectsFieldsAndSortField(t *testing.T) {
+	var input *dynamodb.ScanInput
+	client := &mockDynamoDBClient{
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			input = params
+			return &dynamodb.ScanOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	options := &ListItemsOptions{Limit: 10, ProjectionFields: []string{"category"}, SortField: models.SortName}
+	if _, err := repo.ListItems(context.Background(), options); err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+
+	if input.ProjectionExpression == nil {
+		t.Fatal("Expected a projection expression")
+	}
+	projected := strings.Split(*input.ProjectionExpression, ", ")
+	for _, placeholder := range []string{"#id", "#created_at", "#category", "#name"} {
+		if !slices.Contains(projected, placeholder) {
+			t.Errorf("Expected %s in projection %q", placeholder, *input.ProjectionExpression)
+		}
+		if _, ok := input.ExpressionAttributeNames[placeholder]; !ok {
+			t.Errorf("Expected a name for %s, got %v", placeholder, input.ExpressionAttributeNames)
+		}
+	}
+	if slices.Contains(projected, "#description") || slices.Contains(projected, "#tags") {
+		t.Errorf("Expected unrequested fields to be left out of projection %q", *input.ProjectionExpression)
+	}
+}
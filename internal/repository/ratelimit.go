@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// TokenBucket paces operations to a steady rate while allowing bursts up to
+// its capacity. Waiters reserve tokens in arrival order, so the bucket may
+// go into debt and later callers wait for it to be repaid.
+type TokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // tokens added per second
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket creates a full bucket refilling at rate tokens per second.
+// Its capacity is one second's worth of tokens, and at least one.
+func NewTokenBucket(rate float64) *TokenBucket {
+	return &TokenBucket{
+		rate:     rate,
+		capacity: max(rate, 1),
+		tokens:   max(rate, 1),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until n tokens are available, or returns the context's error.
+// When the context's deadline falls before the tokens would be available it
+// fails immediately instead of waiting for nothing.
+func (b *TokenBucket) Wait(ctx context.Context, n int) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= float64(n)
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		b.release(n)
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.release(n)
+		return ctx.Err()
+	}
+}
+
+// release returns tokens reserved by a wait that gave up
+func (b *TokenBucket) release(n int) {
+	b.mu.Lock()
+	b.tokens += float64(n)
+	b.mu.Unlock()
+}
+
+// WriteLimitedClient paces the writes made through a DynamoDB client so a
+// burst is smoothed out instead of exceeding the table's provisioned write
+// capacity and being throttled. Each item written takes one token, so a
+// batch write waits for as many tokens as it has requests. Reads are not
+// limited.
+//
+// The limit applies per client, so with several Lambda instances the table
+// sees up to the limit times the number of instances.
+type WriteLimitedClient struct {
+	DynamoDBAPI
+	bucket *TokenBucket
+}
+
+// NewWriteLimitedClient wraps client, allowing writesPerSec item writes per second
+func NewWriteLimitedClient(client DynamoDBAPI, writesPerSec float64) *WriteLimitedClient {
+	return &WriteLimitedClient{
+		DynamoDBAPI: client,
+		bucket:      NewTokenBucket(writesPerSec),
+	}
+}
+
+// wait takes n tokens, reporting a wait cut short by the context as a
+// throughput error
+func (c *WriteLimitedClient) wait(ctx context.Context, n int) error {
+	if err := c.bucket.Wait(ctx, n); err != nil {
+		return fmt.Errorf("%w: write throughput limit: %w", ErrOperationFailed, err)
+	}
+	return nil
+}
+
+// PutItem waits for write capacity, then puts the item
+func (c *WriteLimitedClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if err := c.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return c.DynamoDBAPI.PutItem(ctx, params, optFns...)
+}
+
+// UpdateItem waits for write capacity, then updates the item
+func (c *WriteLimitedClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if err := c.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return c.DynamoDBAPI.UpdateItem(ctx, params, optFns...)
+}
+
+// DeleteItem waits for write capacity, then deletes the item
+func (c *WriteLimitedClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	if err := c.wait(ctx, 1); err != nil {
+		return nil, err
+	}
+	return c.DynamoDBAPI.DeleteItem(ctx, params, optFns...)
+}
+
+// BatchWriteItem waits for capacity for every request in the batch, then writes it
+func (c *WriteLimitedClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	requests := 0
+	for _, tableRequests := range params.RequestItems {
+		requests += len(tableRequests)
+	}
+	if err := c.wait(ctx, requests); err != nil {
+		return nil, err
+	}
+	return c.DynamoDBAPI.BatchWriteItem(ctx, params, optFns...)
+}
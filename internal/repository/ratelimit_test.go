@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestWriteLimitedClient_PacesWrites(t *testing.T) {
+	puts := 0
+	client := NewWriteLimitedClient(&mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			puts++
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}, 50)
+
+	// The first 50 writes use the burst; the next 5 wait 20ms each
+	start := time.Now()
+	for i := 0; i < 55; i++ {
+		if _, err := client.PutItem(context.Background(), &dynamodb.PutItemInput{}); err != nil {
+			t.Fatalf("Expected write to succeed, got %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	if puts != 55 {
+		t.Errorf("Expected 55 writes, got %d", puts)
+	}
+	if elapsed < 90*time.Millisecond {
+		t.Errorf("Expected writes beyond the burst to be paced, took %s", elapsed)
+	}
+}
+
+func TestWriteLimitedClient_BatchTakesTokenPerRequest(t *testing.T) {
+	client := NewWriteLimitedClient(&mockDynamoDBClient{
+		BatchWriteFn: func(ctx context.Context, params *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			return &dynamodb.BatchWriteItemOutput{}, nil
+		},
+	}, 10)
+
+	batch := &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{"items": make([]types.WriteRequest, 15)},
+	}
+
+	// 15 requests overdraw the 10-token bucket, so the first batch waits
+	// half a second and the second would wait a second and a half
+	start := time.Now()
+	if _, err := client.BatchWriteItem(context.Background(), batch); err != nil {
+		t.Fatalf("Expected batch to succeed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond {
+		t.Errorf("Expected the batch to wait for its overdraw, took %s", elapsed)
+	}
+
+	start = time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.BatchWriteItem(ctx, batch)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the second batch to exceed the deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected an unreachable deadline to fail without waiting, took %s", elapsed)
+	}
+}
+
+func TestWriteLimitedClient_CancellationInterruptsWait(t *testing.T) {
+	client := NewWriteLimitedClient(&mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}, 1)
+
+	if _, err := client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{}); err != nil {
+		t.Fatalf("Expected the first write to use the burst, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrOperationFailed) {
+		t.Fatalf("Expected a cancelled throughput error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected cancellation to interrupt the one second wait, took %s", elapsed)
+	}
+
+	// The cancelled wait gave its token back, so the next write waits at most a second
+	ctx, cancel = context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	if _, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{}); err != nil {
+		t.Errorf("Expected the next write to succeed once a token is available, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// GetRawItem returns an item exactly as stored, without unmarshaling
+func (r *DynamoDBRepository) GetRawItem(ctx context.Context, id string) (map[string]types.AttributeValue, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       r.attrNames.key(id),
+	})
+	if err != nil {
+		return nil, HandleDynamoDBError(err)
+	}
+	if result.Item == nil {
+		return nil, ErrItemNotFound
+	}
+
+	return result.Item, nil
+}
+
+// RawItemJSON renders stored attributes in DynamoDB's JSON format, where
+// each value is an object keyed by its type descriptor (e.g. {"S": "x"})
+func RawItemJSON(item map[string]types.AttributeValue) map[string]interface{} {
+	result := make(map[string]interface{}, len(item))
+	for name, av := range item {
+		result[name] = rawAttributeJSON(av)
+	}
+	return result
+}
+
+// rawAttributeJSON renders a single attribute value with its type descriptor
+func rawAttributeJSON(av types.AttributeValue) map[string]interface{} {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]interface{}{"S": v.Value}
+	case *types.AttributeValueMemberN:
+		return map[string]interface{}{"N": v.Value}
+	case *types.AttributeValueMemberB:
+		return map[string]interface{}{"B": v.Value}
+	case *types.AttributeValueMemberBOOL:
+		return map[string]interface{}{"BOOL": v.Value}
+	case *types.AttributeValueMemberNULL:
+		return map[string]interface{}{"NULL": v.Value}
+	case *types.AttributeValueMemberSS:
+		return map[string]interface{}{"SS": v.Value}
+	case *types.AttributeValueMemberNS:
+		return map[string]interface{}{"NS": v.Value}
+	case *types.AttributeValueMemberBS:
+		return map[string]interface{}{"BS": v.Value}
+	case *types.AttributeValueMemberL:
+		list := make([]interface{}, len(v.Value))
+		for i, elem := range v.Value {
+			list[i] = rawAttributeJSON(elem)
+		}
+		return map[string]interface{}{"L": list}
+	case *types.AttributeValueMemberM:
+		return map[string]interface{}{"M": RawItemJSON(v.Value)}
+	default:
+		return map[string]interface{}{"unknown": fmt.Sprintf("%T", av)}
+	}
+}
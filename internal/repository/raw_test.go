@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestRawItemJSON(t *testing.T) {
+	item := map[string]types.AttributeValue{
+		"id":     &types.AttributeValueMemberS{Value: "item-1"},
+		"count":  &types.AttributeValueMemberN{Value: "3"},
+		"active": &types.AttributeValueMemberBOOL{Value: true},
+		"tags":   &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "a"}}},
+		"meta":   &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"k": &types.AttributeValueMemberNULL{Value: true}}},
+	}
+
+	data, err := json.Marshal(RawItemJSON(item))
+	if err != nil {
+		t.Fatalf("Failed to marshal raw item: %v", err)
+	}
+
+	expected := `{"active":{"BOOL":true},"count":{"N":"3"},"id":{"S":"item-1"},"meta":{"M":{"k":{"NULL":true}}},"tags":{"L":[{"S":"a"}]}}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
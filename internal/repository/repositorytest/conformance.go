@@ -0,0 +1,156 @@
+// Package repositorytest provides a conformance suite that every
+// repository.ItemRepository implementation is expected to pass.
+package repositorytest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+// RunRepositoryConformanceTests runs the same create, get, list, update,
+// delete, not-found and conflict assertions against the repositories
+// newRepo returns, so the in-memory repository and DynamoDB are held to the
+// same behaviour. Each subtest gets its own repository from newRepo and
+// deletes the items it created when it ends, so implementations backed by
+// a shared table start every subtest empty.
+//
+// The suite expects hard deletes: run it with SOFT_DELETE unset.
+func RunRepositoryConformanceTests(t *testing.T, newRepo func() repository.ItemRepository) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo()
+		item := create(t, repo, "Conformance item", "Created by the conformance suite")
+
+		got, err := repo.GetItem(context.Background(), item.ID, nil)
+		if err != nil {
+			t.Fatalf("GetItem() error = %v", err)
+		}
+		if got.ID != item.ID || got.Name != item.Name || got.Description != item.Description {
+			t.Errorf("GetItem() = %+v, want %+v", got, item)
+		}
+		if got.Status != item.Status {
+			t.Errorf("GetItem() status = %q, want %q", got.Status, item.Status)
+		}
+	})
+
+	t.Run("GetNotFound", func(t *testing.T) {
+		repo := newRepo()
+		_, err := repo.GetItem(context.Background(), uuid.New().String(), nil)
+		if !repository.IsNotFoundError(err) {
+			t.Errorf("GetItem() error = %v, want not found", err)
+		}
+	})
+
+	t.Run("CreateConflict", func(t *testing.T) {
+		repo := newRepo()
+		item := create(t, repo, "Original", "The first item with this ID")
+
+		duplicate := models.NewItem("Duplicate", "A second item with the same ID")
+		duplicate.ID = item.ID
+		err := repo.CreateItem(context.Background(), duplicate)
+		if !repository.IsConflictError(err) {
+			t.Fatalf("CreateItem() error = %v, want conflict", err)
+		}
+
+		got, err := repo.GetItem(context.Background(), item.ID, nil)
+		if err != nil {
+			t.Fatalf("GetItem() error = %v", err)
+		}
+		if got.Name != "Original" {
+			t.Errorf("GetItem() name = %q, want the original item kept", got.Name)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		repo := newRepo()
+		var want []string
+		for _, name := range []string{"First", "Second", "Third"} {
+			want = append(want, create(t, repo, name, "Listed by the conformance suite").ID)
+		}
+
+		result, err := repo.ListItems(context.Background(), &repository.ListItemsOptions{Limit: 50})
+		if err != nil {
+			t.Fatalf("ListItems() error = %v", err)
+		}
+		var got []string
+		for _, item := range result.Items {
+			got = append(got, item.ID)
+		}
+		for _, id := range want {
+			if !slices.Contains(got, id) {
+				t.Errorf("ListItems() = %v, missing %s", got, id)
+			}
+		}
+	})
+
+	t.Run("UpdateKeepsOmittedFields", func(t *testing.T) {
+		repo := newRepo()
+		item := create(t, repo, "Before", "Kept across the update")
+
+		updated, err := repo.UpdateItem(context.Background(), item.ID, &models.UpdateItemRequest{Name: "After"})
+		if err != nil {
+			t.Fatalf("UpdateItem() error = %v", err)
+		}
+		if updated.Name != "After" || updated.Description != item.Description {
+			t.Errorf("UpdateItem() = %+v, want name changed and description kept", updated)
+		}
+
+		got, err := repo.GetItem(context.Background(), item.ID, nil)
+		if err != nil {
+			t.Fatalf("GetItem() error = %v", err)
+		}
+		if got.Name != "After" || got.Description != item.Description {
+			t.Errorf("GetItem() after update = %+v, want name changed and description kept", got)
+		}
+	})
+
+	t.Run("UpdateNotFound", func(t *testing.T) {
+		repo := newRepo()
+		_, err := repo.UpdateItem(context.Background(), uuid.New().String(), &models.UpdateItemRequest{Name: "Missing"})
+		if !repository.IsNotFoundError(err) {
+			t.Errorf("UpdateItem() error = %v, want not found", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo()
+		item := create(t, repo, "Doomed", "Deleted by the conformance suite")
+
+		if err := repo.DeleteItem(context.Background(), item.ID, nil); err != nil {
+			t.Fatalf("DeleteItem() error = %v", err)
+		}
+		_, err := repo.GetItem(context.Background(), item.ID, nil)
+		if !repository.IsNotFoundError(err) {
+			t.Errorf("GetItem() after delete error = %v, want not found", err)
+		}
+	})
+
+	t.Run("DeleteNotFound", func(t *testing.T) {
+		repo := newRepo()
+		err := repo.DeleteItem(context.Background(), uuid.New().String(), nil)
+		if !repository.IsNotFoundError(err) {
+			t.Errorf("DeleteItem() error = %v, want not found", err)
+		}
+	})
+}
+
+// create creates an item in repo and deletes it again when the test ends
+func create(t *testing.T, repo repository.ItemRepository, name, description string) *models.Item {
+	t.Helper()
+	item := models.NewItem(name, description)
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("CreateItem() error = %v", err)
+	}
+	t.Cleanup(func() {
+		err := repo.DeleteItem(context.Background(), item.ID, nil)
+		if err != nil && !repository.IsNotFoundError(err) {
+			t.Errorf("cleanup: DeleteItem(%s) error = %v", item.ID, err)
+		}
+	})
+	return item
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DefaultMaxRetries is how many times a throttled or failed DynamoDB call is
+// retried when DYNAMODB_MAX_RETRIES is not set
+const DefaultMaxRetries = 3
+
+// Retry backoff bounds
+const (
+	retryBaseDelay = 25 * time.Millisecond
+	retryMaxDelay  = time.Second
+)
+
+// withRetry calls op, retrying errors IsRetryableError accepts up to
+// maxRetries times with exponential backoff and full jitter. Other errors
+// are returned at once, as is the last error when the context is done, its
+// deadline falls before the next attempt or its retry budget is spent.
+//
+// A retried write may already have been applied, so a conditional write can
+// fail on its retry because of its own first attempt; writtenByAttempt
+// tells such failures apart.
+func withRetry[T any](ctx context.Context, maxRetries int, op func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := op()
+		if err == nil || attempt >= maxRetries || !IsRetryableError(err) {
+			return result, err
+		}
+
+		delay := time.Duration(rand.Int64N(int64(min(retryMaxDelay, retryBaseDelay<<attempt)) + 1))
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return result, err
+		}
+		if !RetryBudgetFromContext(ctx).take() {
+			return result, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		}
+	}
+}
+
+// writtenByAttempt reports whether err is a failed put condition whose
+// stored item, returned through ReturnValuesOnConditionCheckFailure, is
+// exactly the item being written: an earlier attempt of the same put
+// succeeded even though it reported an error.
+func writtenByAttempt(err error, item map[string]types.AttributeValue) bool {
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	return errors.As(err, &conditionalCheckFailed) &&
+		conditionalCheckFailed.Item != nil &&
+		reflect.DeepEqual(conditionalCheckFailed.Item, item)
+}
+
+// deletedByAttempt reports whether err is a failed delete condition that
+// found no stored item: an earlier attempt of the same delete succeeded
+// even though it reported an error. A delete that fails its status, owner
+// or generation condition returns the stored item instead. A concurrent
+// delete between attempts looks the same and is also reported as success.
+func deletedByAttempt(err error) bool {
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	return errors.As(err, &conditionalCheckFailed) && conditionalCheckFailed.Item == nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// flakyPutMock fails the first failures puts with err, then succeeds
+func flakyPutMock(failures int, err error) (*mockDynamoDBClient, *int) {
+	calls := 0
+	return &mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			calls++
+			if calls <= failures {
+				return nil, err
+			}
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}, &calls
+}
+
+func TestCreateItem_RetriesThrottledWrites(t *testing.T) {
+	client, calls := flakyPutMock(2, &types.ProvisionedThroughputExceededException{})
+	repo := NewDynamoDBRepository(client, "items")
+
+	if err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description")); err != nil {
+		t.Fatalf("Expected create to succeed after retries, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *calls)
+	}
+}
+
+func TestGetItem_RetriesInternalErrors(t *testing.T) {
+	calls := 0
+	client := &mockDynamoDBClient{
+		GetItemFn: func(ctx context.Context, params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			calls++
+			if calls <= 2 {
+				return nil, &types.InternalServerError{}
+			}
+			return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+				"id":   params.Key["id"],
+				"name": &types.AttributeValueMemberS{Value: "Item"},
+			}}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	item, err := repo.GetItem(context.Background(), "item-1", nil)
+	if err != nil {
+		t.Fatalf("Expected get to succeed after retries, got %v", err)
+	}
+	if item.Name != "Item" || calls != 3 {
+		t.Errorf("Expected the item after 3 attempts, got %+v after %d", item, calls)
+	}
+}
+
+func TestCreateItem_NonRetryableErrorFailsFast(t *testing.T) {
+	client, calls := flakyPutMock(5, &types.ConditionalCheckFailedException{})
+	repo := NewDynamoDBRepository(client, "items")
+
+	err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description"))
+	if !errors.Is(err, ErrItemAlreadyExists) {
+		t.Fatalf("Expected ErrItemAlreadyExists, got %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", *calls)
+	}
+}
+
+func TestCreateItem_RetriesCapped(t *testing.T) {
+	client, calls := flakyPutMock(10, &types.ProvisionedThroughputExceededException{})
+	repo := NewDynamoDBRepository(client, "items")
+	repo.maxRetries = 2
+
+	err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description"))
+	if !errors.Is(err, ErrOperationFailed) {
+		t.Fatalf("Expected ErrOperationFailed, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 1 attempt and 2 retries, got %d attempts", *calls)
+	}
+}
+
+func TestWithRetry_StopsAtContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	_, err := withRetry(ctx, 100, func() (struct{}, error) {
+		calls++
+		return struct{}{}, &types.InternalServerError{}
+	})
+	if err == nil {
+		t.Fatal("Expected the last error once the deadline is reached")
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected retries to stop at the deadline, took %s over %d attempts", elapsed, calls)
+	}
+}
+
+// appliedThenFailedPutMock stores each put, failing the first with an
+// internal error after storing it, and evaluates attribute_not_exists(id)
+// like DynamoDB, returning the stored item when the condition fails
+func appliedThenFailedPutMock(stored map[string]map[string]types.AttributeValue) *mockDynamoDBClient {
+	calls := 0
+	return &mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			calls++
+			id := params.Item["id"].(*types.AttributeValueMemberS).Value
+			if old, exists := stored[id]; exists {
+				failed := &types.ConditionalCheckFailedException{}
+				if params.ReturnValuesOnConditionCheckFailure == types.ReturnValuesOnConditionCheckFailureAllOld {
+					failed.Item = old
+				}
+				return nil, failed
+			}
+			stored[id] = params.Item
+			if calls == 1 {
+				return nil, &types.InternalServerError{}
+			}
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+}
+
+func TestCreateItem_RetryOfAppliedWriteSucceeds(t *testing.T) {
+	stored := map[string]map[string]types.AttributeValue{}
+	repo := NewDynamoDBRepository(appliedThenFailedPutMock(stored), "items")
+
+	if err := repo.CreateItem(context.Background(), models.NewItem("Item", "Description")); err != nil {
+		t.Errorf("Expected the retry to recognize its own write, got %v", err)
+	}
+
+	// A different item under the ID is still a duplicate
+	item := models.NewItem("Item", "Description")
+	for id := range stored {
+		item.ID = id
+	}
+	if err := repo.CreateItem(context.Background(), item); !errors.Is(err, ErrItemAlreadyExists) {
+		t.Errorf("Expected ErrItemAlreadyExists, got %v", err)
+	}
+}
+
+func TestDeleteItem_RetryOfAppliedDeleteSucceeds(t *testing.T) {
+	stored := map[string]bool{"item-1": true}
+	calls := 0
+	repo := NewDynamoDBRepository(&mockDynamoDBClient{
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			calls++
+			id := params.Key["id"].(*types.AttributeValueMemberS).Value
+			if !stored[id] {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			delete(stored, id)
+			if calls == 1 {
+				return nil, &types.InternalServerError{}
+			}
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}, "items")
+
+	if err := repo.DeleteItem(context.Background(), "item-1", nil); err != nil {
+		t.Errorf("Expected the retry to recognize its own delete, got %v", err)
+	}
+
+	// A delete that finds nothing on its first attempt is still not found
+	if err := repo.DeleteItem(context.Background(), "item-1", nil); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expected ErrItemNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ErrRetryBudgetExhausted is returned by the SDK retryer when the request's
+// retry budget has run out; it is joined with the error being retried
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudget bounds the retries made while serving one request. The SDK's
+// retryer, withRetry and the batch unprocessed-item loops each take from
+// the budget in the request context before retrying, so retries stacked
+// across those layers can't multiply beyond it.
+//
+// A nil budget is unlimited, so code paths without one behave as before.
+type RetryBudget struct {
+	limit     int64
+	remaining atomic.Int64
+}
+
+// NewRetryBudget creates a budget allowing n retries
+func NewRetryBudget(n int) *RetryBudget {
+	b := &RetryBudget{limit: int64(n)}
+	b.remaining.Store(int64(n))
+	return b
+}
+
+// take uses one retry, reporting false when none are left
+func (b *RetryBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	for {
+		remaining := b.remaining.Load()
+		if remaining <= 0 {
+			return false
+		}
+		if b.remaining.CompareAndSwap(remaining, remaining-1) {
+			return true
+		}
+	}
+}
+
+// Remaining returns how many retries are left
+func (b *RetryBudget) Remaining() int {
+	return int(b.remaining.Load())
+}
+
+// Used returns how many retries have been made
+func (b *RetryBudget) Used() int {
+	return int(b.limit - b.remaining.Load())
+}
+
+type retryBudgetKey struct{}
+
+// WithRetryBudget returns a context whose retries draw from budget
+func WithRetryBudget(ctx context.Context, budget *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, budget)
+}
+
+// RetryBudgetFromContext returns the context's retry budget, or nil when
+// retries are unbounded
+func RetryBudgetFromContext(ctx context.Context) *RetryBudget {
+	budget, _ := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return budget
+}
+
+// BudgetRetryer wraps an SDK retryer so its retries also draw from the
+// request's retry budget
+type BudgetRetryer struct {
+	aws.Retryer
+}
+
+// NewBudgetRetryer wraps retryer with the request retry budget
+func NewBudgetRetryer(retryer aws.Retryer) *BudgetRetryer {
+	return &BudgetRetryer{Retryer: retryer}
+}
+
+// GetAttemptToken delegates to the wrapped retryer
+func (r *BudgetRetryer) GetAttemptToken(ctx context.Context) (func(error) error, error) {
+	if v2, ok := r.Retryer.(aws.RetryerV2); ok {
+		return v2.GetAttemptToken(ctx)
+	}
+	return r.Retryer.GetInitialToken(), nil
+}
+
+// GetRetryToken takes a retry from the request's budget before asking the
+// wrapped retryer, refusing the retry once the budget is spent
+func (r *BudgetRetryer) GetRetryToken(ctx context.Context, opErr error) (func(error) error, error) {
+	if !RetryBudgetFromContext(ctx).take() {
+		return nil, ErrRetryBudgetExhausted
+	}
+	return r.Retryer.GetRetryToken(ctx, opErr)
+}
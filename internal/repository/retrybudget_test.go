@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// throttlingHTTPClient answers every DynamoDB call with a throttling error
+type throttlingHTTPClient struct {
+	calls atomic.Int32
+}
+
+func (c *throttlingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls.Add(1)
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": {"application/x-amz-json-1.0"}},
+		Body: io.NopCloser(strings.NewReader(
+			`{"__type":"com.amazonaws.dynamodb.v20120810#ProvisionedThroughputExceededException","message":"Rate exceeded"}`)),
+		Request: req,
+	}, nil
+}
+
+// newThrottledSDKClient returns a real DynamoDB client whose SDK retryer
+// makes up to sdkRetries retries without delay and draws from the budget
+func newThrottledSDKClient(httpClient *throttlingHTTPClient, sdkRetries int) *dynamodb.Client {
+	return dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String("http://dynamodb.test"),
+		Credentials:  aws.AnonymousCredentials{},
+		HTTPClient:   httpClient,
+		Retryer: NewBudgetRetryer(retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = sdkRetries + 1
+			o.RateLimiter = ratelimit.None
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+		})),
+	})
+}
+
+func TestRetryBudget_SharedAcrossStackedLayers(t *testing.T) {
+	httpClient := &throttlingHTTPClient{}
+	repo := NewDynamoDBRepository(newThrottledSDKClient(httpClient, 2), "items")
+
+	// Unbudgeted, 4 repository attempts of 3 SDK attempts each would make 12 calls
+	budget := NewRetryBudget(5)
+	ctx := WithRetryBudget(context.Background(), budget)
+
+	_, err := repo.GetItem(ctx, "item-1", nil)
+	if !IsOperationError(err) {
+		t.Fatalf("Expected the throttling error, got %v", err)
+	}
+
+	if calls := httpClient.calls.Load(); calls != 6 {
+		t.Errorf("Expected 1 call plus 5 budgeted retries, got %d calls", calls)
+	}
+	if budget.Remaining() != 0 || budget.Used() != 5 {
+		t.Errorf("Expected the budget to be spent, got %d used and %d remaining", budget.Used(), budget.Remaining())
+	}
+
+	// Later calls in the same request get no retries at all
+	if _, err := repo.GetItem(ctx, "item-2", nil); err == nil {
+		t.Fatal("Expected the throttling error")
+	}
+	if calls := httpClient.calls.Load(); calls != 7 {
+		t.Errorf("Expected a single call once the budget is spent, got %d calls", calls-6)
+	}
+}
+
+func TestRetryBudget_UnboundedWithoutBudget(t *testing.T) {
+	httpClient := &throttlingHTTPClient{}
+	repo := NewDynamoDBRepository(newThrottledSDKClient(httpClient, 2), "items")
+	repo.maxRetries = 1
+
+	if _, err := repo.GetItem(context.Background(), "item-1", nil); err == nil {
+		t.Fatal("Expected the throttling error")
+	}
+
+	if calls := httpClient.calls.Load(); calls != 6 {
+		t.Errorf("Expected 2 repository attempts of 3 SDK attempts, got %d calls", calls)
+	}
+}
+
+func TestRetryBudget_BoundsUnprocessedBatchRetries(t *testing.T) {
+	calls := 0
+	client := &mockDynamoDBClient{
+		BatchWriteFn: func(ctx context.Context, params *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+			calls++
+			return &dynamodb.BatchWriteItemOutput{UnprocessedItems: params.RequestItems}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	budget := NewRetryBudget(2)
+	ctx := WithRetryBudget(context.Background(), budget)
+
+	unprocessed, err := repo.batchWrite(ctx, []types.WriteRequest{{
+		DeleteRequest: &types.DeleteRequest{Key: repo.attrNames.key("item-1")},
+	}})
+	if err != nil {
+		t.Fatalf("Expected unprocessed requests to be returned, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 1 call plus 2 budgeted retries, got %d calls", calls)
+	}
+	if len(unprocessed) != 1 {
+		t.Errorf("Expected the request to stay unprocessed, got %d", len(unprocessed))
+	}
+}
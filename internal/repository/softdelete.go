@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// softDeleteFromEnv reports whether SOFT_DELETE enables soft deletes
+func softDeleteFromEnv() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("SOFT_DELETE"))
+	return enabled
+}
+
+// storedDeleted reports whether a stored item, such as the one a failed
+// condition check returns, is soft-deleted
+func (r *DynamoDBRepository) storedDeleted(av map[string]types.AttributeValue) bool {
+	_, ok := av[r.attrNames.Storage("deleted_at")]
+	return ok
+}
+
+// softDeleteItem marks an item deleted, keeping its status for a restore.
+// The item keeps its slot under the item cap until it is compacted.
+func (r *DynamoDBRepository) softDeleteItem(ctx context.Context, id string, options *DeleteItemOptions) error {
+	if isMetaItemID(id) {
+		return ErrItemNotFound
+	}
+
+	now, err := attributevalue.Marshal(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to marshal timestamp: %w", err)
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       r.attrNames.key(id),
+		UpdateExpression: aws.String("SET #deleted_at = :now, #updated_at = :now, #previous_status = #status, #status = :deleted, " +
+			"#generation = if_not_exists(#generation, :zero) + :one"),
+		ConditionExpression:      aws.String("attribute_exists(#id) AND attribute_not_exists(#deleted_at)"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id", "deleted_at", "updated_at", "previous_status", "status", "generation"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":     now,
+			":deleted": &types.AttributeValueMemberS{Value: models.StatusDeleted},
+			":zero":    &types.AttributeValueMemberN{Value: "0"},
+			":one":     &types.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+	if options != nil && options.RequireStatus != "" {
+		*input.ConditionExpression += " AND #status = :required_status"
+		input.ExpressionAttributeValues[":required_status"] = &types.AttributeValueMemberS{Value: options.RequireStatus}
+	}
+	if options != nil && options.RequireOwner != "" {
+		*input.ConditionExpression += " AND #created_by = :owner"
+		input.ExpressionAttributeNames["#created_by"] = r.attrNames.Storage("created_by")
+		input.ExpressionAttributeValues[":owner"] = &types.AttributeValueMemberS{Value: options.RequireOwner}
+	}
+	if options != nil && options.Generation != nil {
+		*input.ConditionExpression += " AND " + r.generationCondition(*options.Generation, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	}
+
+	_, err = withRetry(ctx, r.maxRetries, func() (*dynamodb.UpdateItemOutput, error) {
+		return r.client.UpdateItem(ctx, input)
+	})
+	if err != nil {
+		// An item that is already deleted looks missing, as it does to reads
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) && conditionalCheckFailed.Item != nil && !r.storedDeleted(conditionalCheckFailed.Item) {
+			if options != nil && options.RequireOwner != "" && r.storedOwner(conditionalCheckFailed.Item) != options.RequireOwner {
+				return fmt.Errorf("%w: %s", ErrNotOwner, id)
+			}
+			if options != nil && options.Generation != nil && r.storedGeneration(conditionalCheckFailed.Item) != *options.Generation {
+				return fmt.Errorf("%w: expected generation %d", ErrStaleGeneration, *options.Generation)
+			}
+			if options != nil && options.RequireStatus != "" {
+				return fmt.Errorf("%w: item status is not %q", ErrPreconditionFailed, options.RequireStatus)
+			}
+		}
+		return HandleDynamoDBError(err)
+	}
+	return nil
+}
+
+// RestoreItem undoes a soft delete, returning the item to the status it had
+// before. Restoring an item that isn't deleted fails with
+// ErrPreconditionFailed. When requireOwner is set, only that principal's
+// items can be restored.
+func (r *DynamoDBRepository) RestoreItem(ctx context.Context, id string, requireOwner string) (*models.Item, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if isMetaItemID(id) {
+		return nil, ErrItemNotFound
+	}
+
+	now, err := attributevalue.Marshal(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal timestamp: %w", err)
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       r.attrNames.key(id),
+		UpdateExpression: aws.String("SET #status = if_not_exists(#previous_status, :active), #updated_at = :now, " +
+			"#generation = if_not_exists(#generation, :zero) + :one REMOVE #deleted_at, #previous_status"),
+		ConditionExpression:      aws.String("attribute_exists(#id) AND attribute_exists(#deleted_at)"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id", "deleted_at", "updated_at", "previous_status", "status", "generation"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":    now,
+			":active": &types.AttributeValueMemberS{Value: "active"},
+			":zero":   &types.AttributeValueMemberN{Value: "0"},
+			":one":    &types.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValues:                        types.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+	if requireOwner != "" {
+		*input.ConditionExpression += " AND #created_by = :owner"
+		input.ExpressionAttributeNames["#created_by"] = r.attrNames.Storage("created_by")
+		input.ExpressionAttributeValues[":owner"] = &types.AttributeValueMemberS{Value: requireOwner}
+	}
+
+	result, err := withRetry(ctx, r.maxRetries, func() (*dynamodb.UpdateItemOutput, error) {
+		return r.client.UpdateItem(ctx, input)
+	})
+	if err != nil {
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) && conditionalCheckFailed.Item != nil {
+			if requireOwner != "" && r.storedOwner(conditionalCheckFailed.Item) != requireOwner {
+				return nil, fmt.Errorf("%w: %s", ErrNotOwner, id)
+			}
+			return nil, fmt.Errorf("%w: item %s is not deleted", ErrPreconditionFailed, id)
+		}
+		return nil, HandleDynamoDBError(err)
+	}
+
+	var item models.Item
+	if err := attributevalue.UnmarshalMap(r.attrNames.fromStorage(result.Attributes), &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal restored item: %w", err)
+	}
+	r.touchCollection(ctx)
+	return &item, nil
+}
+
+// PurgeItem permanently removes a soft-deleted item. Purging an item that
+// isn't deleted fails with ErrPreconditionFailed, so live items can't be
+// removed this way.
+func (r *DynamoDBRepository) PurgeItem(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if isMetaItemID(id) {
+		return ErrItemNotFound
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		TableName:                           aws.String(r.tableName),
+		Key:                                 r.attrNames.key(id),
+		ConditionExpression:                 aws.String("attribute_exists(#id) AND attribute_exists(#deleted_at)"),
+		ExpressionAttributeNames:            r.attrNames.placeholders("id", "deleted_at"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+
+	_, err := withRetry(ctx, r.maxRetries, func() (*dynamodb.DeleteItemOutput, error) {
+		return r.client.DeleteItem(ctx, input)
+	})
+	if err != nil {
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) && conditionalCheckFailed.Item != nil {
+			return fmt.Errorf("%w: item %s is not deleted", ErrPreconditionFailed, id)
+		}
+		return HandleDynamoDBError(err)
+	}
+
+	if r.maxItems > 0 {
+		r.releaseItemSlot(ctx)
+	}
+
+	return nil
+}
+
+// RestoreItem undoes a soft delete, as for the DynamoDB repository
+func (r *MemoryRepository) RestoreItem(ctx context.Context, id string, requireOwner string) (*models.Item, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, ErrItemNotFound
+	}
+	if requireOwner != "" && item.CreatedBy != requireOwner {
+		return nil, fmt.Errorf("%w: %s", ErrNotOwner, id)
+	}
+	if !item.IsDeleted() {
+		return nil, fmt.Errorf("%w: item %s is not deleted", ErrPreconditionFailed, id)
+	}
+	item.Restore(time.Now())
+	r.items[id] = item
+
+	return &item, nil
+}
+
+// PurgeItem permanently removes a soft-deleted item, as for the DynamoDB
+// repository
+func (r *MemoryRepository) PurgeItem(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return ErrItemNotFound
+	}
+	if !item.IsDeleted() {
+		return fmt.Errorf("%w: item %s is not deleted", ErrPreconditionFailed, id)
+	}
+	delete(r.items, id)
+
+	return nil
+}
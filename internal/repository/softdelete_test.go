@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestMemorySoftDeleteAndRestore(t *testing.T) {
+	t.Setenv("SOFT_DELETE", "true")
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	item := models.NewItem("Item", "Description")
+	item.Status = "inactive"
+	if err := repo.CreateItem(ctx, item); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	if err := repo.DeleteItem(ctx, item.ID, nil); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+
+	listed, err := repo.ListItems(ctx, nil)
+	if err != nil || len(listed.Items) != 0 {
+		t.Fatalf("Expected the deleted item to be unlisted, got %+v (%v)", listed, err)
+	}
+	listed, err = repo.ListItems(ctx, &ListItemsOptions{IncludeDeleted: true})
+	if err != nil || len(listed.Items) != 1 || listed.Items[0].Status != models.StatusDeleted {
+		t.Fatalf("Expected the deleted item with include deleted, got %+v (%v)", listed, err)
+	}
+	if err := repo.DeleteItem(ctx, item.ID, nil); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expected deleting twice to be not found, got %v", err)
+	}
+	if _, err := repo.UpdateItem(ctx, item.ID, &models.UpdateItemRequest{Name: "Renamed"}); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expected updating a deleted item to be not found, got %v", err)
+	}
+
+	restored, err := repo.RestoreItem(ctx, item.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to restore item: %v", err)
+	}
+	if restored.Status != "inactive" || restored.IsDeleted() {
+		t.Errorf("Expected the item restored to inactive, got status %q deleted %v", restored.Status, restored.DeletedAt)
+	}
+	listed, err = repo.ListItems(ctx, nil)
+	if err != nil || len(listed.Items) != 1 {
+		t.Fatalf("Expected the restored item to be listed, got %+v (%v)", listed, err)
+	}
+	if _, err := repo.RestoreItem(ctx, item.ID, ""); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected restoring a live item to fail its precondition, got %v", err)
+	}
+}
+
+func TestDeleteItem_SoftDeleteUpdatesInPlace(t *testing.T) {
+	t.Setenv("SOFT_DELETE", "true")
+	var captured *dynamodb.UpdateItemInput
+	client := &mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			captured = params
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			t.Error("Expected a soft delete not to remove the item")
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	if err := repo.DeleteItem(context.Background(), "item-1", &DeleteItemOptions{RequireStatus: "inactive"}); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+	if captured == nil {
+		t.Fatal("Expected an update")
+	}
+	update := aws.ToString(captured.UpdateExpression)
+	for _, want := range []string{"#deleted_at = :now", "#previous_status = #status", "#status = :deleted"} {
+		if !strings.Contains(update, want) {
+			t.Errorf("Expected update expression to contain %q, got %q", want, update)
+		}
+	}
+	condition := aws.ToString(captured.ConditionExpression)
+	if !strings.Contains(condition, "attribute_not_exists(#deleted_at)") || !strings.Contains(condition, "#status = :required_status") {
+		t.Errorf("Expected the condition to require a live item with the status, got %q", condition)
+	}
+}
+
+func TestRestoreItem_ConditionFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      map[string]types.AttributeValue
+		expected error
+	}{
+		{name: "Missing", old: nil, expected: ErrItemNotFound},
+		{name: "Not deleted", old: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "item-1"}}, expected: ErrPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockDynamoDBClient{
+				UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+					if !strings.Contains(aws.ToString(params.ConditionExpression), "attribute_exists(#deleted_at)") {
+						t.Errorf("Expected the condition to require a deleted item, got %q", aws.ToString(params.ConditionExpression))
+					}
+					return nil, &types.ConditionalCheckFailedException{Message: aws.String("failed"), Item: tt.old}
+				},
+			}
+			repo := NewDynamoDBRepository(client, "items")
+
+			if _, err := repo.RestoreItem(context.Background(), "item-1", ""); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestPurgeItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      map[string]types.AttributeValue
+		expected error
+	}{
+		{name: "Soft-deleted", expected: nil},
+		{name: "Live", old: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "item-1"}}, expected: ErrPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockDynamoDBClient{
+				DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+					if got := aws.ToString(params.ConditionExpression); got != "attribute_exists(#id) AND attribute_exists(#deleted_at)" {
+						t.Errorf("Expected the condition to require a deleted item, got %q", got)
+					}
+					if tt.old != nil {
+						return nil, &types.ConditionalCheckFailedException{Message: aws.String("failed"), Item: tt.old}
+					}
+					return &dynamodb.DeleteItemOutput{}, nil
+				},
+			}
+			repo := NewDynamoDBRepository(client, "items")
+
+			if err := repo.PurgeItem(context.Background(), "item-1"); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// newStatusIndexMock reports both listing GSIs and captures queries
+func newStatusIndexMock(queries *[]*dynamodb.QueryInput) *mockDynamoDBClient {
+	return &mockDynamoDBClient{
+		DescribeTableFn: func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+			return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+				GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+					{IndexName: aws.String(DefaultListIndexName)},
+					{IndexName: aws.String(DefaultStatusIndexName)},
+				},
+			}}, nil
+		},
+		QueryFn: func(ctx context.Context, params *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+			*queries = append(*queries, params)
+			return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+				{"id": &types.AttributeValueMemberS{Value: "item-1"}, "status": &types.AttributeValueMemberS{Value: "inactive"}},
+			}}, nil
+		},
+		ScanFn: func(ctx context.Context, params *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+			panic("status listings should not scan")
+		},
+	}
+}
+
+func TestListItems_StatusFilterQueriesStatusIndex(t *testing.T) {
+	var queries []*dynamodb.QueryInput
+	repo := NewDynamoDBRepository(newStatusIndexMock(&queries), "items")
+	repo.listIndexName = DefaultListIndexName
+	repo.statusIndexName = DefaultStatusIndexName
+
+	result, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 10, StatusFilter: "inactive"})
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].Status != "inactive" {
+		t.Errorf("Expected the queried item, got %+v", result.Items)
+	}
+
+	if len(queries) != 1 {
+		t.Fatalf("Expected 1 query, got %d", len(queries))
+	}
+	query := queries[0]
+	if aws.ToString(query.IndexName) != DefaultStatusIndexName {
+		t.Errorf("Expected index %s, got %s", DefaultStatusIndexName, aws.ToString(query.IndexName))
+	}
+	if got := aws.ToString(query.KeyConditionExpression); got != "#status = :status" {
+		t.Errorf("Expected key condition '#status = :status', got %q", got)
+	}
+	if query.ExpressionAttributeNames["#status"] != "status" {
+		t.Errorf("Expected #status to name the status attribute, got %v", query.ExpressionAttributeNames)
+	}
+	if value, ok := query.ExpressionAttributeValues[":status"].(*types.AttributeValueMemberS); !ok || value.Value != "inactive" {
+		t.Errorf("Expected :status to be 'inactive', got %v", query.ExpressionAttributeValues[":status"])
+	}
+	if got := aws.ToString(query.FilterExpression); got != "attribute_not_exists(#deleted_at)" {
+		t.Errorf("Expected only soft-deleted items filtered, got %q", got)
+	}
+	if !aws.ToBool(query.ScanIndexForward) || aws.ToInt32(query.Limit) != 10 {
+		t.Errorf("Expected an ascending query limited to 10, got forward=%v limit=%d", aws.ToBool(query.ScanIndexForward), aws.ToInt32(query.Limit))
+	}
+}
+
+func TestQueryItemsByStatus_UsesMappedStatusAttribute(t *testing.T) {
+	var queries []*dynamodb.QueryInput
+	repo := NewDynamoDBRepository(newStatusIndexMock(&queries), "items")
+	repo.statusIndexName = DefaultStatusIndexName
+	repo.attrNames = AttributeNames{"status": "item_state"}
+
+	if _, err := repo.QueryItemsByStatus(context.Background(), "active", &ListItemsOptions{Limit: 5}); err != nil {
+		t.Fatalf("Failed to query items: %v", err)
+	}
+
+	if got := queries[0].ExpressionAttributeNames["#status"]; got != "item_state" {
+		t.Errorf("Expected #status to map to 'item_state', got %q", got)
+	}
+}
+
+func TestListItems_UnfilteredSkipsStatusIndex(t *testing.T) {
+	var queries []*dynamodb.QueryInput
+	repo := NewDynamoDBRepository(newStatusIndexMock(&queries), "items")
+	repo.listIndexName = DefaultListIndexName
+	repo.statusIndexName = DefaultStatusIndexName
+
+	if _, err := repo.ListItems(context.Background(), &ListItemsOptions{Limit: 10}); err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+
+	if len(queries) != 1 || aws.ToString(queries[0].IndexName) != DefaultListIndexName {
+		t.Errorf("Expected one query on the listing index, got %d", len(queries))
+	}
+}
+
+func TestListItems_DescendingQueriesIndexBackwards(t *testing.T) {
+	var queries []*dynamodb.QueryInput
+	repo := NewDynamoDBRepository(newStatusIndexMock(&queries), "items")
+	repo.listIndexName = DefaultListIndexName
+	repo.statusIndexName = DefaultStatusIndexName
+
+	for _, options := range []*ListItemsOptions{
+		{Limit: 10, StatusFilter: "inactive", Descending: true},
+		{Limit: 10, Descending: true},
+		{Limit: 10},
+	} {
+		if _, err := repo.ListItems(context.Background(), options); err != nil {
+			t.Fatalf("Failed to list items: %v", err)
+		}
+	}
+
+	if len(queries) != 3 {
+		t.Fatalf("Expected 3 queries, got %d", len(queries))
+	}
+	for i, forward := range []bool{false, false, true} {
+		if got := aws.ToBool(queries[i].ScanIndexForward); got != forward {
+			t.Errorf("Expected query %d on %s to have ScanIndexForward %t, got %t", i, aws.ToString(queries[i].IndexName), forward, got)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TableHealth is the result of probing one table
+type TableHealth struct {
+	Table     string `json:"table"`
+	Healthy   bool   `json:"healthy"`
+	Message   string `json:"message"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// TableChecker is implemented by repositories that can probe tables other
+// than their own, such as the tables of other tenants
+type TableChecker interface {
+	CheckTables(ctx context.Context, tables []string, timeout time.Duration) []TableHealth
+}
+
+// CheckTables probes the tables concurrently, each with DescribeTable
+// bounded by timeout, and reports them in the order given. A table is
+// healthy when it exists and serves requests.
+func (r *DynamoDBRepository) CheckTables(ctx context.Context, tables []string, timeout time.Duration) []TableHealth {
+	results := make([]TableHealth, len(tables))
+
+	var wg sync.WaitGroup
+	for i, table := range tables {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.checkTable(ctx, table, timeout)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// checkTable probes a single table
+func (r *DynamoDBRepository) checkTable(ctx context.Context, table string, timeout time.Duration) TableHealth {
+	return ProbeTable(ctx, r.client, table, timeout)
+}
+
+// ProbeTable runs DescribeTable against a table through client, bounded by
+// timeout, and reports whether the table serves requests and how long the
+// call took
+func ProbeTable(ctx context.Context, client DynamoDBAPI, table string, timeout time.Duration) TableHealth {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(table),
+	})
+	health := TableHealth{Table: table, LatencyMs: time.Since(start).Milliseconds()}
+
+	switch {
+	case err != nil:
+		health.Message = fmt.Sprintf("DynamoDB health check failed: %v", err)
+	case result.Table == nil || !tableServing(result.Table.TableStatus):
+		health.Message = "Table is not active"
+		if result.Table != nil {
+			health.Message = fmt.Sprintf("Table is %s", result.Table.TableStatus)
+		}
+	default:
+		health.Healthy = true
+		health.Message = "Connected"
+	}
+	return health
+}
+
+// CheckTables delegates to the wrapped repository, returning nil when it
+// can't probe tables
+func (c *CachingRepository) CheckTables(ctx context.Context, tables []string, timeout time.Duration) []TableHealth {
+	if checker, ok := c.inner.(TableChecker); ok {
+		return checker.CheckTables(ctx, tables, timeout)
+	}
+	return nil
+}
+
+// CheckTables delegates to the wrapped repository, returning nil when it
+// can't probe tables
+func (c *CoalescingRepository) CheckTables(ctx context.Context, tables []string, timeout time.Duration) []TableHealth {
+	if checker, ok := c.ItemRepository.(TableChecker); ok {
+		return checker.CheckTables(ctx, tables, timeout)
+	}
+	return nil
+}
+
+// CheckTables delegates to the primary repository, returning nil when it
+// can't probe tables
+func (d *DualWriteRepository) CheckTables(ctx context.Context, tables []string, timeout time.Duration) []TableHealth {
+	if checker, ok := d.ItemRepository.(TableChecker); ok {
+		return checker.CheckTables(ctx, tables, timeout)
+	}
+	return nil
+}
+
+// tableServing reports whether a table in this status serves requests;
+// tables being updated, e.g. to add an index, still do
+func tableServing(status types.TableStatus) bool {
+	return status == types.TableStatusActive || status == types.TableStatusUpdating
+}
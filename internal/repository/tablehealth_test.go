@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestCheckTables(t *testing.T) {
+	client := &mockDynamoDBClient{
+		DescribeTableFn: func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+			switch aws.ToString(params.TableName) {
+			case "tenant-a", "tenant-d":
+				return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusActive}}, nil
+			case "tenant-b":
+				return nil, &types.ResourceNotFoundException{Message: aws.String("table not found")}
+			case "tenant-c":
+				return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusCreating}}, nil
+			default:
+				<-ctx.Done() // a table that never answers
+				return nil, ctx.Err()
+			}
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	start := time.Now()
+	results := repo.CheckTables(context.Background(), []string{"tenant-a", "tenant-b", "tenant-c", "tenant-slow", "tenant-d"}, 50*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the probes to run concurrently within their timeout, took %v", elapsed)
+	}
+
+	expected := map[string]bool{"tenant-a": true, "tenant-b": false, "tenant-c": false, "tenant-slow": false, "tenant-d": true}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for i, table := range []string{"tenant-a", "tenant-b", "tenant-c", "tenant-slow", "tenant-d"} {
+		if results[i].Table != table {
+			t.Errorf("Expected result %d for %s, got %s", i, table, results[i].Table)
+		}
+		if results[i].Healthy != expected[table] {
+			t.Errorf("Expected %s healthy=%v, got %+v", table, expected[table], results[i])
+		}
+		if !results[i].Healthy && results[i].Message == "" {
+			t.Errorf("Expected a message for unhealthy %s", table)
+		}
+	}
+}
+
+func TestCheckTables_ThroughDecorators(t *testing.T) {
+	client := &mockDynamoDBClient{
+		DescribeTableFn: func(ctx context.Context, params *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+			return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusActive}}, nil
+		},
+	}
+	primary := NewDynamoDBRepository(client, "items")
+
+	// Dual writes wrap the caching and coalescing layers in production
+	for name, repo := range map[string]ItemRepository{
+		"dual write":              NewDualWriteRepository(primary, NewMemoryRepository()),
+		"dual write behind cache": NewCachingRepository(NewDualWriteRepository(primary, NewMemoryRepository()), time.Minute),
+	} {
+		checker, ok := repo.(TableChecker)
+		if !ok {
+			t.Errorf("Expected %s to check tables", name)
+			continue
+		}
+		results := checker.CheckTables(context.Background(), []string{"tenant-a"}, time.Second)
+		if len(results) != 1 || !results[0].Healthy {
+			t.Errorf("Expected %s to probe tenant-a through the primary, got %+v", name, results)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/smithy-go/middleware"
+
+	"fis-playground/internal/tracing"
+)
+
+// traceDynamoDB adds a step to the SDK's middleware stack recording each
+// DynamoDB call, including the SDK's retries of it, as an X-Ray subsegment
+// of the request's segment. Calls made outside a traced request, such as
+// the index lookup at startup, are not recorded.
+func traceDynamoDB(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TraceDynamoDB", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		ctx, subsegment := tracing.StartSubsegment(ctx, "DynamoDB")
+		if subsegment == nil {
+			return next.HandleInitialize(ctx, in)
+		}
+		subsegment.SetNamespace("aws")
+		subsegment.SetAWS("operation", awsmiddleware.GetOperationName(ctx))
+		if table := tracedTableName(in.Parameters); table != "" {
+			subsegment.SetAWS("table_name", table)
+		}
+
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		subsegment.SetError(err)
+		subsegment.Close()
+		return out, metadata, err
+	}), middleware.After)
+}
+
+// tracedTableName returns the table a DynamoDB call targets, or "" for
+// batch calls, which can span tables
+func tracedTableName(params interface{}) string {
+	switch input := params.(type) {
+	case *dynamodb.GetItemInput:
+		return aws.ToString(input.TableName)
+	case *dynamodb.PutItemInput:
+		return aws.ToString(input.TableName)
+	case *dynamodb.UpdateItemInput:
+		return aws.ToString(input.TableName)
+	case *dynamodb.DeleteItemInput:
+		return aws.ToString(input.TableName)
+	case *dynamodb.ScanInput:
+		return aws.ToString(input.TableName)
+	case *dynamodb.QueryInput:
+		return aws.ToString(input.TableName)
+	case *dynamodb.DescribeTableInput:
+		return aws.ToString(input.TableName)
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+
+	"fis-playground/internal/tracing"
+)
+
+// newTracedClient returns a DynamoDB client tracing its calls, backed by a
+// fake endpoint answering every call with status and body
+func newTracedClient(t *testing.T, status int, body string) *dynamodb.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return dynamodb.New(dynamodb.Options{
+		Region:           "us-east-1",
+		BaseEndpoint:     aws.String(srv.URL),
+		Credentials:      aws.AnonymousCredentials{},
+		RetryMaxAttempts: 1,
+		APIOptions:       []func(*middleware.Stack) error{traceDynamoDB},
+	})
+}
+
+// tracedSubsegment is the part of a subsegment document the tests check
+type tracedSubsegment struct {
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	ParentID  string            `json:"parent_id"`
+	Namespace string            `json:"namespace"`
+	Error     bool              `json:"error"`
+	AWS       map[string]string `json:"aws"`
+}
+
+// decodeSubsegments decodes the documents sent to daemon, skipping the
+// request's own segment
+func decodeSubsegments(t *testing.T, daemon *bytes.Buffer) []tracedSubsegment {
+	t.Helper()
+	var subsegments []tracedSubsegment
+	for _, line := range strings.Split(daemon.String(), "\n") {
+		if !strings.Contains(line, `"subsegment"`) {
+			continue
+		}
+		var subsegment tracedSubsegment
+		if err := json.Unmarshal([]byte(line), &subsegment); err != nil {
+			t.Fatalf("Failed to decode %q: %v", line, err)
+		}
+		subsegments = append(subsegments, subsegment)
+	}
+	return subsegments
+}
+
+func TestTraceDynamoDB_RecordsSubsegment(t *testing.T) {
+	var daemon bytes.Buffer
+	segment := tracing.NewTracer("items-api", &daemon).StartSegment("")
+	ctx := tracing.WithSegment(context.Background(), segment)
+	client := newTracedClient(t, http.StatusOK, `{}`)
+
+	_, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("items"),
+		Key:       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "item-1"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+
+	subsegments := decodeSubsegments(t, &daemon)
+	if len(subsegments) != 1 {
+		t.Fatalf("Expected one subsegment, got %d: %s", len(subsegments), daemon.String())
+	}
+	got := subsegments[0]
+	if got.Name != "DynamoDB" || got.Namespace != "aws" || got.ParentID == "" {
+		t.Errorf("Expected a DynamoDB subsegment of the request, got %+v", got)
+	}
+	if got.AWS["operation"] != "GetItem" || got.AWS["table_name"] != "items" {
+		t.Errorf("Expected the operation and table recorded, got %v", got.AWS)
+	}
+}
+
+func TestTraceDynamoDB_RecordsErrors(t *testing.T) {
+	var daemon bytes.Buffer
+	ctx := tracing.WithSegment(context.Background(), tracing.NewTracer("items-api", &daemon).StartSegment(""))
+	client := newTracedClient(t, http.StatusBadRequest, `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`)
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("items"),
+		Item:      map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "item-1"}},
+	})
+	if err == nil {
+		t.Fatal("Expected the put to fail")
+	}
+
+	subsegments := decodeSubsegments(t, &daemon)
+	if len(subsegments) != 1 || !subsegments[0].Error || subsegments[0].AWS["operation"] != "PutItem" {
+		t.Errorf("Expected the failed put recorded as an error, got %+v", subsegments)
+	}
+}
+
+func TestTraceDynamoDB_UntracedRequest(t *testing.T) {
+	client := newTracedClient(t, http.StatusOK, `{}`)
+
+	// Without a segment in the context, calls go through untraced
+	_, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String("items"),
+		Key:       map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "item-1"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+func TestCreateItem_StoresTTLAsEpoch(t *testing.T) {
+	var stored map[string]types.AttributeValue
+	client := &mockDynamoDBClient{
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			stored = params.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBRepository(client, "items")
+
+	ttl := int64(3600)
+	req := &models.CreateItemRequest{Name: "Item", Description: "Description", TTLSeconds: &ttl}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Failed to validate request: %v", err)
+	}
+	item := req.NewItem()
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+
+	epoch, ok := stored["ttl"].(*types.AttributeValueMemberN)
+	if !ok {
+		t.Fatalf("Expected ttl stored as a number, got %T", stored["ttl"])
+	}
+	if epoch.Value != strconv.FormatInt(item.ExpiresAt.Unix(), 10) {
+		t.Errorf("Expected ttl %d, got %s", item.ExpiresAt.Unix(), epoch.Value)
+	}
+	if want := item.CreatedAt.Unix() + ttl; item.ExpiresAt.Unix() != want {
+		t.Errorf("Expected expiry %d seconds after creation, got %d", ttl, item.ExpiresAt.Unix()-item.CreatedAt.Unix())
+	}
+
+	var read models.Item
+	if err := attributevalue.UnmarshalMap(stored, &read); err != nil {
+		t.Fatalf("Failed to unmarshal stored item: %v", err)
+	}
+	if read.ExpiresAt == nil || !read.ExpiresAt.Equal(*item.ExpiresAt) {
+		t.Errorf("Expected expires_at to round-trip, got %v", read.ExpiresAt)
+	}
+}
+
+func TestCreateItem_OmitsTTLWithoutExpiry(t *testing.T) {
+	av, err := attributevalue.MarshalMap(models.NewItem("Item", "Description"))
+	if err != nil {
+		t.Fatalf("Failed to marshal item: %v", err)
+	}
+	if _, ok := av["ttl"]; ok {
+		t.Errorf("Expected no ttl attribute, got %v", av["ttl"])
+	}
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/logging"
+	"fis-playground/internal/models"
+)
+
+// NameUniqueness is the scope within which item names must be unique
+type NameUniqueness string
+
+const (
+	// NameUniquenessNone, the default, lets any number of items share a name
+	NameUniquenessNone NameUniqueness = ""
+	// NameUniquenessGlobal makes names unique across all items
+	NameUniquenessGlobal NameUniqueness = "global"
+	// NameUniquenessCategory makes names unique within a category, so
+	// items in different categories may share a name
+	NameUniquenessCategory NameUniqueness = "category"
+)
+
+// NameUniquenessFromEnv returns the scope configured via NAME_UNIQUENESS:
+// global, category, or unset for none
+func NameUniquenessFromEnv() NameUniqueness {
+	value := os.Getenv("NAME_UNIQUENESS")
+	switch uniqueness := NameUniqueness(value); uniqueness {
+	case NameUniquenessNone, NameUniquenessGlobal, NameUniquenessCategory:
+		return uniqueness
+	}
+	log.Printf("Ignoring invalid NAME_UNIQUENESS %q, names are not unique", value)
+	return NameUniquenessNone
+}
+
+// key returns the uniqueness key of a name in a category, e.g.
+// name#tools#Hammer when names are unique within categories, or "" when
+// names aren't unique. The parts are escaped, so a '#' in them can't make
+// two keys collide.
+func (u NameUniqueness) key(name, category string) string {
+	switch u {
+	case NameUniquenessGlobal:
+		return "name#" + url.QueryEscape(name)
+	case NameUniquenessCategory:
+		return "name#" + url.QueryEscape(category) + "#" + url.QueryEscape(name)
+	}
+	return ""
+}
+
+// takenError reports that another item already has the name
+func (u NameUniqueness) takenError(name, category string) error {
+	if u == NameUniquenessCategory {
+		return fmt.Errorf("%w: an item named %q already exists in category %q", ErrItemAlreadyExists, name, category)
+	}
+	return fmt.Errorf("%w: an item named %q already exists", ErrItemAlreadyExists, name)
+}
+
+// checkName fails when an item other than item, deleted or not, has the
+// same uniqueness key. The caller holds r.mu.
+func (r *MemoryRepository) checkName(item *models.Item) error {
+	key := r.nameUniqueness.key(item.Name, item.Category)
+	if key == "" {
+		return nil
+	}
+	for id, other := range r.items {
+		if id != item.ID && r.nameUniqueness.key(other.Name, other.Category) == key {
+			return r.nameUniqueness.takenError(item.Name, item.Category)
+		}
+	}
+	return nil
+}
+
+// nameClaimGrace is how long a claim is honored even though its item
+// doesn't have the name, covering the time between claiming a name and
+// writing the item
+const nameClaimGrace = time.Minute
+
+// nameClaimID is the key of the record claiming a uniqueness key
+func nameClaimID(key string) string {
+	return metaItemPrefix + key
+}
+
+// claimName claims the item's name with a conditional write on the record
+// for its uniqueness key, failing with ErrItemAlreadyExists when another
+// item holds it. The returned release gives up a claim this call made, for
+// when the item's write then fails.
+//
+// Claims are never removed when items are deleted or renamed. Instead, a
+// claim whose item no longer has the name is stale, and is taken over once
+// it is older than nameClaimGrace.
+func (r *DynamoDBRepository) claimName(ctx context.Context, itemID, name, category string) (release func(), err error) {
+	key := r.nameUniqueness.key(name, category)
+	if key == "" {
+		return func() {}, nil
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName:                aws.String(r.tableName),
+		Item:                     r.nameClaim(key, itemID),
+		ConditionExpression:      aws.String("attribute_not_exists(#id) OR item_id = :item_id"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":item_id": &types.AttributeValueMemberS{Value: itemID},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	}
+	output, err := r.client.PutItem(ctx, input)
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionalCheckFailed) {
+		return r.takeOverNameClaim(ctx, key, itemID, name, category)
+	}
+	if err != nil {
+		return nil, HandleDynamoDBError(err)
+	}
+	if output.Attributes != nil {
+		// The item already held the name, so there is nothing to release
+		return func() {}, nil
+	}
+	return func() { r.releaseNameClaim(ctx, key, itemID) }, nil
+}
+
+// takeOverNameClaim claims key from the item holding it, when that claim is
+// stale
+func (r *DynamoDBRepository) takeOverNameClaim(ctx context.Context, key, itemID, name, category string) (func(), error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            r.attrNames.key(nameClaimID(key)),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, HandleDynamoDBError(err)
+	}
+	holder, _ := output.Item["item_id"].(*types.AttributeValueMemberS)
+	claimedAt, _ := output.Item["claimed_at"].(*types.AttributeValueMemberN)
+	if holder == nil || claimedAt == nil {
+		return nil, r.nameUniqueness.takenError(name, category)
+	}
+	if seconds, _ := strconv.ParseInt(claimedAt.Value, 10, 64); time.Since(time.Unix(seconds, 0)) < nameClaimGrace {
+		return nil, r.nameUniqueness.takenError(name, category)
+	}
+	held, err := r.GetItem(ctx, holder.Value, &GetItemOptions{Consistent: true})
+	if err == nil && r.nameUniqueness.key(held.Name, held.Category) == key {
+		return nil, r.nameUniqueness.takenError(name, category)
+	}
+	if err != nil && !IsNotFoundError(err) {
+		return nil, err
+	}
+
+	// Replace the stale claim, unless another writer got there first
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                r.nameClaim(key, itemID),
+		ConditionExpression: aws.String("item_id = :stale_item_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":stale_item_id": holder,
+		},
+	})
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionalCheckFailed) {
+		return nil, r.nameUniqueness.takenError(name, category)
+	}
+	if err != nil {
+		return nil, HandleDynamoDBError(err)
+	}
+	return func() { r.releaseNameClaim(ctx, key, itemID) }, nil
+}
+
+// releaseNameClaim removes itemID's claim on key. Failures are logged
+// rather than returned, since a claim left behind goes stale.
+func (r *DynamoDBRepository) releaseNameClaim(ctx context.Context, key, itemID string) {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 r.attrNames.key(nameClaimID(key)),
+		ConditionExpression: aws.String("item_id = :item_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":item_id": &types.AttributeValueMemberS{Value: itemID},
+		},
+	})
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if err != nil && !errors.As(err, &conditionalCheckFailed) {
+		// The key holds the name, so it is logged as one to be masked
+		logging.FromContext(ctx).Warn("Failed to release name claim", "item_id", itemID, "name", key, "error", err)
+	}
+}
+
+// nameClaim is the record claiming key for itemID
+func (r *DynamoDBRepository) nameClaim(key, itemID string) map[string]types.AttributeValue {
+	record := r.attrNames.key(nameClaimID(key))
+	record["item_id"] = &types.AttributeValueMemberS{Value: itemID}
+	record["claimed_at"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Unix(), 10)}
+	return record
+}
+
+// nameChange is the claim a rename made
+type nameChange struct {
+	release func()
+	oldKey  string
+}
+
+// claimRename claims the name an update of item id gives it, when the
+// update changes its uniqueness key. set and remove are the attributes the
+// update writes and removes.
+func (r *DynamoDBRepository) claimRename(ctx context.Context, id string, set map[string]string, remove []string) (*nameChange, error) {
+	_, renamed := set["name"]
+	_, recategorized := set["category"]
+	recategorized = recategorized || slices.Contains(remove, "category")
+	if r.nameUniqueness == NameUniquenessNone || !renamed && (r.nameUniqueness == NameUniquenessGlobal || !recategorized) {
+		return nil, nil
+	}
+
+	current, err := r.GetItem(ctx, id, &GetItemOptions{Consistent: true})
+	if IsNotFoundError(err) {
+		// The update reports the missing item
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	name, category := current.Name, current.Category
+	if value, ok := set["name"]; ok {
+		name = value
+	}
+	if value, ok := set["category"]; ok {
+		category = value
+	} else if recategorized {
+		category = ""
+	}
+
+	oldKey := r.nameUniqueness.key(current.Name, current.Category)
+	if r.nameUniqueness.key(name, category) == oldKey {
+		return nil, nil
+	}
+	release, err := r.claimName(ctx, id, name, category)
+	if err != nil {
+		return nil, err
+	}
+	return &nameChange{release: release, oldKey: oldKey}, nil
+}
+
+// finish completes a rename with the update's outcome: a failed update
+// gives up the new name, a successful one the old name
+func (c *nameChange) finish(r *DynamoDBRepository, ctx context.Context, id string, item *models.Item, err error) (*models.Item, error) {
+	if c == nil {
+		return item, err
+	}
+	if err != nil {
+		c.release()
+		return nil, err
+	}
+	r.releaseNameClaim(ctx, c.oldKey, id)
+	return item, nil
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"fis-playground/internal/models"
+)
+
+// newClaimTable returns a mock that stores records by ID and evaluates the
+// conditions used for creating items and claiming names. Updates set the
+// name and category.
+func newClaimTable(records map[string]map[string]types.AttributeValue) *mockDynamoDBClient {
+	idOf := func(key map[string]types.AttributeValue) string {
+		return key["id"].(*types.AttributeValueMemberS).Value
+	}
+	holder := func(record map[string]types.AttributeValue) string {
+		if value, ok := record["item_id"].(*types.AttributeValueMemberS); ok {
+			return value.Value
+		}
+		return ""
+	}
+	wanted := func(values map[string]types.AttributeValue, name string) string {
+		return values[name].(*types.AttributeValueMemberS).Value
+	}
+	return &mockDynamoDBClient{
+		GetItemFn: func(ctx context.Context, params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: records[idOf(params.Key)]}, nil
+		},
+		PutItemFn: func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+			id := idOf(params.Item)
+			old, exists := records[id]
+			switch condition := *params.ConditionExpression; {
+			case condition == "attribute_not_exists(#id)" && exists,
+				strings.HasPrefix(condition, "attribute_not_exists(#id) OR") && exists && holder(old) != wanted(params.ExpressionAttributeValues, ":item_id"),
+				condition == "item_id = :stale_item_id" && (!exists || holder(old) != wanted(params.ExpressionAttributeValues, ":stale_item_id")):
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			records[id] = params.Item
+			if params.ReturnValues == types.ReturnValueAllOld {
+				return &dynamodb.PutItemOutput{Attributes: old}, nil
+			}
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			record, exists := records[idOf(params.Key)]
+			if !exists {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			for _, name := range []string{"name", "category"} {
+				if value, ok := params.ExpressionAttributeValues[":"+name]; ok {
+					record[name] = value
+				}
+			}
+			return &dynamodb.UpdateItemOutput{Attributes: record}, nil
+		},
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			id := idOf(params.Key)
+			if record, exists := records[id]; !exists || holder(record) != wanted(params.ExpressionAttributeValues, ":item_id") {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			delete(records, id)
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}
+}
+
+// categorizedItem returns a valid item named name in category
+func categorizedItem(name, category string) *models.Item {
+	item := models.NewItem(name, "Description")
+	item.Category = category
+	return item
+}
+
+func TestNameUniquenessFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  NameUniqueness
+	}{
+		{"", NameUniquenessNone},
+		{"global", NameUniquenessGlobal},
+		{"category", NameUniquenessCategory},
+		{"per-category", NameUniquenessNone},
+	}
+	for _, tt := range tests {
+		t.Setenv("NAME_UNIQUENESS", tt.value)
+		if got := NameUniquenessFromEnv(); got != tt.want {
+			t.Errorf("NAME_UNIQUENESS=%q: expected %q, got %q", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestNameUniqueness_KeyEscapesSeparator(t *testing.T) {
+	u := NameUniquenessCategory
+	if u.key("b", "a#") == u.key("#b", "a") {
+		t.Error("Expected names and categories containing '#' not to collide")
+	}
+	if NameUniquenessNone.key("Hammer", "tools") != "" {
+		t.Error("Expected no key when names aren't unique")
+	}
+}
+
+func TestMemoryRepository_NameUniqueByCategory(t *testing.T) {
+	t.Setenv("NAME_UNIQUENESS", "category")
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	if err := repo.CreateItem(ctx, categorizedItem("Hammer", "tools")); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	garden := categorizedItem("Hammer", "garden")
+	if err := repo.CreateItem(ctx, garden); err != nil {
+		t.Fatalf("Expected the same name in another category to be allowed, got %v", err)
+	}
+	if err := repo.CreateItem(ctx, categorizedItem("Hammer", "tools")); !errors.Is(err, ErrItemAlreadyExists) {
+		t.Errorf("Expected ErrItemAlreadyExists for the same name in the same category, got %v", err)
+	}
+
+	tools := "tools"
+	if _, err := repo.PatchItem(ctx, garden.ID, &models.PatchItemRequest{Category: &tools}); !errors.Is(err, ErrItemAlreadyExists) {
+		t.Errorf("Expected moving into a category with the name taken to fail, got %v", err)
+	}
+	if _, err := repo.UpdateItem(ctx, garden.ID, &models.UpdateItemRequest{Name: "Hammer", Description: "Renamed to itself"}); err != nil {
+		t.Errorf("Expected an item to keep its own name, got %v", err)
+	}
+}
+
+func TestMemoryRepository_NameUniqueGlobally(t *testing.T) {
+	t.Setenv("NAME_UNIQUENESS", "global")
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	if err := repo.CreateItem(ctx, categorizedItem("Hammer", "tools")); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	if err := repo.CreateItem(ctx, categorizedItem("Hammer", "garden")); !errors.Is(err, ErrItemAlreadyExists) {
+		t.Errorf("Expected ErrItemAlreadyExists across categories, got %v", err)
+	}
+}
+
+func TestDynamoDBRepository_NameUniqueByCategory(t *testing.T) {
+	t.Setenv("NAME_UNIQUENESS", "category")
+	records := map[string]map[string]types.AttributeValue{}
+	repo := NewDynamoDBRepository(newClaimTable(records), "items")
+	ctx := context.Background()
+
+	if err := repo.CreateItem(ctx, categorizedItem("Hammer", "tools")); err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	garden := categorizedItem("Hammer", "garden")
+	if err := repo.CreateItem(ctx, garden); err != nil {
+		t.Fatalf("Expected the same name in another category to be allowed, got %v", err)
+	}
+	duplicate := categorizedItem("Hammer", "tools")
+	if err := repo.CreateItem(ctx, duplicate); !errors.Is(err, ErrItemAlreadyExists) {
+		t.Fatalf("Expected ErrItemAlreadyExists for the same name in the same category, got %v", err)
+	}
+	if _, stored := records[duplicate.ID]; stored {
+		t.Error("Expected the duplicate not to be written")
+	}
+
+	// Renaming claims the new name and gives up the old one
+	if _, err := repo.UpdateItem(ctx, garden.ID, &models.UpdateItemRequest{Name: "Rake"}); err != nil {
+		t.Fatalf("Failed to rename item: %v", err)
+	}
+	if _, held := records[nameClaimID(repo.nameUniqueness.key("Hammer", "garden"))]; held {
+		t.Error("Expected the old name's claim to be released")
+	}
+	if err := repo.CreateItem(ctx, categorizedItem("Rake", "garden")); !errors.Is(err, ErrItemAlreadyExists) {
+		t.Errorf("Expected the new name to be taken, got %v", err)
+	}
+	if err := repo.CreateItem(ctx, categorizedItem("Hammer", "garden")); err != nil {
+		t.Errorf("Expected the old name to be free, got %v", err)
+	}
+}
+
+func TestDynamoDBRepository_FailedCreateReleasesName(t *testing.T) {
+	t.Setenv("NAME_UNIQUENESS", "global")
+	records := map[string]map[string]types.AttributeValue{}
+	mock := newClaimTable(records)
+	claim := mock.PutItemFn
+	mock.PutItemFn = func(ctx context.Context, params *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+		if !isMetaItemID(params.Item["id"].(*types.AttributeValueMemberS).Value) {
+			return nil, errors.New("boom")
+		}
+		return claim(ctx, params)
+	}
+	repo := NewDynamoDBRepository(mock, "items")
+
+	if err := repo.CreateItem(context.Background(), categorizedItem("Hammer", "")); err == nil {
+		t.Fatal("Expected the create to fail")
+	}
+	if len(records) != 0 {
+		t.Errorf("Expected the claim to be released, got %v", records)
+	}
+}
+
+func TestDynamoDBRepository_TakesOverStaleNameClaim(t *testing.T) {
+	t.Setenv("NAME_UNIQUENESS", "global")
+	records := map[string]map[string]types.AttributeValue{}
+	repo := NewDynamoDBRepository(newClaimTable(records), "items")
+	ctx := context.Background()
+
+	// A fresh claim is honored even though its item doesn't exist yet
+	claimID := nameClaimID(repo.nameUniqueness.key("Hammer", ""))
+	records[claimID] = repo.nameClaim(repo.nameUniqueness.key("Hammer", ""), "deleted-item")
+	if err := repo.CreateItem(ctx, categorizedItem("Hammer", "")); !errors.Is(err, ErrItemAlreadyExists) {
+		t.Fatalf("Expected a fresh claim to hold the name, got %v", err)
+	}
+
+	// Once past the grace period, a claim whose item is gone is taken over
+	records[claimID]["claimed_at"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Add(-2*nameClaimGrace).Unix(), 10)}
+	item := categorizedItem("Hammer", "")
+	if err := repo.CreateItem(ctx, item); err != nil {
+		t.Fatalf("Expected the stale claim to be taken over, got %v", err)
+	}
+	if holder := records[claimID]["item_id"].(*types.AttributeValueMemberS).Value; holder != item.ID {
+		t.Errorf("Expected the claim to be held by %s, got %s", item.ID, holder)
+	}
+}
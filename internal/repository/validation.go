@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+type skipValidationKey struct{}
+
+// WithSkipValidation returns a context whose writes skip the model
+// validators, so trusted repair tools can write items that fail them, such
+// as legacy rows. Checks the storage itself relies on, like a non-empty
+// ID, still apply.
+func WithSkipValidation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipValidationKey{}, true)
+}
+
+// SkipsValidation reports whether writes in ctx skip the model validators
+func SkipsValidation(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipValidationKey{}).(bool)
+	return skip
+}
+
+// validate runs the model's validator, unless ctx skips validation,
+// reporting a failure as ErrInvalidInput
+func validate(ctx context.Context, model interface{ Validate() error }) error {
+	if SkipsValidation(ctx) {
+		return nil
+	}
+	if err := model.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"fis-playground/internal/models"
+)
+
+func TestSkipValidation_KeepsStorageChecks(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := WithSkipValidation(context.Background())
+
+	item := &models.Item{Name: "", Description: "", Status: "legacy"}
+	if err := repo.CreateItem(ctx, item); err != nil {
+		t.Fatalf("Expected an invalid item to be created when skipping validation, got %v", err)
+	}
+	if item.ID == "" {
+		t.Error("Expected an ID to be generated")
+	}
+	if err := repo.CreateItem(context.Background(), &models.Item{Status: "legacy"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Expected validation without the skip, got %v", err)
+	}
+
+	if _, err := repo.UpdateItem(ctx, "", &models.UpdateItemRequest{Status: "legacy"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Expected an empty ID to be rejected when skipping validation, got %v", err)
+	}
+	if _, err := repo.PatchItem(ctx, item.ID, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Expected a nil patch to be rejected when skipping validation, got %v", err)
+	}
+}
+
+func TestSkipValidation_RejectsMetaItemIDs(t *testing.T) {
+	ctx := WithSkipValidation(context.Background())
+	dynamo := NewDynamoDBRepository(&mockDynamoDBClient{}, "items")
+
+	for name, repo := range map[string]ItemRepository{"memory": NewMemoryRepository(), "dynamodb": dynamo} {
+		item := &models.Item{ID: itemCounterID, Name: "Counter", Description: "Overwritten", Status: "active"}
+		if err := repo.CreateItem(ctx, item); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected %s to refuse a bookkeeping ID, got %v", name, err)
+		}
+	}
+}
+
+func TestWrites_RejectMetaItemIDs(t *testing.T) {
+	ctx := context.Background()
+	// The item counter must not be touched, nor the record written
+	dynamo := NewDynamoDBRepository(&mockDynamoDBClient{
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			t.Errorf("Unexpected update of %v", params.Key)
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+		DeleteItemFn: func(ctx context.Context, params *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+			t.Errorf("Unexpected delete of %v", params.Key)
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}, "items")
+	dynamo.maxItems = 10
+
+	for name, repo := range map[string]ItemRepository{"memory": NewMemoryRepository(), "dynamodb": dynamo} {
+		if err := repo.DeleteItem(ctx, itemCounterID, nil); !errors.Is(err, ErrItemNotFound) {
+			t.Errorf("Expected %s to refuse deleting a bookkeeping record, got %v", name, err)
+		}
+		if _, err := repo.UpdateItem(ctx, itemCounterID, &models.UpdateItemRequest{Name: "Counter"}); !errors.Is(err, ErrItemNotFound) {
+			t.Errorf("Expected %s to refuse updating a bookkeeping record, got %v", name, err)
+		}
+		if _, err := repo.PatchItem(ctx, itemCounterID, &models.PatchItemRequest{}); !errors.Is(err, ErrItemNotFound) {
+			t.Errorf("Expected %s to refuse patching a bookkeeping record, got %v", name, err)
+		}
+	}
+}
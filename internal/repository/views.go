@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// IncrementViewCount atomically adds one to the item's view_count and
+// returns the new count. ADD starts a missing counter at zero, so the
+// first view needs no initialization, and the write is not retried since
+// a retried ADD could count a view twice.
+//
+// Views are analytics rather than edits: they don't bump the generation
+// or updated_at, so counting a view never invalidates a client's
+// expected generation.
+func (r *DynamoDBRepository) IncrementViewCount(ctx context.Context, id string) (int64, error) {
+	if id == "" {
+		return 0, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+	if isMetaItemID(id) {
+		return 0, ErrItemNotFound
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                aws.String(r.tableName),
+		Key:                      r.attrNames.key(id),
+		UpdateExpression:         aws.String("ADD #view_count :one"),
+		ConditionExpression:      aws.String("attribute_exists(#id)"),
+		ExpressionAttributeNames: r.attrNames.placeholders("id", "view_count"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	}
+
+	result, err := r.client.UpdateItem(ctx, input)
+	if err != nil {
+		return 0, HandleDynamoDBError(err)
+	}
+
+	count, ok := result.Attributes[r.attrNames.Storage("view_count")].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("%w: view_count missing from update result", ErrOperationFailed)
+	}
+	return strconv.ParseInt(count.Value, 10, 64)
+}
+
+// IncrementViewCount adds one to the item's view count
+func (r *MemoryRepository) IncrementViewCount(ctx context.Context, id string) (int64, error) {
+	if id == "" {
+		return 0, fmt.Errorf("%w: item ID cannot be empty", ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return 0, fmt.Errorf("%w: conditional check failed", ErrItemNotFound)
+	}
+	item.ViewCount++
+	r.items[id] = item
+
+	return item.ViewCount, nil
+}
+
+// IncrementViewCount counts the view and invalidates the item's entry
+func (c *CachingRepository) IncrementViewCount(ctx context.Context, id string) (int64, error) {
+	count, err := c.inner.IncrementViewCount(ctx, id)
+	c.Invalidate(id)
+	return count, err
+}
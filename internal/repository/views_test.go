@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// newViewCounterMock emulates ADD on view_count for the stored items,
+// failing the existence condition for others
+func newViewCounterMock(t *testing.T, table map[string]map[string]types.AttributeValue) *mockDynamoDBClient {
+	return &mockDynamoDBClient{
+		GetItemFn: func(ctx context.Context, params *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+			t.Fatal("Expected no read before incrementing")
+			return nil, nil
+		},
+		UpdateItemFn: func(ctx context.Context, params *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+			if got := aws.ToString(params.UpdateExpression); got != "ADD #view_count :one" {
+				t.Errorf("Expected an ADD expression, got %q", got)
+			}
+			if params.ReturnValues != types.ReturnValueUpdatedNew {
+				t.Errorf("Expected UPDATED_NEW return values, got %s", params.ReturnValues)
+			}
+
+			item, ok := table[params.Key["id"].(*types.AttributeValueMemberS).Value]
+			if !ok {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			count := int64(0)
+			if current, ok := item["view_count"].(*types.AttributeValueMemberN); ok {
+				count, _ = strconv.ParseInt(current.Value, 10, 64)
+			}
+			updated := &types.AttributeValueMemberN{Value: strconv.FormatInt(count+1, 10)}
+			item["view_count"] = updated
+			return &dynamodb.UpdateItemOutput{Attributes: map[string]types.AttributeValue{"view_count": updated}}, nil
+		},
+	}
+}
+
+func TestIncrementViewCount_ExistingCounter(t *testing.T) {
+	table := map[string]map[string]types.AttributeValue{
+		"item-1": {"id": &types.AttributeValueMemberS{Value: "item-1"}, "view_count": &types.AttributeValueMemberN{Value: "41"}},
+	}
+	repo := NewDynamoDBRepository(newViewCounterMock(t, table), "items")
+
+	count, err := repo.IncrementViewCount(context.Background(), "item-1")
+	if err != nil {
+		t.Fatalf("Failed to increment view count: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Expected view count 42, got %d", count)
+	}
+}
+
+func TestIncrementViewCount_InitializesCounter(t *testing.T) {
+	table := map[string]map[string]types.AttributeValue{
+		"item-1": {"id": &types.AttributeValueMemberS{Value: "item-1"}},
+	}
+	repo := NewDynamoDBRepository(newViewCounterMock(t, table), "items")
+
+	for expected := int64(1); expected <= 2; expected++ {
+		count, err := repo.IncrementViewCount(context.Background(), "item-1")
+		if err != nil {
+			t.Fatalf("Failed to increment view count: %v", err)
+		}
+		if count != expected {
+			t.Errorf("Expected view count %d, got %d", expected, count)
+		}
+	}
+}
+
+func TestIncrementViewCount_MissingItem(t *testing.T) {
+	repo := NewDynamoDBRepository(newViewCounterMock(t, map[string]map[string]types.AttributeValue{}), "items")
+
+	if _, err := repo.IncrementViewCount(context.Background(), "missing"); !IsNotFoundError(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
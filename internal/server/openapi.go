@@ -0,0 +1,18 @@
+package server
+
+import (
+	"net/http"
+
+	fisplayground "fis-playground"
+	"fis-playground/internal/logging"
+)
+
+// serveOpenAPISpec handles GET /openapi.yaml requests, returning the
+// OpenAPI specification embedded from swagger.yaml
+func serveOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(fisplayground.OpenAPISpec); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to write OpenAPI spec", "error", err)
+	}
+}
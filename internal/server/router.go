@@ -0,0 +1,202 @@
+// Package server builds the HTTP API shared by the Lambda and standalone
+// server entrypoints, so their routes and middleware can't drift apart.
+package server
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"fis-playground/internal/emf"
+	"fis-playground/internal/events"
+	"fis-playground/internal/graphql"
+	"fis-playground/internal/handlers"
+	"fis-playground/internal/middleware"
+	"fis-playground/internal/repository"
+	"fis-playground/internal/tracing"
+)
+
+// NewRepository creates the item repository configured by the environment:
+// DynamoDB, or an in-memory store when REPOSITORY=memory, wrapped in dual
+// writes, update coalescing and caching when those are enabled
+func NewRepository(ctx context.Context) (repository.ItemRepository, error) {
+	var repo repository.ItemRepository
+	if os.Getenv("REPOSITORY") == "memory" {
+		repo = repository.NewMemoryRepository()
+	} else {
+		clientManager, err := repository.SharedClientManager(ctx)
+		if err != nil {
+			return nil, err
+		}
+		repo = repository.NewDynamoDBRepositoryFromManager(clientManager)
+		if table := repository.DualWriteTableFromEnv(); table != "" {
+			secondary := repository.NewDynamoDBRepositoryForTable(clientManager, table)
+			repo = repository.NewDualWriteRepository(repo, secondary)
+		}
+	}
+
+	if window := repository.CoalesceWindowFromEnv(); window > 0 {
+		repo = repository.NewCoalescingRepository(repo, window)
+	}
+	if ttl := repository.CacheTTLFromEnv(); ttl > 0 {
+		repo = repository.NewCachingRepository(repo, ttl)
+	}
+	return repo, nil
+}
+
+// Route names GZIP_ROUTES can list to compress only those routes. They are
+// the routes whose responses grow with the number of items.
+const (
+	RouteList     = "list"      // GET /items
+	RouteBatchGet = "batch-get" // POST /items/batch-get
+	RouteDiff     = "diff"      // GET /items/diff
+	RouteFacets   = "facets"    // GET /items/facets
+	RouteGraphQL  = "graphql"   // POST /graphql
+)
+
+// NewRouter creates the Chi router serving the API from repo. It returns
+// the concrete *chi.Mux because the Lambda proxy adapter requires it.
+// GET /metrics is only served when METRICS_ENABLED=true, so it isn't
+// exposed through API Gateway by default.
+func NewRouter(repo repository.ItemRepository) *chi.Mux {
+	return newRouter(repo, nil, middleware.MetricsEnabledFromEnv(false))
+}
+
+// NewServerRouter creates the router for the standalone server. On top of
+// NewRouter's routes it publishes item changes to broker and streams them
+// at GET /items/events, which needs connections Lambda can't hold open.
+// It serves GET /metrics unless METRICS_ENABLED=false.
+func NewServerRouter(repo repository.ItemRepository, broker *events.Broker) *chi.Mux {
+	return newRouter(repo, broker, middleware.MetricsEnabledFromEnv(true))
+}
+
+// newRouter creates the router, with the item event stream when broker is
+// set and request metrics at GET /metrics when metrics is true
+func newRouter(repo repository.ItemRepository, broker *events.Broker, metrics bool) *chi.Mux {
+	itemHandler := handlers.NewItemHandler(repo)
+	if broker != nil {
+		itemHandler.SetEventBroker(broker)
+	}
+	graphqlHandler := graphql.NewHandler(itemHandler)
+	adminKey := middleware.AdminKeyFromEnv()
+	adminOnly := middleware.RequireAdminKey(adminKey)
+	createLimit := middleware.LimitCreatesPerPrincipal(middleware.CreateRateConfigFromEnv())
+
+	// Create Chi router
+	r := chi.NewRouter()
+
+	// Add middleware
+	r.Use(chimiddleware.RequestID)
+	r.Use(middleware.RequestLogger(slog.Default()))
+	r.Use(middleware.EmitMetrics(emf.NewEmitterFromEnv()))
+	var requestMetrics *middleware.Metrics
+	if metrics {
+		requestMetrics = middleware.NewMetrics()
+		r.Use(requestMetrics.Record)
+	}
+	r.Use(middleware.Trace(tracing.NewTracerFromEnv()))
+	r.Use(middleware.Recover(slog.Default()))
+	r.Use(middleware.LimitRequestHeaders(middleware.HeaderLimitsFromEnv()))
+	r.Use(middleware.IdentifyAdmin(adminKey))
+	// With token authentication the principal comes from the token alone,
+	// so clients can't claim one through the principal header
+	if jwt := middleware.JWTConfigFromEnv(); jwt.JWKSURL != "" {
+		r.Use(middleware.AuthenticateJWT(jwt, nil))
+	} else {
+		r.Use(middleware.IdentifyPrincipal(middleware.PrincipalHeaderFromEnv()))
+	}
+	if budget := middleware.RetryBudgetFromEnv(); budget > 0 {
+		r.Use(middleware.RetryBudget(slog.Default(), budget))
+	}
+	compression := middleware.CompressionConfigFromEnv()
+	if compression.Global() {
+		r.Use(middleware.Gzip(compression))
+	}
+	if debugLog := middleware.DebugLogConfigFromEnv(); debugLog.SamplePercent > 0 {
+		r.Use(middleware.DebugLogging(slog.Default(), debugLog))
+	}
+
+	// Add CORS middleware
+	r.Use(middleware.CORS(middleware.CORSConfigFromEnv()))
+
+	// Rate limit after CORS, so preflights aren't counted and 429s carry
+	// the CORS headers browsers need to read them
+	r.Use(middleware.LimitRequestsPerClient(middleware.ClientRateConfigFromEnv()))
+
+	// Health check endpoints
+	r.Get("/health", itemHandler.HealthCheck)      // Simple API health check
+	r.Get("/health/db", itemHandler.HealthCheckDB) // DynamoDB connectivity check
+	r.Get("/", itemHandler.HealthCheck)            // Root path health check
+
+	// Error code catalog, so clients can enumerate the codes they may receive
+	r.Get("/errors", itemHandler.ListErrorCodes)
+
+	// OpenAPI specification
+	r.Get("/openapi.yaml", serveOpenAPISpec)
+
+	// Prometheus metrics
+	if requestMetrics != nil {
+		r.Method("GET", "/metrics", requestMetrics)
+	}
+
+	// API routes
+	r.Route("/items", func(r chi.Router) {
+		r.With(compression.ForRoute(RouteList)).Get("/", itemHandler.ListItems)
+		r.With(compression.ForRoute(RouteList)).Get("/by-date/{date}", itemHandler.ListItems)
+		r.With(createLimit).Post("/", itemHandler.CreateItem)
+		r.With(createLimit).Post("/batch", itemHandler.BatchCreateItems)
+		r.Post("/explain", itemHandler.ExplainItem)
+		r.With(compression.ForRoute(RouteBatchGet)).Post("/batch-get", itemHandler.BatchGetItems)
+		r.With(compression.ForRoute(RouteDiff)).Get("/diff", itemHandler.DiffItems)
+		r.With(compression.ForRoute(RouteFacets)).Get("/facets", itemHandler.FacetItems)
+		r.Get("/autocomplete", itemHandler.AutocompleteItems)
+		r.With(adminOnly).Post("/bulk-tag", itemHandler.BulkTagItems)
+		r.With(adminOnly).Post("/bulk-delete-by-filter", itemHandler.BulkDeleteByFilter)
+		if broker != nil {
+			r.Get("/events", itemHandler.StreamEvents)
+		}
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", itemHandler.GetItem)
+			r.Put("/", itemHandler.UpdateItem)
+			r.Patch("/", itemHandler.PatchItem)
+			r.Delete("/", itemHandler.DeleteItem)
+			r.Post("/view", itemHandler.RecordView)
+			r.Post("/lease", itemHandler.GetItemForUpdate)
+			r.Post("/restore", itemHandler.RestoreItem)
+			r.Delete("/purge", itemHandler.PurgeItem)
+			if broker != nil {
+				r.With(adminOnly).Post("/replay-events", itemHandler.ReplayItemEvents)
+			}
+		})
+	})
+
+	// Browsable HTML table of items
+	r.Get("/items.html", itemHandler.ListItemsHTML)
+
+	// Status of creates accepted asynchronously
+	r.Get("/jobs/{id}", itemHandler.GetCreateJob)
+
+	// Admin routes, guarded by the admin API key
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(adminOnly)
+		r.Post("/import", itemHandler.ImportItem)
+		r.Post("/compact", itemHandler.CompactItems)
+		r.Get("/items/{id}/raw", itemHandler.GetRawItem)
+		r.Get("/probe", itemHandler.ProbeTable)
+	})
+
+	// GraphQL API; createItem mutations share the creates' budget
+	r.With(createLimit, compression.ForRoute(RouteGraphQL)).Post("/graphql", graphqlHandler.ServeGraphQL)
+	r.Get("/graphql/schema", graphqlHandler.ServeSchema)
+
+	// Answer unknown routes and methods with JSON errors like the API's
+	// others; the 405 handler reads the routes, so it comes last
+	r.NotFound(handlers.RouteNotFound)
+	r.MethodNotAllowed(handlers.MethodNotAllowed(r))
+
+	return r
+}
@@ -0,0 +1,469 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"fis-playground/internal/events"
+	"fis-playground/internal/models"
+	"fis-playground/internal/repository"
+)
+
+func TestNewRouter_ServesRoutes(t *testing.T) {
+	router := NewRouter(repository.NewMemoryRepository())
+
+	tests := []struct {
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{"GET", "/health", "", http.StatusOK},
+		{"GET", "/errors", "", http.StatusOK},
+		{"POST", "/items", `{"name":"Item","description":"Description"}`, http.StatusCreated},
+		{"GET", "/items", "", http.StatusOK},
+		{"GET", "/items/missing", "", http.StatusNotFound},
+		{"POST", "/admin/compact?before=2024-01-01T00:00:00Z", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewRouter_ServesOpenAPISpec(t *testing.T) {
+	router := NewRouter(repository.NewMemoryRepository())
+
+	req := httptest.NewRequest("GET", "/openapi.yaml", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("Expected Content-Type application/yaml, got %q", ct)
+	}
+
+	// No YAML library is vendored, so check the document's block structure:
+	// top-level keys, and the path keys nested directly under "paths"
+	topLevel := map[string]bool{}
+	paths := map[string]bool{}
+	section := ""
+	for i, line := range strings.Split(w.Body.String(), "\n") {
+		if strings.HasPrefix(strings.TrimLeft(line, " "), "\t") {
+			t.Fatalf("Line %d is indented with a tab: %q", i+1, line)
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			key, _, ok := strings.Cut(line, ":")
+			if !ok {
+				t.Fatalf("Line %d is not a mapping key: %q", i+1, line)
+			}
+			section = key
+			topLevel[key] = true
+			continue
+		}
+		if section == "paths" && strings.HasPrefix(line, "  /") && !strings.HasPrefix(line, "   ") {
+			paths[strings.TrimSuffix(strings.TrimSpace(line), ":")] = true
+		}
+	}
+
+	for _, key := range []string{"openapi", "info", "paths", "components"} {
+		if !topLevel[key] {
+			t.Errorf("Expected top-level key %q, got %v", key, topLevel)
+		}
+	}
+	for _, path := range []string{"/items", "/items/{id}", "/openapi.yaml"} {
+		if !paths[path] {
+			t.Errorf("Expected path %q, got %v", path, paths)
+		}
+	}
+}
+
+func TestNewRouter_RefusesWritesToBookkeepingRecords(t *testing.T) {
+	router := NewRouter(repository.NewMemoryRepository())
+
+	// The path parameter is decoded, so %23 reaches the repository as '#'
+	for _, method := range []string{"PUT", "PATCH", "DELETE"} {
+		req := httptest.NewRequest(method, "/items/_meta%23item_count", strings.NewReader(`{"name":"Counter"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected %s to return 404, got %d: %s", method, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestNewRouter_TokenAuthenticationIgnoresPrincipalHeader(t *testing.T) {
+	createdBy := func(router http.Handler) string {
+		req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Principal-ID", "alice")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.Item `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response.Data.CreatedBy
+	}
+
+	if got := createdBy(NewRouter(repository.NewMemoryRepository())); got != "alice" {
+		t.Errorf("Expected the principal header to be used without tokens, got %q", got)
+	}
+
+	// Anonymous requests don't fetch the keys, so the URL is never used
+	t.Setenv("JWT_JWKS_URL", "https://auth.example.com/jwks.json")
+	if got := createdBy(NewRouter(repository.NewMemoryRepository())); got != "" {
+		t.Errorf("Expected an anonymous request to claim no principal, got %q", got)
+	}
+}
+
+func TestNewRouter_MethodNotAllowed(t *testing.T) {
+	router := NewRouter(repository.NewMemoryRepository())
+
+	tests := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{"POST", "/items/abc", "GET, PUT, PATCH, DELETE"},
+		{"DELETE", "/items", "GET, POST"},
+		{"PUT", "/health", "GET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("Expected status 405, got %d: %s", w.Code, w.Body.String())
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.allow {
+				t.Errorf("Expected Allow %q, got %q", tt.allow, allow)
+			}
+			var response models.APIResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Expected a JSON body: %v", err)
+			}
+			if response.Success || response.Error == nil || response.Error.Code != "METHOD_NOT_ALLOWED" {
+				t.Errorf("Expected a METHOD_NOT_ALLOWED error, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestNewRouter_RouteNotFound(t *testing.T) {
+	router := NewRouter(repository.NewMemoryRepository())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/no-such-route", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected a JSON response, got %q", contentType)
+	}
+	var response models.APIResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Expected a JSON body: %v", err)
+	}
+	if response.Success || response.Error == nil || response.Error.Code != "NOT_FOUND" {
+		t.Errorf("Expected a NOT_FOUND error, got %+v", response)
+	}
+}
+
+func TestNewServerRouter_StreamsItemEvents(t *testing.T) {
+	srv := httptest.NewServer(NewServerRouter(repository.NewMemoryRepository(), events.NewBroker()))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", srv.URL+"/items/events", nil)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect to the event stream: %v", err)
+	}
+	defer stream.Body.Close()
+	if got := stream.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Expected Content-Type text/event-stream, got %q", got)
+	}
+
+	created, err := http.Post(srv.URL+"/items", "application/json", strings.NewReader(`{"name":"Item","description":"Description"}`))
+	if err != nil {
+		t.Fatalf("Failed to create item: %v", err)
+	}
+	created.Body.Close()
+
+	scanner := bufio.NewScanner(stream.Body)
+	var eventName string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			eventName = name
+			continue
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var event events.Event
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				t.Fatalf("Failed to decode event %q: %v", data, err)
+			}
+			if eventName != events.ItemCreated || event.Item == nil || event.Item.Name != "Item" || event.ID != event.Item.ID {
+				t.Errorf("Expected a created event for the item, got %s %+v", eventName, event)
+			}
+			return
+		}
+	}
+	t.Fatalf("Stream ended without an event: %v", scanner.Err())
+}
+
+func TestNewServerRouter_ReplayEventsRequiresAdminKey(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", "admin-secret")
+	repo := repository.NewMemoryRepository()
+	item := models.NewItem("Item", "Description")
+	if err := repo.CreateItem(context.Background(), item); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	router := NewServerRouter(repo, events.NewBroker())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/items/"+item.ID+"/replay-events", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected replay without the admin key to be rejected with 401, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest("POST", "/items/"+item.ID+"/replay-events", nil)
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected replay with the admin key to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestNewRouter_HasNoEventStream(t *testing.T) {
+	router := NewRouter(repository.NewMemoryRepository())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/items/events", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected the Lambda router to have no event stream, got %d", w.Code)
+	}
+}
+
+func TestNewServerRouter_ServesMetrics(t *testing.T) {
+	router := NewServerRouter(repository.NewMemoryRepository(), events.NewBroker())
+
+	for _, path := range []string{"/items", "/items", "/items/missing-1", "/items/missing-2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("Expected the text exposition format, got %q", contentType)
+	}
+
+	body := w.Body.String()
+	for _, line := range []string{
+		`http_requests_total{method="GET",route="/items",status="200"} 2`,
+		`http_requests_total{method="GET",route="/items/{id}",status="404"} 2`,
+		`http_request_duration_seconds_bucket{method="GET",route="/items",le="+Inf"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="/items/{id}"} 2`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected metrics to contain %s, got:\n%s", line, body)
+		}
+	}
+	if strings.Contains(body, "missing-1") {
+		t.Errorf("Expected item IDs not to become labels, got:\n%s", body)
+	}
+}
+
+func TestNewRouter_HasNoMetrics(t *testing.T) {
+	router := NewRouter(repository.NewMemoryRepository())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected the Lambda router not to expose metrics, got %d", w.Code)
+	}
+
+	t.Setenv("METRICS_ENABLED", "true")
+	router = NewRouter(repository.NewMemoryRepository())
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected METRICS_ENABLED to expose metrics, got %d", w.Code)
+	}
+}
+
+func TestNewRouter_TracingDisabled(t *testing.T) {
+	t.Setenv("ENABLE_XRAY", "")
+	router := NewRouter(repository.NewMemoryRepository())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/items", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("X-Amzn-Trace-Id"); got != "" {
+		t.Errorf("Expected no trace header without ENABLE_XRAY, got %q", got)
+	}
+}
+
+func TestNewRouter_CompressesOnlyConfiguredRoutes(t *testing.T) {
+	t.Setenv("GZIP_ENABLED", "true")
+	t.Setenv("GZIP_ROUTES", RouteList)
+	t.Setenv("GZIP_MIN_SIZE", "0")
+	router := NewRouter(repository.NewMemoryRepository())
+
+	created := httptest.NewRecorder()
+	router.ServeHTTP(created, httptest.NewRequest("POST", "/items", strings.NewReader(`{"name":"Item","description":"Description"}`)))
+	if created.Code != http.StatusCreated {
+		t.Fatalf("Failed to create item: %d %s", created.Code, created.Body.String())
+	}
+	var response struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(created.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode created item: %v", err)
+	}
+
+	tests := []struct {
+		path       string
+		expectGzip bool
+	}{
+		{"/items", true},
+		{"/items/" + response.Data.ID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if gzipped := w.Header().Get("Content-Encoding") == "gzip"; gzipped != tt.expectGzip {
+				t.Errorf("Expected gzip %v, got Content-Encoding %q", tt.expectGzip, w.Header().Get("Content-Encoding"))
+			}
+		})
+	}
+}
+
+func TestNewRouter_CompressesLargeResponses(t *testing.T) {
+	t.Setenv("GZIP_ENABLED", "true")
+	repo := repository.NewMemoryRepository()
+	router := NewRouter(repo)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// One item's list is under the 1KB default threshold
+	if err := repo.CreateItem(context.Background(), models.NewItem("Item 0", "Description")); err != nil {
+		t.Fatalf("Failed to seed item: %v", err)
+	}
+	small := get("/items")
+	if small.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected a small response to be sent uncompressed, got Content-Encoding %q", small.Header().Get("Content-Encoding"))
+	}
+
+	for i := 1; i < 20; i++ {
+		if err := repo.CreateItem(context.Background(), models.NewItem(fmt.Sprintf("Item %d", i), "Description")); err != nil {
+			t.Fatalf("Failed to seed item: %v", err)
+		}
+	}
+	w := get("/items")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected a large response to be gzipped, got Content-Encoding %q", w.Header().Get("Content-Encoding"))
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected Content-Type to stay application/json, got %q", got)
+	}
+	if got := w.Header().Get("Content-Length"); got != strconv.Itoa(w.Body.Len()) {
+		t.Errorf("Expected Content-Length %d for the compressed body, got %q", w.Body.Len(), got)
+	}
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Expected a gzip body, got %v", err)
+	}
+	var response struct {
+		Success bool                     `json:"success"`
+		Data    models.ListItemsResponse `json:"data"`
+	}
+	if err := json.NewDecoder(gz).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode the decompressed body: %v", err)
+	}
+	if !response.Success || len(response.Data.Items) != 20 {
+		t.Errorf("Expected the 20 listed items, got success %v with %d items", response.Success, len(response.Data.Items))
+	}
+}
+
+func TestNewRouter_CORSAllowlist(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	router := NewRouter(repository.NewMemoryRepository())
+
+	for origin, want := range map[string]string{
+		"https://app.example.com":  "https://app.example.com",
+		"https://evil.example.com": "",
+	} {
+		req := httptest.NewRequest("OPTIONS", "/items", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("Origin %s: expected Access-Control-Allow-Origin %q, got %q", origin, want, got)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+// Package tracing records AWS X-Ray segments for requests and subsegments
+// for the calls they make, and sends them to the X-Ray daemon. It speaks
+// the daemon's UDP protocol directly: each segment or subsegment is sent as
+// its own JSON document once it closes.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceHeader carries the trace ID, parent segment and sampling decision
+// between services
+const TraceHeader = "X-Amzn-Trace-Id"
+
+// Defaults used when AWS_XRAY_DAEMON_ADDRESS or AWS_XRAY_TRACING_NAME are
+// not set
+const (
+	DefaultDaemonAddress = "127.0.0.1:2000"
+	DefaultServiceName   = "fis-playground"
+)
+
+// daemonHeader precedes every document sent to the daemon
+const daemonHeader = `{"format": "json", "version": 1}` + "\n"
+
+// Enabled reports whether ENABLE_XRAY turns tracing on
+func Enabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("ENABLE_XRAY"))
+	return enabled
+}
+
+// Tracer starts segments and sends them to the X-Ray daemon once they
+// close. A nil *Tracer starts no segments, so callers can hold one whether
+// or not tracing is enabled.
+type Tracer struct {
+	service string
+
+	mu     sync.Mutex
+	daemon io.Writer
+}
+
+// NewTracer creates a tracer naming its segments after service and writing
+// each closed segment to daemon in a single Write, which is one datagram
+// on a UDP connection
+func NewTracer(service string, daemon io.Writer) *Tracer {
+	return &Tracer{service: service, daemon: daemon}
+}
+
+// NewTracerFromEnv creates a tracer sending to the daemon at
+// AWS_XRAY_DAEMON_ADDRESS, which Lambda sets when active tracing is on. It
+// returns nil when ENABLE_XRAY is not set, or the address is invalid.
+func NewTracerFromEnv() *Tracer {
+	if !Enabled() {
+		return nil
+	}
+	address := os.Getenv("AWS_XRAY_DAEMON_ADDRESS")
+	if address == "" {
+		address = DefaultDaemonAddress
+	}
+	// The variable may list separate TCP and UDP addresses
+	for _, part := range strings.Fields(address) {
+		if udp, ok := strings.CutPrefix(part, "udp:"); ok {
+			address = udp
+		}
+	}
+	service := os.Getenv("AWS_XRAY_TRACING_NAME")
+	if service == "" {
+		service = DefaultServiceName
+	}
+
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		log.Printf("Ignoring invalid AWS_XRAY_DAEMON_ADDRESS %q, tracing disabled: %v", address, err)
+		return nil
+	}
+	return NewTracer(service, conn)
+}
+
+// StartSegment starts a segment for a request, continuing the trace in
+// header when the caller sent one. It returns nil when the tracer is nil or
+// the caller decided not to sample the trace.
+func (t *Tracer) StartSegment(header string) *Segment {
+	if t == nil {
+		return nil
+	}
+	root, parent, sampled := parseTraceHeader(header)
+	if sampled == "0" {
+		return nil
+	}
+	if root == "" {
+		root = newTraceID()
+	}
+	return &Segment{
+		tracer: t,
+		doc: document{
+			Name:      t.service,
+			ID:        newSegmentID(),
+			TraceID:   root,
+			ParentID:  parent,
+			StartTime: epochSeconds(time.Now()),
+		},
+	}
+}
+
+// send writes a closed segment to the daemon
+func (t *Tracer) send(doc *document) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		log.Printf("Failed to encode trace segment: %v", err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.daemon.Write(append([]byte(daemonHeader), data...)); err != nil {
+		log.Printf("Failed to send trace segment: %v", err)
+	}
+}
+
+// parseTraceHeader reads the root trace ID, parent segment ID and sampling
+// decision from a trace header such as
+// "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+func parseTraceHeader(header string) (root, parent, sampled string) {
+	for _, part := range strings.Split(header, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "Root":
+			root = value
+		case "Parent":
+			parent = value
+		case "Sampled":
+			sampled = value
+		}
+	}
+	return root, parent, sampled
+}
+
+// newTraceID generates a trace ID: the version, the start time in epoch
+// seconds and 96 random bits, all in hex
+func newTraceID() string {
+	return fmt.Sprintf("1-%08x-%s", time.Now().Unix(), randomHex(12))
+}
+
+// newSegmentID generates a 64-bit segment ID in hex
+func newSegmentID() string {
+	return randomHex(8)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// epochSeconds converts a time to X-Ray's fractional epoch seconds
+func epochSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+// Segment is a segment or subsegment being recorded. Its methods do
+// nothing on a nil *Segment, so code can record onto whatever segment the
+// context holds without checking whether tracing is on.
+type Segment struct {
+	tracer *Tracer
+
+	mu  sync.Mutex
+	doc document
+}
+
+// document is a segment as the X-Ray daemon expects it
+type document struct {
+	Name        string            `json:"name"`
+	ID          string            `json:"id"`
+	TraceID     string            `json:"trace_id"`
+	ParentID    string            `json:"parent_id,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	StartTime   float64           `json:"start_time"`
+	EndTime     float64           `json:"end_time,omitempty"`
+	Error       bool              `json:"error,omitempty"`
+	Fault       bool              `json:"fault,omitempty"`
+	Throttle    bool              `json:"throttle,omitempty"`
+	HTTP        *httpData         `json:"http,omitempty"`
+	AWS         map[string]string `json:"aws,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// httpData is the HTTP request a segment served, or a subsegment made
+type httpData struct {
+	Request  *httpRequest  `json:"request,omitempty"`
+	Response *httpResponse `json:"response,omitempty"`
+}
+
+type httpRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type httpResponse struct {
+	Status int `json:"status"`
+}
+
+// StartSubsegment starts a subsegment of the segment in ctx, returning a
+// context holding it. It returns a nil subsegment when ctx holds no
+// segment.
+func StartSubsegment(ctx context.Context, name string) (context.Context, *Segment) {
+	parent := FromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	parent.mu.Lock()
+	traceID, parentID := parent.doc.TraceID, parent.doc.ID
+	parent.mu.Unlock()
+
+	subsegment := &Segment{
+		tracer: parent.tracer,
+		doc: document{
+			Name:      name,
+			ID:        newSegmentID(),
+			TraceID:   traceID,
+			ParentID:  parentID,
+			Type:      "subsegment",
+			StartTime: epochSeconds(time.Now()),
+		},
+	}
+	return WithSegment(ctx, subsegment), subsegment
+}
+
+// TraceID returns the ID of the segment's trace
+func (s *Segment) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.doc.TraceID
+}
+
+// SetNamespace marks a subsegment as a call to an AWS service ("aws") or
+// another remote service ("remote")
+func (s *Segment) SetNamespace(namespace string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc.Namespace = namespace
+}
+
+// SetHTTPRequest records the request's method and URL
+func (s *Segment) SetHTTPRequest(method, url string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.doc.HTTP == nil {
+		s.doc.HTTP = &httpData{}
+	}
+	s.doc.HTTP.Request = &httpRequest{Method: method, URL: url}
+}
+
+// SetHTTPStatus records the response status, flagging 4xx responses as
+// errors, 429 as throttled and 5xx as faults the way X-Ray expects
+func (s *Segment) SetHTTPStatus(status int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.doc.HTTP == nil {
+		s.doc.HTTP = &httpData{}
+	}
+	s.doc.HTTP.Response = &httpResponse{Status: status}
+	switch {
+	case status == http.StatusTooManyRequests:
+		s.doc.Error, s.doc.Throttle = true, true
+	case status >= 500:
+		s.doc.Fault = true
+	case status >= 400:
+		s.doc.Error = true
+	}
+}
+
+// SetError records a failed call. Errors carrying an HTTP status, as AWS
+// SDK errors do, are flagged by that status; any other error is a fault.
+func (s *Segment) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	var withStatus interface{ HTTPStatusCode() int }
+	if errors.As(err, &withStatus) && withStatus.HTTPStatusCode() > 0 {
+		s.SetHTTPStatus(withStatus.HTTPStatusCode())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc.Fault = true
+}
+
+// SetAWS records AWS call details, such as the operation and table name
+func (s *Segment) SetAWS(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.doc.AWS == nil {
+		s.doc.AWS = map[string]string{}
+	}
+	s.doc.AWS[key] = value
+}
+
+// SetAnnotation records an indexed value traces can be searched by
+func (s *Segment) SetAnnotation(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.doc.Annotations == nil {
+		s.doc.Annotations = map[string]string{}
+	}
+	s.doc.Annotations[key] = value
+}
+
+// Close ends the segment and sends it to the daemon
+func (s *Segment) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.doc.EndTime = epochSeconds(time.Now())
+	doc := s.doc
+	s.mu.Unlock()
+
+	s.tracer.send(&doc)
+}
+
+// segmentKey is the context key for the current segment
+type segmentKey struct{}
+
+// WithSegment returns a context holding the segment, which subsegments
+// started from the context become children of
+func WithSegment(ctx context.Context, segment *Segment) context.Context {
+	return context.WithValue(ctx, segmentKey{}, segment)
+}
+
+// FromContext returns the segment in ctx, or nil when there is none
+func FromContext(ctx context.Context) *Segment {
+	segment, _ := ctx.Value(segmentKey{}).(*Segment)
+	return segment
+}
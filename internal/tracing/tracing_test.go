@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// daemonRecorder stands in for the X-Ray daemon, decoding each document
+// it is sent
+type daemonRecorder struct {
+	t    *testing.T
+	docs []document
+}
+
+func (d *daemonRecorder) Write(p []byte) (int, error) {
+	body, ok := strings.CutPrefix(string(p), daemonHeader)
+	if !ok {
+		d.t.Errorf("Expected the daemon header, got %q", p)
+	}
+	var doc document
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		d.t.Errorf("Failed to decode document %q: %v", body, err)
+	}
+	d.docs = append(d.docs, doc)
+	return len(p), nil
+}
+
+func TestStartSegment_NewTrace(t *testing.T) {
+	daemon := &daemonRecorder{t: t}
+	segment := NewTracer("items-api", daemon).StartSegment("")
+	segment.SetHTTPRequest("GET", "/items")
+	segment.SetHTTPStatus(http.StatusOK)
+	segment.Close()
+
+	if len(daemon.docs) != 1 {
+		t.Fatalf("Expected one document, got %d", len(daemon.docs))
+	}
+	doc := daemon.docs[0]
+	if !regexp.MustCompile(`^1-[0-9a-f]{8}-[0-9a-f]{24}$`).MatchString(doc.TraceID) {
+		t.Errorf("Expected a new trace ID, got %q", doc.TraceID)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{16}$`).MatchString(doc.ID) {
+		t.Errorf("Expected a 16-digit segment ID, got %q", doc.ID)
+	}
+	if doc.Name != "items-api" || doc.ParentID != "" || doc.Type != "" {
+		t.Errorf("Expected a root segment named after the service, got %+v", doc)
+	}
+	if doc.EndTime < doc.StartTime || doc.StartTime == 0 {
+		t.Errorf("Expected the segment to be timed, got %v to %v", doc.StartTime, doc.EndTime)
+	}
+	if doc.HTTP == nil || doc.HTTP.Request.Method != "GET" || doc.HTTP.Response.Status != http.StatusOK {
+		t.Errorf("Expected the HTTP request and response recorded, got %+v", doc.HTTP)
+	}
+}
+
+func TestStartSegment_ContinuesTrace(t *testing.T) {
+	daemon := &daemonRecorder{t: t}
+	tracer := NewTracer("items-api", daemon)
+
+	segment := tracer.StartSegment("Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+	segment.Close()
+	if doc := daemon.docs[0]; doc.TraceID != "1-5759e988-bd862e3fe1be46a994272793" || doc.ParentID != "53995c3f42cd8ad8" {
+		t.Errorf("Expected the caller's trace to continue, got %+v", doc)
+	}
+
+	if segment := tracer.StartSegment("Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=0"); segment != nil {
+		t.Error("Expected no segment for a trace the caller didn't sample")
+	}
+}
+
+func TestStartSubsegment(t *testing.T) {
+	daemon := &daemonRecorder{t: t}
+	segment := NewTracer("items-api", daemon).StartSegment("")
+
+	ctx, subsegment := StartSubsegment(WithSegment(context.Background(), segment), "DynamoDB")
+	if FromContext(ctx) != subsegment {
+		t.Error("Expected the context to hold the subsegment")
+	}
+	subsegment.SetNamespace("aws")
+	subsegment.SetAWS("operation", "GetItem")
+	subsegment.SetError(errors.New("connection reset"))
+	subsegment.Close()
+	segment.Close()
+
+	if len(daemon.docs) != 2 {
+		t.Fatalf("Expected two documents, got %d", len(daemon.docs))
+	}
+	sub, root := daemon.docs[0], daemon.docs[1]
+	if sub.Type != "subsegment" || sub.TraceID != root.TraceID || sub.ParentID != root.ID {
+		t.Errorf("Expected a subsegment of %s in trace %s, got %+v", root.ID, root.TraceID, sub)
+	}
+	if sub.Namespace != "aws" || sub.AWS["operation"] != "GetItem" || !sub.Fault {
+		t.Errorf("Expected a faulted AWS call, got %+v", sub)
+	}
+}
+
+func TestStartSubsegment_WithoutSegment(t *testing.T) {
+	ctx, subsegment := StartSubsegment(context.Background(), "DynamoDB")
+	if subsegment != nil || FromContext(ctx) != nil {
+		t.Error("Expected no subsegment outside a traced request")
+	}
+
+	// Recording onto a nil segment is a no-op
+	subsegment.SetAWS("operation", "GetItem")
+	subsegment.SetError(errors.New("failed"))
+	subsegment.Close()
+}
+
+func TestSegment_SetHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status                 int
+		error, throttle, fault bool
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusNotFound, error: true},
+		{status: http.StatusTooManyRequests, error: true, throttle: true},
+		{status: http.StatusServiceUnavailable, fault: true},
+	}
+
+	for _, tt := range tests {
+		segment := NewTracer("items-api", &daemonRecorder{t: t}).StartSegment("")
+		segment.SetHTTPStatus(tt.status)
+		if segment.doc.Error != tt.error || segment.doc.Throttle != tt.throttle || segment.doc.Fault != tt.fault {
+			t.Errorf("Status %d: expected error=%t throttle=%t fault=%t, got %+v", tt.status, tt.error, tt.throttle, tt.fault, segment.doc)
+		}
+	}
+}
+
+func TestNewTracerFromEnv(t *testing.T) {
+	t.Setenv("ENABLE_XRAY", "")
+	if tracer := NewTracerFromEnv(); tracer != nil {
+		t.Error("Expected no tracer unless ENABLE_XRAY is set")
+	}
+
+	t.Setenv("ENABLE_XRAY", "true")
+	t.Setenv("AWS_XRAY_DAEMON_ADDRESS", "tcp:127.0.0.1:2000 udp:127.0.0.1:2001")
+	if tracer := NewTracerFromEnv(); tracer == nil || tracer.service != DefaultServiceName {
+		t.Errorf("Expected a tracer for the default service, got %+v", tracer)
+	}
+}
@@ -0,0 +1,10 @@
+// Package fisplayground holds repository-level assets compiled into the
+// service binaries.
+package fisplayground
+
+import _ "embed"
+
+// OpenAPISpec is the API's OpenAPI specification (swagger.yaml)
+//
+//go:embed swagger.yaml
+var OpenAPISpec []byte
@@ -9,6 +9,7 @@ ENVIRONMENT="dev"
 REGION="us-east-1"
 TEMPLATE_FILE="deployments/cloudformation/template.yaml"
 ROLLBACK_ON_FAILURE="true"
+PAGE_TOKEN_SECRET="${PAGE_TOKEN_SECRET:-}"
 
 # Color codes for output
 RED='\033[0;31m'
@@ -117,6 +118,10 @@ while [[ $# -gt 0 ]]; do
       ROLLBACK_ON_FAILURE="false"
       shift
       ;;
+    --page-token-secret)
+      PAGE_TOKEN_SECRET="$2"
+      shift 2
+      ;;
     --help)
       echo "Usage: $0 [OPTIONS]"
       echo "Options:"
@@ -124,6 +129,7 @@ while [[ $# -gt 0 ]]; do
       echo "  --environment   Environment name (default: dev)"
       echo "  --region        AWS region (default: us-east-1)"
       echo "  --no-rollback   Disable rollback on failure (default: enabled)"
+      echo "  --page-token-secret  Secret signing pagination tokens (default: \$PAGE_TOKEN_SECRET, else kept from the stack or generated)"
       echo "  --help          Show this help message"
       exit 0
       ;;
@@ -206,11 +212,22 @@ else
     log_info "Creating new stack..."
 fi
 
+# Keep the stack's pagination token secret unless a new one was given, so
+# tokens issued before the deployment stay valid; generate one otherwise
+if [ -n "$PAGE_TOKEN_SECRET" ]; then
+    SECRET_PARAM="ParameterKey=PageTokenSecret,ParameterValue=$PAGE_TOKEN_SECRET"
+elif [ "$STACK_EXISTS" = true ] && [ -n "$(aws cloudformation describe-stacks --stack-name "$STACK_NAME" --region "$REGION" --query "Stacks[0].Parameters[?ParameterKey=='PageTokenSecret'].ParameterKey" --output text)" ]; then
+    SECRET_PARAM="ParameterKey=PageTokenSecret,UsePreviousValue=true"
+else
+    log_info "Generating a pagination token secret..."
+    SECRET_PARAM="ParameterKey=PageTokenSecret,ParameterValue=$(openssl rand -hex 32)"
+fi
+
 # Prepare CloudFormation parameters
 CF_PARAMS=(
     --stack-name "$STACK_NAME"
     --template-body file://"$TEMPLATE_FILE"
-    --parameters ParameterKey=Environment,ParameterValue="$ENVIRONMENT" ParameterKey=LambdaS3Bucket,ParameterValue="$S3_BUCKET" ParameterKey=LambdaS3Key,ParameterValue="$S3_KEY"
+    --parameters ParameterKey=Environment,ParameterValue="$ENVIRONMENT" ParameterKey=LambdaS3Bucket,ParameterValue="$S3_BUCKET" ParameterKey=LambdaS3Key,ParameterValue="$S3_KEY" "$SECRET_PARAM"
     --capabilities CAPABILITY_IAM CAPABILITY_AUTO_EXPAND
     --region "$REGION"
 )
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -102,6 +103,18 @@ func parseResponse(resp *http.Response) (*models.APIResponse, error) {
 	return &apiResp, nil
 }
 
+// parseListResponse parses a GET /items response into the typed list payload
+func parseListResponse(resp *http.Response) (*models.ListItemsResponse, error) {
+	defer resp.Body.Close()
+	
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	
+	return models.ParseListResponse(body)
+}
+
 // TestEndToEndCRUDWorkflow tests complete CRUD operations through deployed API
 func TestEndToEndCRUDWorkflow(t *testing.T) {
 	config := getTestConfig()
@@ -212,36 +225,16 @@ func TestEndToEndCRUDWorkflow(t *testing.T) {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
 		}
 		
-		apiResp, err := parseResponse(resp)
+		list, err := parseListResponse(resp)
 		if err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
-		}
-		
-		if !apiResp.Success {
-			t.Errorf("Expected success=true, got success=%v, error=%v", apiResp.Success, apiResp.Error)
-		}
-		
-		// Verify response contains items array
-		if apiResp.Data == nil {
-			t.Fatal("Expected data in response, got nil")
-		}
-		
-		listData, ok := apiResp.Data.(map[string]interface{})
-		if !ok {
-			t.Fatal("Expected list data to be object")
-		}
-		
-		items, ok := listData["items"].([]interface{})
-		if !ok {
-			t.Fatal("Expected items array in response")
+			t.Fatalf("Failed to parse list response: %v", err)
 		}
 		
 		// Verify our item is in the list
 		itemID := os.Getenv("TEST_ITEM_ID")
 		found := false
-		for _, item := range items {
-			itemObj, ok := item.(map[string]interface{})
-			if ok && itemObj["id"] == itemID {
+		for _, item := range list.Items {
+			if item.ID == itemID {
 				found = true
 				break
 			}
@@ -726,7 +719,7 @@ func TestEdgeCases(t *testing.T) {
 	
 	t.Run("ListItemsWithPagination", func(t *testing.T) {
 		// Test pagination parameters
-		resp, err := client.makeRequest("GET", "/items?limit=5&cursor=test", nil)
+		resp, err := client.makeRequest("GET", "/items?limit=5", nil)
 		if err != nil {
 			t.Fatalf("Failed to make request: %v", err)
 		}
@@ -735,27 +728,97 @@ func TestEdgeCases(t *testing.T) {
 			t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
 		}
 		
+		list, err := parseListResponse(resp)
+		if err != nil {
+			t.Fatalf("Failed to parse list response: %v", err)
+		}
+		
+		// Verify pagination structure
+		if list.Count != len(list.Items) {
+			t.Errorf("Expected count %d to match items returned, got %d", len(list.Items), list.Count)
+		}
+		if list.HasMore && list.NextToken == "" {
+			t.Error("Expected next_token when has_more is true")
+		}
+	})
+	
+	t.Run("ListItemsWithInvalidCursor", func(t *testing.T) {
+		// Test a cursor that was never issued by the API
+		resp, err := client.makeRequest("GET", "/items?limit=5&cursor=test", nil)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+		
 		apiResp, err := parseResponse(resp)
 		if err != nil {
 			t.Fatalf("Failed to parse response: %v", err)
 		}
 		
-		if !apiResp.Success {
-			t.Errorf("Expected success=true, got success=%v, error=%v", apiResp.Success, apiResp.Error)
+		if apiResp.Success || apiResp.Error == nil {
+			t.Errorf("Expected an error response, got success=%v", apiResp.Success)
 		}
-		
-		// Verify pagination structure
-		if apiResp.Data != nil {
-			listData, ok := apiResp.Data.(map[string]interface{})
-			if ok {
-				if _, exists := listData["items"]; !exists {
-					t.Error("Expected items array in paginated response")
-				}
-				if _, exists := listData["has_more"]; !exists {
-					t.Error("Expected has_more field in paginated response")
+	})
+	
+	t.Run("ListItemsPageTokenRoundTrip", func(t *testing.T) {
+		// Create enough items that a one-item page has a next page
+		for i := 0; i < 2; i++ {
+			createReq := models.CreateItemRequest{
+				Name:        fmt.Sprintf("Paged Item %d", i),
+				Description: "Created to page through",
+			}
+			
+			resp, err := client.makeRequest("POST", "/items", createReq)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			
+			apiResp, err := parseResponse(resp)
+			if err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if itemData, ok := apiResp.Data.(map[string]interface{}); ok {
+				if itemID, ok := itemData["id"].(string); ok {
+					defer client.makeRequest("DELETE", "/items/"+itemID, nil)
 				}
 			}
 		}
+		
+		resp, err := client.makeRequest("GET", "/items?limit=1", nil)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		
+		first, err := parseListResponse(resp)
+		if err != nil {
+			t.Fatalf("Failed to parse list response: %v", err)
+		}
+		
+		if !first.HasMore || first.NextToken == "" {
+			t.Fatalf("Expected a next_token after a one-item page, got has_more=%v", first.HasMore)
+		}
+		
+		// Follow the token the API issued
+		resp, err = client.makeRequest("GET", "/items?limit=1&next_token="+url.QueryEscape(first.NextToken), nil)
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		
+		second, err := parseListResponse(resp)
+		if err != nil {
+			t.Fatalf("Failed to parse list response: %v", err)
+		}
+		
+		if len(first.Items) == 1 && len(second.Items) == 1 && first.Items[0].ID == second.Items[0].ID {
+			t.Errorf("Expected the next page to continue past item %s", first.Items[0].ID)
+		}
 	})
 	
 	t.Run("ConcurrentOperations", func(t *testing.T) {